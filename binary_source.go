@@ -0,0 +1,122 @@
+package ii18n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// Type BinarySource reads the binary catalogs written by ii18n gen -binary,
+// which load without parsing.
+type BinarySource struct {
+	MessageSource
+}
+
+// New BinarySource
+func NewBinarySource(conf *Config) Source {
+	s := &BinarySource{}
+	s.init(conf, "bin", binaryCodec{})
+
+	return s
+}
+
+// binaryMagic the first bytes of binary catalogs, with the version of the
+// format.
+const binaryMagic = "II18NBC1"
+
+// binaryHeaderSize the size of the magic and the entry count.
+const binaryHeaderSize = len(binaryMagic) + 4
+
+// binaryCodec binary catalogs, little-endian:
+//
+//	magic   "II18NBC1"
+//	count   uint32
+//	entries count × {keyOffset, keyLength, valueOffset, valueLength uint32}
+//	strings the keys and values, UTF-8
+//
+// The entries are sorted by key, the offsets relative to the strings, so a
+// catalog can be searched in place. Decode copies the strings once and
+// slices the messages out of the copy.
+type binaryCodec struct{}
+
+func (binaryCodec) Decode(data []byte) (TMsgs, error) {
+	entries, strs, err := binaryEntries(data)
+	if err != nil {
+		return nil, err
+	}
+	blob := string(strs)
+	msgs := make(TMsgs, len(entries)/16)
+	for pos := 0; pos < len(entries); pos += 16 {
+		e := entries[pos:]
+		key, err := binaryString(blob, binary.LittleEndian.Uint32(e), binary.LittleEndian.Uint32(e[4:]))
+		if err != nil {
+			return nil, err
+		}
+		val, err := binaryString(blob, binary.LittleEndian.Uint32(e[8:]), binary.LittleEndian.Uint32(e[12:]))
+		if err != nil {
+			return nil, err
+		}
+		msgs[key] = val
+	}
+	return msgs, nil
+}
+
+// Encode Encodes msgs, the values sharing the strings of equal values.
+func (binaryCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	keys := make([]string, 0, len(msgs))
+	for key := range msgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var entries, strs bytes.Buffer
+	offsets := make(map[string]uint32)
+	write := func(s string) error {
+		offset, ok := offsets[s]
+		if !ok {
+			if uint64(strs.Len())+uint64(len(s)) > 1<<32-1 {
+				return errors.New("binary catalog larger than 4 GiB")
+			}
+			offset = uint32(strs.Len())
+			offsets[s] = offset
+			strs.WriteString(s)
+		}
+		return binary.Write(&entries, binary.LittleEndian, [2]uint32{offset, uint32(len(s))})
+	}
+	for _, key := range keys {
+		if err := write(key); err != nil {
+			return nil, err
+		}
+		if err := write(msgs[key]); err != nil {
+			return nil, err
+		}
+	}
+	b := bytes.NewBuffer(make([]byte, 0, binaryHeaderSize+entries.Len()+strs.Len()))
+	b.WriteString(binaryMagic)
+	binary.Write(b, binary.LittleEndian, uint32(len(keys)))
+	b.Write(entries.Bytes())
+	b.Write(strs.Bytes())
+	return b.Bytes(), nil
+}
+
+// binaryEntries Returns the entries and the strings of the binary catalog
+// data.
+func binaryEntries(data []byte) ([]byte, []byte, error) {
+	if len(data) < binaryHeaderSize || string(data[:len(binaryMagic)]) != binaryMagic {
+		return nil, nil, errors.New("not a binary catalog")
+	}
+	n := uint64(binary.LittleEndian.Uint32(data[len(binaryMagic):]))
+	end := uint64(binaryHeaderSize) + n*16
+	if end > uint64(len(data)) {
+		return nil, nil, errors.New("binary catalog entries out of range")
+	}
+	return data[binaryHeaderSize:end], data[end:], nil
+}
+
+// binaryString Returns the string of length at offset in strs.
+func binaryString(strs string, offset uint32, length uint32) (string, error) {
+	if uint64(offset)+uint64(length) > uint64(len(strs)) {
+		return "", errors.New("binary catalog string out of range")
+	}
+	return strs[offset : offset+length], nil
+}
@@ -0,0 +1,426 @@
+package ii18n
+
+import (
+	"strings"
+	"time"
+)
+
+// Calendar a CLDR calendar system, selected with the -u-ca- extension of a
+// language tag such as "th-TH-u-ca-buddhist".
+type Calendar string
+
+// Calendars.
+const (
+	CalendarGregorian Calendar = "gregory"
+	CalendarBuddhist  Calendar = "buddhist"
+	// CalendarIslamicUmalqura the Umm al-Qura calendar of Saudi Arabia,
+	// computed with the arithmetic of the tabular Islamic calendar, which
+	// may differ from the observed calendar by a day.
+	CalendarIslamicUmalqura Calendar = "islamic-umalqura"
+	// CalendarIslamicCivil the tabular Islamic calendar.
+	CalendarIslamicCivil Calendar = "islamic-civil"
+	CalendarHebrew       Calendar = "hebrew"
+	CalendarJapanese     Calendar = "japanese"
+	CalendarPersian      Calendar = "persian"
+)
+
+// regionCalendars default calendars of regions not using the Gregorian
+// calendar by default.
+var regionCalendars = map[string]Calendar{
+	"AF": CalendarPersian,
+	"IR": CalendarPersian,
+	"SA": CalendarIslamicUmalqura,
+	"TH": CalendarBuddhist,
+}
+
+// WithCalendar Returns lang requesting the calendar cal, "ja-JP-u-ca-japanese"
+// for "ja-JP" and CalendarJapanese.
+func WithCalendar(lang string, cal Calendar) string {
+	if pos := strings.Index(lang, "-u-ca-"); pos != -1 {
+		lang = lang[:pos]
+	}
+	return lang + "-u-ca-" + string(cal)
+}
+
+// calendarOf Returns the calendar lang requests with its -u-ca- extension,
+// or the default calendar of its region.
+func calendarOf(lang string) Calendar {
+	lang = strings.Replace(lang, "_", "-", -1)
+	if pos := strings.Index(lang, "-u-ca-"); pos != -1 {
+		cal := lang[pos+len("-u-ca-"):]
+		if end := strings.Index(cal, "-"); end != -1 && cal[:end] != "islamic" {
+			cal = cal[:end]
+		}
+		return Calendar(cal)
+	}
+	parts := strings.Split(lang, "-")
+	for _, part := range parts[1:] {
+		if cal, ok := regionCalendars[strings.ToUpper(part)]; ok && len(part) == 2 {
+			return cal
+		}
+	}
+	return CalendarGregorian
+}
+
+// calendarDate a date in a calendar. month is the index of the month in
+// the month names of the calendar, counted from one.
+type calendarDate struct {
+	era   int
+	year  int
+	month int
+	day   int
+	leap  bool
+}
+
+// toCalendar Returns the date of t in cal, the Gregorian date for unknown
+// calendars.
+func toCalendar(cal Calendar, t time.Time) calendarDate {
+	y, m, d := t.Date()
+	date := calendarDate{era: 1, year: y, month: int(m), day: d}
+	if y <= 0 {
+		date.era, date.year = 0, 1-y
+	}
+	switch cal {
+	case CalendarBuddhist:
+		date.era, date.year = 0, y+543
+	case CalendarJapanese:
+		for i := len(japaneseEras) - 1; i >= 0; i-- {
+			start := japaneseEras[i]
+			if !t.Before(time.Date(start.year, start.month, start.day, 0, 0, 0, 0, t.Location())) {
+				date.era, date.year = i, y-start.year+1
+				break
+			}
+		}
+	case CalendarPersian:
+		date.year, date.month, date.day = persianFromJDN(julianDay(y, int(m), d))
+		date.era = 0
+	case CalendarIslamicUmalqura, CalendarIslamicCivil:
+		date.year, date.month, date.day = islamicFromJDN(julianDay(y, int(m), d))
+		date.era = 0
+	case CalendarHebrew:
+		date.year, date.month, date.day, date.leap = hebrewFromFixed(julianDay(y, int(m), d) - 1721425)
+		date.era = 0
+	}
+	return date
+}
+
+// japaneseEras start dates of the modern Japanese eras.
+var japaneseEras = []struct {
+	year  int
+	month time.Month
+	day   int
+}{
+	{1868, time.September, 8},
+	{1912, time.July, 30},
+	{1926, time.December, 25},
+	{1989, time.January, 8},
+	{2019, time.May, 1},
+}
+
+// julianDay Returns the Julian day number of a proleptic Gregorian date.
+func julianDay(y int, m int, d int) int {
+	a := (14 - m) / 12
+	y += 4800 - a
+	m += 12*a - 3
+	return d + (153*m+2)/5 + 365*y + floorDiv(y, 4) - floorDiv(y, 100) + floorDiv(y, 400) - 32045
+}
+
+func floorDiv(a int, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// persianBreaks years of the 33-year cycle breaks of the Persian calendar.
+var persianBreaks = []int{-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210, 1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178}
+
+// persianYear Returns whether the Persian year jy is leap, as 0 for leap
+// years, and the day of March its first day falls on.
+func persianYear(jy int) (leap int, march int) {
+	gy := jy + 621
+	leapJ, jp, jump := -14, persianBreaks[0], 0
+	for _, jm := range persianBreaks[1:] {
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := jy - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return leap, march
+}
+
+// persianFromJDN Returns the Persian date of a Julian day number.
+func persianFromJDN(jdn int) (int, int, int) {
+	gy := jdn - 1721060
+	gy = gy * 4 / 1461
+	// the Gregorian year of jdn, refined from the estimate
+	for julianDay(gy+1, 1, 1) <= jdn {
+		gy++
+	}
+	for julianDay(gy, 1, 1) > jdn {
+		gy--
+	}
+	jy := gy - 621
+	leap, march := persianYear(jy)
+	k := jdn - julianDay(gy, 3, march)
+	if k >= 0 {
+		if k <= 185 {
+			return jy, 1 + k/31, k%31 + 1
+		}
+		k -= 186
+	} else {
+		jy--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return jy, 7 + k/30, k%30 + 1
+}
+
+// islamicEpoch Julian day number of 1 Muharram 1 AH in the civil tabular
+// Islamic calendar.
+const islamicEpoch = 1948440
+
+// islamicFromJDN Returns the tabular Islamic date of a Julian day number.
+func islamicFromJDN(jdn int) (int, int, int) {
+	y := floorDiv(30*(jdn-islamicEpoch)+10646, 10631)
+	m := 1
+	for m < 12 && islamicToJDN(y, m+1, 1) <= jdn {
+		m++
+	}
+	return y, m, jdn - islamicToJDN(y, m, 1) + 1
+}
+
+// islamicToJDN Returns the Julian day number of a tabular Islamic date.
+func islamicToJDN(y int, m int, d int) int {
+	return d + (59*(m-1)+1)/2 + (y-1)*354 + floorDiv(3+11*y, 30) + islamicEpoch - 1
+}
+
+// hebrewEpoch fixed day number of 1 Tishri 1 AM, with day 1 being
+// 1 January 1 in the proleptic Gregorian calendar.
+const hebrewEpoch = -1373427
+
+func hebrewLeap(y int) bool {
+	return (7*y+1)%19 < 7
+}
+
+// hebrewElapsedDays Returns the days from the epoch to the molad of Tishri
+// of year y, delayed when it falls on a Sunday, Wednesday or Friday.
+func hebrewElapsedDays(y int) int {
+	months := floorDiv(235*y-234, 19)
+	parts := 12084 + 13753*months
+	day := 29*months + floorDiv(parts, 25920)
+	if (3*(day+1))%7 < 3 {
+		day++
+	}
+	return day
+}
+
+// hebrewNewYear Returns the fixed day number of 1 Tishri of year y.
+func hebrewNewYear(y int) int {
+	ny0, ny1, ny2 := hebrewElapsedDays(y-1), hebrewElapsedDays(y), hebrewElapsedDays(y+1)
+	delay := 0
+	if ny2-ny1 == 356 {
+		delay = 2
+	} else if ny1-ny0 == 382 {
+		delay = 1
+	}
+	return hebrewEpoch + ny1 + delay
+}
+
+// hebrewMonthDays Returns the days of month m of year y, months counted
+// from Nisan and Adar II being 13.
+func hebrewMonthDays(y int, m int) int {
+	days := hebrewNewYear(y+1) - hebrewNewYear(y)
+	switch {
+	case m == 2 || m == 4 || m == 6 || m == 10 || m == 13:
+		return 29
+	case m == 12 && !hebrewLeap(y):
+		return 29
+	case m == 8 && days%10 != 5:
+		return 29
+	case m == 9 && days%10 == 3:
+		return 29
+	}
+	return 30
+}
+
+// hebrewToFixed Returns the fixed day number of a Hebrew date.
+func hebrewToFixed(y int, m int, d int) int {
+	last := 12
+	if hebrewLeap(y) {
+		last = 13
+	}
+	fixed := hebrewNewYear(y) + d - 1
+	if m < 7 {
+		for i := 7; i <= last; i++ {
+			fixed += hebrewMonthDays(y, i)
+		}
+		for i := 1; i < m; i++ {
+			fixed += hebrewMonthDays(y, i)
+		}
+	} else {
+		for i := 7; i < m; i++ {
+			fixed += hebrewMonthDays(y, i)
+		}
+	}
+	return fixed
+}
+
+// hebrewFromFixed Returns the Hebrew year, the index of the month counted
+// from Tishri as in CLDR, the day and whether the year is leap.
+func hebrewFromFixed(fixed int) (int, int, int, bool) {
+	y := floorDiv((fixed-hebrewEpoch)*98496, 35975351) + 1
+	for hebrewNewYear(y) > fixed {
+		y--
+	}
+	for hebrewNewYear(y+1) <= fixed {
+		y++
+	}
+	m := 7
+	if fixed < hebrewToFixed(y, 1, 1) {
+		for fixed >= hebrewToFixed(y, m+1, 1) {
+			m++
+		}
+	} else {
+		m = 1
+		for m < 6 && fixed >= hebrewToFixed(y, m+1, 1) {
+			m++
+		}
+	}
+	d := fixed - hebrewToFixed(y, m, 1) + 1
+	leap := hebrewLeap(y)
+	// CLDR counts Tishri as 1, Adar I as 6 and Adar or Adar II as 7.
+	var index int
+	switch {
+	case m >= 7 && m <= 11:
+		index = m - 6
+	case m == 12 && leap:
+		index = 6
+	case m == 12 || m == 13:
+		index = 7
+	default:
+		index = m + 7
+	}
+	return y, index, d, leap
+}
+
+// calendarNames month and era names of a calendar in a language. Hebrew
+// months have a thirteenth entry naming Adar II in leap years.
+type calendarNames struct {
+	months []string
+	eras   []string
+}
+
+// calendarNameData names of non-Gregorian calendars by calendar and
+// language, English being the fallback.
+var calendarNameData = map[Calendar]map[string]calendarNames{
+	CalendarBuddhist: {
+		"en": {eras: []string{"BE"}},
+		"th": {
+			months: []string{"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน", "กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม"},
+			eras:   []string{"พ.ศ."},
+		},
+	},
+	CalendarJapanese: {
+		"en": {eras: []string{"Meiji", "Taishō", "Shōwa", "Heisei", "Reiwa"}},
+		"ja": {eras: []string{"明治", "大正", "昭和", "平成", "令和"}},
+	},
+	CalendarPersian: {
+		"en": {
+			months: []string{"Farvardin", "Ordibehesht", "Khordad", "Tir", "Mordad", "Shahrivar", "Mehr", "Aban", "Azar", "Dey", "Bahman", "Esfand"},
+			eras:   []string{"AP"},
+		},
+		"fa": {
+			months: []string{"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور", "مهر", "آبان", "آذر", "دی", "بهمن", "اسفند"},
+			eras:   []string{"ه‍.ش."},
+		},
+	},
+	CalendarIslamicCivil: {
+		"en": {
+			months: []string{"Muharram", "Safar", "Rabiʻ I", "Rabiʻ II", "Jumada I", "Jumada II", "Rajab", "Shaʻban", "Ramadan", "Shawwal", "Dhuʻl-Qiʻdah", "Dhuʻl-Hijjah"},
+			eras:   []string{"AH"},
+		},
+		"ar": {
+			months: []string{"محرم", "صفر", "ربيع الأول", "ربيع الآخر", "جمادى الأولى", "جمادى الآخرة", "رجب", "شعبان", "رمضان", "شوال", "ذو القعدة", "ذو الحجة"},
+			eras:   []string{"هـ"},
+		},
+	},
+	CalendarHebrew: {
+		"en": {
+			months: []string{"Tishri", "Heshvan", "Kislev", "Tevet", "Shevat", "Adar I", "Adar", "Nisan", "Iyar", "Sivan", "Tamuz", "Av", "Elul", "Adar II"},
+			eras:   []string{"AM"},
+		},
+		"he": {
+			months: []string{"תשרי", "חשוון", "כסלו", "טבת", "שבט", "אדר א׳", "אדר", "ניסן", "אייר", "סיוון", "תמוז", "אב", "אלול", "אדר ב׳"},
+			eras:   []string{"לבריאה"},
+		},
+	},
+}
+
+// calendarNamesOf Returns the names of cal in lang, or nil for the
+// Gregorian calendar. Calendars without month names of their own, such as
+// the Buddhist calendar in English, use the Gregorian months of lang.
+func calendarNamesOf(cal Calendar, lang string) *calendarNames {
+	if cal == CalendarIslamicUmalqura {
+		cal = CalendarIslamicCivil
+	}
+	data, ok := calendarNameData[cal]
+	if !ok {
+		return nil
+	}
+	names, ok := data[baseLang(lang)]
+	if !ok {
+		names = data["en"]
+	}
+	return &names
+}
+
+// withEra Returns the date pattern with the era after the year, or before
+// it in Chinese and Japanese, unless it shows the era already. A two-digit
+// year is written in full, as it is ambiguous across eras.
+func withEra(pattern string, lang string) string {
+	if strings.ContainsRune(pattern, 'G') {
+		return pattern
+	}
+	start := -1
+	quoted := false
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\'' {
+			quoted = !quoted
+		} else if !quoted && pattern[i] == 'y' {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return pattern
+	}
+	end := start
+	for end < len(pattern) && pattern[end] == 'y' {
+		end++
+	}
+	switch baseLang(lang) {
+	case "ja", "zh":
+		return pattern[:start] + "Gy" + pattern[end:]
+	}
+	return pattern[:start] + "y G" + pattern[end:]
+}
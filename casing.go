@@ -0,0 +1,218 @@
+package ii18n
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ToUpper Returns s in upper case by the rules of lang, e.g. for button
+// labels in capitals: "i" is "İ" in Turkish and Azerbaijani, Greek loses its
+// accents, the dot above the soft-dotted i and j of Lithuanian is dropped,
+// and "ß" is "SS".
+func ToUpper(s string, lang string) string {
+	switch strings.ToLower(baseLang(lang)) {
+	case "tr", "az":
+		s = strings.ToUpperSpecial(unicode.TurkishCase, s)
+	case "el":
+		s = strings.ToUpper(stripGreekAccents(s))
+	case "lt":
+		s = strings.ToUpper(dropDotAbove(s))
+	default:
+		s = strings.ToUpper(s)
+	}
+	return strings.ReplaceAll(s, "ß", "SS")
+}
+
+// ToLower Returns s in lower case by the rules of lang: "I" is "ı" in
+// Turkish and Azerbaijani, Lithuanian keeps the dot of "i" under accents,
+// and the capital sigma ending a word is "ς".
+func ToLower(s string, lang string) string {
+	s = finalSigma(s)
+	switch strings.ToLower(baseLang(lang)) {
+	case "tr", "az":
+		return strings.ToLowerSpecial(unicode.TurkishCase, s)
+	case "lt":
+		return strings.ToLower(keepDotAbove(s))
+	}
+	return strings.ToLower(s)
+}
+
+// greekUnaccented the unaccented capitals of the accented Greek letters.
+var greekUnaccented = map[rune]rune{
+	'ά': 'Α', 'έ': 'Ε', 'ή': 'Η', 'ί': 'Ι', 'ό': 'Ο', 'ύ': 'Υ', 'ώ': 'Ω',
+	'Ά': 'Α', 'Έ': 'Ε', 'Ή': 'Η', 'Ί': 'Ι', 'Ό': 'Ο', 'Ύ': 'Υ', 'Ώ': 'Ω',
+	'ΐ': 'Ϊ', 'ΰ': 'Ϋ',
+}
+
+// stripGreekAccents Returns s without the tonos of its Greek letters, which
+// capitals do not take.
+func stripGreekAccents(s string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range s {
+		if u, ok := greekUnaccented[r]; ok {
+			r = u
+		} else if (r == '\u0301' || r == '\u0342') && unicode.Is(unicode.Greek, prev) {
+			// The combining acute and perispomeni.
+			continue
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// dropDotAbove Returns s without the combining dots above following the
+// soft-dotted i, j and į, which capitals do not need.
+func dropDotAbove(s string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range s {
+		if r == '\u0307' && (prev == 'i' || prev == 'j' || prev == 'į') {
+			continue
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// lithuanianDotted the lower case of the accented capital I of Lithuanian,
+// with the dot above kept.
+var lithuanianDotted = map[rune]string{
+	'Ì': "i\u0307\u0300",
+	'Í': "i\u0307\u0301",
+	'Ĩ': "i\u0307\u0303",
+}
+
+// keepDotAbove Returns s with a combining dot above inserted after the I, J
+// and Į followed by accents, and the accented capital I decomposed, so the
+// lower case keeps the dot of the i under the accent.
+func keepDotAbove(s string) string {
+	var b strings.Builder
+	for pos, r := range s {
+		if dotted, ok := lithuanianDotted[r]; ok {
+			b.WriteString(dotted)
+			continue
+		}
+		b.WriteRune(r)
+		if r == 'I' || r == 'J' || r == 'Į' {
+			if next, _ := utf8.DecodeRuneInString(s[pos+utf8.RuneLen(r):]); accentAbove(next) {
+				b.WriteRune('\u0307')
+			}
+		}
+	}
+	return b.String()
+}
+
+// accentAbove Whether r is a combining accent written above its letter.
+func accentAbove(r rune) bool {
+	return r >= '\u0300' && r <= '\u0314' || r >= '\u033d' && r <= '\u0344' || r == '\u0346'
+}
+
+// finalSigma Returns s with the capital sigma ending a word as ς: after a
+// letter and not before one, combining marks aside.
+func finalSigma(s string) string {
+	if !strings.ContainsRune(s, 'Σ') {
+		return s
+	}
+	runes := []rune(s)
+	for n, r := range runes {
+		if r != 'Σ' {
+			continue
+		}
+		before := n - 1
+		for before >= 0 && unicode.Is(unicode.Mn, runes[before]) {
+			before--
+		}
+		after := n + 1
+		for after < len(runes) && unicode.Is(unicode.Mn, runes[after]) {
+			after++
+		}
+		if before >= 0 && unicode.IsLetter(runes[before]) && (after == len(runes) || !unicode.IsLetter(runes[after])) {
+			runes[n] = 'ς'
+		}
+	}
+	return string(runes)
+}
+
+// ToTitle Returns s title-cased by the conventions of lang, e.g. for
+// headings built from translated fragments. English capitalizes every word
+// but the articles, conjunctions and short prepositions inside the title or
+// its parts separated by colons; languages titled in sentence case, such as
+// French, German or Swedish, only the first word of each sentence; other
+// languages every word. Words with a capital already, such as acronyms and
+// brands, are left as they are. Dutch capitalizes "ij" as "IJ", Turkish and
+// Azerbaijani "i" as "İ".
+func ToTitle(s string, lang string) string {
+	base := strings.ToLower(baseLang(lang))
+	type word struct{ start, end int }
+	var words []word
+	start := -1
+	for pos, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) ||
+			start >= 0 && (r == '\'' || r == '’')
+		if inWord && start < 0 {
+			start = pos
+		} else if !inWord && start >= 0 {
+			words = append(words, word{start, pos})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{start, len(s)})
+	}
+
+	var b strings.Builder
+	last := 0
+	for n, w := range words {
+		sep := s[last:w.start]
+		b.WriteString(sep)
+		last = w.end
+		text := s[w.start:w.end]
+		first := n == 0 || strings.ContainsAny(sep, ".!?") || base == "en" && strings.Contains(sep, ":")
+		switch {
+		case strings.IndexFunc(text, unicode.IsUpper) >= 0:
+		case titleSentenceCase[base] && !first:
+		case base == "en" && !first && n != len(words)-1 && englishMinorWords[text]:
+		default:
+			text = capitalize(text, base)
+		}
+		b.WriteString(text)
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// titleSentenceCase the languages whose titles are in sentence case.
+var titleSentenceCase = map[string]bool{
+	"az": true, "be": true, "bg": true, "bs": true, "ca": true, "cs": true, "da": true,
+	"de": true, "el": true, "es": true, "et": true, "fi": true, "fr": true, "gl": true,
+	"hr": true, "hu": true, "it": true, "lt": true, "lv": true, "nb": true, "nl": true,
+	"nn": true, "no": true, "pl": true, "pt": true, "ro": true, "ru": true, "sk": true,
+	"sl": true, "sr": true, "sv": true, "tr": true, "uk": true,
+}
+
+// englishMinorWords the English words lower-cased inside titles.
+var englishMinorWords = map[string]bool{
+	"a": true, "an": true, "the": true,
+	"and": true, "but": true, "or": true, "nor": true, "for": true, "so": true, "yet": true,
+	"as": true, "at": true, "by": true, "in": true, "of": true, "off": true, "on": true,
+	"per": true, "to": true, "up": true, "via": true, "vs": true,
+}
+
+// capitalize Returns word with its first letter in title case by the rules
+// of the language base.
+func capitalize(word string, base string) string {
+	if base == "nl" && strings.HasPrefix(word, "ij") {
+		return "IJ" + word[2:]
+	}
+	r, size := utf8.DecodeRuneInString(word)
+	if base == "tr" || base == "az" {
+		r = unicode.TurkishCase.ToTitle(r)
+	} else {
+		r = unicode.ToTitle(r)
+	}
+	return string(r) + word[size:]
+}
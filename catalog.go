@@ -0,0 +1,110 @@
+package ii18n
+
+import "sort"
+
+// Entry a catalog message with its comments and metadata.
+type Entry struct {
+	Key   string
+	Value string
+	// Context disambiguates equal keys, msgctxt in PO files.
+	Context string
+	// Comments translator comments, without comment markers.
+	Comments []string
+	// Extracted comments for translators from the source code.
+	Extracted []string
+	// Refs source references such as "main.go:12".
+	Refs []string
+	// Flags such as "fuzzy".
+	Flags []string
+	// Previous the previous msgctxt and msgid of a fuzzy entry, "#|"
+	// comments in PO files, e.g. `msgid "Old key"`.
+	Previous []string
+	// Plural the plural key, msgid_plural in PO files.
+	Plural string
+	// Forms the plural forms indexed as in the Plural-Forms header; Value
+	// is the first.
+	Forms []string
+}
+
+// Catalog a catalog with its comments and header metadata, as read and
+// written by a CatalogCodec.
+type Catalog struct {
+	// Meta header fields such as "Plural-Forms" in PO files.
+	Meta    map[string]string
+	Entries []Entry
+}
+
+// CatalogCodec is implemented by codecs that keep comments and metadata.
+// EncodeCatalog writes entries sorted by key so files diff well.
+type CatalogCodec interface {
+	Codec
+	DecodeCatalog(data []byte) (*Catalog, error)
+	EncodeCatalog(c *Catalog, lang string) ([]byte, error)
+}
+
+// NewCatalog Returns a catalog of msgs, sorted by key.
+func NewCatalog(msgs TMsgs) *Catalog {
+	c := &Catalog{}
+	c.Update(msgs)
+	return c
+}
+
+// Msgs Returns the messages of c.
+func (c *Catalog) Msgs() TMsgs {
+	msgs := make(TMsgs, len(c.Entries))
+	for _, e := range c.Entries {
+		msgs[e.Key] = e.Value
+	}
+	return msgs
+}
+
+// Update Makes c hold exactly msgs: values of existing entries are replaced,
+// keeping their comments, entries not in msgs are dropped and new ones added.
+// Entries end up sorted by key.
+func (c *Catalog) Update(msgs TMsgs) {
+	entries := make([]Entry, 0, len(msgs))
+	seen := make(map[string]bool, len(msgs))
+	for _, e := range c.Entries {
+		if val, ok := msgs[e.Key]; ok && !seen[e.Key] {
+			if val != e.Value {
+				e.Plural, e.Forms = "", nil
+			}
+			e.Value = val
+			entries = append(entries, e)
+			seen[e.Key] = true
+		}
+	}
+	for key, val := range msgs {
+		if !seen[key] {
+			entries = append(entries, Entry{Key: key, Value: val})
+		}
+	}
+	c.Entries = entries
+	c.Sort()
+}
+
+// Add Adds an entry for each key of msgs c has none for, leaving the
+// existing entries as they are, plural forms and flags included. Entries end
+// up sorted by key.
+func (c *Catalog) Add(msgs TMsgs) {
+	seen := make(map[string]bool, len(c.Entries))
+	for _, e := range c.Entries {
+		seen[e.Key] = true
+	}
+	for key, val := range msgs {
+		if !seen[key] {
+			c.Entries = append(c.Entries, Entry{Key: key, Value: val})
+		}
+	}
+	c.Sort()
+}
+
+// Sort Sorts the entries of c by key and context.
+func (c *Catalog) Sort() {
+	sort.SliceStable(c.Entries, func(i, j int) bool {
+		if c.Entries[i].Key != c.Entries[j].Key {
+			return c.Entries[i].Key < c.Entries[j].Key
+		}
+		return c.Entries[i].Context < c.Entries[j].Context
+	})
+}
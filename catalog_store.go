@@ -0,0 +1,94 @@
+package ii18n
+
+import (
+	"runtime"
+	"sync"
+)
+
+// catalogStore the catalog files read by sources, by path, shared by the
+// sources reading the same files alike, e.g. the sources of per-tenant
+// managers or tests with the same BasePath, so the catalogs are read and
+// kept once.
+type catalogStore struct {
+	files map[string]fetched
+	// generation counts the evictions, so reads in progress do not cache
+	// stale files.
+	generation uint64
+	// key the key of the store in sharedStores, "" when it is not shared.
+	key   string
+	refs  int
+	mutex sync.RWMutex
+}
+
+// sharedStores the stores shared by sources, by the format, BasePath and
+// verification of their files. A store is dropped when the last of its
+// sources is garbage collected.
+var sharedStores = struct {
+	stores map[string]*catalogStore
+	mutex  sync.Mutex
+}{stores: make(map[string]*catalogStore)}
+
+// newCatalogStore Returns a store of its own.
+func newCatalogStore() *catalogStore {
+	return &catalogStore{files: make(map[string]fetched)}
+}
+
+// shareStore Sets the store of ms to the one shared by the sources reading
+// the files of conf in format suffix alike. Decrypted catalogs are kept in a
+// store of their own, so they are never served to sources without the keys.
+func (ms *MessageSource) shareStore(conf *Config, suffix string) {
+	if conf.DecryptKeys != nil {
+		ms.store = newCatalogStore()
+		return
+	}
+	key := suffix + "\x00" + conf.BasePath + "\x00" + conf.Manifest + "\x00" + string(conf.VerifyKey)
+	sharedStores.mutex.Lock()
+	defer sharedStores.mutex.Unlock()
+	store, ok := sharedStores.stores[key]
+	if !ok {
+		store = newCatalogStore()
+		store.key = key
+		sharedStores.stores[key] = store
+	}
+	store.refs++
+	ms.store = store
+	runtime.AddCleanup(ms, (*catalogStore).release, store)
+}
+
+// release Drops a reference to the shared store s, and s with the last.
+func (s *catalogStore) release() {
+	sharedStores.mutex.Lock()
+	defer sharedStores.mutex.Unlock()
+	if s.refs--; s.refs == 0 {
+		delete(sharedStores.stores, s.key)
+	}
+}
+
+// get Returns the file path read and the generation of s.
+func (s *catalogStore) get(path string) (fetched, bool, uint64) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	f, ok := s.files[path]
+	return f, ok, s.generation
+}
+
+// put Caches the file path read, unless evicted since generation.
+func (s *catalogStore) put(path string, f fetched, generation uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.generation == generation {
+		s.files[path] = f
+	}
+}
+
+// evict Removes the files matched, so they are read again.
+func (s *catalogStore) evict(match func(path string, f fetched) bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for path, f := range s.files {
+		if match(path, f) {
+			delete(s.files, path)
+		}
+	}
+	s.generation++
+}
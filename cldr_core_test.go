@@ -0,0 +1,20 @@
+//go:build ii18n_core
+
+package ii18n
+
+import "testing"
+
+// localeData whether the build has the locale data beyond English.
+const localeData = false
+
+func TestCoreData(t *testing.T) {
+	if actual, _ := FormatNumber(1234.5, "de", nil); actual != "1,234.5" {
+		t.Errorf("FormatNumber(de) = %q, want English fallback", actual)
+	}
+	if actual := PluralCategory("ru", 3); actual != "few" {
+		t.Errorf("PluralCategory(ru, 3) = %q", actual)
+	}
+	if _, ok := calendars["de"]; ok {
+		t.Error("German calendar data in core build")
+	}
+}
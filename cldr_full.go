@@ -0,0 +1,397 @@
+//go:build !ii18n_core
+
+package ii18n
+
+// init Adds the locale data beyond English, left out of builds tagged
+// ii18n_core which only need plural rules and English formatting.
+func init() {
+	for lang, data := range map[string]numberSymbols{
+		"de":    {",", ".", "-", 3, 3, 1, ""},
+		"de-AT": {",", "\u00a0", "-", 3, 3, 1, ""},
+		"de-CH": {".", "’", "-", 3, 3, 1, ""},
+		"fr":    {",", "\u202f", "-", 3, 3, 1, ""},
+		"fr-CH": {",", "\u202f", "-", 3, 3, 1, ""},
+		"es":    {",", ".", "-", 3, 3, 2, ""},
+		"es-MX": {".", ",", "-", 3, 3, 1, ""},
+		"it":    {",", ".", "-", 3, 3, 1, ""},
+		"pt":    {",", ".", "-", 3, 3, 1, ""},
+		"pt-PT": {",", "\u00a0", "-", 3, 3, 2, ""},
+		"nl":    {",", ".", "-", 3, 3, 1, ""},
+		"ru":    {",", "\u00a0", "-", 3, 3, 1, ""},
+		"uk":    {",", "\u00a0", "-", 3, 3, 1, ""},
+		"pl":    {",", "\u00a0", "-", 3, 3, 2, ""},
+		"cs":    {",", "\u00a0", "-", 3, 3, 1, ""},
+		"sv":    {",", "\u00a0", "\u2212", 3, 3, 1, ""},
+		"da":    {",", ".", "-", 3, 3, 1, ""},
+		"nb":    {",", "\u00a0", "\u2212", 3, 3, 1, ""},
+		"fi":    {",", "\u00a0", "\u2212", 3, 3, 1, ""},
+		"tr":    {",", ".", "-", 3, 3, 1, ""},
+		"ar":    {"٫", "٬", "\u061c-", 3, 3, 1, "٠١٢٣٤٥٦٧٨٩"},
+		"he":    {".", ",", "\u200e-", 3, 3, 1, ""},
+		"hi":    {".", ",", "-", 3, 2, 1, ""},
+		"ja":    {".", ",", "-", 3, 3, 1, ""},
+		"zh":    {".", ",", "-", 3, 3, 1, ""},
+		"ko":    {".", ",", "-", 3, 3, 1, ""},
+		"th":    {".", ",", "-", 3, 3, 1, ""},
+		"id":    {",", ".", "-", 3, 3, 1, ""},
+		"vi":    {",", ".", "-", 3, 3, 1, ""},
+	} {
+		numberData[lang] = data
+	}
+	for lang, data := range map[string]currencyPattern{
+		"de":    {"#\u00a0¤", "#\u00a0¤"},
+		"de-AT": {"¤\u00a0#", "¤\u00a0#"},
+		"de-CH": {"¤\u00a0#", "¤\u00a0#"},
+		"fr":    {"#\u00a0¤", "(#\u00a0¤)"},
+		"es":    {"#\u00a0¤", "#\u00a0¤"},
+		"es-MX": {"¤#", "¤#"},
+		"it":    {"#\u00a0¤", "#\u00a0¤"},
+		"pt":    {"¤\u00a0#", "¤\u00a0#"},
+		"pt-PT": {"#\u00a0¤", "(#\u00a0¤)"},
+		"nl":    {"¤\u00a0#", "(¤\u00a0#)"},
+		"ru":    {"#\u00a0¤", "#\u00a0¤"},
+		"uk":    {"#\u00a0¤", "#\u00a0¤"},
+		"pl":    {"#\u00a0¤", "(#\u00a0¤)"},
+		"cs":    {"#\u00a0¤", "#\u00a0¤"},
+		"sv":    {"#\u00a0¤", "#\u00a0¤"},
+		"da":    {"#\u00a0¤", "#\u00a0¤"},
+		"nb":    {"#\u00a0¤", "(#\u00a0¤)"},
+		"fi":    {"#\u00a0¤", "#\u00a0¤"},
+		"tr":    {"¤#", "(¤#)"},
+		"ar":    {"#\u00a0¤", "#\u00a0¤"},
+		"he":    {"#\u00a0¤", "#\u00a0¤"},
+		"hi":    {"¤#", "¤#"},
+		"ja":    {"¤#", "(¤#)"},
+		"zh":    {"¤#", "(¤#)"},
+		"ko":    {"¤#", "(¤#)"},
+		"th":    {"¤#", "(¤#)"},
+		"id":    {"¤#", "¤#"},
+		"vi":    {"#\u00a0¤", "#\u00a0¤"},
+	} {
+		currencyPatterns[lang] = data
+	}
+	for lang, data := range map[string]map[string]string{
+		"de":    {"USD": "$", "JPY": "¥"},
+		"fr":    {"USD": "$US", "CAD": "$CA", "JPY": "JPY"},
+		"fr-CA": {"CAD": "$", "USD": "$\u00a0US"},
+		"es":    {"USD": "US$", "JPY": "JPY"},
+		"es-MX": {"MXN": "$", "USD": "USD"},
+		"it":    {"USD": "USD", "JPY": "JPY"},
+		"pt":    {"USD": "US$", "JPY": "JP¥"},
+		"ru":    {"RUB": "₽", "USD": "$", "UAH": "₴"},
+		"uk":    {"UAH": "₴", "USD": "USD"},
+		"pl":    {"PLN": "zł", "USD": "USD"},
+		"cs":    {"CZK": "Kč", "USD": "US$"},
+		"sv":    {"SEK": "kr", "USD": "US$"},
+		"da":    {"DKK": "kr.", "USD": "US$"},
+		"nb":    {"NOK": "kr", "USD": "USD"},
+		"tr":    {"TRY": "₺", "USD": "$"},
+		"ja":    {"JPY": "￥", "USD": "$", "CNY": "元"},
+		"zh":    {"CNY": "¥", "USD": "US$", "JPY": "JP¥"},
+		"ko":    {"USD": "US$"},
+		"hi":    {"USD": "$"},
+		"th":    {"THB": "฿", "USD": "US$"},
+		"id":    {"IDR": "Rp", "USD": "US$"},
+	} {
+		currencySymbols[lang] = data
+	}
+	for lang, data := range map[string]map[string][2]string{
+		"de": {
+			"CHF": {"Schweizer Franken", "Schweizer Franken"},
+			"EUR": {"Euro", "Euro"},
+			"GBP": {"Britisches Pfund", "Britische Pfund"},
+			"JPY": {"Japanischer Yen", "Japanische Yen"},
+			"USD": {"US-Dollar", "US-Dollar"},
+		},
+		"fr": {
+			"CHF": {"franc suisse", "francs suisses"},
+			"EUR": {"euro", "euros"},
+			"GBP": {"livre sterling", "livres sterling"},
+			"JPY": {"yen japonais", "yens japonais"},
+			"USD": {"dollar des États-Unis", "dollars des États-Unis"},
+		},
+		"es": {
+			"EUR": {"euro", "euros"},
+			"GBP": {"libra esterlina", "libras esterlinas"},
+			"MXN": {"peso mexicano", "pesos mexicanos"},
+			"USD": {"dólar estadounidense", "dólares estadounidenses"},
+		},
+	} {
+		currencyNames[lang] = data
+	}
+	for lang, data := range map[string]*calendarData{
+		"de": {
+			months:               [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			monthsAbbr:           [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+			monthsStandaloneAbbr: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+			days:                 [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			daysAbbr:             [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+			dayPeriods:           [2]string{"AM", "PM"},
+			datePatterns:         [4]string{"EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"},
+			timePatterns:         [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+			dateTimePatterns:     [4]string{"{1} 'um' {0}", "{1} 'um' {0}", "{1}, {0}", "{1}, {0}"},
+			skeletons: map[string]string{
+				"y": "y", "yM": "M/y", "yMd": "d.M.y", "yMEd": "E, d.M.y", "yMMM": "MMM y",
+				"yMMMd": "d. MMM y", "yMMMEd": "E, d. MMM y", "yMMMM": "MMMM y", "yMMMMd": "d. MMMM y",
+				"Md": "d.M.", "MEd": "E, d.M.", "MMMd": "d. MMM", "MMMEd": "E, d. MMM", "MMMMd": "d. MMMM",
+				"d": "d", "Ed": "E, d.", "E": "ccc", "MMM": "LLL", "MMMM": "LLLL",
+				"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm\u202fa", "hms": "h:mm:ss\u202fa", "H": "HH 'Uhr'", "h": "h\u202fa",
+			},
+		},
+		"fr": {
+			months:           [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			monthsAbbr:       [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			days:             [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			daysAbbr:         [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			dayPeriods:       [2]string{"AM", "PM"},
+			datePatterns:     [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+			timePatterns:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+			dateTimePatterns: [4]string{"{1} 'à' {0}", "{1} 'à' {0}", "{1}, {0}", "{1} {0}"},
+			skeletons: map[string]string{
+				"y": "y", "yM": "MM/y", "yMd": "dd/MM/y", "yMEd": "E dd/MM/y", "yMMM": "MMM y",
+				"yMMMd": "d MMM y", "yMMMEd": "E d MMM y", "yMMMM": "MMMM y", "yMMMMd": "d MMMM y",
+				"Md": "dd/MM", "MEd": "E dd/MM", "MMMd": "d MMM", "MMMEd": "E d MMM", "MMMMd": "d MMMM",
+				"d": "d", "Ed": "E d", "E": "E", "MMM": "LLL", "MMMM": "LLLL",
+				"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm\u202fa", "hms": "h:mm:ss\u202fa", "H": "HH 'h'", "h": "h\u202fa",
+			},
+		},
+		"es": {
+			months:           [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			monthsAbbr:       [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			days:             [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			daysAbbr:         [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			dayPeriods:       [2]string{"a.\u00a0m.", "p.\u00a0m."},
+			datePatterns:     [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d MMM y", "d/M/yy"},
+			timePatterns:     [4]string{"H:mm:ss (zzzz)", "H:mm:ss z", "H:mm:ss", "H:mm"},
+			dateTimePatterns: [4]string{"{1}, {0}", "{1}, {0}", "{1}, {0}", "{1}, {0}"},
+			skeletons: map[string]string{
+				"y": "y", "yM": "M/y", "yMd": "d/M/y", "yMEd": "EEE, d/M/y", "yMMM": "MMM y",
+				"yMMMd": "d MMM y", "yMMMEd": "EEE, d MMM y", "yMMMM": "MMMM 'de' y", "yMMMMd": "d 'de' MMMM 'de' y",
+				"Md": "d/M", "MEd": "E, d/M", "MMMd": "d MMM", "MMMEd": "E, d MMM", "MMMMd": "d 'de' MMMM",
+				"d": "d", "Ed": "E d", "E": "ccc", "MMM": "LLL", "MMMM": "LLLL",
+				"Hm": "H:mm", "Hms": "H:mm:ss", "hm": "h:mm\u00a0a", "hms": "h:mm:ss\u00a0a", "H": "H", "h": "h\u00a0a",
+			},
+		},
+		"ru": {
+			months:               [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+			monthsAbbr:           [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+			monthsStandalone:     [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+			monthsStandaloneAbbr: [12]string{"янв.", "февр.", "март", "апр.", "май", "июнь", "июль", "авг.", "сент.", "окт.", "нояб.", "дек."},
+			days:                 [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+			daysAbbr:             [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+			dayPeriods:           [2]string{"AM", "PM"},
+			datePatterns:         [4]string{"EEEE, d MMMM y 'г'.", "d MMMM y 'г'.", "d MMM y 'г'.", "dd.MM.y"},
+			timePatterns:         [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+			dateTimePatterns:     [4]string{"{1}, {0}", "{1}, {0}", "{1}, {0}", "{1}, {0}"},
+			skeletons: map[string]string{
+				"y": "y", "yM": "MM.y", "yMd": "dd.MM.y", "yMEd": "ccc, dd.MM.y 'г'.", "yMMM": "LLL y 'г'.",
+				"yMMMd": "d MMM y 'г'.", "yMMMEd": "E, d MMM y 'г'.", "yMMMM": "LLLL y 'г'.", "yMMMMd": "d MMMM y 'г'.",
+				"Md": "dd.MM", "MEd": "E, dd.MM", "MMMd": "d MMM", "MMMEd": "ccc, d MMM", "MMMMd": "d MMMM",
+				"d": "d", "Ed": "ccc, d", "E": "ccc", "MMM": "LLL", "MMMM": "LLLL",
+				"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm\u00a0a", "hms": "h:mm:ss\u00a0a", "H": "HH", "h": "h\u00a0a",
+			},
+		},
+		"ja": {
+			months:           [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			monthsAbbr:       [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			days:             [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+			daysAbbr:         [7]string{"日", "月", "火", "水", "木", "金", "土"},
+			dayPeriods:       [2]string{"午前", "午後"},
+			datePatterns:     [4]string{"y年M月d日EEEE", "y年M月d日", "y/MM/dd", "y/MM/dd"},
+			timePatterns:     [4]string{"H時mm分ss秒 zzzz", "H:mm:ss z", "H:mm:ss", "H:mm"},
+			dateTimePatterns: [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+			skeletons: map[string]string{
+				"y": "y年", "yM": "y/M", "yMd": "y/M/d", "yMEd": "y/M/d(E)", "yMMM": "y年M月",
+				"yMMMd": "y年M月d日", "yMMMEd": "y年M月d日(E)", "yMMMM": "y年M月", "yMMMMd": "y年M月d日",
+				"Md": "M/d", "MEd": "M/d(E)", "MMMd": "M月d日", "MMMEd": "M月d日(E)", "MMMMd": "M月d日",
+				"d": "d日", "Ed": "d日(E)", "E": "ccc", "MMM": "M月", "MMMM": "M月",
+				"Hm": "H:mm", "Hms": "H:mm:ss", "hm": "aK:mm", "hms": "aK:mm:ss", "H": "H時", "h": "aK時",
+			},
+		},
+		"zh": {
+			months:           [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+			monthsAbbr:       [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			days:             [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+			daysAbbr:         [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+			dayPeriods:       [2]string{"上午", "下午"},
+			datePatterns:     [4]string{"y年M月d日EEEE", "y年M月d日", "y年M月d日", "y/M/d"},
+			timePatterns:     [4]string{"zzzz HH:mm:ss", "z HH:mm:ss", "HH:mm:ss", "HH:mm"},
+			dateTimePatterns: [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+			skeletons: map[string]string{
+				"y": "y年", "yM": "y/M", "yMd": "y/M/d", "yMEd": "y/M/dE", "yMMM": "y年M月",
+				"yMMMd": "y年M月d日", "yMMMEd": "y年M月d日E", "yMMMM": "y年M月", "yMMMMd": "y年M月d日",
+				"Md": "M/d", "MEd": "M/dE", "MMMd": "M月d日", "MMMEd": "M月d日E", "MMMMd": "M月d日",
+				"d": "d日", "Ed": "d日E", "E": "ccc", "MMM": "LLL", "MMMM": "LLLL",
+				"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "ah:mm", "hms": "ah:mm:ss", "H": "H时", "h": "ah时",
+			},
+		},
+	} {
+		calendars[lang] = data
+	}
+	for lang, data := range map[string]map[string]*relativePatterns{
+		"de": {
+			"year":         {plurals("in {0} Jahr", "in {0} Jahren"), plurals("vor {0} Jahr", "vor {0} Jahren"), map[int]string{-1: "letztes Jahr", 0: "dieses Jahr", 1: "nächstes Jahr"}},
+			"year-short":   {plurals("in {0} J.", "in {0} J."), plurals("vor {0} J.", "vor {0} J."), map[int]string{-1: "letztes Jahr", 0: "dieses Jahr", 1: "nächstes Jahr"}},
+			"month":        {plurals("in {0} Monat", "in {0} Monaten"), plurals("vor {0} Monat", "vor {0} Monaten"), map[int]string{-1: "letzten Monat", 0: "diesen Monat", 1: "nächsten Monat"}},
+			"month-short":  {plurals("in {0} Mon.", "in {0} Mon."), plurals("vor {0} Mon.", "vor {0} Mon."), map[int]string{-1: "letzten Monat", 0: "diesen Monat", 1: "nächsten Monat"}},
+			"week":         {plurals("in {0} Woche", "in {0} Wochen"), plurals("vor {0} Woche", "vor {0} Wochen"), map[int]string{-1: "letzte Woche", 0: "diese Woche", 1: "nächste Woche"}},
+			"week-short":   {plurals("in {0} Wo.", "in {0} Wo."), plurals("vor {0} Wo.", "vor {0} Wo."), map[int]string{-1: "letzte Woche", 0: "diese Woche", 1: "nächste Woche"}},
+			"day":          {plurals("in {0} Tag", "in {0} Tagen"), plurals("vor {0} Tag", "vor {0} Tagen"), map[int]string{-2: "vorgestern", -1: "gestern", 0: "heute", 1: "morgen", 2: "übermorgen"}},
+			"hour":         {plurals("in {0} Stunde", "in {0} Stunden"), plurals("vor {0} Stunde", "vor {0} Stunden"), map[int]string{0: "in dieser Stunde"}},
+			"hour-short":   {plurals("in {0} Std.", "in {0} Std."), plurals("vor {0} Std.", "vor {0} Std."), map[int]string{0: "in dieser Stunde"}},
+			"minute":       {plurals("in {0} Minute", "in {0} Minuten"), plurals("vor {0} Minute", "vor {0} Minuten"), map[int]string{0: "in dieser Minute"}},
+			"minute-short": {plurals("in {0} Min.", "in {0} Min."), plurals("vor {0} Min.", "vor {0} Min."), map[int]string{0: "in dieser Minute"}},
+			"second":       {plurals("in {0} Sekunde", "in {0} Sekunden"), plurals("vor {0} Sekunde", "vor {0} Sekunden"), map[int]string{0: "jetzt"}},
+			"second-short": {plurals("in {0} Sek.", "in {0} Sek."), plurals("vor {0} Sek.", "vor {0} Sek."), map[int]string{0: "jetzt"}},
+		},
+		"fr": {
+			"year":   {plurals("dans {0} an", "dans {0} ans"), plurals("il y a {0} an", "il y a {0} ans"), map[int]string{-1: "l’année dernière", 0: "cette année", 1: "l’année prochaine"}},
+			"month":  {plurals("dans {0} mois", "dans {0} mois"), plurals("il y a {0} mois", "il y a {0} mois"), map[int]string{-1: "le mois dernier", 0: "ce mois-ci", 1: "le mois prochain"}},
+			"week":   {plurals("dans {0} semaine", "dans {0} semaines"), plurals("il y a {0} semaine", "il y a {0} semaines"), map[int]string{-1: "la semaine dernière", 0: "cette semaine", 1: "la semaine prochaine"}},
+			"day":    {plurals("dans {0} jour", "dans {0} jours"), plurals("il y a {0} jour", "il y a {0} jours"), map[int]string{-2: "avant-hier", -1: "hier", 0: "aujourd’hui", 1: "demain", 2: "après-demain"}},
+			"hour":   {plurals("dans {0} heure", "dans {0} heures"), plurals("il y a {0} heure", "il y a {0} heures"), map[int]string{0: "cette heure-ci"}},
+			"minute": {plurals("dans {0} minute", "dans {0} minutes"), plurals("il y a {0} minute", "il y a {0} minutes"), map[int]string{0: "cette minute-ci"}},
+			"second": {plurals("dans {0} seconde", "dans {0} secondes"), plurals("il y a {0} seconde", "il y a {0} secondes"), map[int]string{0: "maintenant"}},
+		},
+		"es": {
+			"year":   {plurals("dentro de {0} año", "dentro de {0} años"), plurals("hace {0} año", "hace {0} años"), map[int]string{-1: "el año pasado", 0: "este año", 1: "el próximo año"}},
+			"month":  {plurals("dentro de {0} mes", "dentro de {0} meses"), plurals("hace {0} mes", "hace {0} meses"), map[int]string{-1: "el mes pasado", 0: "este mes", 1: "el próximo mes"}},
+			"week":   {plurals("dentro de {0} semana", "dentro de {0} semanas"), plurals("hace {0} semana", "hace {0} semanas"), map[int]string{-1: "la semana pasada", 0: "esta semana", 1: "la próxima semana"}},
+			"day":    {plurals("dentro de {0} día", "dentro de {0} días"), plurals("hace {0} día", "hace {0} días"), map[int]string{-2: "anteayer", -1: "ayer", 0: "hoy", 1: "mañana", 2: "pasado mañana"}},
+			"hour":   {plurals("dentro de {0} hora", "dentro de {0} horas"), plurals("hace {0} hora", "hace {0} horas"), map[int]string{0: "esta hora"}},
+			"minute": {plurals("dentro de {0} minuto", "dentro de {0} minutos"), plurals("hace {0} minuto", "hace {0} minutos"), map[int]string{0: "este minuto"}},
+			"second": {plurals("dentro de {0} segundo", "dentro de {0} segundos"), plurals("hace {0} segundo", "hace {0} segundos"), map[int]string{0: "ahora"}},
+		},
+		"ru": {
+			"year":   {slavicPlurals("через {0} год", "через {0} года", "через {0} лет", "через {0} года"), slavicPlurals("{0} год назад", "{0} года назад", "{0} лет назад", "{0} года назад"), map[int]string{-1: "в прошлом году", 0: "в этом году", 1: "в следующем году"}},
+			"month":  {slavicPlurals("через {0} месяц", "через {0} месяца", "через {0} месяцев", "через {0} месяца"), slavicPlurals("{0} месяц назад", "{0} месяца назад", "{0} месяцев назад", "{0} месяца назад"), map[int]string{-1: "в прошлом месяце", 0: "в этом месяце", 1: "в следующем месяце"}},
+			"week":   {slavicPlurals("через {0} неделю", "через {0} недели", "через {0} недель", "через {0} недели"), slavicPlurals("{0} неделю назад", "{0} недели назад", "{0} недель назад", "{0} недели назад"), map[int]string{-1: "на прошлой неделе", 0: "на этой неделе", 1: "на следующей неделе"}},
+			"day":    {slavicPlurals("через {0} день", "через {0} дня", "через {0} дней", "через {0} дня"), slavicPlurals("{0} день назад", "{0} дня назад", "{0} дней назад", "{0} дня назад"), map[int]string{-2: "позавчера", -1: "вчера", 0: "сегодня", 1: "завтра", 2: "послезавтра"}},
+			"hour":   {slavicPlurals("через {0} час", "через {0} часа", "через {0} часов", "через {0} часа"), slavicPlurals("{0} час назад", "{0} часа назад", "{0} часов назад", "{0} часа назад"), map[int]string{0: "в этот час"}},
+			"minute": {slavicPlurals("через {0} минуту", "через {0} минуты", "через {0} минут", "через {0} минуты"), slavicPlurals("{0} минуту назад", "{0} минуты назад", "{0} минут назад", "{0} минуты назад"), map[int]string{0: "в эту минуту"}},
+			"second": {slavicPlurals("через {0} секунду", "через {0} секунды", "через {0} секунд", "через {0} секунды"), slavicPlurals("{0} секунду назад", "{0} секунды назад", "{0} секунд назад", "{0} секунды назад"), map[int]string{0: "сейчас"}},
+		},
+		"ja": {
+			"year":   {plurals("{0} 年後", "{0} 年後"), plurals("{0} 年前", "{0} 年前"), map[int]string{-1: "昨年", 0: "今年", 1: "来年"}},
+			"month":  {plurals("{0} か月後", "{0} か月後"), plurals("{0} か月前", "{0} か月前"), map[int]string{-1: "先月", 0: "今月", 1: "来月"}},
+			"week":   {plurals("{0} 週間後", "{0} 週間後"), plurals("{0} 週間前", "{0} 週間前"), map[int]string{-1: "先週", 0: "今週", 1: "来週"}},
+			"day":    {plurals("{0} 日後", "{0} 日後"), plurals("{0} 日前", "{0} 日前"), map[int]string{-2: "一昨日", -1: "昨日", 0: "今日", 1: "明日", 2: "明後日"}},
+			"hour":   {plurals("{0} 時間後", "{0} 時間後"), plurals("{0} 時間前", "{0} 時間前"), map[int]string{0: "1 時間以内"}},
+			"minute": {plurals("{0} 分後", "{0} 分後"), plurals("{0} 分前", "{0} 分前"), map[int]string{0: "1 分以内"}},
+			"second": {plurals("{0} 秒後", "{0} 秒後"), plurals("{0} 秒前", "{0} 秒前"), map[int]string{0: "今"}},
+		},
+		"zh": {
+			"year":   {plurals("{0}年后", "{0}年后"), plurals("{0}年前", "{0}年前"), map[int]string{-1: "去年", 0: "今年", 1: "明年"}},
+			"month":  {plurals("{0}个月后", "{0}个月后"), plurals("{0}个月前", "{0}个月前"), map[int]string{-1: "上个月", 0: "本月", 1: "下个月"}},
+			"week":   {plurals("{0}周后", "{0}周后"), plurals("{0}周前", "{0}周前"), map[int]string{-1: "上周", 0: "本周", 1: "下周"}},
+			"day":    {plurals("{0}天后", "{0}天后"), plurals("{0}天前", "{0}天前"), map[int]string{-2: "前天", -1: "昨天", 0: "今天", 1: "明天", 2: "后天"}},
+			"hour":   {plurals("{0}小时后", "{0}小时后"), plurals("{0}小时前", "{0}小时前"), map[int]string{0: "这一时间"}},
+			"minute": {plurals("{0}分钟后", "{0}分钟后"), plurals("{0}分钟前", "{0}分钟前"), map[int]string{0: "此刻"}},
+			"second": {plurals("{0}秒钟后", "{0}秒钟后"), plurals("{0}秒钟前", "{0}秒钟前"), map[int]string{0: "现在"}},
+		},
+	} {
+		relativeTimes[lang] = data
+	}
+	for lang, data := range map[string]map[ListStyle]listPattern{
+		"de": {
+			ListAnd:        {"{0} und {1}", "{0}, {1}", "{0}, {1}", "{0} und {1}"},
+			ListOr:         {"{0} oder {1}", "{0}, {1}", "{0}, {1}", "{0} oder {1}"},
+			ListUnit:       {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0} und {1}"},
+			ListUnitNarrow: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+		},
+		"fr": {
+			ListAnd:        {"{0} et {1}", "{0}, {1}", "{0}, {1}", "{0} et {1}"},
+			ListOr:         {"{0} ou {1}", "{0}, {1}", "{0}, {1}", "{0} ou {1}"},
+			ListUnit:       {"{0} et {1}", "{0}, {1}", "{0}, {1}", "{0} et {1}"},
+			ListUnitShort:  {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0}, {1}"},
+			ListUnitNarrow: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+		},
+		"es": {
+			ListAnd:        {"{0} y {1}", "{0}, {1}", "{0}, {1}", "{0} y {1}"},
+			ListOr:         {"{0} o {1}", "{0}, {1}", "{0}, {1}", "{0} o {1}"},
+			ListUnit:       {"{0} y {1}", "{0}, {1}", "{0}, {1}", "{0} y {1}"},
+			ListUnitNarrow: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+		},
+		"it": {
+			ListAnd: {"{0} e {1}", "{0}, {1}", "{0}, {1}", "{0} e {1}"},
+			ListOr:  {"{0} o {1}", "{0}, {1}", "{0}, {1}", "{0} o {1}"},
+		},
+		"pt": {
+			ListAnd: {"{0} e {1}", "{0}, {1}", "{0}, {1}", "{0} e {1}"},
+			ListOr:  {"{0} ou {1}", "{0}, {1}", "{0}, {1}", "{0} ou {1}"},
+		},
+		"nl": {
+			ListAnd: {"{0} en {1}", "{0}, {1}", "{0}, {1}", "{0} en {1}"},
+			ListOr:  {"{0} of {1}", "{0}, {1}", "{0}, {1}", "{0} of {1}"},
+		},
+		"ru": {
+			ListAnd: {"{0} и {1}", "{0}, {1}", "{0}, {1}", "{0} и {1}"},
+			ListOr:  {"{0} или {1}", "{0}, {1}", "{0}, {1}", "{0} или {1}"},
+		},
+		"ja": {
+			ListAnd: {"{0}、{1}", "{0}、{1}", "{0}、{1}", "{0}、{1}"},
+			ListOr:  {"{0}または{1}", "{0}、{1}", "{0}、{1}", "{0}、または{1}"},
+		},
+		"zh": {
+			ListAnd: {"{0}和{1}", "{0}、{1}", "{0}、{1}", "{0}和{1}"},
+			ListOr:  {"{0}或{1}", "{0}、{1}", "{0}、{1}", "{0}或{1}"},
+		},
+		"ko": {
+			ListAnd: {"{0} 및 {1}", "{0}, {1}", "{0}, {1}", "{0} 및 {1}"},
+			ListOr:  {"{0} 또는 {1}", "{0}, {1}", "{0}, {1}", "{0} 또는 {1}"},
+		},
+	} {
+		listPatterns[lang] = data
+	}
+	for lang, data := range map[string]map[string]map[byte]string{
+		"de": {
+			"yMMMd":  {'d': "d.–d. MMM y", 'M': "d. MMM – d. MMM y", 'y': "d. MMM y – d. MMM y"},
+			"yMMMMd": {'d': "d.–d. MMMM y", 'M': "d. MMMM – d. MMMM y", 'y': "d. MMMM y – d. MMMM y"},
+			"yMd":    {'d': "dd.–dd.MM.y", 'M': "dd.MM. – dd.MM.y", 'y': "dd.MM.y – dd.MM.y"},
+			"yMMM":   {'M': "MMM–MMM y", 'y': "MMM y – MMM y"},
+			"MMMd":   {'d': "d.–d. MMM", 'M': "d. MMM – d. MMM"},
+			"Hm":     {'h': "HH:mm–HH:mm 'Uhr'", 'm': "HH:mm–HH:mm 'Uhr'"},
+			"hm":     {'a': "h:mm\u202fa – h:mm\u202fa", 'h': "h:mm–h:mm\u202fa", 'm': "h:mm–h:mm\u202fa"},
+		},
+		"fr": {
+			"yMMMd":  {'d': "d–d MMM y", 'M': "d MMM – d MMM y", 'y': "d MMM y – d MMM y"},
+			"yMMMMd": {'d': "d–d MMMM y", 'M': "d MMMM – d MMMM y", 'y': "d MMMM y – d MMMM y"},
+			"yMd":    {'d': "dd/MM/y – dd/MM/y", 'M': "dd/MM/y – dd/MM/y", 'y': "dd/MM/y – dd/MM/y"},
+			"yMMM":   {'M': "MMM–MMM y", 'y': "MMM y – MMM y"},
+			"MMMd":   {'d': "d–d MMM", 'M': "d MMM – d MMM"},
+			"Hm":     {'h': "HH:mm – HH:mm", 'm': "HH:mm – HH:mm"},
+		},
+		"es": {
+			"yMMMd":  {'d': "d–d MMM y", 'M': "d MMM – d MMM y", 'y': "d MMM y – d MMM y"},
+			"yMMMMd": {'d': "d–d 'de' MMMM 'de' y", 'M': "d 'de' MMMM – d 'de' MMMM 'de' y", 'y': "d 'de' MMMM 'de' y – d 'de' MMMM 'de' y"},
+			"yMd":    {'d': "d/M/y – d/M/y", 'M': "d/M/y – d/M/y", 'y': "d/M/y – d/M/y"},
+			"yMMM":   {'M': "MMM–MMM y", 'y': "MMM y – MMM y"},
+			"MMMd":   {'d': "d–d MMM", 'M': "d MMM – d MMM"},
+			"Hm":     {'h': "H:mm–H:mm", 'm': "H:mm–H:mm"},
+		},
+		"ja": {
+			"yMMMd":  {'d': "y年M月d日～d日", 'M': "y年M月d日～M月d日", 'y': "y年M月d日～y年M月d日"},
+			"yMMMMd": {'d': "y年M月d日～d日", 'M': "y年M月d日～M月d日", 'y': "y年M月d日～y年M月d日"},
+			"yMd":    {'d': "y/MM/dd～y/MM/dd", 'M': "y/MM/dd～y/MM/dd", 'y': "y/MM/dd～y/MM/dd"},
+			"yMMM":   {'M': "y年M月～M月", 'y': "y年M月～y年M月"},
+			"MMMd":   {'d': "M月d日～d日", 'M': "M月d日～M月d日"},
+			"Hm":     {'h': "H:mm～H:mm", 'm': "H:mm～H:mm"},
+		},
+		"zh": {
+			"yMMMd":  {'d': "y年M月d日至d日", 'M': "y年M月d日至M月d日", 'y': "y年M月d日至y年M月d日"},
+			"yMMMMd": {'d': "y年M月d日至d日", 'M': "y年M月d日至M月d日", 'y': "y年M月d日至y年M月d日"},
+			"yMd":    {'d': "y/M/d – y/M/d", 'M': "y/M/d – y/M/d", 'y': "y/M/d – y/M/d"},
+			"yMMM":   {'M': "y年M月至M月", 'y': "y年M月至y年M月"},
+			"MMMd":   {'d': "M月d日至d日", 'M': "M月d日至M月d日"},
+			"Hm":     {'h': "HH:mm–HH:mm", 'm': "HH:mm–HH:mm"},
+		},
+	} {
+		intervalFormats[lang] = data
+	}
+	for lang, data := range map[string]string{
+		"ja": "{0}～{1}",
+		"zh": "{0}至{1}",
+	} {
+		intervalFallbacks[lang] = data
+	}
+}
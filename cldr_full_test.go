@@ -0,0 +1,166 @@
+//go:build !ii18n_core
+
+package ii18n
+
+import (
+	"testing"
+	"time"
+)
+
+// localeData whether the build has the locale data beyond English.
+const localeData = true
+
+func TestFormatNumberLocales(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		lang     string
+		expected string
+	}{
+		{1234567.891, "de-DE", "1.234.567,891"},
+		{1234, "es", "1234"},
+		{12345, "es", "12.345"},
+		{12345678, "hi", "1,23,45,678"},
+		{-1234.5, "fr", "-1\u202f234,5"},
+		{1234, "ar", "١٬٢٣٤"},
+	}
+	for _, test := range tests {
+		actual, err := FormatNumber(test.value, test.lang, nil)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatNumber(%v, %q) = %q, %v, want %q", test.value, test.lang, actual, err, test.expected)
+		}
+	}
+}
+
+func TestFormatCurrencyLocales(t *testing.T) {
+	tests := []struct {
+		amount   interface{}
+		code     string
+		lang     string
+		opts     *CurrencyOptions
+		expected string
+	}{
+		{1234.5, "EUR", "de-DE", nil, "1.234,50\u00a0€"},
+		{1234.5, "JPY", "ja", nil, "￥1,234"},
+		{5, "EUR", "de", &CurrencyOptions{Style: CurrencyCode}, "5,00\u00a0EUR"},
+		{2, "EUR", "fr", &CurrencyOptions{Style: CurrencyName}, "2,00 euros"},
+	}
+	for _, test := range tests {
+		actual, err := FormatCurrency(test.amount, test.code, test.lang, test.opts)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatCurrency(%v, %s, %q) = %q, %v, want %q", test.amount, test.code, test.lang, actual, err, test.expected)
+		}
+	}
+	f := NewFormatter()
+	actual, _ := f.format("Total: {n, number, ::currency/EUR}", map[string]string{"n": "12"}, "fr")
+	if actual != "Total: 12,00\u00a0€" {
+		t.Errorf("format currency = %q", actual)
+	}
+}
+
+func TestFormatDateTimeLocales(t *testing.T) {
+	tm := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+	tests := []struct {
+		style    DateStyle
+		lang     string
+		format   func(time.Time, DateStyle, string) (string, error)
+		expected string
+	}{
+		{DateLong, "de-DE", FormatDate, "5. März 2024"},
+		{DateShort, "de-DE", FormatDateTime, "05.03.24, 14:07"},
+		{DateLong, "fr-FR", FormatDateTime, "5 mars 2024 à 14:07:09 UTC"},
+		{DateLong, "es", FormatDate, "5 de marzo de 2024"},
+		{DateLong, "ru", FormatDate, "5 марта 2024 г."},
+		{DateFull, "ja", FormatDate, "2024年3月5日火曜日"},
+		{"yMMMM", "ru", FormatDate, "март 2024 г."},
+		{"MMMEd", "de", FormatDate, "Di., 5. März"},
+		{"hm", "ja", FormatTime, "午後2:07"},
+		{DateLong, "ja-JP-u-ca-japanese", FormatDate, "令和6年3月5日"},
+		{DateLong, WithCalendar("ja-JP", CalendarJapanese), FormatDate, "令和6年3月5日"},
+	}
+	for _, test := range tests {
+		actual, err := test.format(tm, test.style, test.lang)
+		if err != nil || actual != test.expected {
+			t.Errorf("format(%s, %q) = %q, %v, want %q", test.style, test.lang, actual, err, test.expected)
+		}
+	}
+	heisei := time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC)
+	if actual, _ := FormatDate(heisei, DateLong, "ja-JP-u-ca-japanese"); actual != "平成31年4月30日" {
+		t.Errorf("FormatDate(%v) = %q", heisei, actual)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	summer := time.Date(2024, time.July, 1, 12, 0, 0, 0, berlin)
+	if actual, _ := FormatTime(summer, DateFull, "de"); actual != "12:00:00 Mitteleuropäische Sommerzeit" {
+		t.Errorf("FormatTime(full) = %q", actual)
+	}
+}
+
+func TestFormatRelativeTimeLocales(t *testing.T) {
+	ref := time.Date(2024, time.March, 5, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t        time.Time
+		lang     string
+		expected string
+	}{
+		{ref.AddDate(0, 0, 3), "de", "in 3 Tagen"},
+		{ref.AddDate(0, 0, -2), "fr", "avant-hier"},
+		{ref.AddDate(0, 5, 0), "es", "dentro de 5 meses"},
+		{ref.AddDate(-1, 0, 0), "ja", "昨年"},
+		{ref.AddDate(-3, 0, 0), "zh", "3年前"},
+	}
+	for _, test := range tests {
+		actual := FormatRelativeTime(test.t, ref, test.lang, RelativeLong)
+		if actual != test.expected {
+			t.Errorf("FormatRelativeTime(%v, %q) = %q, want %q", test.t, test.lang, actual, test.expected)
+		}
+	}
+}
+
+func TestFormatListLocales(t *testing.T) {
+	tests := []struct {
+		items    []string
+		lang     string
+		style    ListStyle
+		expected string
+	}{
+		{[]string{"A", "B", "C"}, "de", ListAndShort, "A, B und C"},
+		{[]string{"España", "Italia"}, "es", ListAnd, "España e Italia"},
+		{[]string{"siete", "ocho"}, "es", ListOr, "siete u ocho"},
+		{[]string{"苹果", "香蕉", "橙子"}, "zh", ListAnd, "苹果、香蕉和橙子"},
+	}
+	for _, test := range tests {
+		actual := FormatList(test.items, test.lang, test.style)
+		if actual != test.expected {
+			t.Errorf("FormatList(%v, %q) = %q, want %q", test.items, test.lang, actual, test.expected)
+		}
+	}
+}
+
+func TestFormatDateIntervalLocales(t *testing.T) {
+	from := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.January, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		lang     string
+		expected string
+	}{
+		{"de", "3.–5. Jan. 2025"},
+		{"ja", "2025年1月3日～5日"},
+		{"ru", "3 янв. 2025 г.\u2009–\u20095 янв. 2025 г."},
+	}
+	for _, test := range tests {
+		actual, err := FormatDateInterval(from, to, "yMMMd", test.lang)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatDateInterval(yMMMd, %q) = %q, %v, want %q", test.lang, actual, err, test.expected)
+		}
+	}
+}
+
+func TestPluralFormatLocales(t *testing.T) {
+	f := NewFormatter()
+	pattern := "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}"
+	if actual, _ := f.format(pattern, map[string]string{"n": "1.5"}, "ru"); actual != "1,5 файла" {
+		t.Errorf("format ru decimal plural = %q", actual)
+	}
+}
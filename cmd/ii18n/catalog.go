@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+// catalogPath Returns the JSON catalog file of category in lang: the part of
+// the category after the first dot names the file, its dots being
+// directories, as with ii18n.T.
+func catalogPath(basePath string, category string, lang string) string {
+	name := category
+	if pos := strings.Index(category, "."); pos != -1 {
+		name = category[pos+1:]
+	}
+	return filepath.Join(basePath, lang, filepath.FromSlash(strings.ReplaceAll(name, ".", "/"))+".json")
+}
+
+// catalogCategory Returns the category of the catalog file rel, relative
+// to the directory of its language, under prefix: its directories are dots,
+// the reverse of catalogPath.
+func catalogCategory(prefix string, rel string) string {
+	name := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+	return prefix + "." + strings.ReplaceAll(name, "/", ".")
+}
+
+// readText Reads the catalog file filename as UTF-8 text, see
+// ii18n.DecodeText.
+func readText(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ii18n.DecodeText(data)
+}
+
+// readCatalog Reads a JSON catalog, an empty one if the file does not exist.
+func readCatalog(filename string) (ii18n.TMsgs, error) {
+	msgs := make(ii18n.TMsgs)
+	data, err := readText(filename)
+	if os.IsNotExist(err) {
+		return msgs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// writeCatalog Writes msgs as a JSON catalog with sorted keys.
+func writeCatalog(filename string, msgs ii18n.TMsgs) error {
+	codec, _ := ii18n.GetCodec("json")
+	data, err := codec.Encode(msgs, "")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// orderedCatalog a catalog keeping the order of its keys.
+type orderedCatalog struct {
+	keys []string
+	msgs ii18n.TMsgs
+}
+
+// set Sets key, appending it when new.
+func (c *orderedCatalog) set(key string, val string) {
+	if _, ok := c.msgs[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.msgs[key] = val
+}
+
+// remove Removes key.
+func (c *orderedCatalog) remove(key string) {
+	if _, ok := c.msgs[key]; !ok {
+		return
+	}
+	delete(c.msgs, key)
+	for i, k := range c.keys {
+		if k == key {
+			c.keys = append(c.keys[:i], c.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// readOrderedCatalog Reads a JSON catalog keeping its key order, an empty
+// one if the file does not exist.
+func readOrderedCatalog(filename string) (*orderedCatalog, error) {
+	data, err := readText(filename)
+	if os.IsNotExist(err) {
+		return &orderedCatalog{msgs: make(ii18n.TMsgs)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c, _, err := decodeCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return c, nil
+}
+
+// writeOrderedCatalog Writes c as a JSON catalog in the order of its keys.
+func writeOrderedCatalog(filename string, c *orderedCatalog) error {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range c.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n\t")
+		buf.Write(jsonString(key))
+		buf.WriteString(": ")
+		buf.Write(jsonString(c.msgs[key]))
+	}
+	buf.WriteString("\n}\n")
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// jsonString Encodes s as a JSON string without escaping HTML.
+func jsonString(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// decodeCatalog Decodes a JSON catalog keeping its key order, also returning
+// the keys that occur more than once, which encoding/json would silently
+// merge.
+func decodeCatalog(data []byte) (*orderedCatalog, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, errors.New("catalog is not a JSON object")
+	}
+	c := &orderedCatalog{msgs: make(ii18n.TMsgs)}
+	var dups []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		val, ok := tok.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("value of %q is not a string", key)
+		}
+		if _, ok := c.msgs[key]; ok {
+			dups = append(dups, key)
+		}
+		c.set(key, val)
+	}
+	return c, dups, nil
+}
+
+// catalogFiles Returns the JSON catalogs under dir relative to it, sorted.
+func catalogFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// languages Returns the language directories under base, sorted.
+func languages(base string) ([]string, error) {
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil, err
+	}
+	var langs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			langs = append(langs, entry.Name())
+		}
+	}
+	return langs, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := fs.String("from", "", "input format, default the input file suffix")
+	to := fs.String("to", "", "output format, default the output file suffix")
+	lang := fs.String("lang", "", "language of a single input file")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n convert [flags] <input> <output>\n\nConverts a catalog, or every catalog of a base path whose first directory\nlevel is the language. Formats: "+strings.Join(ii18n.Formats(), ", "))
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	input, output := fs.Arg(0), fs.Arg(1)
+
+	info, err := os.Stat(input)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return convertFile(input, output, formatOf(*from, input), formatOf(*to, output), *lang)
+	}
+	if *from == "" || *to == "" {
+		return errors.New("-from and -to are required to convert a directory")
+	}
+	return filepath.WalkDir(input, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || formatOf("", path) != *from {
+			return err
+		}
+		rel, err := filepath.Rel(input, path)
+		if err != nil {
+			return err
+		}
+		out := filepath.Join(output, strings.TrimSuffix(rel, filepath.Ext(rel))+"."+*to)
+		return convertFile(path, out, *from, *to, strings.SplitN(filepath.ToSlash(rel), "/", 2)[0])
+	})
+}
+
+// formatOf Returns format, or the suffix of filename when format is empty.
+func formatOf(format string, filename string) string {
+	if format != "" {
+		return format
+	}
+	return strings.TrimPrefix(filepath.Ext(filename), ".")
+}
+
+// convertFile Converts the catalog input in format from to output in format to.
+func convertFile(input string, output string, from string, to string, lang string) error {
+	decoder, ok := ii18n.GetCodec(from)
+	if !ok {
+		return fmt.Errorf("unknown format %q", from)
+	}
+	encoder, ok := ii18n.GetCodec(to)
+	if !ok {
+		return fmt.Errorf("unknown format %q", to)
+	}
+	data, err := readText(input)
+	if err != nil {
+		return err
+	}
+	if data, err = convertData(data, decoder, encoder, lang); err != nil {
+		return fmt.Errorf("%s: %v", input, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(output, data, 0644)
+}
+
+// convertData Converts data, keeping comments and metadata when both
+// formats support them.
+func convertData(data []byte, decoder ii18n.Codec, encoder ii18n.Codec, lang string) ([]byte, error) {
+	dc, ok1 := decoder.(ii18n.CatalogCodec)
+	ec, ok2 := encoder.(ii18n.CatalogCodec)
+	if ok1 && ok2 {
+		c, err := dc.DecodeCatalog(data)
+		if err != nil {
+			return nil, err
+		}
+		return ec.EncodeCatalog(c, lang)
+	}
+	msgs, err := decoder.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return encoder.Encode(msgs, lang)
+}
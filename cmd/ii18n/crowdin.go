@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/syyongx/ii18n/integrations/crowdin"
+)
+
+func runCrowdin(args []string) error {
+	fs := flag.NewFlagSet("crowdin", flag.ExitOnError)
+	base := fs.String("base", "", "base path of the catalogs, laid out as {base}/{lang}/{file}")
+	source := fs.String("source", "en-US", "source language, pushed by push")
+	project := fs.Int("project", 0, "Crowdin project ID")
+	endpoint := fs.String("endpoint", crowdin.DefaultEndpoint, "Crowdin API endpoint")
+	langs := fs.String("langs", "", "comma-separated languages pulled by pull")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n crowdin -base dir -project id [flags] push|pull")
+		fmt.Fprintln(os.Stderr, "The API token is read from CROWDIN_TOKEN.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	token := os.Getenv("CROWDIN_TOKEN")
+	if *base == "" || *project == 0 || fs.NArg() != 1 || token == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	c := crowdin.New(token, *project)
+	c.Endpoint = *endpoint
+	ctx := context.Background()
+	switch fs.Arg(0) {
+	case "push":
+		uploaded, err := c.Push(ctx, *base, *source)
+		for _, p := range uploaded {
+			fmt.Println(p)
+		}
+		return err
+	case "pull":
+		if *langs == "" {
+			return fmt.Errorf("pull needs -langs")
+		}
+		return c.Pull(ctx, *base, strings.Split(*langs, ","))
+	}
+	fs.Usage()
+	os.Exit(2)
+	return nil
+}
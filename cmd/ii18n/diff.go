@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+// change a message added, removed or changed between two catalogs.
+type change struct {
+	Kind string `json:"kind"`
+	Lang string `json:"lang,omitempty"`
+	File string `json:"file"`
+	Key  string `json:"key"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	gitBase := fs.String("git", "", "compare the catalogs under this path between two git refs")
+	format := fs.String("format", "text", "output format, text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n diff [flags] <old> <new>\n\n<old> and <new> are two catalog files, two base paths, or two git refs\nwith -git.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var oldTree, newTree map[string]ii18n.TMsgs
+	var err error
+	if *gitBase != "" {
+		if oldTree, err = gitCatalogs(fs.Arg(0), *gitBase); err == nil {
+			newTree, err = gitCatalogs(fs.Arg(1), *gitBase)
+		}
+	} else if info, statErr := os.Stat(fs.Arg(0)); statErr == nil && !info.IsDir() {
+		oldTree, newTree = make(map[string]ii18n.TMsgs), make(map[string]ii18n.TMsgs)
+		name := filepath.Base(fs.Arg(1))
+		if oldTree[name], err = decodeFile(fs.Arg(0)); err == nil {
+			newTree[name], err = decodeFile(fs.Arg(1))
+		}
+	} else {
+		if oldTree, err = dirCatalogs(fs.Arg(0)); err == nil {
+			newTree, err = dirCatalogs(fs.Arg(1))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	changes := diffTrees(oldTree, newTree)
+	switch *format {
+	case "json":
+		if changes == nil {
+			changes = []change{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	case "text":
+		file := ""
+		for _, c := range changes {
+			if c.File != file {
+				file = c.File
+				fmt.Println(file)
+			}
+			switch c.Kind {
+			case "added":
+				fmt.Printf("  + %q: %q\n", c.Key, c.New)
+			case "removed":
+				fmt.Printf("  - %q: %q\n", c.Key, c.Old)
+			default:
+				fmt.Printf("  ~ %q: %q -> %q\n", c.Key, c.Old, c.New)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", *format)
+}
+
+// diffTrees Returns the changes between the catalogs of two trees, keyed by
+// slash separated path, sorted by file and key.
+func diffTrees(oldTree map[string]ii18n.TMsgs, newTree map[string]ii18n.TMsgs) []change {
+	files := make(map[string]bool)
+	for file := range oldTree {
+		files[file] = true
+	}
+	for file := range newTree {
+		files[file] = true
+	}
+	var changes []change
+	for _, file := range sortedKeys(files) {
+		lang := ""
+		if pos := strings.Index(file, "/"); pos != -1 {
+			lang = file[:pos]
+		}
+		oldMsgs, newMsgs := oldTree[file], newTree[file]
+		keys := make(map[string]bool)
+		for key := range oldMsgs {
+			keys[key] = true
+		}
+		for key := range newMsgs {
+			keys[key] = true
+		}
+		for _, key := range sortedKeys(keys) {
+			oldVal, inOld := oldMsgs[key]
+			newVal, inNew := newMsgs[key]
+			c := change{Lang: lang, File: file, Key: key, Old: oldVal, New: newVal}
+			switch {
+			case !inOld:
+				c.Kind = "added"
+			case !inNew:
+				c.Kind = "removed"
+			case oldVal != newVal:
+				c.Kind = "changed"
+			default:
+				continue
+			}
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// decodeFile Decodes a catalog in the format of its suffix.
+func decodeFile(filename string) (ii18n.TMsgs, error) {
+	data, err := readText(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeData(filename, data)
+}
+
+// decodeData Decodes data, a catalog in the format of the suffix of filename.
+func decodeData(filename string, data []byte) (ii18n.TMsgs, error) {
+	codec, ok := ii18n.GetCodec(formatOf("", filename))
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown catalog format", filename)
+	}
+	msgs, err := codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return msgs, nil
+}
+
+// dirCatalogs Returns the catalogs of known formats under base by slash
+// separated relative path.
+func dirCatalogs(base string) (map[string]ii18n.TMsgs, error) {
+	tree := make(map[string]ii18n.TMsgs)
+	err := filepath.WalkDir(base, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, ok := ii18n.GetCodec(formatOf("", p)); !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		tree[filepath.ToSlash(rel)], err = decodeFile(p)
+		return err
+	})
+	return tree, err
+}
+
+// gitCatalogs Returns the catalogs of known formats under base at ref by
+// slash separated path relative to base.
+func gitCatalogs(ref string, base string) (map[string]ii18n.TMsgs, error) {
+	base = path.Clean(filepath.ToSlash(base))
+	out, err := exec.Command("git", "ls-tree", "-r", "--name-only", ref, "--", base).Output()
+	if err != nil {
+		return nil, gitError(err)
+	}
+	tree := make(map[string]ii18n.TMsgs)
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if _, ok := ii18n.GetCodec(formatOf("", file)); file == "" || !ok {
+			continue
+		}
+		data, err := exec.Command("git", "show", ref+":"+file).Output()
+		if err != nil {
+			return nil, gitError(err)
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(file, base), "/")
+		if tree[rel], err = decodeData(file, data); err != nil {
+			return nil, err
+		}
+	}
+	return tree, nil
+}
+
+// gitError Adds the standard error output of a failed git command to err.
+func gitError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return errors.New("git: " + strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runEncrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	keys := fs.String("keys", "", "directory of the AES keys, one file named by key ID each")
+	keyID := fs.String("key-id", "", "ID of the key to encrypt with")
+	decrypt := fs.Bool("decrypt", false, "write the catalogs in the clear instead")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n encrypt -keys dir (-key-id id | -decrypt) file...")
+		fmt.Fprintln(os.Stderr, "Encrypted catalogs are decrypted first, so -key-id rotates their key.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *keys == "" || (*keyID == "") == !*decrypt || fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	provider := func(id string) ([]byte, error) {
+		if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+			return nil, errors.New("invalid key ID " + id)
+		}
+		return os.ReadFile(filepath.Join(*keys, id))
+	}
+	var key []byte
+	if !*decrypt {
+		var err error
+		if key, err = provider(*keyID); err != nil {
+			return err
+		}
+	}
+	for _, filename := range fs.Args() {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if _, ok := ii18n.IsEncryptedCatalog(data); ok {
+			if data, err = ii18n.DecryptCatalog(data, provider); err != nil {
+				return fmt.Errorf("%s: %w", filename, err)
+			}
+		}
+		if !*decrypt {
+			if data, err = ii18n.EncryptCatalog(data, *keyID, key); err != nil {
+				return fmt.Errorf("%s: %w", filename, err)
+			}
+		}
+		if err := os.WriteFile(filename, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+// message a translatable string found in Go source.
+type message struct {
+	category string
+	key      string
+	refs     []string
+}
+
+// defaultFuncs the translation functions of ii18n, whose category is the
+// first argument but for TContext.
+const defaultFuncs = "T,TE,MustT,Tf,TContext:1"
+
+func runExtract(args []string) error {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	base := fs.String("base", "", "catalog base path; catalogs are not written when empty")
+	langs := fs.String("lang", "", "comma separated languages whose catalogs receive new keys")
+	funcs := fs.String("func", defaultFuncs, "comma separated translation functions, name[:index of the category argument]")
+	pot := fs.String("pot", "", "also write a gettext template with source references to this file")
+	refs := fs.String("refs", "", "also write the source references of the messages as JSON to this file")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n extract [flags] [packages]\n\nPackages are directories, dir/... includes subdirectories. Default ./...\nJSON catalogs cannot hold comments: use -refs or -pot for source references.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	specs, err := parseFuncSpecs(*funcs)
+	if err != nil {
+		return err
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	files, err := goFiles(patterns)
+	if err != nil {
+		return err
+	}
+	msgs, err := extract(files, specs)
+	if err != nil {
+		return err
+	}
+	if *base != "" && *langs != "" {
+		for _, lang := range strings.Split(*langs, ",") {
+			if err := mergeMessages(*base, strings.TrimSpace(lang), msgs); err != nil {
+				return err
+			}
+		}
+	}
+	if *pot != "" {
+		f, err := os.Create(*pot)
+		if err != nil {
+			return err
+		}
+		if err := writePOT(f, msgs); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if *refs != "" {
+		if err := writeRefs(*refs, msgs); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "ii18n extract: %d messages from %d files\n", len(msgs), len(files))
+	return nil
+}
+
+// parseFuncSpecs Parses "T,TContext:1" into function name to category
+// argument index.
+func parseFuncSpecs(spec string) (map[string]int, error) {
+	specs := make(map[string]int)
+	for _, item := range strings.Split(spec, ",") {
+		name, index := strings.TrimSpace(item), 0
+		if pos := strings.Index(name, ":"); pos != -1 {
+			var err error
+			if index, err = strconv.Atoi(name[pos+1:]); err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid function %q", item)
+			}
+			name = name[:pos]
+		}
+		if name != "" {
+			specs[name] = index
+		}
+	}
+	return specs, nil
+}
+
+// goFiles Returns the Go files of the packages matching patterns, sorted.
+// Test files, testdata, vendor and hidden directories are skipped.
+func goFiles(patterns []string) ([]string, error) {
+	var files []string
+	add := func(dir string, recursive bool) error {
+		return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != dir && (!recursive || name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	for _, pattern := range patterns {
+		var err error
+		if strings.HasSuffix(pattern, "/...") || pattern == "..." {
+			dir := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+			if dir == "" {
+				dir = "."
+			}
+			err = add(dir, true)
+		} else if strings.HasSuffix(pattern, ".go") {
+			files = append(files, pattern)
+		} else {
+			err = add(pattern, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// extract Finds the calls of the functions in specs whose category and
+// message arguments are string literals. Messages are sorted by category
+// and key.
+func extract(files []string, specs map[string]int) ([]*message, error) {
+	fset := token.NewFileSet()
+	found := make(map[string]*message)
+	for _, filename := range files {
+		file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			var name string
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			}
+			index, ok := specs[name]
+			if !ok || len(call.Args) < index+2 {
+				return true
+			}
+			category, ok1 := stringLit(call.Args[index])
+			key, ok2 := stringLit(call.Args[index+1])
+			if !ok1 || !ok2 {
+				return true
+			}
+			if !strings.Contains(category, ".") {
+				category = "app." + category
+			}
+			id := category + "\x04" + key
+			msg := found[id]
+			if msg == nil {
+				msg = &message{category: category, key: key}
+				found[id] = msg
+			}
+			pos := fset.Position(call.Pos())
+			msg.refs = append(msg.refs, filepath.ToSlash(pos.Filename)+":"+strconv.Itoa(pos.Line))
+			return true
+		})
+	}
+	msgs := make([]*message, 0, len(found))
+	for _, msg := range found {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if msgs[i].category != msgs[j].category {
+			return msgs[i].category < msgs[j].category
+		}
+		return msgs[i].key < msgs[j].key
+	})
+	return msgs, nil
+}
+
+// stringLit Returns the value of a string literal expression.
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// mergeMessages Adds the keys missing from the catalogs of lang with an
+// empty translation, keeping existing entries.
+func mergeMessages(base string, lang string, msgs []*message) error {
+	byFile := make(map[string][]*message)
+	for _, msg := range msgs {
+		filename := catalogPath(base, msg.category, lang)
+		byFile[filename] = append(byFile[filename], msg)
+	}
+	for _, filename := range sortedKeys(byFile) {
+		fileMsgs := byFile[filename]
+		catalog, err := readCatalog(filename)
+		if err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+		changed := false
+		for _, msg := range fileMsgs {
+			if _, ok := catalog[msg.key]; !ok {
+				catalog[msg.key] = ""
+				changed = true
+			}
+		}
+		if changed {
+			if err := writeCatalog(filename, catalog); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeRefs Writes the sorted source references of msgs to filename as a
+// JSON object of categories, each an object of keys.
+func writeRefs(filename string, msgs []*message) error {
+	refs := make(map[string]map[string][]string)
+	for _, msg := range msgs {
+		if refs[msg.category] == nil {
+			refs[msg.category] = make(map[string][]string)
+		}
+		sorted := append([]string(nil), msg.refs...)
+		sort.Strings(sorted)
+		refs[msg.category][msg.key] = sorted
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(refs); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// writePOT Writes msgs as a gettext template, the category as msgctxt and
+// the source locations as references.
+func writePOT(w io.Writer, msgs []*message) error {
+	codec, _ := ii18n.GetCodec("po")
+	c := &ii18n.Catalog{}
+	for _, msg := range msgs {
+		refs := append([]string(nil), msg.refs...)
+		sort.Strings(refs)
+		c.Entries = append(c.Entries, ii18n.Entry{Key: msg.key, Context: msg.category, Refs: refs})
+	}
+	data, err := codec.(ii18n.CatalogCodec).EncodeCatalog(c, "")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	catalogFormat := fs.String("catalog", "json", "catalog format")
+	pkg := fs.String("pkg", "locales", "package name of the generated file")
+	out := fs.String("o", "catalogs_gen.go", "output file, or output directory with -binary, -base by default")
+	binaryFormat := fs.Bool("binary", false, "write binary catalogs {o}/{lang}/{file}.bin for ii18n.NewBinarySource instead of Go code")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n gen [flags]\n\nCompiles the catalogs under -base into Go code registering them for\nii18n.NewCompiledSource, e.g.\n\n\t//go:generate ii18n gen -base ../locales -pkg locales\n\nor with -binary into binary catalogs, which load without parsing.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *binaryFormat {
+		dir := *base
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "o" {
+				dir = *out
+			}
+		})
+		return generateBinaryCatalogs(*base, *catalogFormat, dir)
+	}
+	src, err := generateCatalogs(*base, *catalogFormat, *pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, src, 0644)
+}
+
+// generateCatalogs Returns the formatted Go source registering the catalogs
+// of format under base.
+func generateCatalogs(base string, catalogFormat string, pkg string) ([]byte, error) {
+	codec, ok := ii18n.GetCodec(catalogFormat)
+	if !ok {
+		return nil, fmt.Errorf("unknown catalog format %q", catalogFormat)
+	}
+	langs, err := languages(base)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by ii18n gen. DO NOT EDIT.\n\npackage %s\n\nimport \"github.com/syyongx/ii18n\"\n\nfunc init() {\n", pkg)
+	for _, lang := range langs {
+		files, err := catalogFilesOf(filepath.Join(base, lang), catalogFormat)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range files {
+			filename := filepath.Join(base, lang, rel)
+			data, err := readText(filename)
+			if err != nil {
+				return nil, err
+			}
+			msgs, err := codec.Decode(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", filename, err)
+			}
+			name := lang + "/" + filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+			fmt.Fprintf(&b, "ii18n.RegisterCatalog(%s, ii18n.CompiledCatalog{\n", strconv.Quote(name))
+			for _, key := range sortedKeys(msgs) {
+				nodes, err := ii18n.ParsePattern(msgs[key])
+				if err != nil {
+					return nil, fmt.Errorf("%s: %q: %v", filename, key, err)
+				}
+				fmt.Fprintf(&b, "%s: {Text: %s, Nodes: ", strconv.Quote(key), strconv.Quote(msgs[key]))
+				writeNodes(&b, nodes)
+				b.WriteString("},\n")
+			}
+			b.WriteString("})\n")
+		}
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+// generateBinaryCatalogs Writes the catalogs of format under base as binary
+// catalogs under dir.
+func generateBinaryCatalogs(base string, catalogFormat string, dir string) error {
+	codec, ok := ii18n.GetCodec(catalogFormat)
+	if !ok {
+		return fmt.Errorf("unknown catalog format %q", catalogFormat)
+	}
+	binaryCodec, _ := ii18n.GetCodec("binary")
+	langs, err := languages(base)
+	if err != nil {
+		return err
+	}
+	for _, lang := range langs {
+		files, err := catalogFilesOf(filepath.Join(base, lang), catalogFormat)
+		if err != nil {
+			return err
+		}
+		for _, rel := range files {
+			filename := filepath.Join(base, lang, rel)
+			data, err := readText(filename)
+			if err != nil {
+				return err
+			}
+			msgs, err := codec.Decode(data)
+			if err != nil {
+				return fmt.Errorf("%s: %v", filename, err)
+			}
+			if data, err = binaryCodec.Encode(msgs, lang); err != nil {
+				return fmt.Errorf("%s: %v", filename, err)
+			}
+			target := filepath.Join(dir, lang, strings.TrimSuffix(rel, filepath.Ext(rel))+".bin")
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeNodes Writes nodes as a Go composite literal.
+func writeNodes(b *bytes.Buffer, nodes []ii18n.Node) {
+	b.WriteString("[]ii18n.Node{")
+	for _, n := range nodes {
+		switch n.Kind {
+		case ii18n.TextNode:
+			fmt.Fprintf(b, "{Kind: ii18n.TextNode, Text: %s}, ", strconv.Quote(n.Text))
+		case ii18n.PoundNode:
+			b.WriteString("{Kind: ii18n.PoundNode}, ")
+		case ii18n.ArgNode:
+			fmt.Fprintf(b, "{Kind: ii18n.ArgNode, Name: %s", strconv.Quote(n.Name))
+			if n.Type != "" {
+				fmt.Fprintf(b, ", Type: %s", strconv.Quote(n.Type))
+			}
+			if n.Style != "" {
+				fmt.Fprintf(b, ", Style: %s", strconv.Quote(n.Style))
+			}
+			if n.Offset != 0 {
+				fmt.Fprintf(b, ", Offset: %d", n.Offset)
+			}
+			if len(n.Variants) > 0 {
+				b.WriteString(", Variants: []ii18n.Variant{")
+				for _, v := range n.Variants {
+					fmt.Fprintf(b, "{Selector: %s, Nodes: ", strconv.Quote(v.Selector))
+					writeNodes(b, v.Nodes)
+					b.WriteString("}, ")
+				}
+				b.WriteString("}")
+			}
+			b.WriteString("}, ")
+		}
+	}
+	b.WriteString("}")
+}
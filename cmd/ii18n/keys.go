@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runKeys(args []string) error {
+	fs := flag.NewFlagSet("keys", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	source := fs.String("source", ii18n.DefaultOriginalLang, "language whose catalogs define the keys")
+	catalogFormat := fs.String("catalog", "json", "catalog format")
+	prefix := fs.String("prefix", "app", "category prefix of the catalogs")
+	pkg := fs.String("pkg", "msg", "package name of the generated file")
+	out := fs.String("o", "keys_gen.go", "output file")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n keys [flags]\n\nGenerates a constant per message key and an accessor per category, e.g.\nmsg.AppUI.Save(lang), so mistyped keys fail to compile.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	src, err := generateKeys(*base, *source, *catalogFormat, *prefix, *pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, src, 0644)
+}
+
+// generateKeys Returns the formatted Go source declaring the keys of the
+// catalogs of the source language.
+func generateKeys(base string, source string, catalogFormat string, prefix string, pkg string) ([]byte, error) {
+	codec, ok := ii18n.GetCodec(catalogFormat)
+	if !ok {
+		return nil, fmt.Errorf("unknown catalog format %q", catalogFormat)
+	}
+	files, err := catalogFilesOf(filepath.Join(base, source), catalogFormat)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by ii18n keys. DO NOT EDIT.\n\npackage %s\n\nimport \"github.com/syyongx/ii18n\"\n", pkg)
+	for _, rel := range files {
+		filename := filepath.Join(base, source, rel)
+		data, err := readText(filename)
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := codec.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		category := catalogCategory(prefix, rel)
+		typeName := identifier(category)
+		names := make(map[string]bool)
+		keyNames := make(map[string]string, len(msgs))
+		for _, key := range sortedKeys(msgs) {
+			name := identifier(key)
+			for n := 2; names[name]; n++ {
+				name = identifier(key) + strconv.Itoa(n)
+			}
+			names[name] = true
+			keyNames[key] = name
+		}
+
+		fmt.Fprintf(&b, "\n// Keys of the %s category.\nconst (\n", category)
+		for _, key := range sortedKeys(msgs) {
+			fmt.Fprintf(&b, "%s%s = %s\n", typeName, keyNames[key], strconv.Quote(key))
+		}
+		fmt.Fprintf(&b, ")\n\n// %s translates the messages of the %s category.\nvar %s = %s{}\n\n", typeName, category, typeName, unexported(typeName))
+		fmt.Fprintf(&b, "type %s struct{}\n", unexported(typeName))
+		for _, key := range sortedKeys(msgs) {
+			placeholders, err := ii18n.Placeholders(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q: %v", filename, key, err)
+			}
+			fmt.Fprintf(&b, "\n// %s Returns %s.\n", keyNames[key], strconv.Quote(key))
+			if len(placeholders) == 0 {
+				fmt.Fprintf(&b, "func (%s) %s(lang string) string {\nreturn ii18n.T(%s, %s%s, nil, lang)\n}\n",
+					unexported(typeName), keyNames[key], strconv.Quote(category), typeName, keyNames[key])
+			} else {
+				fmt.Fprintf(&b, "func (%s) %s(params map[string]string, lang string) string {\nreturn ii18n.T(%s, %s%s, params, lang)\n}\n",
+					unexported(typeName), keyNames[key], strconv.Quote(category), typeName, keyNames[key])
+			}
+		}
+	}
+	return format.Source(b.Bytes())
+}
+
+// identifier Returns an exported Go identifier for s: its letters and digits
+// in title-cased words, e.g. "Hello {name}!" becomes "HelloName".
+func identifier(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+		if b.Len() >= 48 {
+			break
+		}
+	}
+	name := b.String()
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "K" + name
+	}
+	return name
+}
+
+// unexported Returns name with a lower-case first letter.
+func unexported(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
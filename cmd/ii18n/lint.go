@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	source := fs.String("source", ii18n.DefaultOriginalLang, "language of the original messages")
+	skipEmpty := fs.Bool("skip-empty", false, "do not report empty translations")
+	skipGrammar := fs.Bool("skip-grammar", false, "do not check gender and case select arguments")
+	glossaryFile := fs.String("glossary", "", "JSON glossary of approved and do-not-translate terms to check translations against")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n lint [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var glossary *ii18n.Glossary
+	if *glossaryFile != "" {
+		var err error
+		if glossary, err = ii18n.LoadGlossary(*glossaryFile); err != nil {
+			return err
+		}
+	}
+	problems, err := lint(*base, *source, *skipEmpty, *skipGrammar, glossary)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+	return nil
+}
+
+// lint Checks every catalog under base and returns its problems, sorted.
+// Unless skipGrammar, gender and case select arguments must cover the values
+// of their language. Translations are checked against glossary when not
+// nil.
+func lint(base string, source string, skipEmpty bool, skipGrammar bool, glossary *ii18n.Glossary) ([]string, error) {
+	langs, err := languages(base)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	report := func(filename string, key string, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("%s: %q: ", filename, key)+fmt.Sprintf(format, args...))
+	}
+	for _, lang := range langs {
+		files, err := catalogFiles(filepath.Join(base, lang))
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range files {
+			filename := filepath.Join(base, lang, rel)
+			data, err := readText(filename)
+			if err != nil {
+				return nil, err
+			}
+			catalog, dups, err := decodeCatalog(data)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", filename, err))
+				continue
+			}
+			for _, key := range dups {
+				report(filename, key, "duplicate key")
+			}
+			var originals ii18n.TMsgs
+			if lang != source {
+				if originals, err = readCatalog(filepath.Join(base, source, rel)); err != nil {
+					return nil, err
+				}
+			}
+			for key, val := range catalog.msgs {
+				if _, err := ii18n.Placeholders(key); err != nil {
+					report(filename, key, "key: %v", err)
+				}
+				if val == "" {
+					if !skipEmpty {
+						report(filename, key, "empty translation")
+					}
+					continue
+				}
+				names, err := ii18n.Placeholders(val)
+				if err != nil {
+					report(filename, key, "%v", err)
+					continue
+				}
+				if !skipGrammar {
+					grammar, _ := ii18n.CheckGrammar(val, lang)
+					for _, problem := range grammar {
+						report(filename, key, "%s", problem)
+					}
+				}
+				original := key
+				if originals[key] != "" {
+					original = originals[key]
+				}
+				if glossary != nil && lang != source {
+					for _, e := range glossary.Check(original, val, lang) {
+						report(filename, key, "%v", e)
+					}
+				}
+				expected, err := ii18n.Placeholders(original)
+				if err != nil {
+					continue
+				}
+				if missing := difference(expected, names); len(missing) > 0 {
+					report(filename, key, "missing placeholders %s", strings.Join(missing, ", "))
+				}
+				if extra := difference(names, expected); len(extra) > 0 {
+					report(filename, key, "unknown placeholders %s", strings.Join(extra, ", "))
+				}
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// difference Returns the names of a not in b.
+func difference(a []string, b []string) []string {
+	var diff []string
+	for _, name := range a {
+		found := false
+		for _, other := range b {
+			if name == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, name)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/syyongx/ii18n/integrations/lokalise"
+)
+
+func runLokalise(args []string) error {
+	fs := flag.NewFlagSet("lokalise", flag.ExitOnError)
+	base := fs.String("base", "", "base path of the catalogs, laid out as {base}/{lang}/{file}")
+	source := fs.String("source", "en-US", "source language, pushed by push")
+	project := fs.String("project", "", "Lokalise project ID")
+	langs := fs.String("langs", "", "comma-separated languages pulled by pull")
+	descriptions := fs.String("descriptions", "", "JSON file of the descriptions of the keys pushed, by key")
+	dryRun := fs.Bool("dry-run", false, "report the changes without making them")
+	overwrite := fs.Bool("overwrite", false, "replace conflicting local translations on pull")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n lokalise -base dir -project id [flags] push|pull")
+		fmt.Fprintln(os.Stderr, "The API token is read from LOKALISE_TOKEN. Conflicts are reported and,\nunless -overwrite on pull, left as they are.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	token := os.Getenv("LOKALISE_TOKEN")
+	if *base == "" || *project == "" || fs.NArg() != 1 || token == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts := lokalise.Options{DryRun: *dryRun, Overwrite: *overwrite}
+	if *descriptions != "" {
+		data, err := readText(*descriptions)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &opts.Descriptions); err != nil {
+			return fmt.Errorf("%s: %w", *descriptions, err)
+		}
+	}
+	c := lokalise.New(token, *project)
+	ctx := context.Background()
+	var report *lokalise.Report
+	var err error
+	switch fs.Arg(0) {
+	case "push":
+		report, err = c.Push(ctx, *base, *source, opts)
+	case "pull":
+		if *langs == "" {
+			return fmt.Errorf("pull needs -langs")
+		}
+		report, err = c.Pull(ctx, *base, strings.Split(*langs, ","), opts)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	if report != nil {
+		for _, added := range report.Added {
+			fmt.Printf("+ %s\n", added)
+		}
+		for _, c := range report.Conflicts {
+			fmt.Printf("! %s %s: %s: local %q, remote %q\n", c.Lang, c.File, c.Key, c.Local, c.Remote)
+		}
+	}
+	return err
+}
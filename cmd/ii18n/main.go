@@ -0,0 +1,68 @@
+// Command ii18n maintains ii18n message catalogs.
+//
+// Usage:
+//
+//	ii18n <command> [flags] [arguments]
+//
+// Run "ii18n <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command a subcommand, run with the arguments following its name.
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"extract":   {"extract translatable strings from Go source into catalogs", runExtract},
+	"gen":       {"compile catalogs into Go code", runGen},
+	"keys":      {"generate typed message key constants", runKeys},
+	"convert":   {"convert catalogs between formats", runConvert},
+	"crowdin":   {"push source catalogs to and pull translations from Crowdin", runCrowdin},
+	"diff":      {"report added, removed and changed messages", runDiff},
+	"encrypt":   {"encrypt, re-encrypt or decrypt catalogs with AES-GCM", runEncrypt},
+	"lint":      {"check catalogs for syntax and placeholder errors", runLint},
+	"lokalise":  {"push new keys to and pull translations from Lokalise", runLokalise},
+	"merge":     {"sync catalogs against a reference language", runMerge},
+	"mt-fill":   {"fill missing catalog entries with machine translations", runMTFill},
+	"prune":     {"report or remove catalog keys unused in Go source", runPrune},
+	"stats":     {"print translation coverage per language and category", runStats},
+	"suggest":   {"suggest translations from similar translated messages", runSuggest},
+	"sync":      {"list, push and pull the resources of any translation management system connector", runSync},
+	"transifex": {"push source catalogs to and pull translations from Transifex", runTransifex},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ii18n: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ii18n "+os.Args[1]+":", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: ii18n <command> [flags] [arguments]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syyongx/ii18n"
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// runCommand Runs the command name with args, returning its standard
+// output.
+func runCommand(t *testing.T, name string, args []string) (string, error) {
+	t.Helper()
+	out, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	stdout := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = stdout }()
+	runErr := commands[name].run(args)
+	out.Seek(0, io.SeekStart)
+	data, err := io.ReadAll(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), runErr
+}
+
+// writeFiles Writes files, by path relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const testSource = `package app
+
+func render(lang string) {
+	T("shop", "Cart", nil, lang)
+	i18n.T("app.admin.users", "Delete", nil, lang)
+	i18n.MustT("app.admin.users", "Invite", nil, lang)
+	if _, err := i18n.TE("shop", "Pay", nil, lang); err != nil {
+		i18n.Tf("shop", "Total", lang)
+	}
+}
+`
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		// args the arguments, {dir} being the directory of the files.
+		args []string
+		// output substrings of the standard output.
+		output []string
+		err    string
+		// expected the contents of files after the command.
+		expected map[string]string
+		// contains substrings of files after the command.
+		contains map[string][]string
+	}{
+		{
+			name:  "extract",
+			files: map[string]string{"src/app.go": testSource, "locales/de/shop.json": `{"Cart": "Warenkorb"}`},
+			args:  []string{"-base", "{dir}/locales", "-lang", "de", "-refs", "{dir}/refs.json", "{dir}/src/..."},
+			expected: map[string]string{
+				"locales/de/shop.json":        "{\n\t\"Cart\": \"Warenkorb\",\n\t\"Pay\": \"\",\n\t\"Total\": \"\"\n}\n",
+				"locales/de/admin/users.json": "{\n\t\"Delete\": \"\",\n\t\"Invite\": \"\"\n}\n",
+				"refs.json":                   "{\n\t\"app.admin.users\": {\n\t\t\"Delete\": [\n\t\t\t\"{dir}/src/app.go:5\"\n\t\t],\n\t\t\"Invite\": [\n\t\t\t\"{dir}/src/app.go:6\"\n\t\t]\n\t},\n\t\"app.shop\": {\n\t\t\"Cart\": [\n\t\t\t\"{dir}/src/app.go:4\"\n\t\t],\n\t\t\"Pay\": [\n\t\t\t\"{dir}/src/app.go:7\"\n\t\t],\n\t\t\"Total\": [\n\t\t\t\"{dir}/src/app.go:8\"\n\t\t]\n\t}\n}\n",
+			},
+		},
+		{
+			name: "lint",
+			files: map[string]string{
+				"locales/en-US/shop.json": `{"Hi {name}": "Hi {name}"}`,
+				"locales/de/shop.json":    `{"Hi {name}": "Hallo {nom}", "Bye": ""}`,
+			},
+			args: []string{"-base", "{dir}/locales", "-source", "en-US"},
+			output: []string{
+				`de/shop.json: "Bye": empty translation`,
+				`de/shop.json: "Hi {name}": missing placeholders name`,
+				`de/shop.json: "Hi {name}": unknown placeholders nom`,
+			},
+			err: "3 problems found",
+		},
+		{
+			name: "merge",
+			files: map[string]string{
+				"locales/en-US/shop.json": `{"a": "A", "b": "B"}`,
+				"locales/de/shop.json":    `{"c": "C", "a": "X"}`,
+			},
+			args:     []string{"-base", "{dir}/locales", "-source", "en-US", "-remove-obsolete"},
+			output:   []string{"shop.json: +1 -1"},
+			expected: map[string]string{"locales/de/shop.json": "{\n\t\"a\": \"X\",\n\t\"b\": \"\"\n}\n"},
+		},
+		{
+			name:     "convert",
+			files:    map[string]string{"de.json": `{"Cart": "Warenkorb"}`},
+			args:     []string{"-lang", "de", "{dir}/de.json", "{dir}/de.yaml"},
+			expected: map[string]string{"de.yaml": "\"Cart\": \"Warenkorb\"\n"},
+		},
+		{
+			name: "stats",
+			files: map[string]string{
+				"locales/en-US/shop.json": `{"a": "A", "b": "B"}`,
+				"locales/de/shop.json":    `{"a": "X"}`,
+			},
+			args:   []string{"-base", "{dir}/locales", "-source", "en-US", "-format", "json"},
+			output: []string{`"de": {`, `"Translated": 1,`, `"Percent": 50`, `"en-US": {`},
+		},
+		{
+			name:     "gen",
+			files:    map[string]string{"locales/de/shop.json": `{"Cart": "Warenkorb"}`},
+			args:     []string{"-base", "{dir}/locales", "-o", "{dir}/catalogs_gen.go"},
+			contains: map[string][]string{"catalogs_gen.go": {"package locales", `ii18n.RegisterCatalog("de/shop"`, `"Cart": {Text: "Warenkorb"`}},
+		},
+		{
+			name: "keys",
+			files: map[string]string{
+				"locales/en-US/shop.json":        `{"Cart": "Cart"}`,
+				"locales/en-US/admin/users.json": `{"Delete": "Delete"}`,
+			},
+			args:     []string{"-base", "{dir}/locales", "-source", "en-US", "-o", "{dir}/keys_gen.go"},
+			contains: map[string][]string{"keys_gen.go": {"package msg", `AppShopCart = "Cart"`, "// Keys of the app.admin.users category."}},
+		},
+		{
+			name: "prune",
+			files: map[string]string{
+				"src/app.go":                  testSource,
+				"locales/de/shop.json":        `{"Cart": "Warenkorb", "Old": "Alt"}`,
+				"locales/de/admin/users.json": `{"Delete": "Löschen", "Ban": "Sperren"}`,
+			},
+			args:   []string{"-src", "{dir}/src/...", "-base", "{dir}/locales", "-remove"},
+			output: []string{`shop.json: "Old"`, `users.json: "Ban"`},
+			expected: map[string]string{
+				"locales/de/shop.json":        "{\n\t\"Cart\": \"Warenkorb\"\n}\n",
+				"locales/de/admin/users.json": "{\n\t\"Delete\": \"Löschen\"\n}\n",
+			},
+		},
+		{
+			name: "diff",
+			files: map[string]string{
+				"old/de/shop.json": `{"a": "A", "b": "B"}`,
+				"new/de/shop.json": `{"a": "A2", "c": "C"}`,
+			},
+			args:   []string{"{dir}/old", "{dir}/new"},
+			output: []string{`+ "c": "C"`, `- "b": "B"`, `~ "a": "A" -> "A2"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := filepath.ToSlash(t.TempDir())
+			writeFiles(t, dir, test.files)
+			args := make([]string, len(test.args))
+			for n, arg := range test.args {
+				args[n] = strings.ReplaceAll(arg, "{dir}", dir)
+			}
+			output, err := runCommand(t, test.name, args)
+			if test.err == "" && err != nil || test.err != "" && (err == nil || err.Error() != test.err) {
+				t.Errorf("error = %v, want %q", err, test.err)
+			}
+			for _, s := range test.output {
+				if !strings.Contains(output, s) {
+					t.Errorf("output %q lacks %q", output, s)
+				}
+			}
+			for name, expected := range test.expected {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if expected = strings.ReplaceAll(expected, "{dir}", dir); err != nil || string(data) != expected {
+					t.Errorf("%s = %q, %v, want %q", name, data, err, expected)
+				}
+			}
+			for name, parts := range test.contains {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, s := range parts {
+					if !strings.Contains(string(data), s) {
+						t.Errorf("%s lacks %q", name, s)
+					}
+				}
+			}
+		})
+	}
+}
+
+// mtFunc an ii18n.MTProvider of a function.
+type mtFunc func(ctx context.Context, text string, from string, to string) (string, error)
+
+func (f mtFunc) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	return f(ctx, text, from, to)
+}
+
+func TestMTFillFailure(t *testing.T) {
+	mtProviders["test"] = func() (ii18n.MTProvider, error) {
+		return mtFunc(func(ctx context.Context, text string, from string, to string) (string, error) {
+			switch text {
+			case "Broken":
+				return "", errors.New("quota exceeded")
+			case "Lost {0}":
+				return "[Lost]", nil
+			}
+			return "[" + text + "]", nil
+		}), nil
+	}
+	defer delete(mtProviders, "test")
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"en-US/shop.json":  `{"Cart": "Cart", "Items": "{n, plural, one {# item} other {# items}}", "Lost {name}": "", "Broken": "Broken", "Pay": "Pay"}`,
+		"en-US/users.json": `{"Delete": "Delete"}`,
+	})
+	_, err := runCommand(t, "mt-fill", []string{"-base", dir, "-source", "en-US", "-langs", "de", "-provider", "test", "-rate", "1000"})
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("error = %v, want the provider error", err)
+	}
+	// The entries translated before the failure are kept, and listed as
+	// machine translations so a later run does not take them as reviewed.
+	// The arguments are not sent, and translations losing them are skipped.
+	items := `{n, plural, one {[# item]} other {[# items]}}`
+	expected := map[string]string{
+		"de/shop.json":    "{\n\t\"Cart\": \"[Cart]\",\n\t\"Items\": \"" + items + "\"\n}\n",
+		"machine_de.json": "{\n\t\"shop.json\": {\n\t\t\"Cart\": \"[Cart]\",\n\t\t\"Items\": \"" + items + "\"\n\t}\n}\n",
+	}
+	for name, expected := range expected {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != expected {
+			t.Errorf("%s = %q, %v, want %q", name, data, err, expected)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "de", "users.json")); !os.IsNotExist(err) {
+		t.Errorf("de/users.json filled after the failure: %v", err)
+	}
+}
+
+// syncConnector an integrations.Connector recording the calls of the sync
+// command.
+type syncConnector struct {
+	project string
+	calls   []string
+}
+
+func (c *syncConnector) ListResources(ctx context.Context) ([]string, error) {
+	c.calls = append(c.calls, "ls "+c.project)
+	return []string{"admin/users.json", "shop.json"}, nil
+}
+
+func (c *syncConnector) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	c.calls = append(c.calls, "push "+base+" "+sourceLang)
+	return []string{"shop.json"}, nil
+}
+
+func (c *syncConnector) Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error) {
+	c.calls = append(c.calls, "pull "+base+" "+sourceLang+" "+strings.Join(langs, " "))
+	return []string{base + "/de/shop.json"}, errors.New("fr: not found")
+}
+
+func (c *syncConnector) Webhook(r *http.Request) ([]integrations.Change, error) {
+	return nil, nil
+}
+
+func TestSync(t *testing.T) {
+	c := &syncConnector{}
+	connectors["test"] = func(project string) (integrations.Connector, error) {
+		c.project = project
+		return c, nil
+	}
+	defer delete(connectors, "test")
+	tests := []struct {
+		args   []string
+		output string
+		err    string
+		call   string
+	}{
+		{[]string{"ls"}, "admin/users.json\nshop.json\n", "", "ls p1"},
+		{[]string{"-base", "locales", "-source", "en-GB", "push"}, "shop.json\n", "", "push locales en-GB"},
+		// The files written before an error are listed.
+		{[]string{"-base", "locales", "-langs", "de,fr", "pull"}, "locales/de/shop.json\n", "fr: not found", "pull locales en-US de fr"},
+		{[]string{"pull"}, "", "pull needs -langs", ""},
+	}
+	for _, test := range tests {
+		c.calls = nil
+		output, err := runCommand(t, "sync", append([]string{"-connector", "test", "-project", "p1"}, test.args...))
+		if test.err == "" && err != nil || test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("%v: error = %v, want %q", test.args, err, test.err)
+		}
+		if output != test.output {
+			t.Errorf("%v: output = %q, want %q", test.args, output, test.output)
+		}
+		if call := strings.Join(c.calls, "; "); call != test.call {
+			t.Errorf("%v: called %q, want %q", test.args, call, test.call)
+		}
+	}
+	if _, err := runCommand(t, "sync", []string{"-connector", "none", "-project", "p1", "ls"}); err == nil || err.Error() != `unknown connector "none"` {
+		t.Errorf("error of an unknown connector = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	source := fs.String("source", ii18n.DefaultOriginalLang, "reference language")
+	langs := fs.String("lang", "", "comma separated languages to sync, default all")
+	removeObsolete := fs.Bool("remove-obsolete", false, "remove keys that are not in the reference catalogs")
+	dryRun := fs.Bool("dry-run", false, "report changes without writing catalogs")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n merge [flags]\n\nAdds the keys of the reference catalogs to the other languages with an\nempty translation. Existing keys keep their order; new keys are appended\nin reference order.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	targets, err := languages(*base)
+	if err != nil {
+		return err
+	}
+	if *langs != "" {
+		targets = strings.Split(*langs, ",")
+	}
+	files, err := catalogFiles(filepath.Join(*base, *source))
+	if err != nil {
+		return err
+	}
+	for _, lang := range targets {
+		if lang == *source {
+			continue
+		}
+		for _, rel := range files {
+			added, removed, err := mergeCatalog(filepath.Join(*base, *source, rel), filepath.Join(*base, lang, rel), *removeObsolete, *dryRun)
+			if err != nil {
+				return err
+			}
+			if added > 0 || removed > 0 {
+				fmt.Printf("%s: +%d -%d\n", filepath.Join(*base, lang, rel), added, removed)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeCatalog Syncs the catalog target with the reference catalog source
+// and returns the number of added and removed keys.
+func mergeCatalog(source string, target string, removeObsolete bool, dryRun bool) (int, int, error) {
+	ref, err := readOrderedCatalog(source)
+	if err != nil {
+		return 0, 0, err
+	}
+	c, err := readOrderedCatalog(target)
+	if err != nil {
+		return 0, 0, err
+	}
+	added, removed := 0, 0
+	for _, key := range ref.keys {
+		if _, ok := c.msgs[key]; !ok {
+			c.set(key, "")
+			added++
+		}
+	}
+	if removeObsolete {
+		for _, key := range append([]string(nil), c.keys...) {
+			if _, ok := ref.msgs[key]; !ok {
+				c.remove(key)
+				removed++
+			}
+		}
+	}
+	if dryRun || added+removed == 0 {
+		return added, removed, nil
+	}
+	return added, removed, writeOrderedCatalog(target, c)
+}
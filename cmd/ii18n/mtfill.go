@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/syyongx/ii18n"
+	"github.com/syyongx/ii18n/mt"
+)
+
+// mtProviders machine translation providers by name, with the environment
+// variable of their key.
+var mtProviders = map[string]func() (ii18n.MTProvider, error){
+	"deepl": func() (ii18n.MTProvider, error) {
+		key := os.Getenv("DEEPL_AUTH_KEY")
+		if key == "" {
+			return nil, errors.New("deepl needs DEEPL_AUTH_KEY")
+		}
+		return mt.NewDeepL(key), nil
+	},
+	"google": func() (ii18n.MTProvider, error) {
+		key := os.Getenv("GOOGLE_API_KEY")
+		if key == "" {
+			return nil, errors.New("google needs GOOGLE_API_KEY")
+		}
+		return mt.NewGoogle(key), nil
+	},
+}
+
+func runMTFill(args []string) error {
+	fs := flag.NewFlagSet("mt-fill", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	source := fs.String("source", ii18n.DefaultOriginalLang, "language translated from")
+	langs := fs.String("langs", "", "comma separated languages to fill")
+	provider := fs.String("provider", "deepl", "machine translation provider: "+strings.Join(sortedKeys(mtProviders), ", "))
+	onlyMissing := fs.Bool("only-missing", false, "fill only missing and empty entries, keeping earlier machine translations")
+	rate := fs.Float64("rate", 5, "requests per second sent to the provider")
+	dryRun := fs.Bool("dry-run", false, "report the entries to fill without translating them")
+	glossaryFile := fs.String("glossary", "", "JSON glossary: do-not-translate terms are protected, translations without approved terms skipped")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n mt-fill -langs de,fr [flags]\n\nFills the catalogs of langs with machine translations of the reference\ncatalogs, to bootstrap human review. Machine translated entries are listed\nin {base}/machine_{lang}.json; entries edited since are kept as reviewed.\nTranslations losing placeholders of the source, or violating -glossary, are\nskipped.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *langs == "" || *rate <= 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	newProvider, ok := mtProviders[*provider]
+	if !ok {
+		return fmt.Errorf("unknown provider %q", *provider)
+	}
+	p, err := newProvider()
+	if err != nil {
+		return err
+	}
+	if *glossaryFile != "" {
+		glossary, err := ii18n.LoadGlossary(*glossaryFile)
+		if err != nil {
+			return err
+		}
+		p = ii18n.GlossaryProvider(p, glossary)
+	}
+
+	files, err := catalogFiles(filepath.Join(*base, *source))
+	if err != nil {
+		return err
+	}
+	f := &mtFiller{
+		provider:    p,
+		base:        *base,
+		source:      *source,
+		onlyMissing: *onlyMissing,
+		dryRun:      *dryRun,
+		interval:    time.Duration(float64(time.Second) / *rate),
+	}
+	for _, lang := range strings.Split(*langs, ",") {
+		if lang = strings.TrimSpace(lang); lang == "" || lang == *source {
+			continue
+		}
+		if err := f.fill(context.Background(), lang, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mtFiller fills catalogs with machine translations.
+type mtFiller struct {
+	provider    ii18n.MTProvider
+	base        string
+	source      string
+	onlyMissing bool
+	dryRun      bool
+	interval    time.Duration
+	next        time.Time
+}
+
+// machineFile Returns the file listing the machine translated entries of
+// lang: the translation of each key by catalog file.
+func (f *mtFiller) machineFile(lang string) string {
+	return filepath.Join(f.base, "machine_"+lang+".json")
+}
+
+// fill Fills the catalogs files of lang. The machine file is written with
+// each catalog, so the entries translated before a failure stay listed.
+func (f *mtFiller) fill(ctx context.Context, lang string, files []string) error {
+	machine := make(map[string]map[string]string)
+	if data, err := readText(f.machineFile(lang)); err == nil {
+		if err := json.Unmarshal(data, &machine); err != nil {
+			return fmt.Errorf("%s: %v", f.machineFile(lang), err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	filled, skipped := 0, 0
+	for _, rel := range files {
+		ref, err := readOrderedCatalog(filepath.Join(f.base, f.source, rel))
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(f.base, lang, rel)
+		c, err := readOrderedCatalog(target)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		marks := machine[key]
+		if marks == nil {
+			marks = make(map[string]string)
+		}
+		// Entries edited since they were machine translated are reviewed.
+		for k, val := range marks {
+			if c.msgs[k] != val {
+				delete(marks, k)
+			}
+		}
+		changed := false
+		var failed error
+		for _, k := range ref.keys {
+			text := ref.msgs[k]
+			if text == "" {
+				text = k
+			}
+			_, isMachine := marks[k]
+			if c.msgs[k] != "" && (f.onlyMissing || !isMachine) {
+				continue
+			}
+			if f.dryRun {
+				fmt.Printf("%s: %q\n", target, k)
+				filled++
+				continue
+			}
+			val, err := f.translate(ctx, text, lang)
+			var glossaryErr *ii18n.GlossaryError
+			if errors.As(err, &glossaryErr) || errors.Is(err, ii18n.ErrMTPlaceholders) {
+				fmt.Fprintf(os.Stderr, "%s: %q: skipped: %v\n", target, k, err)
+				skipped++
+				continue
+			}
+			if err != nil {
+				failed = fmt.Errorf("%s: %q: %w", target, k, err)
+				break
+			}
+			c.set(k, val)
+			marks[k] = val
+			changed = true
+			filled++
+		}
+		if len(marks) > 0 {
+			machine[key] = marks
+		} else {
+			delete(machine, key)
+		}
+		if changed {
+			if err := writeOrderedCatalog(target, c); err != nil {
+				return err
+			}
+		}
+		if !f.dryRun {
+			if err := writeMachineFile(f.machineFile(lang), machine); err != nil {
+				return err
+			}
+		}
+		if failed != nil {
+			return failed
+		}
+	}
+	fmt.Fprintf(os.Stderr, "ii18n mt-fill: %s: %d filled, %d skipped\n", lang, filled, skipped)
+	return nil
+}
+
+// translate Translates the pattern text to lang, keeping its arguments.
+func (f *mtFiller) translate(ctx context.Context, text string, lang string) (string, error) {
+	return ii18n.TranslatePattern(ctx, f, text, f.source, lang)
+}
+
+// Translate implements ii18n.MTProvider, waiting for the rate limit.
+func (f *mtFiller) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	if wait := time.Until(f.next); wait > 0 {
+		time.Sleep(wait)
+	}
+	f.next = time.Now().Add(f.interval)
+	return f.provider.Translate(ctx, text, from, to)
+}
+
+// writeMachineFile Writes the machine translated entries v, removing
+// filename when there are none.
+func writeMachineFile(filename string, v map[string]map[string]string) error {
+	if len(v) == 0 {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,16 @@
+//go:build ii18n_aws
+
+package main
+
+import (
+	"context"
+
+	"github.com/syyongx/ii18n"
+	"github.com/syyongx/ii18n/mt/awstranslate"
+)
+
+func init() {
+	mtProviders["aws"] = func() (ii18n.MTProvider, error) {
+		return awstranslate.New(context.Background())
+	}
+}
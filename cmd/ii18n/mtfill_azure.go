@@ -0,0 +1,27 @@
+//go:build ii18n_azure
+
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/syyongx/ii18n"
+	"github.com/syyongx/ii18n/mt/azuretranslator"
+)
+
+func init() {
+	// AZURE_TRANSLATOR_KEY, or the default credential chain with
+	// AZURE_TRANSLATOR_RESOURCE_ID.
+	mtProviders["azure"] = func() (ii18n.MTProvider, error) {
+		region := os.Getenv("AZURE_TRANSLATOR_REGION")
+		if key := os.Getenv("AZURE_TRANSLATOR_KEY"); key != "" {
+			return azuretranslator.New(key, region), nil
+		}
+		resourceID := os.Getenv("AZURE_TRANSLATOR_RESOURCE_ID")
+		if resourceID == "" {
+			return nil, errors.New("azure needs AZURE_TRANSLATOR_KEY or AZURE_TRANSLATOR_RESOURCE_ID")
+		}
+		return azuretranslator.NewDefault(resourceID, region)
+	}
+}
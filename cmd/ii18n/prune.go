@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runPrune(args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	src := fs.String("src", "./...", "comma separated packages to scan, dir/... includes subdirectories")
+	base := fs.String("base", ".", "catalog base path")
+	prefix := fs.String("prefix", "app", "category prefix of the catalogs")
+	funcs := fs.String("func", defaultFuncs, "comma separated translation functions, name[:index of the category argument]")
+	remove := fs.Bool("remove", false, "remove unused keys from the catalogs instead of only reporting them")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n prune [flags]\n\nReports the JSON catalog keys no translation call in the sources uses.\nKeys passed as variables cannot be seen; review before using -remove.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	specs, err := parseFuncSpecs(*funcs)
+	if err != nil {
+		return err
+	}
+	files, err := goFiles(strings.Split(*src, ","))
+	if err != nil {
+		return err
+	}
+	msgs, err := extract(files, specs)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool, len(msgs))
+	for _, msg := range msgs {
+		used[msg.category+"\x04"+msg.key] = true
+	}
+
+	langs, err := languages(*base)
+	if err != nil {
+		return err
+	}
+	unused := 0
+	for _, lang := range langs {
+		catalogs, err := catalogFiles(filepath.Join(*base, lang))
+		if err != nil {
+			return err
+		}
+		for _, rel := range catalogs {
+			filename := filepath.Join(*base, lang, rel)
+			category := catalogCategory(*prefix, rel)
+			c, err := readOrderedCatalog(filename)
+			if err != nil {
+				return err
+			}
+			removed := false
+			for _, key := range append([]string(nil), c.keys...) {
+				if used[category+"\x04"+key] {
+					continue
+				}
+				fmt.Printf("%s: %q\n", filename, key)
+				unused++
+				if *remove {
+					c.remove(key)
+					removed = true
+				}
+			}
+			if removed {
+				if err := writeOrderedCatalog(filename, c); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if unused > 0 && !*remove {
+		return fmt.Errorf("%d unused keys", unused)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/syyongx/ii18n"
+)
+
+// sourceFuncs source constructors by catalog format.
+var sourceFuncs = map[string]func(*ii18n.Config) ii18n.Source{
+	"json":    ii18n.NewJSONSource,
+	"po":      ii18n.NewPOSource,
+	"mo":      ii18n.NewMOSource,
+	"yaml":    ii18n.NewYAMLSource,
+	"csv":     ii18n.NewCSVSource,
+	"xliff":   ii18n.NewXLIFFSource,
+	"strings": ii18n.NewStringsSource,
+}
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	source := fs.String("source", ii18n.DefaultOriginalLang, "language of the original messages")
+	catalogFormat := fs.String("catalog", "json", "catalog format")
+	format := fs.String("format", "table", "output format, table or json")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n stats [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	stats, err := coverageStats(*base, *source, *catalogFormat)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	case "table":
+		printStats(stats)
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", *format)
+}
+
+// coverageStats Returns the coverage of every category under base by
+// language and category.
+func coverageStats(base string, source string, format string) (map[string]map[string]ii18n.CoverageStats, error) {
+	newSource, ok := sourceFuncs[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown catalog format %q", format)
+	}
+	files, err := catalogFilesOf(filepath.Join(base, source), format)
+	if err != nil {
+		return nil, err
+	}
+	fileMap := make(map[string]string, len(files))
+	for _, rel := range files {
+		name := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		fileMap[name] = filepath.ToSlash(rel)
+	}
+	i18n := ii18n.NewI18N(map[string]ii18n.Config{
+		"app": {
+			SourceNewFunc: newSource,
+			OriginalLang:  source,
+			BasePath:      base,
+			FileMap:       fileMap,
+		},
+	})
+	stats := make(map[string]map[string]ii18n.CoverageStats)
+	for name := range fileMap {
+		coverage, err := i18n.Coverage("app." + name)
+		if err != nil {
+			return nil, err
+		}
+		for lang, s := range coverage {
+			if stats[lang] == nil {
+				stats[lang] = make(map[string]ii18n.CoverageStats)
+			}
+			stats[lang][name] = s
+		}
+	}
+	return stats, nil
+}
+
+// printStats Prints stats as a table sorted by language and category, with
+// a total row per language.
+func printStats(stats map[string]map[string]ii18n.CoverageStats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "LANG\tCATEGORY\tTRANSLATED\tMISSING\tSTALE\tTOTAL\tPERCENT")
+	row := func(lang string, category string, s ii18n.CoverageStats) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%.1f%%\n", lang, category, s.Translated, s.Missing, s.Stale, s.Total, s.Percent)
+	}
+	for _, lang := range sortedKeys(stats) {
+		var total ii18n.CoverageStats
+		categories := sortedKeys(stats[lang])
+		for _, category := range categories {
+			s := stats[lang][category]
+			row(lang, category, s)
+			total.Translated += s.Translated
+			total.Missing += s.Missing
+			total.Stale += s.Stale
+			total.Total += s.Total
+		}
+		if total.Total > 0 {
+			total.Percent = float64(total.Translated) * 100 / float64(total.Total)
+		}
+		if len(categories) > 1 {
+			row(lang, "*", total)
+		}
+	}
+	w.Flush()
+}
+
+// sortedKeys Returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// catalogFilesOf Returns the catalogs of format under dir relative to it, sorted.
+func catalogFilesOf(dir string, format string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && formatOf("", path) == format {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
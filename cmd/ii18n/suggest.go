@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+func runSuggest(args []string) error {
+	fs := flag.NewFlagSet("suggest", flag.ExitOnError)
+	base := fs.String("base", ".", "catalog base path")
+	source := fs.String("source", ii18n.DefaultOriginalLang, "language of the texts")
+	lang := fs.String("lang", "", "language of the suggestions")
+	n := fs.Int("n", 5, "maximum number of suggestions")
+	minScore := fs.Float64("min", 0.5, "minimum similarity, from 0 to 1")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n suggest -lang de [flags] text\n\nSuggests translations of text from the translations of similar texts in\nthe catalogs.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *lang == "" || fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	tm, err := translationMemory(*base, *source, *lang)
+	if err != nil {
+		return err
+	}
+	tm.MinScore = *minScore
+	for _, s := range tm.Suggest(strings.Join(fs.Args(), " "), *lang, *n) {
+		fmt.Printf("%3.0f%%  %q → %q  (%s)\n", s.Score*100, s.Source, s.Translation, s.Category)
+	}
+	return nil
+}
+
+// translationMemory Returns the translation memory of the JSON catalogs of
+// lang under base, the categories being the catalog files.
+func translationMemory(base string, source string, lang string) (*ii18n.TranslationMemory, error) {
+	tm := ii18n.NewTranslationMemory()
+	files, err := catalogFiles(filepath.Join(base, lang))
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		originals, err := readCatalog(filepath.Join(base, source, rel))
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := readCatalog(filepath.Join(base, lang, rel))
+		if err != nil {
+			return nil, err
+		}
+		for key, translation := range msgs {
+			text := key
+			if originals[key] != "" {
+				text = originals[key]
+			}
+			tm.Add(filepath.ToSlash(rel), text, lang, translation)
+		}
+	}
+	return tm, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/syyongx/ii18n/integrations"
+	"github.com/syyongx/ii18n/integrations/crowdin"
+	"github.com/syyongx/ii18n/integrations/lokalise"
+	"github.com/syyongx/ii18n/integrations/transifex"
+	"github.com/syyongx/ii18n/integrations/weblate"
+)
+
+// connectors the connectors of translation management systems by name,
+// opening a project with the token in an environment variable. Builds with
+// in-house connectors register them in an init function.
+var connectors = map[string]func(project string) (integrations.Connector, error){
+	"crowdin": func(project string) (integrations.Connector, error) {
+		token := os.Getenv("CROWDIN_TOKEN")
+		if token == "" {
+			return nil, errors.New("crowdin needs CROWDIN_TOKEN")
+		}
+		id, err := strconv.Atoi(project)
+		if err != nil {
+			return nil, fmt.Errorf("crowdin project %q is not a project ID", project)
+		}
+		return crowdin.New(token, id).Connector(), nil
+	},
+	"lokalise": func(project string) (integrations.Connector, error) {
+		token := os.Getenv("LOKALISE_TOKEN")
+		if token == "" {
+			return nil, errors.New("lokalise needs LOKALISE_TOKEN")
+		}
+		return lokalise.New(token, project).Connector(lokalise.Options{}), nil
+	},
+	"transifex": func(project string) (integrations.Connector, error) {
+		token := os.Getenv("TX_TOKEN")
+		if token == "" {
+			return nil, errors.New("transifex needs TX_TOKEN")
+		}
+		org, slug, ok := strings.Cut(project, "/")
+		if !ok {
+			return nil, fmt.Errorf("transifex project %q is not {organization}/{project}", project)
+		}
+		return transifex.New(token, org, slug).Connector(transifex.Options{}), nil
+	},
+	"weblate": func(project string) (integrations.Connector, error) {
+		token := os.Getenv("WEBLATE_TOKEN")
+		if token == "" {
+			return nil, errors.New("weblate needs WEBLATE_TOKEN")
+		}
+		c := weblate.NewClient(token, project)
+		if endpoint := os.Getenv("WEBLATE_URL"); endpoint != "" {
+			c.Endpoint = endpoint
+		}
+		return c.Connector(), nil
+	},
+}
+
+func runSync(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	name := fs.String("connector", "", "translation management system: "+strings.Join(sortedKeys(connectors), ", "))
+	project := fs.String("project", "", "project of the connector, {organization}/{project} for transifex")
+	base := fs.String("base", ".", "base path of the catalogs, laid out as {base}/{lang}/{resource}")
+	source := fs.String("source", "en-US", "source language, pushed by push")
+	langs := fs.String("langs", "", "comma-separated languages pulled by pull")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n sync -connector name -project id [flags] ls|push|pull\n\nLists the resources of a translation management system project, pushes the\nsource catalogs to it or pulls the translations from it.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *name == "" || *project == "" || fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	open, ok := connectors[*name]
+	if !ok {
+		return fmt.Errorf("unknown connector %q", *name)
+	}
+	c, err := open(*project)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var names []string
+	switch fs.Arg(0) {
+	case "ls":
+		names, err = c.ListResources(ctx)
+	case "push":
+		names, err = c.Push(ctx, *base, *source)
+	case "pull":
+		if *langs == "" {
+			return errors.New("pull needs -langs")
+		}
+		names, err = c.Pull(ctx, *base, *source, strings.Split(*langs, ","))
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	for _, n := range names {
+		fmt.Println(n)
+	}
+	return err
+}
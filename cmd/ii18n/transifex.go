@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/syyongx/ii18n/integrations/transifex"
+)
+
+func runTransifex(args []string) error {
+	fs := flag.NewFlagSet("transifex", flag.ExitOnError)
+	base := fs.String("base", "", "base path of the JSON catalogs, laid out as {base}/{lang}/{file}")
+	source := fs.String("source", "en-US", "source language")
+	org := fs.String("org", "", "Transifex organization slug")
+	project := fs.String("project", "", "Transifex project slug")
+	prefix := fs.String("prefix", "app", "category prefix of the catalogs")
+	langs := fs.String("langs", "", "comma-separated languages pulled by pull")
+	reviewed := fs.Bool("reviewed", false, "pull only reviewed translations")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ii18n transifex -base dir -org slug -project slug [flags] push|pull")
+		fmt.Fprintln(os.Stderr, "The API token is read from TX_TOKEN. Each category is a resource, e.g.\napp.admin.users is admin-users.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	token := os.Getenv("TX_TOKEN")
+	if *base == "" || *org == "" || *project == "" || fs.NArg() != 1 || token == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	c := transifex.New(token, *org, *project)
+	c.Prefix = *prefix
+	ctx := context.Background()
+	switch fs.Arg(0) {
+	case "push":
+		pushed, err := c.Push(ctx, *base, *source)
+		for _, category := range pushed {
+			fmt.Println(category)
+		}
+		return err
+	case "pull":
+		if *langs == "" {
+			return fmt.Errorf("pull needs -langs")
+		}
+		changed, err := c.Pull(ctx, *base, *source, strings.Split(*langs, ","), transifex.Options{Reviewed: *reviewed})
+		for _, name := range changed {
+			fmt.Println(name)
+		}
+		return err
+	}
+	fs.Usage()
+	os.Exit(2)
+	return nil
+}
@@ -0,0 +1,182 @@
+package ii18n
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Codec decodes and encodes the catalogs of a file format.
+type Codec interface {
+	Decode(data []byte) (TMsgs, error)
+	// Encode Encodes msgs, the catalog of lang.
+	Encode(msgs TMsgs, lang string) ([]byte, error)
+}
+
+// codecs registered codecs by format name.
+var codecs = map[string]Codec{
+	"json":    jsonCodec{},
+	"po":      poCodec{},
+	"mo":      moCodec{},
+	"yaml":    yamlCodec{},
+	"csv":     csvCodec{},
+	"xliff":   xliffCodec{},
+	"strings": stringsCodec{},
+	"binary":  binaryCodec{},
+}
+
+// formatAliases other file suffixes of formats.
+var formatAliases = map[string]string{
+	"yml": "yaml",
+	"xlf": "xliff",
+	"pot": "po",
+	"bin": "binary",
+}
+
+// RegisterCodec registers codec for format, replacing any codec registered
+// for it before. It is not safe to call concurrently with GetCodec.
+func RegisterCodec(format string, codec Codec) {
+	codecs[format] = codec
+}
+
+// GetCodec Returns the codec of format, which may also be a file suffix such
+// as "yml".
+func GetCodec(format string) (Codec, bool) {
+	if name, ok := formatAliases[format]; ok {
+		format = name
+	}
+	codec, ok := codecs[format]
+	return codec, ok
+}
+
+// Formats Returns the names of the registered formats, sorted.
+func Formats() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// init Sets up ms for conf, reading catalogs with suffix fileSuffix through
+// codec. Sources without codec set loadFunc themselves.
+func (ms *MessageSource) init(conf *Config, fileSuffix string, codec Codec) {
+	ms.OriginalLang = conf.OriginalLang
+	ms.BasePath = conf.BasePath
+	ms.ForceTranslation = conf.ForceTranslation
+	ms.FileMap = conf.FileMap
+	ms.messages = make(map[string]TMsgs)
+	ms.observer = conf.observer
+	ms.validateOnLoad = conf.ValidateOnLoad
+	ms.compileOnLoad = conf.CompileOnLoad
+	ms.patterns = make(map[string]compiledPatterns)
+	ms.missingFiles = conf.MissingFiles
+	ms.normalizeKeys = conf.NormalizeKeys
+	ms.fallbacks = conf.Fallbacks
+	if conf.LoadConcurrency > 1 {
+		ms.fetches = make(chan struct{}, conf.LoadConcurrency)
+	}
+	if conf.Version != "" {
+		version := conf.Version
+		ms.version.Store(&version)
+	}
+	ms.fileSuffix = fileSuffix
+	if codec == nil {
+		ms.store = newCatalogStore()
+		return
+	}
+	ms.shareStore(conf, fileSuffix)
+	verifyKey, keys, manifest := conf.VerifyKey, conf.DecryptKeys, conf.Manifest
+	ms.readFile = func(filename string) ([]byte, error) {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, &LoadError{Path: filename, Err: err}
+		}
+		if manifest != "" {
+			if err := verifyChecksum(ms.dir(), manifest, filename, data); err != nil {
+				return nil, err
+			}
+		}
+		if verifyKey != nil {
+			if err := verifyFile(filename, data, verifyKey); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := IsEncryptedCatalog(data); ok {
+			if keys == nil {
+				return nil, &LoadError{Path: filename, Err: errors.New("encrypted catalog without Config.DecryptKeys")}
+			}
+			if data, err = DecryptCatalog(data, keys); err != nil {
+				return nil, &LoadError{Path: filename, Err: err}
+			}
+		}
+		if data, err = DecodeText(data); err != nil {
+			return nil, &LoadError{Path: filename, Err: err}
+		}
+		return data, nil
+	}
+	ms.loadFunc = func(filename string) (TMsgs, error) {
+		data, err := ms.readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := codec.Decode(data)
+		if err != nil {
+			return nil, newLoadError(filename, err)
+		}
+		return msgs, nil
+	}
+	ms.saveFunc = func(filename string, lang string, msgs TMsgs) error {
+		if data, err := os.ReadFile(filename); err == nil {
+			if _, ok := IsEncryptedCatalog(data); ok {
+				// Saving would write the messages in the clear.
+				return &LoadError{Path: filename, Err: errors.New("cannot save into an encrypted catalog")}
+			}
+		}
+		data, err := encodeOver(codec, filename, msgs, lang)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(filename, data, 0644)
+	}
+}
+
+// encodeOver Encodes msgs, the messages of filename with the keys to add, as
+// the new content of filename. Codecs keeping comments keep the entries
+// already in the file as they are, since msgs holds their decoded values:
+// the empty value of a fuzzy PO entry or the pattern of plural forms would
+// lose the translation or the forms.
+func encodeOver(codec Codec, filename string, msgs TMsgs, lang string) ([]byte, error) {
+	cc, ok := codec.(CatalogCodec)
+	if !ok {
+		return codec.Encode(msgs, lang)
+	}
+	c := &Catalog{}
+	if data, err := os.ReadFile(filename); err == nil {
+		if data, err = DecodeText(data); err != nil {
+			return nil, &LoadError{Path: filename, Err: err}
+		}
+		if c, err = cc.DecodeCatalog(data); err != nil {
+			return nil, newLoadError(filename, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	c.Add(msgs)
+	return cc.EncodeCatalog(c, lang)
+}
+
+// sortedKeys Returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,449 @@
+package ii18n
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	msgs := TMsgs{
+		"hello":                 "世界",
+		"Hi {name}, \"quoted\"": "Hallo {name}, \"zitiert\"",
+		"two\nlines":            "zwei\nZeilen",
+		"It's: # not a comment": "",
+	}
+	for _, format := range Formats() {
+		codec, _ := GetCodec(format)
+		data, err := codec.Encode(msgs, "de-DE")
+		if err != nil {
+			t.Errorf("%s: Encode: %v", format, err)
+			continue
+		}
+		decoded, err := codec.Decode(data)
+		if err != nil {
+			t.Errorf("%s: Decode: %v\n%s", format, err, data)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, msgs) {
+			t.Errorf("%s: round trip = %q, want %q", format, decoded, msgs)
+		}
+	}
+}
+
+func TestDecodePO(t *testing.T) {
+	data := []byte(`msgid ""
+msgstr "Language: de\n"
+
+#: main.go:1
+msgid "hello"
+msgstr "hallo"
+
+#, fuzzy
+msgid "bye"
+msgstr "tschüss"
+msgid "multi"
+msgstr ""
+"a"
+"b"
+
+#~ msgid "old"
+#~ msgstr "alt"
+`)
+	msgs, err := poCodec{}.Decode(data)
+	expected := TMsgs{"hello": "hallo", "bye": "", "multi": "ab"}
+	if err != nil || !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("Decode = %q, %v", msgs, err)
+	}
+}
+
+func TestCatalogCodecKeepsComments(t *testing.T) {
+	po := []byte(`msgid ""
+msgstr ""
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+# translator note
+#: main.go:3
+#, fuzzy
+#| msgid "bb"
+msgid "b"
+msgstr "B"
+
+msgid "a"
+msgstr "A"
+`)
+	c, err := poCodec{}.DecodeCatalog(po)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Update(TMsgs{"a": "AA", "b": "B", "c": ""})
+	data, _ := poCodec{}.EncodeCatalog(c, "de")
+	expected := `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "a"
+msgstr "AA"
+
+# translator note
+#: main.go:3
+#, fuzzy
+#| msgid "bb"
+msgid "b"
+msgstr "B"
+
+msgid "c"
+msgstr ""
+`
+	if string(data) != expected {
+		t.Errorf("EncodeCatalog =\n%s\nwant\n%s", data, expected)
+	}
+
+	yaml := []byte("# header\n\n# greeting\nhello: Hallo\n")
+	yc, err := yamlCodec{}.DecodeCatalog(yaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := (yamlCodec{}).EncodeCatalog(yc, "de"); string(data) != "# greeting\n\"hello\": \"Hallo\"\n" {
+		t.Errorf("yaml EncodeCatalog = %q", data)
+	}
+}
+
+func TestSaveMsgsKeepsPO(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/pl", 0755)
+	po := `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "# file"
+msgid_plural "# files"
+msgstr[0] "# plik"
+msgstr[1] "# pliki"
+msgstr[2] "# plików"
+
+# check the wording
+#, fuzzy
+msgid "Save"
+msgstr "Zapisz"
+`
+	os.WriteFile(dir+"/pl/app.po", []byte(po), 0644)
+	s := NewPOSource(&Config{BasePath: dir, FileMap: map[string]string{}})
+	if err := s.(Saver).SaveMsgs("app.app", "pl", TMsgs{"Open": "", "Save": ""}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(dir + "/pl/app.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(po, "\n# check", "\nmsgid \"Open\"\nmsgstr \"\"\n\n# check", 1)
+	if string(data) != expected {
+		t.Errorf("catalog after SaveMsgs =\n%s\nwant\n%s", data, expected)
+	}
+	msgs, err := s.LoadMsgs("app.app", "pl")
+	if err != nil || msgs["Save"] != "" || !strings.Contains(msgs["# file"], "1 {# pliki}") {
+		t.Errorf("LoadMsgs() after SaveMsgs = %q, %v", msgs, err)
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	pf, err := ParsePluralForms("nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, expected := range map[int64]int{1: 0, 21: 0, 11: 2, 3: 1, 14: 2, 22: 1, 5: 2, 0: 2} {
+		if actual := pf.Index(n); actual != expected {
+			t.Errorf("Index(%d) = %d, want %d", n, actual, expected)
+		}
+	}
+	for _, header := range []string{"nplurals=2;", "nplurals=2; plural=n >;", "nplurals=2; plural=(n != 1;"} {
+		if _, err := ParsePluralForms(header); err == nil {
+			t.Errorf("ParsePluralForms(%q) expected error", header)
+		}
+	}
+
+	po := []byte(`msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "# file"
+msgid_plural "# files"
+msgstr[0] "# plik"
+msgstr[1] "# pliki"
+msgstr[2] "# plików"
+`)
+	msgs, err := poCodec{}.Decode(po)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFormatter()
+	for n, expected := range map[string]string{"1": "1 plik", "3": "3 pliki", "25": "25 plików"} {
+		if actual, err := f.format(msgs["# file"], map[string]string{"n": n}, "pl"); actual != expected {
+			t.Errorf("format(%q, n=%s) = %q, %v, want %q", msgs["# file"], n, actual, err, expected)
+		}
+	}
+
+	// MO files without Plural-Forms use the gettext default (n != 1).
+	mo, _ := moCodec{}.Encode(TMsgs{"# day\x00# days": "# Tag\x00# Tage"}, "de")
+	msgs, err = moCodec{}.Decode(mo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, _ := f.format(msgs["# day"], map[string]string{"n": "2"}, "de"); actual != "2 Tage" {
+		t.Errorf("mo plural = %q from %q", actual, msgs["# day"])
+	}
+
+	c, _ := poCodec{}.DecodeCatalog(po)
+	data, _ := poCodec{}.EncodeCatalog(c, "pl")
+	if !strings.Contains(string(data), "msgid_plural \"# files\"\nmsgstr[0] \"# plik\"\nmsgstr[1] \"# pliki\"\nmsgstr[2] \"# plików\"\n") {
+		t.Errorf("EncodeCatalog lost plural forms:\n%s", data)
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	utf16le := func(s string, bom bool) []byte {
+		var b []byte
+		if bom {
+			b = append(b, 0xff, 0xfe)
+		}
+		for _, u := range utf16.Encode([]rune(s)) {
+			b = append(b, byte(u), byte(u>>8))
+		}
+		return b
+	}
+	entry := `"hello" = "Grüß dich";`
+	for name, data := range map[string][]byte{
+		"utf-8 bom":      append([]byte{0xef, 0xbb, 0xbf}, entry...),
+		"utf-16le bom":   utf16le(entry, true),
+		"utf-16le plain": utf16le(entry, false),
+		"utf-8":          []byte(entry),
+	} {
+		text, err := DecodeText(data)
+		if err != nil || string(text) != entry {
+			t.Errorf("%s: DecodeText = %q, %v", name, text, err)
+		}
+	}
+	mo, _ := moCodec{}.Encode(TMsgs{"a": "b"}, "de")
+	if text, _ := DecodeText(mo); !bytes.Equal(text, mo) {
+		t.Error("DecodeText changed a MO file")
+	}
+
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte("\xef\xbb\xbf{\"a\": \"A\",\n \"b\" \"B\"}"), 0644)
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir})
+	_, err := s.LoadMsgs("app.app", "de")
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || loadErr.Offset != 17 {
+		t.Errorf("LoadMsgs = %v", err)
+	}
+}
+
+func FuzzParsePO(f *testing.F) {
+	f.Add([]byte("msgid \"\"\nmsgstr \"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n\n#, fuzzy\nmsgid \"hello\"\nmsgstr \"world\"\n"))
+	f.Add([]byte("msgctxt \"menu\"\nmsgid \"file\"\nmsgid_plural \"files\"\nmsgstr[0] \"Datei\"\nmsgstr[1] \"Dateien\"\n"))
+	f.Add([]byte("#~ msgid \"old\"\nmsgid \"a\\tb\\\"\"\n\"continued\"\nmsgstr \"\"\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msgs, err := poCodec{}.Decode(data)
+		poCodec{}.DecodeCatalog(data)
+		if err != nil {
+			return
+		}
+		encoded, err := poCodec{}.Encode(msgs, "de")
+		if err != nil {
+			t.Fatalf("Encode(%q) = %v", msgs, err)
+		}
+		if _, err := (poCodec{}).Decode(encoded); err != nil {
+			t.Fatalf("Decode(Encode(%q)) = %v\n%s", msgs, err, encoded)
+		}
+	})
+}
+
+func FuzzParseJSONCatalog(f *testing.F) {
+	f.Add([]byte(`{"hello": "world", "nice": "ok"}`))
+	f.Add([]byte("\xef\xbb\xbf{\"a\": \"\\u00e9\"}"))
+	f.Add([]byte("\xff\xfe{\x00}\x00"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		text, err := DecodeText(data)
+		if err != nil {
+			return
+		}
+		msgs, err := jsonCodec{}.Decode(text)
+		if err != nil {
+			return
+		}
+		encoded, err := jsonCodec{}.Encode(msgs, "de")
+		if err != nil {
+			t.Fatalf("Encode(%q) = %v", msgs, err)
+		}
+		decoded, err := jsonCodec{}.Decode(encoded)
+		if err != nil || !reflect.DeepEqual(decoded, msgs) && len(msgs) > 0 {
+			t.Fatalf("Decode(Encode(%q)) = %q, %v", msgs, decoded, err)
+		}
+	})
+}
+
+func benchmarkDecode(b *testing.B, format string) {
+	msgs := make(TMsgs, 1000)
+	for i := 0; i < 1000; i++ {
+		msgs["message "+strconv.Itoa(i)] = "Nachricht {name} " + strconv.Itoa(i)
+	}
+	codec, _ := GetCodec(format)
+	data, err := codec.Encode(msgs, "de")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := codec.Decode(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadJSON(b *testing.B) { benchmarkDecode(b, "json") }
+
+func BenchmarkLoadPO(b *testing.B) { benchmarkDecode(b, "po") }
+
+func BenchmarkLoadYAML(b *testing.B) { benchmarkDecode(b, "yaml") }
+
+func BenchmarkLoadBinary(b *testing.B) { benchmarkDecode(b, "binary") }
+
+func TestSignedCatalogs(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.MkdirAll(dir+"/fr", 0755)
+	os.MkdirAll(dir+"/es", 0755)
+	de := []byte(`{"Cart": "Warenkorb"}`)
+	os.WriteFile(dir+"/de/shop.json", de, 0644)
+	os.WriteFile(dir+"/de/shop.json.sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, de))), 0644)
+	os.WriteFile(dir+"/fr/shop.json", []byte(`{"Cart": "<script>"}`), 0644)
+	os.WriteFile(dir+"/fr/shop.json.sig", ed25519.Sign(priv, []byte(`{"Cart": "Panier"}`)), 0644)
+	os.WriteFile(dir+"/es/shop.json", []byte(`{"Cart": "Carrito"}`), 0644)
+
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{}, VerifyKey: pub})
+	if got, err := s.Translate("app.shop", "Cart", "de"); err != nil || got != "Warenkorb" {
+		t.Errorf("Translate(de) = %q, %v", got, err)
+	}
+	for _, lang := range []string{"fr", "es"} {
+		if _, err := s.Translate("app.shop", "Cart", lang); !errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrCatalogNotFound) {
+			t.Errorf("Translate(%s) error = %v, want ErrInvalidSignature", lang, err)
+		}
+	}
+
+	// A minisign -l signature and public key.
+	keyID := []byte("8bytesid")
+	sig := ed25519.Sign(priv, de)
+	comment := "timestamp:1700000000"
+	minisig := "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), sig...)) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))) + "\n"
+	key, err := ParsePublicKey("untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySignature(de, []byte(minisig), key); err != nil {
+		t.Errorf("VerifySignature(minisign) = %v", err)
+	}
+	tampered := strings.Replace(minisig, comment, "timestamp:1", 1)
+	if err := VerifySignature(de, []byte(tampered), key); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifySignature(tampered comment) = %v", err)
+	}
+}
+
+func TestEncryptedCatalogs(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 16)
+	dir := t.TempDir()
+	for lang, keyID := range map[string]string{"de": "2023", "fr": "2024"} {
+		key := map[string][]byte{"2023": oldKey, "2024": newKey}[keyID]
+		data, err := EncryptCatalog([]byte(`{"Codename": "Projekt `+lang+`"}`), keyID, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte("Projekt")) {
+			t.Fatal("EncryptCatalog() left the catalog in the clear")
+		}
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.json", data, 0644)
+	}
+	os.MkdirAll(dir+"/es", 0755)
+	os.WriteFile(dir+"/es/app.json", []byte(`{"Codename": "Proyecto"}`), 0644)
+
+	keys := StaticKeys(map[string][]byte{"2023": oldKey, "2024": newKey})
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{}, DecryptKeys: keys})
+	for lang, want := range map[string]string{"de": "Projekt de", "fr": "Projekt fr", "es": "Proyecto"} {
+		if got, err := s.Translate("app.app", "Codename", lang); err != nil || got != want {
+			t.Errorf("Translate(%s) = %q, %v, want %q", lang, got, err, want)
+		}
+	}
+	if err := s.(Saver).SaveMsgs("app.app", "de", TMsgs{"New": ""}); err == nil {
+		t.Error("SaveMsgs() into an encrypted catalog = nil error")
+	}
+
+	rotated := StaticKeys(map[string][]byte{"2024": newKey})
+	s = NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{}, DecryptKeys: rotated})
+	if _, err := s.Translate("app.app", "Codename", "de"); err == nil {
+		t.Error("Translate() with a retired key = nil error")
+	}
+	data, _ := os.ReadFile(dir + "/fr/app.json")
+	data[len(data)-1] ^= 1
+	if _, err := DecryptCatalog(data, keys); err == nil {
+		t.Error("DecryptCatalog(tampered) = nil error")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	catalogs := map[string]string{
+		"de/shop.json": `{"Cart": "Warenkorb"}`,
+		"fr/shop.json": `{"Cart": "Panier"}`,
+		"es/shop.json": `{"Cart": "Carrito"}`,
+	}
+	var manifest strings.Builder
+	for _, path := range []string{"de/shop.json", "fr/shop.json"} {
+		sum := sha256.Sum256([]byte(catalogs[path]))
+		manifest.WriteString(hex.EncodeToString(sum[:]) + "  " + path + "\n")
+	}
+	for path, data := range catalogs {
+		os.MkdirAll(dir+"/"+path[:2], 0755)
+		os.WriteFile(dir+"/"+path, []byte(data), 0644)
+	}
+	os.WriteFile(dir+"/fr/shop.json", []byte(`{"Cart": "Pan`), 0644)
+	os.WriteFile(dir+"/SHA256SUMS", []byte(manifest.String()), 0644)
+
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{}, Manifest: "SHA256SUMS"})
+	if got, err := s.Translate("app.shop", "Cart", "de"); err != nil || got != "Warenkorb" {
+		t.Errorf("Translate(de) = %q, %v", got, err)
+	}
+	for _, lang := range []string{"fr", "es"} {
+		_, err := s.Translate("app.shop", "Cart", lang)
+		var le *LoadError
+		if !errors.Is(err, ErrChecksumMismatch) || !errors.As(err, &le) || le.Path != dir+"/"+lang+"/shop.json" {
+			t.Errorf("Translate(%s) error = %v, want ErrChecksumMismatch of the file", lang, err)
+		}
+	}
+
+	sums, err := ParseManifest([]byte(`{"./de/shop.json": "` + strings.Repeat("AB", 32) + `"}`))
+	if err != nil || sums["de/shop.json"] != strings.Repeat("ab", 32) {
+		t.Errorf("ParseManifest(json) = %v, %v", sums, err)
+	}
+	if _, err := ParseManifest([]byte("abc  de/shop.json\n")); err == nil {
+		t.Error("ParseManifest(short checksum) = nil error")
+	}
+}
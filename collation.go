@@ -0,0 +1,230 @@
+package ii18n
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CollationStrength the differences a Collator tells apart, each level
+// deciding only between strings equal at the levels before it.
+type CollationStrength int
+
+// Collation strengths.
+const (
+	// CollateTertiary base letters, then accents, then case: "role" <
+	// "Role" < "rôle".
+	CollateTertiary CollationStrength = iota
+	// CollateSecondary base letters, then accents, ignoring case.
+	CollateSecondary
+	// CollatePrimary base letters, ignoring accents and case.
+	CollatePrimary
+)
+
+// Collator compares strings in the alphabetical order of a language, a
+// simplification of the Unicode Collation Algorithm: letters sort by their
+// base letter as tailored by the language, so "ä" sorts with "a" in German
+// but after "z" in Swedish, then by their accents, then lower case first.
+// Spaces, punctuation and digits sort before letters, by code point.
+type Collator struct {
+	Strength CollationStrength
+	table    *collationTable
+}
+
+// collationTable the weights of a language, see collationTailorings.
+type collationTable struct {
+	// letters the tailored letters, lower case, and their weights.
+	letters map[string]collationWeight
+	// contractions whether letters has entries of two runes.
+	contractions bool
+}
+
+// collationWeight the primary and secondary weight of a letter.
+type collationWeight struct {
+	primary, secondary uint32
+}
+
+// Collation weights. Letters weigh letterWeight plus eight times their code
+// point, leaving room for the letters tailored after them; the secondary
+// weight of letters is secondaryBase, that of marks the mark.
+const (
+	letterWeight  = 0x110000
+	secondaryBase = 1
+	// tailoredMark the secondary weight of the letters tailored to sort as
+	// another, above every mark.
+	tailoredMark = 0x110000
+)
+
+// collationExpansions the letters without decomposition that sort as other
+// letters, followed by the mark of their secondary difference, or 0 for a
+// tertiary one.
+var collationExpansions = map[rune]string{
+	'ß': "ss\u0000", 'æ': "ae\u0000", 'œ': "oe\u0000",
+	'ø': "o\u0338", 'ł': "l\u0338", 'đ': "d\u0338", 'ħ': "h\u0338", 'ı': "i\u0000",
+	'ð': "d\u0000", 'þ': "th\u0000",
+}
+
+// collationTables the parsed tables by language.
+var collationTables sync.Map
+
+// NewCollator Returns a Collator of lang at CollateTertiary strength.
+func NewCollator(lang string) *Collator {
+	base := strings.ToLower(baseLang(lang))
+	if table, ok := collationTables.Load(base); ok {
+		return &Collator{table: table.(*collationTable)}
+	}
+	table := parseTailoring(collationTailorings[base])
+	collationTables.Store(base, table)
+	return &Collator{table: table}
+}
+
+// parseTailoring Returns the table of the collation rules, see
+// collationTailorings.
+func parseTailoring(rules string) *collationTable {
+	table := &collationTable{letters: make(map[string]collationWeight)}
+	for _, rule := range strings.Fields(rules) {
+		parts := strings.FieldsFunc(rule[1:], func(r rune) bool { return r == '<' || r == '=' })
+		anchor := table.weight(parts[0])
+		if strings.Contains(rule, "=") {
+			table.letters[parts[1]] = collationWeight{anchor.primary, tailoredMark}
+			continue
+		}
+		for n, letter := range parts[1:] {
+			table.letters[letter] = collationWeight{anchor.primary + uint32(n) + 1, secondaryBase}
+			table.contractions = table.contractions || utf8.RuneCountInString(letter) > 1
+		}
+	}
+	return table
+}
+
+// weight Returns the weight of the lower case letter.
+func (t *collationTable) weight(letter string) collationWeight {
+	if w, ok := t.letters[letter]; ok {
+		return w
+	}
+	r, _ := utf8.DecodeRuneInString(letter)
+	if !unicode.IsLetter(r) {
+		return collationWeight{uint32(r) + 1, secondaryBase}
+	}
+	return collationWeight{letterWeight + uint32(r)*8, secondaryBase}
+}
+
+// Compare Returns -1, 0 or +1 as a sorts before, with or after b.
+func (c *Collator) Compare(a string, b string) int {
+	return slices.Compare(c.key(a), c.key(b))
+}
+
+// Less Reports whether a sorts before b.
+func (c *Collator) Less(a string, b string) bool {
+	return c.Compare(a, b) < 0
+}
+
+// Sort Sorts items in collation order, keeping the order of equal items.
+func (c *Collator) Sort(items []string) {
+	keys := make([][]uint32, len(items))
+	for n, item := range items {
+		keys[n] = c.key(item)
+	}
+	sort.Stable(collationSort{items, keys})
+}
+
+// collationSort items with their collation keys.
+type collationSort struct {
+	items []string
+	keys  [][]uint32
+}
+
+func (s collationSort) Len() int           { return len(s.items) }
+func (s collationSort) Less(i, j int) bool { return slices.Compare(s.keys[i], s.keys[j]) < 0 }
+func (s collationSort) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// key Returns the sort key of s: the primary weights of its letters, then
+// down to the strength of c the secondary and the tertiary ones, each level
+// ended by 0.
+func (c *Collator) key(s string) []uint32 {
+	var primary, secondary, tertiary []uint32
+	add := func(w collationWeight, upper bool) {
+		primary = append(primary, w.primary)
+		secondary = append(secondary, w.secondary)
+		if upper {
+			tertiary = append(tertiary, 2)
+		} else {
+			tertiary = append(tertiary, 1)
+		}
+	}
+	mark := func(m rune) {
+		if m != 0 {
+			secondary = append(secondary, uint32(m))
+		} else {
+			tertiary = append(tertiary, 3)
+		}
+	}
+	runes := []rune(s)
+	for n := 0; n < len(runes); n++ {
+		r := runes[n]
+		lower := unicode.ToLower(r)
+		upper := r != lower
+		if c.table.contractions && n+1 < len(runes) {
+			if w, ok := c.table.letters[string([]rune{lower, unicode.ToLower(runes[n+1])})]; ok {
+				add(w, upper)
+				n++
+				continue
+			}
+		}
+		if w, ok := c.table.letters[string(lower)]; ok {
+			add(w, upper)
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			mark(r)
+			continue
+		}
+		if r == 'ς' {
+			add(c.table.weight("σ"), false)
+			mark(0)
+			continue
+		}
+		if d, ok := collationDecompositions[r]; ok {
+			base, size := utf8.DecodeRuneInString(d)
+			add(c.table.weight(string(unicode.ToLower(base))), upper)
+			for _, m := range d[size:] {
+				mark(m)
+			}
+			continue
+		}
+		if e, ok := collationExpansions[lower]; ok {
+			letters := []rune(e)
+			for _, l := range letters[:len(letters)-1] {
+				add(c.table.weight(string(l)), upper)
+			}
+			mark(letters[len(letters)-1])
+			continue
+		}
+		add(c.table.weight(string(lower)), upper)
+	}
+	key := append(primary, 0)
+	if c.Strength <= CollateSecondary {
+		key = append(append(key, secondary...), 0)
+	}
+	if c.Strength <= CollateTertiary {
+		key = append(key, tertiary...)
+	}
+	return key
+}
+
+// SortStrings Sorts items in the alphabetical order of lang, see Collator.
+func SortStrings(items []string, lang string) {
+	NewCollator(lang).Sort(items)
+}
+
+// Less Reports whether a sorts before b in the alphabetical order of lang,
+// see Collator.
+func Less(a string, b string, lang string) bool {
+	return NewCollator(lang).Less(a, b)
+}
@@ -0,0 +1,140 @@
+package ii18n
+
+// collationDecompositions the canonical decompositions of the accented
+// Latin and Greek letters, and of ё, into a base letter and combining marks,
+// which collation weighs as secondary differences.
+var collationDecompositions = map[rune]string{
+	'À': "A\u0300", 'Á': "A\u0301", 'Â': "A\u0302", 'Ã': "A\u0303", 'Ä': "A\u0308",
+	'Å': "A\u030a", 'Ç': "C\u0327", 'È': "E\u0300", 'É': "E\u0301", 'Ê': "E\u0302",
+	'Ë': "E\u0308", 'Ì': "I\u0300", 'Í': "I\u0301", 'Î': "I\u0302", 'Ï': "I\u0308",
+	'Ñ': "N\u0303", 'Ò': "O\u0300", 'Ó': "O\u0301", 'Ô': "O\u0302", 'Õ': "O\u0303",
+	'Ö': "O\u0308", 'Ù': "U\u0300", 'Ú': "U\u0301", 'Û': "U\u0302", 'Ü': "U\u0308",
+	'Ý': "Y\u0301", 'à': "a\u0300", 'á': "a\u0301", 'â': "a\u0302", 'ã': "a\u0303",
+	'ä': "a\u0308", 'å': "a\u030a", 'ç': "c\u0327", 'è': "e\u0300", 'é': "e\u0301",
+	'ê': "e\u0302", 'ë': "e\u0308", 'ì': "i\u0300", 'í': "i\u0301", 'î': "i\u0302",
+	'ï': "i\u0308", 'ñ': "n\u0303", 'ò': "o\u0300", 'ó': "o\u0301", 'ô': "o\u0302",
+	'õ': "o\u0303", 'ö': "o\u0308", 'ù': "u\u0300", 'ú': "u\u0301", 'û': "u\u0302",
+	'ü': "u\u0308", 'ý': "y\u0301", 'ÿ': "y\u0308", 'Ā': "A\u0304", 'ā': "a\u0304",
+	'Ă': "A\u0306", 'ă': "a\u0306", 'Ą': "A\u0328", 'ą': "a\u0328", 'Ć': "C\u0301",
+	'ć': "c\u0301", 'Ĉ': "C\u0302", 'ĉ': "c\u0302", 'Ċ': "C\u0307", 'ċ': "c\u0307",
+	'Č': "C\u030c", 'č': "c\u030c", 'Ď': "D\u030c", 'ď': "d\u030c", 'Ē': "E\u0304",
+	'ē': "e\u0304", 'Ĕ': "E\u0306", 'ĕ': "e\u0306", 'Ė': "E\u0307", 'ė': "e\u0307",
+	'Ę': "E\u0328", 'ę': "e\u0328", 'Ě': "E\u030c", 'ě': "e\u030c", 'Ĝ': "G\u0302",
+	'ĝ': "g\u0302", 'Ğ': "G\u0306", 'ğ': "g\u0306", 'Ġ': "G\u0307", 'ġ': "g\u0307",
+	'Ģ': "G\u0327", 'ģ': "g\u0327", 'Ĥ': "H\u0302", 'ĥ': "h\u0302", 'Ĩ': "I\u0303",
+	'ĩ': "i\u0303", 'Ī': "I\u0304", 'ī': "i\u0304", 'Ĭ': "I\u0306", 'ĭ': "i\u0306",
+	'Į': "I\u0328", 'į': "i\u0328", 'İ': "I\u0307", 'Ĵ': "J\u0302", 'ĵ': "j\u0302",
+	'Ķ': "K\u0327", 'ķ': "k\u0327", 'Ĺ': "L\u0301", 'ĺ': "l\u0301", 'Ļ': "L\u0327",
+	'ļ': "l\u0327", 'Ľ': "L\u030c", 'ľ': "l\u030c", 'Ń': "N\u0301", 'ń': "n\u0301",
+	'Ņ': "N\u0327", 'ņ': "n\u0327", 'Ň': "N\u030c", 'ň': "n\u030c", 'Ō': "O\u0304",
+	'ō': "o\u0304", 'Ŏ': "O\u0306", 'ŏ': "o\u0306", 'Ő': "O\u030b", 'ő': "o\u030b",
+	'Ŕ': "R\u0301", 'ŕ': "r\u0301", 'Ŗ': "R\u0327", 'ŗ': "r\u0327", 'Ř': "R\u030c",
+	'ř': "r\u030c", 'Ś': "S\u0301", 'ś': "s\u0301", 'Ŝ': "S\u0302", 'ŝ': "s\u0302",
+	'Ş': "S\u0327", 'ş': "s\u0327", 'Š': "S\u030c", 'š': "s\u030c", 'Ţ': "T\u0327",
+	'ţ': "t\u0327", 'Ť': "T\u030c", 'ť': "t\u030c", 'Ũ': "U\u0303", 'ũ': "u\u0303",
+	'Ū': "U\u0304", 'ū': "u\u0304", 'Ŭ': "U\u0306", 'ŭ': "u\u0306", 'Ů': "U\u030a",
+	'ů': "u\u030a", 'Ű': "U\u030b", 'ű': "u\u030b", 'Ų': "U\u0328", 'ų': "u\u0328",
+	'Ŵ': "W\u0302", 'ŵ': "w\u0302", 'Ŷ': "Y\u0302", 'ŷ': "y\u0302", 'Ÿ': "Y\u0308",
+	'Ź': "Z\u0301", 'ź': "z\u0301", 'Ż': "Z\u0307", 'ż': "z\u0307", 'Ž': "Z\u030c",
+	'ž': "z\u030c", 'Ơ': "O\u031b", 'ơ': "o\u031b", 'Ư': "U\u031b", 'ư': "u\u031b",
+	'Ǎ': "A\u030c", 'ǎ': "a\u030c", 'Ǐ': "I\u030c", 'ǐ': "i\u030c", 'Ǒ': "O\u030c",
+	'ǒ': "o\u030c", 'Ǔ': "U\u030c", 'ǔ': "u\u030c", 'Ǖ': "U\u0308\u0304",
+	'ǖ': "u\u0308\u0304", 'Ǘ': "U\u0308\u0301", 'ǘ': "u\u0308\u0301", 'Ǚ': "U\u0308\u030c",
+	'ǚ': "u\u0308\u030c", 'Ǜ': "U\u0308\u0300", 'ǜ': "u\u0308\u0300", 'Ǟ': "A\u0308\u0304",
+	'ǟ': "a\u0308\u0304", 'Ǡ': "A\u0307\u0304", 'ǡ': "a\u0307\u0304", 'Ǣ': "Æ\u0304",
+	'ǣ': "æ\u0304", 'Ǧ': "G\u030c", 'ǧ': "g\u030c", 'Ǩ': "K\u030c", 'ǩ': "k\u030c",
+	'Ǫ': "O\u0328", 'ǫ': "o\u0328", 'Ǭ': "O\u0328\u0304", 'ǭ': "o\u0328\u0304",
+	'Ǯ': "Ʒ\u030c", 'ǯ': "ʒ\u030c", 'ǰ': "j\u030c", 'Ǵ': "G\u0301", 'ǵ': "g\u0301",
+	'Ǹ': "N\u0300", 'ǹ': "n\u0300", 'Ǻ': "A\u030a\u0301", 'ǻ': "a\u030a\u0301",
+	'Ǽ': "Æ\u0301", 'ǽ': "æ\u0301", 'Ǿ': "Ø\u0301", 'ǿ': "ø\u0301", 'Ȁ': "A\u030f",
+	'ȁ': "a\u030f", 'Ȃ': "A\u0311", 'ȃ': "a\u0311", 'Ȅ': "E\u030f", 'ȅ': "e\u030f",
+	'Ȇ': "E\u0311", 'ȇ': "e\u0311", 'Ȉ': "I\u030f", 'ȉ': "i\u030f", 'Ȋ': "I\u0311",
+	'ȋ': "i\u0311", 'Ȍ': "O\u030f", 'ȍ': "o\u030f", 'Ȏ': "O\u0311", 'ȏ': "o\u0311",
+	'Ȑ': "R\u030f", 'ȑ': "r\u030f", 'Ȓ': "R\u0311", 'ȓ': "r\u0311", 'Ȕ': "U\u030f",
+	'ȕ': "u\u030f", 'Ȗ': "U\u0311", 'ȗ': "u\u0311", 'Ș': "S\u0326", 'ș': "s\u0326",
+	'Ț': "T\u0326", 'ț': "t\u0326", 'Ȟ': "H\u030c", 'ȟ': "h\u030c", 'Ȧ': "A\u0307",
+	'ȧ': "a\u0307", 'Ȩ': "E\u0327", 'ȩ': "e\u0327", 'Ȫ': "O\u0308\u0304",
+	'ȫ': "o\u0308\u0304", 'Ȭ': "O\u0303\u0304", 'ȭ': "o\u0303\u0304", 'Ȯ': "O\u0307",
+	'ȯ': "o\u0307", 'Ȱ': "O\u0307\u0304", 'ȱ': "o\u0307\u0304", 'Ȳ': "Y\u0304",
+	'ȳ': "y\u0304", 'Ḁ': "A\u0325", 'ḁ': "a\u0325", 'Ḃ': "B\u0307", 'ḃ': "b\u0307",
+	'Ḅ': "B\u0323", 'ḅ': "b\u0323", 'Ḇ': "B\u0331", 'ḇ': "b\u0331", 'Ḉ': "C\u0327\u0301",
+	'ḉ': "c\u0327\u0301", 'Ḋ': "D\u0307", 'ḋ': "d\u0307", 'Ḍ': "D\u0323", 'ḍ': "d\u0323",
+	'Ḏ': "D\u0331", 'ḏ': "d\u0331", 'Ḑ': "D\u0327", 'ḑ': "d\u0327", 'Ḓ': "D\u032d",
+	'ḓ': "d\u032d", 'Ḕ': "E\u0304\u0300", 'ḕ': "e\u0304\u0300", 'Ḗ': "E\u0304\u0301",
+	'ḗ': "e\u0304\u0301", 'Ḙ': "E\u032d", 'ḙ': "e\u032d", 'Ḛ': "E\u0330", 'ḛ': "e\u0330",
+	'Ḝ': "E\u0327\u0306", 'ḝ': "e\u0327\u0306", 'Ḟ': "F\u0307", 'ḟ': "f\u0307",
+	'Ḡ': "G\u0304", 'ḡ': "g\u0304", 'Ḣ': "H\u0307", 'ḣ': "h\u0307", 'Ḥ': "H\u0323",
+	'ḥ': "h\u0323", 'Ḧ': "H\u0308", 'ḧ': "h\u0308", 'Ḩ': "H\u0327", 'ḩ': "h\u0327",
+	'Ḫ': "H\u032e", 'ḫ': "h\u032e", 'Ḭ': "I\u0330", 'ḭ': "i\u0330", 'Ḯ': "I\u0308\u0301",
+	'ḯ': "i\u0308\u0301", 'Ḱ': "K\u0301", 'ḱ': "k\u0301", 'Ḳ': "K\u0323", 'ḳ': "k\u0323",
+	'Ḵ': "K\u0331", 'ḵ': "k\u0331", 'Ḷ': "L\u0323", 'ḷ': "l\u0323", 'Ḹ': "L\u0323\u0304",
+	'ḹ': "l\u0323\u0304", 'Ḻ': "L\u0331", 'ḻ': "l\u0331", 'Ḽ': "L\u032d", 'ḽ': "l\u032d",
+	'Ḿ': "M\u0301", 'ḿ': "m\u0301", 'Ṁ': "M\u0307", 'ṁ': "m\u0307", 'Ṃ': "M\u0323",
+	'ṃ': "m\u0323", 'Ṅ': "N\u0307", 'ṅ': "n\u0307", 'Ṇ': "N\u0323", 'ṇ': "n\u0323",
+	'Ṉ': "N\u0331", 'ṉ': "n\u0331", 'Ṋ': "N\u032d", 'ṋ': "n\u032d", 'Ṍ': "O\u0303\u0301",
+	'ṍ': "o\u0303\u0301", 'Ṏ': "O\u0303\u0308", 'ṏ': "o\u0303\u0308", 'Ṑ': "O\u0304\u0300",
+	'ṑ': "o\u0304\u0300", 'Ṓ': "O\u0304\u0301", 'ṓ': "o\u0304\u0301", 'Ṕ': "P\u0301",
+	'ṕ': "p\u0301", 'Ṗ': "P\u0307", 'ṗ': "p\u0307", 'Ṙ': "R\u0307", 'ṙ': "r\u0307",
+	'Ṛ': "R\u0323", 'ṛ': "r\u0323", 'Ṝ': "R\u0323\u0304", 'ṝ': "r\u0323\u0304",
+	'Ṟ': "R\u0331", 'ṟ': "r\u0331", 'Ṡ': "S\u0307", 'ṡ': "s\u0307", 'Ṣ': "S\u0323",
+	'ṣ': "s\u0323", 'Ṥ': "S\u0301\u0307", 'ṥ': "s\u0301\u0307", 'Ṧ': "S\u030c\u0307",
+	'ṧ': "s\u030c\u0307", 'Ṩ': "S\u0323\u0307", 'ṩ': "s\u0323\u0307", 'Ṫ': "T\u0307",
+	'ṫ': "t\u0307", 'Ṭ': "T\u0323", 'ṭ': "t\u0323", 'Ṯ': "T\u0331", 'ṯ': "t\u0331",
+	'Ṱ': "T\u032d", 'ṱ': "t\u032d", 'Ṳ': "U\u0324", 'ṳ': "u\u0324", 'Ṵ': "U\u0330",
+	'ṵ': "u\u0330", 'Ṷ': "U\u032d", 'ṷ': "u\u032d", 'Ṹ': "U\u0303\u0301",
+	'ṹ': "u\u0303\u0301", 'Ṻ': "U\u0304\u0308", 'ṻ': "u\u0304\u0308", 'Ṽ': "V\u0303",
+	'ṽ': "v\u0303", 'Ṿ': "V\u0323", 'ṿ': "v\u0323", 'Ẁ': "W\u0300", 'ẁ': "w\u0300",
+	'Ẃ': "W\u0301", 'ẃ': "w\u0301", 'Ẅ': "W\u0308", 'ẅ': "w\u0308", 'Ẇ': "W\u0307",
+	'ẇ': "w\u0307", 'Ẉ': "W\u0323", 'ẉ': "w\u0323", 'Ẋ': "X\u0307", 'ẋ': "x\u0307",
+	'Ẍ': "X\u0308", 'ẍ': "x\u0308", 'Ẏ': "Y\u0307", 'ẏ': "y\u0307", 'Ẑ': "Z\u0302",
+	'ẑ': "z\u0302", 'Ẓ': "Z\u0323", 'ẓ': "z\u0323", 'Ẕ': "Z\u0331", 'ẕ': "z\u0331",
+	'ẖ': "h\u0331", 'ẗ': "t\u0308", 'ẘ': "w\u030a", 'ẙ': "y\u030a", 'ẛ': "ſ\u0307",
+	'Ạ': "A\u0323", 'ạ': "a\u0323", 'Ả': "A\u0309", 'ả': "a\u0309", 'Ấ': "A\u0302\u0301",
+	'ấ': "a\u0302\u0301", 'Ầ': "A\u0302\u0300", 'ầ': "a\u0302\u0300", 'Ẩ': "A\u0302\u0309",
+	'ẩ': "a\u0302\u0309", 'Ẫ': "A\u0302\u0303", 'ẫ': "a\u0302\u0303", 'Ậ': "A\u0323\u0302",
+	'ậ': "a\u0323\u0302", 'Ắ': "A\u0306\u0301", 'ắ': "a\u0306\u0301", 'Ằ': "A\u0306\u0300",
+	'ằ': "a\u0306\u0300", 'Ẳ': "A\u0306\u0309", 'ẳ': "a\u0306\u0309", 'Ẵ': "A\u0306\u0303",
+	'ẵ': "a\u0306\u0303", 'Ặ': "A\u0323\u0306", 'ặ': "a\u0323\u0306", 'Ẹ': "E\u0323",
+	'ẹ': "e\u0323", 'Ẻ': "E\u0309", 'ẻ': "e\u0309", 'Ẽ': "E\u0303", 'ẽ': "e\u0303",
+	'Ế': "E\u0302\u0301", 'ế': "e\u0302\u0301", 'Ề': "E\u0302\u0300", 'ề': "e\u0302\u0300",
+	'Ể': "E\u0302\u0309", 'ể': "e\u0302\u0309", 'Ễ': "E\u0302\u0303", 'ễ': "e\u0302\u0303",
+	'Ệ': "E\u0323\u0302", 'ệ': "e\u0323\u0302", 'Ỉ': "I\u0309", 'ỉ': "i\u0309",
+	'Ị': "I\u0323", 'ị': "i\u0323", 'Ọ': "O\u0323", 'ọ': "o\u0323", 'Ỏ': "O\u0309",
+	'ỏ': "o\u0309", 'Ố': "O\u0302\u0301", 'ố': "o\u0302\u0301", 'Ồ': "O\u0302\u0300",
+	'ồ': "o\u0302\u0300", 'Ổ': "O\u0302\u0309", 'ổ': "o\u0302\u0309", 'Ỗ': "O\u0302\u0303",
+	'ỗ': "o\u0302\u0303", 'Ộ': "O\u0323\u0302", 'ộ': "o\u0323\u0302", 'Ớ': "O\u031b\u0301",
+	'ớ': "o\u031b\u0301", 'Ờ': "O\u031b\u0300", 'ờ': "o\u031b\u0300", 'Ở': "O\u031b\u0309",
+	'ở': "o\u031b\u0309", 'Ỡ': "O\u031b\u0303", 'ỡ': "o\u031b\u0303", 'Ợ': "O\u031b\u0323",
+	'ợ': "o\u031b\u0323", 'Ụ': "U\u0323", 'ụ': "u\u0323", 'Ủ': "U\u0309", 'ủ': "u\u0309",
+	'Ứ': "U\u031b\u0301", 'ứ': "u\u031b\u0301", 'Ừ': "U\u031b\u0300", 'ừ': "u\u031b\u0300",
+	'Ử': "U\u031b\u0309", 'ử': "u\u031b\u0309", 'Ữ': "U\u031b\u0303", 'ữ': "u\u031b\u0303",
+	'Ự': "U\u031b\u0323", 'ự': "u\u031b\u0323", 'Ỳ': "Y\u0300", 'ỳ': "y\u0300",
+	'Ỵ': "Y\u0323", 'ỵ': "y\u0323", 'Ỷ': "Y\u0309", 'ỷ': "y\u0309", 'Ỹ': "Y\u0303",
+	'ỹ': "y\u0303", 'Ά': "Α\u0301", 'Έ': "Ε\u0301", 'Ή': "Η\u0301", 'Ί': "Ι\u0301",
+	'Ό': "Ο\u0301", 'Ύ': "Υ\u0301", 'Ώ': "Ω\u0301", 'ΐ': "ι\u0308\u0301", 'Ϊ': "Ι\u0308",
+	'Ϋ': "Υ\u0308", 'ά': "α\u0301", 'έ': "ε\u0301", 'ή': "η\u0301", 'ί': "ι\u0301",
+	'ΰ': "υ\u0308\u0301", 'ϊ': "ι\u0308", 'ϋ': "υ\u0308", 'ό': "ο\u0301", 'ύ': "υ\u0301",
+	'ώ': "ω\u0301",
+	'Ё': "\u0415\u0308", 'ё': "\u0435\u0308",
+}
+
+// collationTailorings the collation rules of languages whose alphabets
+// differ from the root order, in the syntax of CLDR rules: "&x<y" sorts y
+// as a letter of its own after x, "&x=y" sorts y as x, with a secondary
+// difference, and a rule may chain several letters, "&z<å<ä<ö". A tailored
+// letter of two runes is a contraction, such as the Czech "ch".
+var collationTailorings = map[string]string{
+	"cs": "&c<č &h<ch &r<ř &s<š &z<ž",
+	"da": "&z<æ<ø<å &æ=ä &ø=ö",
+	"es": "&n<ñ",
+	"fi": "&z<å<ä<ö &ä=æ &ö=ø &y=ü",
+	"is": "&a<á &d<ð &e<é &i<í &o<ó &u<ú &y<ý &z<þ<æ<ö",
+	"nb": "&z<æ<ø<å &æ=ä &ø=ö",
+	"nn": "&z<æ<ø<å &æ=ä &ø=ö",
+	"no": "&z<æ<ø<å &æ=ä &ø=ö",
+	"pl": "&a<ą &c<ć &e<ę &l<ł &n<ń &o<ó &s<ś &z<ź<ż",
+	"sk": "&a<ä &c<č &h<ch &o<ô &r<ř &s<š &z<ž",
+	"sv": "&z<å<ä<ö &ä=æ &ö=ø &y=ü",
+	"tr": "&c<ç &g<ğ &h<ı &o<ö &s<ş &u<ü",
+	"az": "&c<ç &g<ğ &h<ı &o<ö &s<ş &u<ü",
+}
@@ -0,0 +1,88 @@
+package ii18n
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// CompiledMessage a message of a catalog compiled into Go code by ii18n gen,
+// with its pre-parsed pattern.
+type CompiledMessage struct {
+	Text  string
+	Nodes []Node
+}
+
+// CompiledCatalog the messages of a compiled catalog by key.
+type CompiledCatalog map[string]CompiledMessage
+
+// compiled catalogs and pattern nodes registered by generated code.
+var compiled = struct {
+	catalogs map[string]TMsgs
+	patterns map[string][]Node
+	mutex    sync.RWMutex
+}{
+	catalogs: make(map[string]TMsgs),
+	patterns: make(map[string][]Node),
+}
+
+// RegisterCatalog registers a compiled catalog under name, "{lang}/{file}"
+// without file suffix. It is called from the init functions of code
+// generated by ii18n gen.
+func RegisterCatalog(name string, catalog CompiledCatalog) {
+	compiled.mutex.Lock()
+	defer compiled.mutex.Unlock()
+
+	msgs := make(TMsgs, len(catalog))
+	for key, msg := range catalog {
+		msgs[key] = msg.Text
+		if msg.Nodes != nil {
+			compiled.patterns[msg.Text] = msg.Nodes
+		}
+	}
+	compiled.catalogs[name] = msgs
+}
+
+// lookupCompiled Returns the pre-parsed nodes of pattern.
+func lookupCompiled(pattern string) ([]Node, bool) {
+	compiled.mutex.RLock()
+	defer compiled.mutex.RUnlock()
+
+	nodes, ok := compiled.patterns[pattern]
+	return nodes, ok
+}
+
+// Type CompiledSource serves catalogs compiled into the binary by ii18n gen,
+// without file IO or pattern parsing. BasePath only prefixes catalog names.
+type CompiledSource struct {
+	MessageSource
+}
+
+// New CompiledSource
+func NewCompiledSource(conf *Config) Source {
+	s := &CompiledSource{}
+	s.init(conf, "", nil)
+	s.loadFunc = s.loadCompiled
+
+	return s
+}
+
+// loadCompiled Returns the compiled catalog of the message file path.
+func (cs *CompiledSource) loadCompiled(filename string) (TMsgs, error) {
+	name := strings.TrimPrefix(filename, cs.BasePath+"/")
+	name = strings.TrimSuffix(name, path.Ext(name))
+
+	compiled.mutex.RLock()
+	defer compiled.mutex.RUnlock()
+
+	msgs, ok := compiled.catalogs[name]
+	if !ok {
+		return nil, &LoadError{Path: filename, Err: fs.ErrNotExist}
+	}
+	copied := make(TMsgs, len(msgs))
+	for key, val := range msgs {
+		copied[key] = val
+	}
+	return copied, nil
+}
@@ -0,0 +1,425 @@
+package ii18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileConfig the declarative setup read by LoadConfig.
+type FileConfig struct {
+	// Sources by category prefix.
+	Sources map[string]FileSource `json:"sources"`
+	// Reload interval of the sources such as "5m", none when empty.
+	Reload string `json:"reload"`
+	// ParamCheck "warn" or "strict" to check params, see WithParamCheck.
+	ParamCheck      string `json:"paramCheck"`
+	MissingTemplate string `json:"missingTemplate"`
+	DebugMarkers    bool   `json:"debugMarkers"`
+}
+
+// FileSource the declaration of a source in a FileConfig. Its fields are
+// those of Config, with the source named by Format and the enumerations by
+// name.
+type FileSource struct {
+	// Format of the catalogs: json, po, mo, yaml, csv, xliff, strings,
+	// binary, mapped (binary catalogs served by NewMappedSource) or
+	// compiled.
+	Format           string              `json:"format"`
+	OriginalLang     string              `json:"originalLang"`
+	BasePath         string              `json:"basePath"`
+	FileMap          map[string]string   `json:"fileMap"`
+	ForceTranslation bool                `json:"forceTranslation"`
+	RecordMissing    bool                `json:"recordMissing"`
+	MissingSidecar   bool                `json:"missingSidecar"`
+	Fallbacks        map[string][]string `json:"fallbacks"`
+	// NormalizeKeys any of "trim", "space" and "case".
+	NormalizeKeys []string `json:"normalizeKeys"`
+	// MissingFiles "fallback", "ignore" or "error".
+	MissingFiles    string `json:"missingFiles"`
+	ValidateOnLoad  bool   `json:"validateOnLoad"`
+	LoadConcurrency int    `json:"loadConcurrency"`
+	Version         string `json:"version"`
+	// VerifyKey the public key of the catalog signatures, see
+	// ParsePublicKey.
+	VerifyKey string `json:"verifyKey"`
+	Manifest  string `json:"manifest"`
+}
+
+// sourceFormats source constructors by FileSource format.
+var sourceFormats = map[string]func(*Config) Source{
+	"json":     NewJSONSource,
+	"po":       NewPOSource,
+	"mo":       NewMOSource,
+	"yaml":     NewYAMLSource,
+	"csv":      NewCSVSource,
+	"xliff":    NewXLIFFSource,
+	"strings":  NewStringsSource,
+	"binary":   NewBinarySource,
+	"mapped":   NewMappedSource,
+	"lazyjson": NewLazyJSONSource,
+	"compiled": NewCompiledSource,
+}
+
+// LoadConfig Returns a new I18N set up by the JSON or YAML file filename, a
+// FileConfig, then by opts. Relative base paths are relative to the
+// directory of filename. The YAML may use block mappings and sequences and
+// flow sequences of scalars, its errors report the line.
+func LoadConfig(filename string, opts ...Option) (*I18N, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = DecodeText(data); err != nil {
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	var fc FileConfig
+	if ext := filepath.Ext(filename); ext == ".yaml" || ext == ".yml" {
+		node, err := parseYAMLConfig(data)
+		if err == nil {
+			err = decodeYAML(node, reflect.ValueOf(&fc).Elem(), "")
+		}
+		if err != nil {
+			return nil, &LoadError{Path: filename, Err: err}
+		}
+	} else if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, newLoadError(filename, err)
+	}
+	config, fileOpts, err := fc.build(filepath.Dir(filename))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return NewI18N(config, append(fileOpts, opts...)...), nil
+}
+
+// build Returns the configuration and options of fc, resolving relative
+// base paths against dir.
+func (fc *FileConfig) build(dir string) (map[string]Config, []Option, error) {
+	if len(fc.Sources) == 0 {
+		return nil, nil, errors.New("no sources")
+	}
+	config := make(map[string]Config, len(fc.Sources))
+	for _, prefix := range sortedKeys(fc.Sources) {
+		fs := fc.Sources[prefix]
+		conf := Config{
+			OriginalLang:     fs.OriginalLang,
+			BasePath:         fs.BasePath,
+			FileMap:          fs.FileMap,
+			ForceTranslation: fs.ForceTranslation,
+			RecordMissing:    fs.RecordMissing,
+			MissingSidecar:   fs.MissingSidecar,
+			Fallbacks:        fs.Fallbacks,
+			ValidateOnLoad:   fs.ValidateOnLoad,
+			LoadConcurrency:  fs.LoadConcurrency,
+			Version:          fs.Version,
+			Manifest:         fs.Manifest,
+		}
+		if conf.SourceNewFunc = sourceFormats[fs.Format]; conf.SourceNewFunc == nil {
+			return nil, nil, fmt.Errorf("source %s: unknown format %q", prefix, fs.Format)
+		}
+		if conf.BasePath == "" {
+			return nil, nil, fmt.Errorf("source %s: no basePath", prefix)
+		}
+		if !filepath.IsAbs(conf.BasePath) {
+			conf.BasePath = filepath.Join(dir, conf.BasePath)
+		}
+		if conf.FileMap == nil {
+			conf.FileMap = map[string]string{}
+		}
+		if fs.VerifyKey != "" {
+			key, err := ParsePublicKey(fs.VerifyKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("source %s: %w", prefix, err)
+			}
+			conf.VerifyKey = key
+		}
+		for _, n := range fs.NormalizeKeys {
+			switch n {
+			case "trim":
+				conf.NormalizeKeys |= NormalizeTrim
+			case "space":
+				conf.NormalizeKeys |= NormalizeSpace
+			case "case":
+				conf.NormalizeKeys |= NormalizeCase
+			default:
+				return nil, nil, fmt.Errorf("source %s: unknown key normalization %q", prefix, n)
+			}
+		}
+		switch fs.MissingFiles {
+		case "", "fallback":
+			conf.MissingFiles = FallbackOnly
+		case "ignore":
+			conf.MissingFiles = IgnoreMissingFile
+		case "error":
+			conf.MissingFiles = ErrorOnMissingFile
+		default:
+			return nil, nil, fmt.Errorf("source %s: unknown missingFiles %q", prefix, fs.MissingFiles)
+		}
+		config[prefix] = conf
+	}
+	var opts []Option
+	if fc.Reload != "" {
+		interval, err := time.ParseDuration(fc.Reload)
+		if err != nil || interval <= 0 {
+			return nil, nil, fmt.Errorf("invalid reload %q", fc.Reload)
+		}
+		opts = append(opts, WithAutoReload(interval))
+	}
+	switch fc.ParamCheck {
+	case "":
+	case "warn":
+		opts = append(opts, WithParamCheck(false))
+	case "strict":
+		opts = append(opts, WithParamCheck(true))
+	default:
+		return nil, nil, fmt.Errorf("unknown paramCheck %q", fc.ParamCheck)
+	}
+	if fc.MissingTemplate != "" {
+		opts = append(opts, WithMissingTemplate(fc.MissingTemplate))
+	}
+	if fc.DebugMarkers {
+		opts = append(opts, WithDebugMarkers())
+	}
+	return config, opts, nil
+}
+
+// yamlLine a significant line of a YAML document.
+type yamlLine struct {
+	n       int
+	indent  int
+	content string
+}
+
+// yamlNode a value of a YAML configuration with the line it is on.
+type yamlNode struct {
+	line int
+	// value nil, a string, a []*yamlNode or a map[string]*yamlNode.
+	value interface{}
+	// quoted whether the string value was quoted, which makes it a string
+	// even where a boolean or a number is expected.
+	quoted bool
+}
+
+// parseYAMLConfig Parses the YAML subset of configuration files: nested
+// block mappings, block sequences of scalars and flow sequences of scalars.
+// Scalars are typed by the fields they are decoded into, see decodeYAML.
+func parseYAMLConfig(data []byte) (*yamlNode, error) {
+	var lines []yamlLine
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content[0] == '#' || content == "---" || content == "..." {
+			continue
+		}
+		if content[0] == '\t' {
+			return nil, errors.New("line " + strconv.Itoa(n+1) + ": tabs are not allowed in indentation")
+		}
+		lines = append(lines, yamlLine{n: n + 1, indent: len(line) - len(content), content: content})
+	}
+	if len(lines) == 0 {
+		return &yamlNode{line: 1, value: map[string]*yamlNode{}}, nil
+	}
+	node, next, err := parseYAMLBlock(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, errors.New("line " + strconv.Itoa(lines[next].n) + ": unexpected " + lines[next].content)
+	}
+	return node, nil
+}
+
+// isYAMLItem Reports whether the line content is an item of a block
+// sequence.
+func isYAMLItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// parseYAMLBlock Parses the mapping or sequence starting at lines[i] with
+// indentation indent, returning the index of the line after it.
+func parseYAMLBlock(lines []yamlLine, i int, indent int) (*yamlNode, int, error) {
+	errorf := func(n int, msg string) error {
+		return errors.New("line " + strconv.Itoa(n) + ": " + msg)
+	}
+	node := &yamlNode{line: lines[i].n}
+	if isYAMLItem(lines[i].content) {
+		var seq []*yamlNode
+		for ; i < len(lines) && lines[i].indent == indent && isYAMLItem(lines[i].content); i++ {
+			item, err := yamlConfigScalar(strings.TrimSpace(lines[i].content[1:]), lines[i].n)
+			if err != nil {
+				return nil, i, errorf(lines[i].n, err.Error())
+			}
+			seq = append(seq, item)
+		}
+		node.value = seq
+		return node, i, nil
+	}
+	mapping := make(map[string]*yamlNode)
+	for i < len(lines) && lines[i].indent == indent {
+		l := lines[i]
+		key, rest, err := yamlScalar(l.content, true)
+		if err != nil {
+			return nil, i, errorf(l.n, err.Error())
+		}
+		if rest = strings.TrimSpace(rest); !strings.HasPrefix(rest, ":") {
+			return nil, i, errorf(l.n, "expected ':' after key")
+		}
+		if _, ok := mapping[key]; ok {
+			return nil, i, errorf(l.n, "duplicate key "+key)
+		}
+		rest = strings.TrimSpace(rest[1:])
+		i++
+		switch {
+		case rest != "" && rest[0] != '#':
+			if mapping[key], err = yamlConfigScalar(rest, l.n); err != nil {
+				return nil, i, errorf(l.n, err.Error())
+			}
+		case i < len(lines) && (lines[i].indent > indent || lines[i].indent == indent && isYAMLItem(lines[i].content)):
+			// Sequences may be indented as much as their key.
+			if mapping[key], i, err = parseYAMLBlock(lines, i, lines[i].indent); err != nil {
+				return nil, i, err
+			}
+		default:
+			mapping[key] = &yamlNode{line: l.n}
+		}
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, i, errorf(lines[i].n, "unexpected indentation")
+	}
+	node.value = mapping
+	return node, i, nil
+}
+
+// yamlConfigScalar Returns the scalar or flow sequence of scalars s on line
+// n.
+func yamlConfigScalar(s string, n int) (*yamlNode, error) {
+	node := &yamlNode{line: n}
+	if s == "" {
+		return node, nil
+	}
+	if s[0] == '[' {
+		end := strings.LastIndexByte(s, ']')
+		if end == -1 {
+			return nil, errors.New("unterminated sequence")
+		}
+		if rest := strings.TrimSpace(s[end+1:]); rest != "" && rest[0] != '#' {
+			return nil, errors.New("unexpected " + rest)
+		}
+		seq := []*yamlNode{}
+		for inner := strings.TrimSpace(s[1:end]); inner != ""; {
+			item := &yamlNode{line: n}
+			if inner[0] == '"' || inner[0] == '\'' {
+				val, rest, err := yamlScalar(inner, false)
+				if err != nil {
+					return nil, err
+				}
+				if rest = strings.TrimSpace(rest); rest != "" && rest[0] != ',' {
+					return nil, errors.New("unexpected " + rest)
+				}
+				item.value, item.quoted, inner = val, true, strings.TrimPrefix(rest, ",")
+			} else {
+				var val string
+				val, inner, _ = strings.Cut(inner, ",")
+				item.value = strings.TrimSpace(val)
+			}
+			seq = append(seq, item)
+			inner = strings.TrimSpace(inner)
+		}
+		node.value = seq
+		return node, nil
+	}
+	if s[0] == '{' || s[0] == '|' || s[0] == '>' || s[0] == '&' || s[0] == '*' {
+		return nil, errors.New("unsupported value " + s)
+	}
+	val, rest, err := yamlScalar(s, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+		return nil, errors.New("unexpected " + rest)
+	}
+	if node.quoted = s[0] == '"' || s[0] == '\''; node.quoted || val != "~" && val != "null" {
+		node.value = val
+	}
+	return node, nil
+}
+
+// decodeYAML Stores node in v, the FileConfig or one of its fields named
+// name. Plain scalars are booleans or integers when v is, and strings
+// otherwise; null values leave v unchanged and unknown keys are ignored,
+// as they are in JSON.
+func decodeYAML(node *yamlNode, v reflect.Value, name string) error {
+	if node.value == nil {
+		return nil
+	}
+	errorf := func(msg string) error {
+		if name == "" {
+			return errors.New("line " + strconv.Itoa(node.line) + ": " + msg)
+		}
+		return errors.New("line " + strconv.Itoa(node.line) + ": " + name + ": " + msg)
+	}
+	s, isScalar := node.value.(string)
+	switch v.Kind() {
+	case reflect.String:
+		if !isScalar {
+			return errorf("expected a string")
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		if !isScalar || node.quoted || s != "true" && s != "false" {
+			return errorf("expected true or false")
+		}
+		v.SetBool(s == "true")
+	case reflect.Int:
+		n, err := strconv.ParseInt(s, 10, 0)
+		if !isScalar || node.quoted || err != nil {
+			return errorf("expected an integer")
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		seq, ok := node.value.([]*yamlNode)
+		if !ok {
+			return errorf("expected a sequence")
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(seq), len(seq)))
+		for n, item := range seq {
+			if err := decodeYAML(item, v.Index(n), name+"["+strconv.Itoa(n)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map, reflect.Struct:
+		mapping, ok := node.value.(map[string]*yamlNode)
+		if !ok {
+			return errorf("expected a mapping")
+		}
+		if name != "" {
+			name += "."
+		}
+		if v.Kind() == reflect.Struct {
+			for n := 0; n < v.NumField(); n++ {
+				key, _, _ := strings.Cut(v.Type().Field(n).Tag.Get("json"), ",")
+				if item, ok := mapping[key]; ok {
+					if err := decodeYAML(item, v.Field(n), name+key); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(mapping)))
+		for _, key := range sortedKeys(mapping) {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeYAML(mapping[key], elem, name+key); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key), elem)
+		}
+	}
+	return nil
+}
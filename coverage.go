@@ -0,0 +1,107 @@
+package ii18n
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CoverageStats translation progress of one language against the original
+// language of a category.
+type CoverageStats struct {
+	// Total messages in the original language catalog.
+	Total int
+	// Translated messages with a non-empty translation.
+	Translated int
+	// Missing messages absent or empty in the language.
+	Missing int
+	// Stale messages of the language no longer in the original catalog.
+	Stale int
+	// Percent of Total that is translated.
+	Percent float64
+}
+
+// Coverage Returns the coverage of every available language of category,
+// keyed by language. Categories without a dot are resolved like T does.
+// The source of category must implement Catalogs.
+func Coverage(category string) (map[string]CoverageStats, error) {
+	return Translator.Coverage(category)
+}
+
+// Coverage Returns the coverage of every available language of category.
+// Catalogs that do not exist count as empty; other load errors are returned.
+func (i *I18N) Coverage(category string) (map[string]CoverageStats, error) {
+	category = i.normalizeCategory(category)
+	cs, ol, err := i.catalogs(category)
+	if err != nil {
+		return nil, err
+	}
+	langs, err := cs.AvailableLanguages()
+	if err != nil {
+		return nil, err
+	}
+	original, err := loadCatalog(cs, category, ol)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]CoverageStats, len(langs))
+	for _, lang := range langs {
+		msgs, err := loadCatalog(cs, category, lang)
+		if err != nil {
+			return nil, err
+		}
+		result[lang] = coverage(original, msgs)
+	}
+	return result, nil
+}
+
+// catalogs Returns the source of category as Catalogs, with its original
+// language.
+func (i *I18N) catalogs(category string) (Catalogs, string, error) {
+	prefix, _, err := splitCategory(category)
+	if err != nil {
+		return nil, "", err
+	}
+	i.mutex.RLock()
+	_, ok := i.Translations[prefix]
+	i.mutex.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("%w %q: no source", ErrInvalidCategory, category)
+	}
+	s, ol := i.getSource(category)
+	cs, ok := s.(Catalogs)
+	if !ok {
+		return nil, "", fmt.Errorf("%w %q: source does not list its catalogs", ErrInvalidCategory, category)
+	}
+	return cs, ol, nil
+}
+
+// loadCatalog Returns the catalog of category in lang, empty when it does
+// not exist.
+func loadCatalog(cs Catalogs, category string, lang string) (TMsgs, error) {
+	msgs, err := cs.LoadCatalog(category, lang)
+	if errors.Is(err, ErrCatalogNotFound) {
+		return TMsgs{}, nil
+	}
+	return msgs, err
+}
+
+// coverage Compares msgs against the original messages.
+func coverage(original TMsgs, msgs TMsgs) CoverageStats {
+	stats := CoverageStats{Total: len(original)}
+	for key := range original {
+		if msgs[key] != "" {
+			stats.Translated++
+		} else {
+			stats.Missing++
+		}
+	}
+	for key := range msgs {
+		if _, ok := original[key]; !ok {
+			stats.Stale++
+		}
+	}
+	if stats.Total > 0 {
+		stats.Percent = float64(stats.Translated) * 100 / float64(stats.Total)
+	}
+	return stats
+}
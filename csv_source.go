@@ -0,0 +1,60 @@
+package ii18n
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+)
+
+// Type CSVSource reads two-column CSV catalogs.
+type CSVSource struct {
+	MessageSource
+}
+
+// New CSVSource
+func NewCSVSource(conf *Config) Source {
+	s := &CSVSource{}
+	s.init(conf, "csv", csvCodec{})
+
+	return s
+}
+
+// csvCodec CSV rows of message and translation, with an optional "key,value"
+// header row.
+type csvCodec struct{}
+
+func (csvCodec) Decode(data []byte) (TMsgs, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	msgs := make(TMsgs, len(records))
+	for i, record := range records {
+		if i == 0 && len(record) == 2 && record[0] == "key" && record[1] == "value" {
+			continue
+		}
+		switch len(record) {
+		case 1:
+			msgs[record[0]] = ""
+		case 2:
+			msgs[record[0]] = record[1]
+		default:
+			return nil, errors.New("csv record must have one or two fields")
+		}
+	}
+	return msgs, nil
+}
+
+// Encode Encodes msgs with a header row and sorted keys.
+func (csvCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write([]string{"key", "value"})
+	for _, key := range sortedKeys(msgs) {
+		w.Write([]string{key, msgs[key]})
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
@@ -0,0 +1,196 @@
+package ii18n
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CurrencyStyle how the currency of an amount is shown.
+type CurrencyStyle int
+
+// Currency styles.
+const (
+	// CurrencySymbol shows the symbol of the currency, "$1,234.50".
+	CurrencySymbol CurrencyStyle = iota
+	// CurrencyCode shows the ISO 4217 code, "USD 1,234.50".
+	CurrencyCode
+	// CurrencyName shows the display name, "1,234.50 US dollars".
+	CurrencyName
+)
+
+// CurrencyOptions options of FormatCurrency.
+type CurrencyOptions struct {
+	Style CurrencyStyle
+	// Accounting formats negative amounts with the accounting pattern of
+	// the locale, "($1,234.50)" in English.
+	Accounting   bool
+	RoundingMode RoundingMode
+}
+
+// currencyPattern CLDR currency patterns of a locale, '¤' stands for the
+// currency and '#' for the number.
+type currencyPattern struct {
+	standard   string
+	accounting string
+}
+
+// currencyPatterns currency patterns by locale.
+var currencyPatterns = map[string]currencyPattern{
+	"en": {"¤#", "(¤#)"},
+}
+
+// currencyDigits fraction digits of the currencies not using two.
+var currencyDigits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 0, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// currencySymbols currency symbols by locale, the root locale "" holding
+// the symbols used everywhere else. Currencies without a symbol show their
+// code.
+var currencySymbols = map[string]map[string]string{
+	"": {
+		"AUD": "A$", "BRL": "R$", "CAD": "CA$", "CNY": "CN¥", "EUR": "€",
+		"GBP": "£", "HKD": "HK$", "ILS": "₪", "INR": "₹", "JPY": "JP¥",
+		"KRW": "₩", "MXN": "MX$", "NZD": "NZ$", "TWD": "NT$", "USD": "US$",
+		"VND": "₫",
+	},
+	"en":    {"USD": "$", "JPY": "¥"},
+	"en-AU": {"AUD": "$", "USD": "US$"},
+	"en-CA": {"CAD": "$", "USD": "US$"},
+	"en-IN": {"USD": "$"},
+}
+
+// currencyNames singular and plural display names of currencies by locale.
+var currencyNames = map[string]map[string][2]string{
+	"en": {
+		"AUD": {"Australian dollar", "Australian dollars"},
+		"BRL": {"Brazilian real", "Brazilian reals"},
+		"CAD": {"Canadian dollar", "Canadian dollars"},
+		"CHF": {"Swiss franc", "Swiss francs"},
+		"CNY": {"Chinese yuan", "Chinese yuan"},
+		"EUR": {"euro", "euros"},
+		"GBP": {"British pound", "British pounds"},
+		"INR": {"Indian rupee", "Indian rupees"},
+		"JPY": {"Japanese yen", "Japanese yen"},
+		"KRW": {"South Korean won", "South Korean won"},
+		"MXN": {"Mexican peso", "Mexican pesos"},
+		"RUB": {"Russian ruble", "Russian rubles"},
+		"USD": {"US dollar", "US dollars"},
+	},
+}
+
+// FormatCurrency Formats amount, an integer, float or decimal string, in
+// the currency with ISO 4217 code, using the fraction digits of the currency
+// and the currency pattern of lang. A nil opts shows the currency symbol.
+func FormatCurrency(amount interface{}, code string, lang string, opts *CurrencyOptions) (string, error) {
+	d, err := toDecimal(amount)
+	if err != nil {
+		return "", err
+	}
+	if opts == nil {
+		opts = &CurrencyOptions{}
+	}
+	code = strings.ToUpper(code)
+	digits, ok := currencyDigits[code]
+	if !ok {
+		digits = 2
+	}
+	numOpts := &NumberOptions{MinFractionDigits: digits, MaxFractionDigits: digits, RoundingMode: opts.RoundingMode}
+	symbols := lookupLocale(numberData, lang, "en")
+	d = d.round(digits, opts.RoundingMode)
+	neg := d.neg && !d.isZero()
+	d.neg = false
+	num := formatDecimal(d, symbols, numOpts)
+
+	if opts.Style == CurrencyName {
+		if neg {
+			num = symbols.minus + num
+		}
+		return num + " " + currencyName(code, lang, d, digits), nil
+	}
+	unit := code
+	if opts.Style == CurrencySymbol {
+		unit = currencySymbol(code, lang)
+	}
+	pattern := lookupLocale(currencyPatterns, lang, "en")
+	layout := pattern.standard
+	if neg && opts.Accounting {
+		layout = pattern.accounting
+	} else if neg {
+		layout = "-" + layout
+	}
+	return expandCurrency(layout, unit, num, symbols.minus), nil
+}
+
+// currencySymbol Returns the symbol of the currency code in lang.
+func currencySymbol(code string, lang string) string {
+	if symbol, ok := lookupLocale(currencySymbols, lang, "")[code]; ok {
+		return symbol
+	}
+	if symbol, ok := currencySymbols[""][code]; ok {
+		return symbol
+	}
+	return code
+}
+
+// currencyName Returns the display name of the currency code in lang, in
+// the plural form matching the formatted amount d.
+func currencyName(code string, lang string, d decimal, digits int) string {
+	names, ok := lookupLocale(currencyNames, lang, "en")[code]
+	if !ok {
+		if names, ok = currencyNames["en"][code]; !ok {
+			return code
+		}
+	}
+	val := d.intg
+	if val == "" {
+		val = "0"
+	}
+	if digits > 0 {
+		val += "." + d.frac + strings.Repeat("0", digits-len(d.frac))
+	}
+	if PluralCategory(lang, val) == "one" {
+		return names[0]
+	}
+	return names[1]
+}
+
+// expandCurrency Replaces '¤' in layout by unit, '#' by num and '-' by
+// minus. A no-break space separates a unit ending or starting with a letter
+// from an adjacent number, as CLDR currency spacing does.
+func expandCurrency(layout string, unit string, num string, minus string) string {
+	var b strings.Builder
+	for i, c := range layout {
+		switch c {
+		case '¤':
+			if i > 0 && strings.HasSuffix(layout[:i], "#") && startsWithLetter(unit) {
+				b.WriteString("\u00a0")
+			}
+			b.WriteString(unit)
+			if rest := layout[i+len("¤"):]; strings.HasPrefix(rest, "#") && endsWithLetter(unit) {
+				b.WriteString("\u00a0")
+			}
+		case '#':
+			b.WriteString(num)
+		case '-':
+			b.WriteString(minus)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func startsWithLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+func endsWithLetter(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsLetter(r)
+}
@@ -0,0 +1,301 @@
+package ii18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DateStyle the length of a formatted date or time, or a CLDR skeleton such
+// as "yMMMd" naming the fields to show.
+type DateStyle string
+
+// Date styles.
+const (
+	DateFull   DateStyle = "full"
+	DateLong   DateStyle = "long"
+	DateMedium DateStyle = "medium"
+	DateShort  DateStyle = "short"
+)
+
+// index index of the patterns of a style, -1 for skeletons.
+func (s DateStyle) index() int {
+	switch s {
+	case DateFull:
+		return 0
+	case DateLong:
+		return 1
+	case DateMedium, "":
+		return 2
+	case DateShort:
+		return 3
+	}
+	return -1
+}
+
+// calendarLocale Returns the calendar data of lang and the language to
+// format in: lang, or English when lang has no date patterns, so the dates
+// of such a locale are in English and the Gregorian calendar rather than its
+// calendar and month names in English patterns.
+func calendarLocale(lang string) (*calendarData, string) {
+	if cal := lookupLocale(calendars, lang, ""); cal != nil {
+		return cal, lang
+	}
+	return calendars["en"], "en"
+}
+
+// FormatDate Formats the date of t in lang with style, which is one of the
+// date styles or a skeleton of date fields.
+func FormatDate(t time.Time, style DateStyle, lang string) (string, error) {
+	cal, lang := calendarLocale(lang)
+	if i := style.index(); i != -1 {
+		return formatDatePattern(t, cal.datePatterns[i], cal, lang), nil
+	}
+	return formatSkeleton(t, string(style), cal, lang)
+}
+
+// FormatTime Formats the time of t in lang with style, which is one of the
+// date styles or a skeleton of time fields.
+func FormatTime(t time.Time, style DateStyle, lang string) (string, error) {
+	cal, lang := calendarLocale(lang)
+	if i := style.index(); i != -1 {
+		return formatDatePattern(t, cal.timePatterns[i], cal, lang), nil
+	}
+	return formatSkeleton(t, string(style), cal, lang)
+}
+
+// FormatDateTime Formats the date and time of t in lang with style, which is
+// one of the date styles or a skeleton of date and time fields such as
+// "yMMMdHm".
+func FormatDateTime(t time.Time, style DateStyle, lang string) (string, error) {
+	cal, lang := calendarLocale(lang)
+	if i := style.index(); i != -1 {
+		pattern := joinDateTime(cal.dateTimePatterns[i], cal.datePatterns[i], cal.timePatterns[i])
+		return formatDatePattern(t, pattern, cal, lang), nil
+	}
+	return formatSkeleton(t, string(style), cal, lang)
+}
+
+// formatSkeleton Formats t with the pattern the locale has for skeleton. A
+// skeleton of date and time fields is split, each half matched on its own,
+// and the halves are joined with the medium date-time pattern.
+func formatSkeleton(t time.Time, skeleton string, cal *calendarData, lang string) (string, error) {
+	split := strings.IndexAny(skeleton, "hHkKjmsaz")
+	if split == -1 || split == 0 {
+		pattern, ok := cal.skeletons[skeleton]
+		if !ok {
+			return "", fmt.Errorf("ii18n: unsupported date skeleton %q", skeleton)
+		}
+		return formatDatePattern(t, pattern, cal, lang), nil
+	}
+	date, ok := cal.skeletons[skeleton[:split]]
+	if !ok {
+		return "", fmt.Errorf("ii18n: unsupported date skeleton %q", skeleton)
+	}
+	clock, ok := cal.skeletons[skeleton[split:]]
+	if !ok {
+		return "", fmt.Errorf("ii18n: unsupported date skeleton %q", skeleton)
+	}
+	return formatDatePattern(t, joinDateTime(cal.dateTimePatterns[2], date, clock), cal, lang), nil
+}
+
+// joinDateTime Substitutes the date and time patterns into glue.
+func joinDateTime(glue string, date string, clock string) string {
+	return strings.NewReplacer("{1}", date, "{0}", clock).Replace(glue)
+}
+
+// formatDatePattern Formats t with a CLDR date pattern: runs of pattern
+// letters are fields, text between apostrophes is literal and a doubled
+// apostrophe is an apostrophe. Dates are shown in the calendar lang
+// requests, with their era.
+func formatDatePattern(t time.Time, pattern string, cal *calendarData, lang string) string {
+	calendar := calendarOf(lang)
+	date := toCalendar(calendar, t)
+	names := calendarNamesOf(calendar, lang)
+	if names != nil {
+		pattern = withEra(pattern, lang)
+	}
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				b.WriteByte('\'')
+				i += 2
+				continue
+			}
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end == -1 {
+				b.WriteString(pattern[i+1:])
+				return b.String()
+			}
+			b.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			n := 1
+			for i+n < len(pattern) && pattern[i+n] == c {
+				n++
+			}
+			b.WriteString(formatDateField(t, date, names, c, n, cal, lang))
+			i += n
+		default:
+			_, size := utf8.DecodeRuneInString(pattern[i:])
+			b.WriteString(pattern[i : i+size])
+			i += size
+		}
+	}
+	return b.String()
+}
+
+// formatDateField Formats the field of t for the pattern letter c repeated
+// n times. date is t in the calendar of the pattern, whose names are nil
+// for the Gregorian calendar.
+func formatDateField(t time.Time, date calendarDate, names *calendarNames, c byte, n int, cal *calendarData, lang string) string {
+	switch c {
+	case 'G':
+		if names != nil {
+			era := names.eras[min(date.era, len(names.eras)-1)]
+			if n == 5 {
+				r, _ := utf8.DecodeRuneInString(era)
+				return string(r)
+			}
+			return era
+		}
+		return [2]string{"BC", "AD"}[date.era]
+	case 'y':
+		year := date.year
+		if n == 2 {
+			return pad(year%100, 2)
+		}
+		return pad(year, n)
+	case 'M', 'L':
+		month := date.month - 1
+		switch {
+		case n <= 2:
+			return pad(month+1, n)
+		case names != nil && names.months != nil:
+			if date.leap && month == 6 && len(names.months) > 13 {
+				return names.months[13]
+			}
+			return names.months[month]
+		case c == 'L' && n == 3 && cal.monthsStandaloneAbbr[month] != "":
+			return cal.monthsStandaloneAbbr[month]
+		case c == 'L' && n >= 4 && cal.monthsStandalone[month] != "":
+			return cal.monthsStandalone[month]
+		case n == 3:
+			return cal.monthsAbbr[month]
+		case n == 4:
+			return cal.months[month]
+		default:
+			r, _ := utf8.DecodeRuneInString(cal.months[month])
+			return strings.ToUpper(string(r))
+		}
+	case 'd':
+		return pad(date.day, n)
+	case 'D':
+		return pad(t.YearDay(), n)
+	case 'E', 'c':
+		day := int(t.Weekday())
+		switch {
+		case c == 'c' && n <= 2:
+			return strconv.Itoa(day + 1)
+		case n == 4:
+			return cal.days[day]
+		case n == 5:
+			r, _ := utf8.DecodeRuneInString(cal.days[day])
+			return strings.ToUpper(string(r))
+		default:
+			return cal.daysAbbr[day]
+		}
+	case 'a':
+		return cal.dayPeriods[t.Hour()/12]
+	case 'h':
+		hour := t.Hour() % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return pad(hour, n)
+	case 'H':
+		return pad(t.Hour(), n)
+	case 'K':
+		return pad(t.Hour()%12, n)
+	case 'k':
+		hour := t.Hour()
+		if hour == 0 {
+			hour = 24
+		}
+		return pad(hour, n)
+	case 'm':
+		return pad(t.Minute(), n)
+	case 's':
+		return pad(t.Second(), n)
+	case 'S':
+		frac := fmt.Sprintf("%09d", t.Nanosecond())
+		for len(frac) < n {
+			frac += "0"
+		}
+		return frac[:n]
+	case 'z', 'v':
+		id := t.Location().String()
+		if n < 4 {
+			return t.Format("MST")
+		}
+		if _, ok := zoneMetazones[id]; !ok {
+			return "GMT" + t.Format("-07:00")
+		}
+		style := TimeZoneGeneric
+		if c == 'z' && t.IsDST() {
+			style = TimeZoneDaylight
+		} else if c == 'z' {
+			style = TimeZoneStandard
+		}
+		return zoneName(id, lang, style)
+	case 'Z':
+		switch n {
+		case 4:
+			return "GMT" + t.Format("-07:00")
+		case 5:
+			return t.Format("-07:00")
+		}
+		return t.Format("-0700")
+	case 'x', 'X':
+		if _, offset := t.Zone(); offset == 0 && c == 'X' {
+			return "Z"
+		}
+		switch n {
+		case 1:
+			return t.Format("-07")
+		case 2:
+			return t.Format("-0700")
+		}
+		return t.Format("-07:00")
+	}
+	return strings.Repeat(string(c), n)
+}
+
+// pad Returns val with at least n digits.
+func pad(val int, n int) string {
+	s := strconv.Itoa(val)
+	if val < 0 {
+		s = s[1:]
+	}
+	for len(s) < n {
+		s = "0" + s
+	}
+	if val < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// parseTimeArg Parses a date argument of a message, an RFC 3339 timestamp
+// or Unix seconds.
+func parseTimeArg(val string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339, val)
+}
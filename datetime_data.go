@@ -0,0 +1,58 @@
+package ii18n
+
+// calendarData CLDR Gregorian calendar data of a locale.
+type calendarData struct {
+	months     [12]string
+	monthsAbbr [12]string
+	// standalone month names, used by 'L', when they differ from months.
+	monthsStandalone     [12]string
+	monthsStandaloneAbbr [12]string
+	days                 [7]string
+	daysAbbr             [7]string
+	dayPeriods           [2]string
+	// date and time patterns by style: full, long, medium, short.
+	datePatterns [4]string
+	timePatterns [4]string
+	// dateTimePatterns glue the time {0} and date {1} patterns, by style.
+	dateTimePatterns [4]string
+	// skeletons patterns of the available formats by skeleton.
+	skeletons map[string]string
+}
+
+// calendars calendar data by locale.
+var calendars = map[string]*calendarData{
+	"en": {
+		months:           [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		monthsAbbr:       [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		days:             [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		daysAbbr:         [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		dayPeriods:       [2]string{"AM", "PM"},
+		datePatterns:     [4]string{"EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"},
+		timePatterns:     [4]string{"h:mm:ss\u202fa zzzz", "h:mm:ss\u202fa z", "h:mm:ss\u202fa", "h:mm\u202fa"},
+		dateTimePatterns: [4]string{"{1} 'at' {0}", "{1} 'at' {0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"y": "y", "yM": "M/y", "yMd": "M/d/y", "yMEd": "E, M/d/y", "yMMM": "MMM y",
+			"yMMMd": "MMM d, y", "yMMMEd": "E, MMM d, y", "yMMMM": "MMMM y", "yMMMMd": "MMMM d, y",
+			"Md": "M/d", "MEd": "E, M/d", "MMMd": "MMM d", "MMMEd": "E, MMM d", "MMMMd": "MMMM d",
+			"d": "d", "Ed": "d E", "E": "ccc", "MMM": "LLL", "MMMM": "LLLL",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm\u202fa", "hms": "h:mm:ss\u202fa", "H": "HH", "h": "h\u202fa",
+		},
+	},
+	"en-GB": {
+		months:           [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		monthsAbbr:       [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"},
+		days:             [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		daysAbbr:         [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		dayPeriods:       [2]string{"am", "pm"},
+		datePatterns:     [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		timePatterns:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimePatterns: [4]string{"{1} 'at' {0}", "{1} 'at' {0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"y": "y", "yM": "MM/y", "yMd": "dd/MM/y", "yMEd": "E, dd/MM/y", "yMMM": "MMM y",
+			"yMMMd": "d MMM y", "yMMMEd": "E, d MMM y", "yMMMM": "MMMM y", "yMMMMd": "d MMMM y",
+			"Md": "dd/MM", "MEd": "E dd/MM", "MMMd": "d MMM", "MMMEd": "E d MMM", "MMMMd": "d MMMM",
+			"d": "d", "Ed": "E d", "E": "ccc", "MMM": "LLL", "MMMM": "LLLL",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm\u202fa", "hms": "h:mm:ss\u202fa", "H": "HH", "h": "h\u202fa",
+		},
+	},
+}
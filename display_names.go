@@ -0,0 +1,215 @@
+package ii18n
+
+import (
+	"strings"
+)
+
+// displayLangs languages of the columns of the display name tables.
+var displayLangs = []string{"en", "de", "fr", "es", "ja", "zh", "ru"}
+
+// displayPatterns patterns composing a name from a language name {0} and
+// its script and region names {1}, and the separator between the latter,
+// by language.
+var displayPatterns = map[string][2]string{
+	"en": {"{0} ({1})", ", "},
+	"de": {"{0} ({1})", ", "},
+	"fr": {"{0} ({1})", ", "},
+	"es": {"{0} ({1})", ", "},
+	"ja": {"{0} ({1})", "、"},
+	"zh": {"{0}（{1}）", "，"},
+	"ru": {"{0} ({1})", ", "},
+}
+
+// languageNames language names by code, in the languages of displayLangs.
+var languageNames = map[string][7]string{
+	"ar":      {"Arabic", "Arabisch", "arabe", "árabe", "アラビア語", "阿拉伯语", "арабский"},
+	"bn":      {"Bangla", "Bengalisch", "bengali", "bengalí", "ベンガル語", "孟加拉语", "бенгальский"},
+	"cs":      {"Czech", "Tschechisch", "tchèque", "checo", "チェコ語", "捷克语", "чешский"},
+	"da":      {"Danish", "Dänisch", "danois", "danés", "デンマーク語", "丹麦语", "датский"},
+	"de":      {"German", "Deutsch", "allemand", "alemán", "ドイツ語", "德语", "немецкий"},
+	"el":      {"Greek", "Griechisch", "grec", "griego", "ギリシャ語", "希腊语", "греческий"},
+	"en":      {"English", "Englisch", "anglais", "inglés", "英語", "英语", "английский"},
+	"en-GB":   {"British English", "Britisches Englisch", "anglais britannique", "inglés británico", "イギリス英語", "英国英语", "британский английский"},
+	"en-US":   {"American English", "Amerikanisches Englisch", "anglais américain", "inglés estadounidense", "アメリカ英語", "美国英语", "американский английский"},
+	"es":      {"Spanish", "Spanisch", "espagnol", "español", "スペイン語", "西班牙语", "испанский"},
+	"es-419":  {"Latin American Spanish", "Lateinamerikanisches Spanisch", "espagnol d’Amérique latine", "español latinoamericano", "スペイン語 (ラテンアメリカ)", "拉丁美洲西班牙语", "латиноамериканский испанский"},
+	"fa":      {"Persian", "Persisch", "persan", "persa", "ペルシア語", "波斯语", "персидский"},
+	"fi":      {"Finnish", "Finnisch", "finnois", "finés", "フィンランド語", "芬兰语", "финский"},
+	"fr":      {"French", "Französisch", "français", "francés", "フランス語", "法语", "французский"},
+	"fr-CA":   {"Canadian French", "Kanadisches Französisch", "français canadien", "francés canadiense", "カナダ フランス語", "加拿大法语", "канадский французский"},
+	"he":      {"Hebrew", "Hebräisch", "hébreu", "hebreo", "ヘブライ語", "希伯来语", "иврит"},
+	"hi":      {"Hindi", "Hindi", "hindi", "hindi", "ヒンディー語", "印地语", "хинди"},
+	"hu":      {"Hungarian", "Ungarisch", "hongrois", "húngaro", "ハンガリー語", "匈牙利语", "венгерский"},
+	"id":      {"Indonesian", "Indonesisch", "indonésien", "indonesio", "インドネシア語", "印度尼西亚语", "индонезийский"},
+	"it":      {"Italian", "Italienisch", "italien", "italiano", "イタリア語", "意大利语", "итальянский"},
+	"ja":      {"Japanese", "Japanisch", "japonais", "japonés", "日本語", "日语", "японский"},
+	"ko":      {"Korean", "Koreanisch", "coréen", "coreano", "韓国語", "韩语", "корейский"},
+	"nb":      {"Norwegian Bokmål", "Norwegisch (Bokmål)", "norvégien bokmål", "noruego bokmal", "ノルウェー語(ブークモール)", "书面挪威语", "норвежский букмол"},
+	"nl":      {"Dutch", "Niederländisch", "néerlandais", "neerlandés", "オランダ語", "荷兰语", "нидерландский"},
+	"pl":      {"Polish", "Polnisch", "polonais", "polaco", "ポーランド語", "波兰语", "польский"},
+	"pt":      {"Portuguese", "Portugiesisch", "portugais", "portugués", "ポルトガル語", "葡萄牙语", "португальский"},
+	"pt-BR":   {"Brazilian Portuguese", "Brasilianisches Portugiesisch", "portugais brésilien", "portugués de Brasil", "ポルトガル語 (ブラジル)", "巴西葡萄牙语", "бразильский португальский"},
+	"ro":      {"Romanian", "Rumänisch", "roumain", "rumano", "ルーマニア語", "罗马尼亚语", "румынский"},
+	"ru":      {"Russian", "Russisch", "russe", "ruso", "ロシア語", "俄语", "русский"},
+	"sv":      {"Swedish", "Schwedisch", "suédois", "sueco", "スウェーデン語", "瑞典语", "шведский"},
+	"th":      {"Thai", "Thailändisch", "thaï", "tailandés", "タイ語", "泰语", "тайский"},
+	"tr":      {"Turkish", "Türkisch", "turc", "turco", "トルコ語", "土耳其语", "турецкий"},
+	"uk":      {"Ukrainian", "Ukrainisch", "ukrainien", "ucraniano", "ウクライナ語", "乌克兰语", "украинский"},
+	"vi":      {"Vietnamese", "Vietnamesisch", "vietnamien", "vietnamita", "ベトナム語", "越南语", "вьетнамский"},
+	"zh":      {"Chinese", "Chinesisch", "chinois", "chino", "中国語", "中文", "китайский"},
+	"zh-Hans": {"Simplified Chinese", "Chinesisch (vereinfacht)", "chinois simplifié", "chino simplificado", "簡体中国語", "简体中文", "упрощенный китайский"},
+	"zh-Hant": {"Traditional Chinese", "Chinesisch (traditionell)", "chinois traditionnel", "chino tradicional", "繁体中国語", "繁体中文", "традиционный китайский"},
+}
+
+// autonyms names of languages in themselves, used when a language is named
+// in a language without a column in the tables.
+var autonyms = map[string]string{
+	"ar": "العربية", "bn": "বাংলা", "cs": "čeština", "da": "dansk", "de": "Deutsch",
+	"el": "Ελληνικά", "en": "English", "es": "español", "fa": "فارسی", "fi": "suomi",
+	"fr": "français", "he": "עברית", "hi": "हिन्दी", "hu": "magyar", "id": "Indonesia",
+	"it": "italiano", "ja": "日本語", "ko": "한국어", "nb": "norsk bokmål", "nl": "Nederlands",
+	"pl": "polski", "pt": "português", "ro": "română", "ru": "русский", "sv": "svenska",
+	"th": "ไทย", "tr": "Türkçe", "uk": "українська", "vi": "Tiếng Việt", "zh": "中文",
+}
+
+// regionNames region names by code, in the languages of displayLangs.
+var regionNames = map[string][7]string{
+	"419": {"Latin America", "Lateinamerika", "Amérique latine", "Latinoamérica", "ラテンアメリカ", "拉丁美洲", "Латинская Америка"},
+	"AR":  {"Argentina", "Argentinien", "Argentine", "Argentina", "アルゼンチン", "阿根廷", "Аргентина"},
+	"AT":  {"Austria", "Österreich", "Autriche", "Austria", "オーストリア", "奥地利", "Австрия"},
+	"AU":  {"Australia", "Australien", "Australie", "Australia", "オーストラリア", "澳大利亚", "Австралия"},
+	"BE":  {"Belgium", "Belgien", "Belgique", "Bélgica", "ベルギー", "比利时", "Бельгия"},
+	"BR":  {"Brazil", "Brasilien", "Brésil", "Brasil", "ブラジル", "巴西", "Бразилия"},
+	"CA":  {"Canada", "Kanada", "Canada", "Canadá", "カナダ", "加拿大", "Канада"},
+	"CH":  {"Switzerland", "Schweiz", "Suisse", "Suiza", "スイス", "瑞士", "Швейцария"},
+	"CN":  {"China", "China", "Chine", "China", "中国", "中国", "Китай"},
+	"DE":  {"Germany", "Deutschland", "Allemagne", "Alemania", "ドイツ", "德国", "Германия"},
+	"DK":  {"Denmark", "Dänemark", "Danemark", "Dinamarca", "デンマーク", "丹麦", "Дания"},
+	"ES":  {"Spain", "Spanien", "Espagne", "España", "スペイン", "西班牙", "Испания"},
+	"FI":  {"Finland", "Finnland", "Finlande", "Finlandia", "フィンランド", "芬兰", "Финляндия"},
+	"FR":  {"France", "Frankreich", "France", "Francia", "フランス", "法国", "Франция"},
+	"GB":  {"United Kingdom", "Vereinigtes Königreich", "Royaume-Uni", "Reino Unido", "イギリス", "英国", "Великобритания"},
+	"IE":  {"Ireland", "Irland", "Irlande", "Irlanda", "アイルランド", "爱尔兰", "Ирландия"},
+	"IN":  {"India", "Indien", "Inde", "India", "インド", "印度", "Индия"},
+	"IT":  {"Italy", "Italien", "Italie", "Italia", "イタリア", "意大利", "Италия"},
+	"JP":  {"Japan", "Japan", "Japon", "Japón", "日本", "日本", "Япония"},
+	"KR":  {"South Korea", "Südkorea", "Corée du Sud", "Corea del Sur", "韓国", "韩国", "Республика Корея"},
+	"MX":  {"Mexico", "Mexiko", "Mexique", "México", "メキシコ", "墨西哥", "Мексика"},
+	"NL":  {"Netherlands", "Niederlande", "Pays-Bas", "Países Bajos", "オランダ", "荷兰", "Нидерланды"},
+	"NO":  {"Norway", "Norwegen", "Norvège", "Noruega", "ノルウェー", "挪威", "Норвегия"},
+	"PL":  {"Poland", "Polen", "Pologne", "Polonia", "ポーランド", "波兰", "Польша"},
+	"PT":  {"Portugal", "Portugal", "Portugal", "Portugal", "ポルトガル", "葡萄牙", "Португалия"},
+	"RU":  {"Russia", "Russland", "Russie", "Rusia", "ロシア", "俄罗斯", "Россия"},
+	"SE":  {"Sweden", "Schweden", "Suède", "Suecia", "スウェーデン", "瑞典", "Швеция"},
+	"TR":  {"Türkiye", "Türkei", "Turquie", "Turquía", "トルコ", "土耳其", "Турция"},
+	"TW":  {"Taiwan", "Taiwan", "Taïwan", "Taiwán", "台湾", "台湾", "Тайвань"},
+	"UA":  {"Ukraine", "Ukraine", "Ukraine", "Ucrania", "ウクライナ", "乌克兰", "Украина"},
+	"US":  {"United States", "Vereinigte Staaten", "États-Unis", "Estados Unidos", "アメリカ合衆国", "美国", "Соединенные Штаты"},
+}
+
+// scriptNames script names by ISO 15924 code, in the languages of
+// displayLangs.
+var scriptNames = map[string][7]string{
+	"Arab": {"Arabic", "Arabisch", "arabe", "árabe", "アラビア文字", "阿拉伯文", "арабица"},
+	"Cyrl": {"Cyrillic", "Kyrillisch", "cyrillique", "cirílico", "キリル文字", "西里尔文", "кириллица"},
+	"Deva": {"Devanagari", "Devanagari", "dévanagari", "devanagari", "デーバナーガリー文字", "天城文", "деванагари"},
+	"Grek": {"Greek", "Griechisch", "grec", "griego", "ギリシャ文字", "希腊文", "греческая"},
+	"Hans": {"Simplified", "Vereinfacht", "sinogrammes simplifiés", "simplificado", "簡体字", "简体", "упрощенная китайская"},
+	"Hant": {"Traditional", "Traditionell", "sinogrammes traditionnels", "tradicional", "繁体字", "繁体", "традиционная китайская"},
+	"Hebr": {"Hebrew", "Hebräisch", "hébreu", "hebreo", "ヘブライ文字", "希伯来文", "еврейская"},
+	"Jpan": {"Japanese", "Japanisch", "japonais", "japonés", "日本語の文字", "日文", "японская"},
+	"Kore": {"Korean", "Koreanisch", "coréen", "coreano", "韓国語の文字", "韩文", "корейская"},
+	"Latn": {"Latin", "Lateinisch", "latin", "latino", "ラテン文字", "拉丁文", "латиница"},
+	"Thai": {"Thai", "Thai", "thaï", "tailandés", "タイ文字", "泰文", "тайская"},
+}
+
+// displayColumn Returns the column of inLang in the display name tables,
+// or -1.
+func displayColumn(inLang string) int {
+	base := baseLang(inLang)
+	for i, lang := range displayLangs {
+		if lang == base {
+			return i
+		}
+	}
+	return -1
+}
+
+// displayName Returns the name of code in the table, in the language of
+// column col, English when col is -1.
+func displayName(table map[string][7]string, code string, col int) (string, bool) {
+	names, ok := table[code]
+	if !ok {
+		return "", false
+	}
+	if col == -1 {
+		col = 0
+	}
+	return names[col], true
+}
+
+// LanguageName Returns the name of the language tag in inLang, such as
+// "Brazilian Portuguese" or "German (Switzerland)" in English. A language
+// is named in itself when inLang has no display names of its own, other
+// names fall back to English. Unknown languages return tag.
+func LanguageName(tag string, inLang string) string {
+	tag = strings.Replace(tag, "_", "-", -1)
+	col := displayColumn(inLang)
+	if col == -1 && tag == baseLang(inLang) && autonyms[tag] != "" {
+		return autonyms[tag]
+	}
+	if name, ok := displayName(languageNames, tag, col); ok {
+		return name
+	}
+	parts := strings.Split(tag, "-")
+	name, ok := displayName(languageNames, parts[0], col)
+	if !ok {
+		return tag
+	}
+	var qualifiers []string
+	for _, part := range parts[1:] {
+		table, code := regionNames, strings.ToUpper(part)
+		if len(part) == 4 {
+			table, code = scriptNames, scriptCode(part)
+		}
+		if qualifier, ok := displayName(table, code, col); ok {
+			qualifiers = append(qualifiers, qualifier)
+		}
+	}
+	if len(qualifiers) == 0 {
+		return name
+	}
+	pattern := displayPatterns[displayLangs[max(col, 0)]]
+	return strings.NewReplacer("{0}", name, "{1}", strings.Join(qualifiers, pattern[1])).Replace(pattern[0])
+}
+
+// RegionName Returns the name of the region with ISO 3166 or UN M.49 code
+// in inLang, falling back to English. Unknown regions return code.
+func RegionName(code string, inLang string) string {
+	if name, ok := displayName(regionNames, strings.ToUpper(code), displayColumn(inLang)); ok {
+		return name
+	}
+	return code
+}
+
+// ScriptName Returns the name of the script with ISO 15924 code in inLang,
+// falling back to English. Unknown scripts return code.
+func ScriptName(code string, inLang string) string {
+	if name, ok := displayName(scriptNames, scriptCode(code), displayColumn(inLang)); ok {
+		return name
+	}
+	return code
+}
+
+// baseLang Returns the language subtag of lang, "pt" for "pt_BR".
+func baseLang(lang string) string {
+	return strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0]
+}
+
+// scriptCode Returns code in the title case of ISO 15924, "Latn".
+func scriptCode(code string) string {
+	if code == "" {
+		return code
+	}
+	return strings.ToUpper(code[:1]) + strings.ToLower(code[1:])
+}
@@ -0,0 +1,95 @@
+package ii18n
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedMagic starts the header line of encrypted catalogs,
+// "ii18n-aesgcm v1 {keyID}\n", followed by the nonce and the sealed catalog.
+const encryptedMagic = "ii18n-aesgcm v1 "
+
+// KeyProvider Returns the AES key, of 16, 24 or 32 bytes, with the ID id.
+// Keeping the keys of older IDs lets catalogs encrypted before a rotation
+// load until they are encrypted again.
+type KeyProvider func(id string) ([]byte, error)
+
+// StaticKeys Returns the KeyProvider of keys by ID.
+func StaticKeys(keys map[string][]byte) KeyProvider {
+	return func(id string) ([]byte, error) {
+		if key, ok := keys[id]; ok {
+			return key, nil
+		}
+		return nil, errors.New("unknown key " + id)
+	}
+}
+
+// EncryptCatalog Returns data, a catalog file, encrypted with AES-GCM by
+// key, whose ID keyID is stored in the clear to select the key again.
+func EncryptCatalog(data []byte, keyID string, key []byte) ([]byte, error) {
+	if keyID == "" || strings.ContainsAny(keyID, " \n") {
+		return nil, errors.New("invalid key ID " + keyID)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	header := encryptedMagic + keyID + "\n"
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(header), nonce...)
+	return aead.Seal(out, nonce, data, []byte(header)), nil
+}
+
+// IsEncryptedCatalog Reports whether data is a catalog encrypted by
+// EncryptCatalog, and returns the ID of its key.
+func IsEncryptedCatalog(data []byte) (string, bool) {
+	if !bytes.HasPrefix(data, []byte(encryptedMagic)) {
+		return "", false
+	}
+	line, _, ok := bytes.Cut(data[len(encryptedMagic):], []byte("\n"))
+	return string(line), ok
+}
+
+// DecryptCatalog Returns the catalog file encrypted in data by
+// EncryptCatalog, with the key of its ID from keys.
+func DecryptCatalog(data []byte, keys KeyProvider) ([]byte, error) {
+	keyID, ok := IsEncryptedCatalog(data)
+	if !ok {
+		return nil, errors.New("not an encrypted catalog")
+	}
+	key, err := keys(keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	headerLen := len(encryptedMagic) + len(keyID) + 1
+	if len(data) < headerLen+aead.NonceSize() {
+		return nil, errors.New("truncated encrypted catalog")
+	}
+	nonce := data[headerLen : headerLen+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, data[headerLen+aead.NonceSize():], data[:headerLen])
+	if err != nil {
+		return nil, fmt.Errorf("decrypting with key %s: %w", keyID, err)
+	}
+	return plain, nil
+}
+
+// newGCM Returns the AES-GCM AEAD of key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,40 @@
+package ii18n
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// enumLabels the message keys of the values of an enum type.
+type enumLabels struct {
+	category string
+	keys     map[interface{}]string
+}
+
+// enums the registered enum types.
+var enums = map[reflect.Type]enumLabels{}
+
+// RegisterEnum registers the message keys in category of the values of the
+// enum type E, replacing any keys registered for it before, e.g.
+//
+//	RegisterEnum("app.orders", map[OrderStatus]string{Pending: "Pending", Shipped: "Shipped"})
+//
+// It is not safe to call concurrently with LocalizedString.
+func RegisterEnum[E comparable](category string, keys map[E]string) {
+	labels := enumLabels{category: normalizeCategory(category), keys: make(map[interface{}]string, len(keys))}
+	for v, key := range keys {
+		labels.keys[v] = key
+	}
+	enums[reflect.TypeFor[E]()] = labels
+}
+
+// LocalizedString Returns the label of v in lang, the translation of its key
+// registered by RegisterEnum, or fmt.Sprint(v) for values without a key.
+func LocalizedString(v interface{}, lang string) string {
+	if labels, ok := enums[reflect.TypeOf(v)]; ok {
+		if key, ok := labels.keys[v]; ok {
+			return Translator.T(labels.category, key, nil, lang)
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,183 @@
+package ii18n
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCatalogNotFound is matched by errors of catalogs that do not exist,
+	// with errors.Is.
+	ErrCatalogNotFound = errors.New("catalog not found")
+	// ErrInvalidPattern is wrapped by the errors of malformed ICU message
+	// patterns.
+	ErrInvalidPattern = errors.New("message pattern is invalid")
+	// ErrInvalidCategory is wrapped by the errors of malformed categories
+	// such as "" or "app..common".
+	ErrInvalidCategory = errors.New("invalid category")
+	// ErrInvalidNumber is wrapped by the errors of values that are not
+	// numbers.
+	ErrInvalidNumber = errors.New("ii18n: invalid number")
+	// ErrInvalidSignature is wrapped by the errors of catalogs whose
+	// detached signature is missing or does not verify, see
+	// Config.VerifyKey.
+	ErrInvalidSignature = errors.New("catalog signature is invalid")
+	// ErrChecksumMismatch is wrapped by the errors of catalogs that do not
+	// match the checksum of the manifest, see Config.Manifest.
+	ErrChecksumMismatch = errors.New("catalog checksum does not match")
+	// ErrMTPlaceholders is wrapped by the errors of machine translations
+	// that drop, repeat or add arguments of the message, see
+	// TranslatePattern.
+	ErrMTPlaceholders = errors.New("machine translation changed the placeholders")
+)
+
+// LoadError an error reading or decoding the catalog file Path.
+type LoadError struct {
+	Path string
+	// Offset of a decode error in the UTF-8 text of the file, 0 when the
+	// codec does not report one.
+	Offset int64
+	Err    error
+}
+
+// newLoadError Returns the LoadError of decoding filename, with the offset
+// of the JSON syntax and type errors.
+func newLoadError(filename string, err error) *LoadError {
+	e := &LoadError{Path: filename, Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) {
+		e.Offset = syntaxErr.Offset
+	} else if errors.As(err, &typeErr) {
+		e.Offset = typeErr.Offset
+	}
+	return e
+}
+
+func (e *LoadError) Error() string {
+	if e.Offset > 0 {
+		return e.Path + ": offset " + strconv.FormatInt(e.Offset, 10) + ": " + e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Is Reports whether the catalog does not exist for ErrCatalogNotFound.
+func (e *LoadError) Is(target error) bool {
+	return target == ErrCatalogNotFound && errors.Is(e.Err, fs.ErrNotExist)
+}
+
+// ParamsError the params of a translation that do not match its
+// placeholders, see WithParamCheck.
+type ParamsError struct {
+	Category string
+	Message  string
+	Lang     string
+	// Missing placeholders without a param, sorted.
+	Missing []string
+	// Extra params without a placeholder, sorted.
+	Extra []string
+}
+
+func (e *ParamsError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		problems = append(problems, "extra "+strings.Join(e.Extra, ", "))
+	}
+	return "params of " + e.Message + " in category " + e.Category + " for " + e.Lang + ": " + strings.Join(problems, "; ")
+}
+
+// MissingTranslationError is returned by sources for a message without
+// translation in Lang.
+type MissingTranslationError struct {
+	Category string
+	Message  string
+	Lang     string
+}
+
+func (e *MissingTranslationError) Error() string {
+	return "no translation of " + e.Message + " in category " + e.Category + " for " + e.Lang
+}
+
+// LocalizedError an error whose message is the message Key of Category,
+// rendered per language by Localize.
+type LocalizedError struct {
+	Category string
+	Key      string
+	Params   map[string]string
+	// Err the cause, appended to the message after ": ".
+	Err error
+}
+
+// Errorf Returns the LocalizedError of key in category. args are param
+// names and values in turn, e.g. Errorf("errors", "{id} not found", "id", 42,
+// err), with an error wrapped as the cause.
+func Errorf(category string, key string, args ...interface{}) *LocalizedError {
+	e := &LocalizedError{Category: category, Key: key}
+	var name string
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && name == "" {
+			e.Err = err
+			continue
+		}
+		if name == "" {
+			name = paramString(reflect.ValueOf(arg))
+			continue
+		}
+		if e.Params == nil {
+			e.Params = make(map[string]string)
+		}
+		e.Params[name] = paramString(reflect.ValueOf(arg))
+		name = ""
+	}
+	return e
+}
+
+// Localize Returns the message of e translated to lang by Translator, then
+// the cause, itself localized when it is a LocalizedError. An empty lang is
+// the default language, see WithDefaultLang, or else the original language.
+func (e *LocalizedError) Localize(lang string) string {
+	msg := e.Key
+	if i := Translator; i != nil {
+		category := normalizeCategory(e.Category)
+		if conf, ok := i.Translations[strings.Split(category, ".")[0]]; ok {
+			if lang == "" && i.defaultLang == "" {
+				lang = conf.OriginalLang
+			}
+			msg = i.T(category, e.Key, e.Params, lang)
+		}
+	}
+	if e.Err == nil {
+		return msg
+	}
+	if cause, ok := e.Err.(*LocalizedError); ok {
+		return msg + ": " + cause.Localize(lang)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Error Returns the message of e in the default or original language.
+func (e *LocalizedError) Error() string {
+	return e.Localize("")
+}
+
+func (e *LocalizedError) Unwrap() error {
+	return e.Err
+}
+
+// Is Reports whether target is a LocalizedError of the same message, so
+// that LocalizedErrors without params or cause serve as sentinels.
+func (e *LocalizedError) Is(target error) bool {
+	t, ok := target.(*LocalizedError)
+	return ok && normalizeCategory(t.Category) == normalizeCategory(e.Category) && t.Key == e.Key
+}
@@ -1,8 +1,11 @@
 package ii18n
 
 import (
-	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Formatter
@@ -14,56 +17,595 @@ func NewFormatter() *Formatter {
 	return &Formatter{}
 }
 
+// Format Formats the ICU message pattern with params for lang.
+func (f *Formatter) Format(pattern string, params map[string]string, lang string) (string, error) {
+	return f.format(pattern, params, lang)
+}
+
 // format message
 func (f *Formatter) format(pattern string, params map[string]string, lang string) (string, error) {
-	tokens := f.tokenizePattern(pattern)
-	if tokens == nil {
-		return "", errors.New("message pattern is invalid")
+	nodes, err := parsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+	return f.formatParsed(nodes, params, lang), nil
+}
+
+// formatParsed Formats the nodes of a parsed pattern.
+func (f *Formatter) formatParsed(nodes []Node, params map[string]string, lang string) string {
+	var b strings.Builder
+	f.formatNodes(&b, nodes, params, lang, "")
+	return b.String()
+}
+
+// formatNodes Writes nodes with params substituted. pound is the value of
+// the enclosing plural argument, written for '#'.
+func (f *Formatter) formatNodes(b *strings.Builder, nodes []Node, params map[string]string, lang string, pound string) {
+	for _, n := range nodes {
+		switch n.Kind {
+		case TextNode:
+			b.WriteString(n.Text)
+		case PoundNode:
+			b.WriteString(pound)
+		case ArgNode:
+			if n.Type == "pluralrange" {
+				f.formatRange(b, n, params, lang)
+				continue
+			}
+			val, ok := params[n.Name]
+			if !ok {
+				b.WriteString("{" + n.Name + "}")
+				continue
+			}
+			switch n.Type {
+			case "plural", "selectordinal":
+				if nodes, ok := n.exact(val); ok {
+					f.formatNodes(b, nodes, params, lang, formatPound(subtractOffset(val, n.Offset), lang))
+					continue
+				}
+				val = subtractOffset(val, n.Offset)
+				category := pluralCategory(lang, val, n.Type == "selectordinal")
+				f.formatNodes(b, n.choose(category), params, lang, formatPound(val, lang))
+			case "pluralforms":
+				f.formatNodes(b, n.choose(pluralFormIndex(n.Style, val)), params, lang, val)
+			case "select":
+				f.formatNodes(b, n.choose(val), params, lang, pound)
+			default:
+				b.WriteString(formatArg(n, val, lang))
+			}
+		}
+	}
+}
+
+// formatRange Writes the pluralrange argument n, named "from:to", choosing
+// its message by the plural category of the range. '#' is the range.
+func (f *Formatter) formatRange(b *strings.Builder, n Node, params map[string]string, lang string) {
+	from, to, _ := strings.Cut(n.Name, ":")
+	fromVal, ok := params[from]
+	toVal, ok2 := params[to]
+	if !ok || !ok2 {
+		b.WriteString("{" + n.Name + "}")
+		return
+	}
+	category := PluralRangeCategory(lang, fromVal, toVal)
+	pound := formatArg(Node{Type: "number"}, fromVal, lang) + "\u2013" + formatArg(Node{Type: "number"}, toVal, lang)
+	f.formatNodes(b, n.choose(category), params, lang, pound)
+}
+
+// formatArg Returns val formatted by the type and style of the simple
+// argument n. Values that cannot be formatted are written unchanged.
+func formatArg(n Node, val string, lang string) string {
+	var out string
+	var err error
+	switch n.Type {
+	case "number":
+		switch {
+		case n.Style == "":
+			out, err = FormatNumber(val, lang, nil)
+		case n.Style == "integer":
+			out, err = FormatNumber(val, lang, &NumberOptions{})
+		case strings.HasPrefix(n.Style, "::currency/"):
+			out, err = FormatCurrency(val, strings.TrimPrefix(n.Style, "::currency/"), lang, nil)
+		default:
+			return val
+		}
+	case "date", "time":
+		t, parseErr := parseTimeArg(val)
+		if parseErr != nil {
+			return val
+		}
+		style := DateStyle(strings.TrimPrefix(n.Style, "::"))
+		if n.Type == "date" {
+			out, err = FormatDate(t, style, lang)
+		} else {
+			out, err = FormatTime(t, style, lang)
+		}
+	case "relativetime":
+		t, err := parseTimeArg(val)
+		if err != nil {
+			return val
+		}
+		width := RelativeLong
+		switch n.Style {
+		case "short":
+			width = RelativeShort
+		case "narrow":
+			width = RelativeNarrow
+		}
+		return FormatRelativeTime(t, time.Now(), lang, width)
+	default:
+		return val
+	}
+	if err != nil {
+		return val
+	}
+	return out
+}
+
+// formatPound Returns the number val of a plural argument formatted for '#'
+// with exactly its visible fraction digits, so "1.0", which selects "other"
+// in English, is not written as "1". val is returned unchanged if it is not
+// a number.
+func formatPound(val string, lang string) string {
+	d, err := parseDecimal(strings.TrimSpace(val))
+	if err != nil {
+		return val
+	}
+	digits := len(d.frac)
+	out, err := FormatNumber(val, lang, &NumberOptions{MinFractionDigits: digits, MaxFractionDigits: digits})
+	if err != nil {
+		return val
+	}
+	return out
+}
+
+// pluralCategory Returns the plural category of the number val, whose
+// visible fraction digits count: "1.0" is not "one" in English.
+func pluralCategory(lang string, val string, ordinal bool) string {
+	if ordinal {
+		return OrdinalCategory(lang, val)
+	}
+	return PluralCategory(lang, val)
+}
+
+// pluralFormIndex Returns the gettext form of the number val selected by
+// the Plural-Forms expression expr, "other" when either is invalid.
+func pluralFormIndex(expr string, val string) string {
+	eval, err := compilePluralExpr(expr)
+	if err != nil {
+		return "other"
+	}
+	ops, err := NewOperands(val)
+	if err != nil {
+		return "other"
 	}
+	return strconv.FormatInt(eval(ops.I), 10)
+}
+
+// NodeKind kind of a parsed pattern node.
+type NodeKind int
+
+// Node kinds.
+const (
+	// TextNode literal text.
+	TextNode NodeKind = iota
+	// ArgNode an argument such as {name} or {n, plural, ...}.
+	ArgNode
+	// PoundNode '#' in a plural option, the number of the argument.
+	PoundNode
+)
+
+// Node a part of a parsed message pattern.
+type Node struct {
+	Kind NodeKind
+	// Text of a TextNode.
+	Text string
+	// Name, Type, Style and Variants of an ArgNode.
+	Name     string
+	Type     string
+	Style    string
+	Variants []Variant
+	// Offset of a plural argument, subtracted from the value before its
+	// category is chosen and '#' is written.
+	Offset int
+}
+
+// Variant a selector and its message of a plural or select argument.
+type Variant struct {
+	Selector string
+	Nodes    []Node
+}
+
+// choose Returns the message of selector, "other" when there is none.
+func (n Node) choose(selector string) []Node {
+	var other []Node
+	for _, o := range n.Variants {
+		if o.Selector == selector {
+			return o.Nodes
+		}
+		if o.Selector == "other" {
+			other = o.Nodes
+		}
+	}
+	return other
+}
+
+// exact Returns the message of the explicit "=N" selector equal to val.
+func (n Node) exact(val string) ([]Node, bool) {
+	num, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, false
+	}
+	for _, o := range n.Variants {
+		if strings.HasPrefix(o.Selector, "=") {
+			if x, err := strconv.ParseFloat(o.Selector[1:], 64); err == nil && x == num {
+				return o.Nodes, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// subtractOffset Returns the number val less offset, keeping the fraction
+// digits of val. val is returned unchanged if it is not a number.
+func subtractOffset(val string, offset int) string {
+	if offset == 0 {
+		return val
+	}
+	num, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return val
+	}
+	digits := 0
+	if pos := strings.IndexByte(val, '.'); pos != -1 {
+		digits = len(val) - pos - 1
+	}
+	return strconv.FormatFloat(num-float64(offset), 'f', digits, 64)
+}
+
+// Placeholders Returns the sorted names of the arguments used in pattern, or
+// an error if pattern is not a valid ICU message pattern.
+func Placeholders(pattern string) ([]string, error) {
+	nodes, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, n := range nodes {
+			if n.Kind != ArgNode {
+				continue
+			}
+			if n.Type == "pluralrange" {
+				from, to, _ := strings.Cut(n.Name, ":")
+				seen[from], seen[to] = true, true
+			} else {
+				seen[n.Name] = true
+			}
+			for _, o := range n.Variants {
+				walk(o.Nodes)
+			}
+		}
+	}
+	walk(nodes)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
 
-	return strings.Join(tokens, ""), nil
+// ParsePattern Parses an ICU message pattern.
+func ParsePattern(pattern string) ([]Node, error) {
+	return parsePattern(pattern)
 }
 
-// Tokenizes a pattern by separating normal text from replaceable patterns.
-func (f *Formatter) tokenizePattern(pattern string) []string {
-	pos := strings.Index(pattern, "{")
-	if pos == -1 {
-		return []string{pattern}
+// parsePattern Parses an ICU message pattern, using the pre-parsed nodes of
+// compiled catalogs when available.
+func parsePattern(pattern string) ([]Node, error) {
+	if nodes, ok := lookupCompiled(pattern); ok {
+		return nodes, nil
+	}
+	p := &patternParser{pattern: pattern}
+	nodes, err := p.parseMessage(false, false)
+	if err != nil {
+		return nil, err
 	}
-	//pr := []rune(pattern)
-	start, depth, length := pos, 1, len(pattern)
-	tokens := []string{pattern[:pos]}
+	return nodes, nil
+}
+
+// patternParser recursive descent parser of ICU message patterns.
+type patternParser struct {
+	pattern string
+	pos     int
+	depth   int
+}
+
+// maxPatternDepth limit of nested select and plural arguments, which keeps
+// malicious patterns from exhausting the stack.
+const maxPatternDepth = 64
+
+func (p *patternParser) errorf(msg string) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidPattern, msg, p.pos)
+}
+
+// parseMessage Parses text and arguments up to the end of the pattern, or up
+// to the closing brace of a nested message.
+func (p *patternParser) parseMessage(inPlural bool, nested bool) ([]Node, error) {
+	var nodes []Node
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, Node{Kind: TextNode, Text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.pattern) {
+		c := p.pattern[p.pos]
+		switch {
+		case c == '\'':
+			p.parseQuoted(&text, inPlural)
+		case c == '{':
+			flush()
+			n, err := p.parseArg()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case c == '}':
+			if !nested {
+				return nil, p.errorf("unmatched '}'")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, Node{Kind: PoundNode})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if nested {
+		return nil, p.errorf("unclosed '{'")
+	}
+	flush()
+	return nodes, nil
+}
+
+// parseQuoted Handles an apostrophe: a doubled apostrophe is literal and an
+// apostrophe before a syntax character quotes text up to the next single
+// apostrophe. Any other apostrophe is literal.
+func (p *patternParser) parseQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos >= len(p.pattern) {
+		text.WriteByte('\'')
+		return
+	}
+	c := p.pattern[p.pos]
+	if c == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+	if c != '{' && c != '}' && c != '|' && !(c == '#' && inPlural) {
+		text.WriteByte('\'')
+		return
+	}
+	for p.pos < len(p.pattern) {
+		c = p.pattern[p.pos]
+		p.pos++
+		if c != '\'' {
+			text.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.pattern) && p.pattern[p.pos] == '\'' {
+			text.WriteByte('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+// parseArg Parses {name}, {name, type}, {name, type, style} and
+// {name, plural|select|selectordinal, options}. A pluralrange argument is
+// named by its two bounds, {from:to, pluralrange, options}, and a
+// pluralforms argument selects gettext forms by index with a Plural-Forms
+// expression, {n, pluralforms, expr, 0 {...} other {...}}.
+func (p *patternParser) parseArg() (Node, error) {
+	p.pos++ // {
+	n := Node{Kind: ArgNode}
+	p.skipSpace()
+	n.Name = p.parseWord()
+	if n.Name == "" {
+		return n, p.errorf("missing argument name")
+	}
+	p.skipSpace()
+	if p.pos >= len(p.pattern) {
+		return n, p.errorf("unclosed '{'")
+	}
+	if p.consume('}') {
+		return n, nil
+	}
+	if !p.consume(',') {
+		return n, p.errorf("expected ',' or '}' after argument name")
+	}
+	p.skipSpace()
+	n.Type = p.parseWord()
+	if n.Type == "" {
+		return n, p.errorf("missing argument type")
+	}
+	p.skipSpace()
+	if p.consume('}') {
+		if isChoiceType(n.Type) {
+			return n, p.errorf("missing options of " + n.Type + " argument")
+		}
+		return n, nil
+	}
+	if !p.consume(',') {
+		return n, p.errorf("expected ',' or '}' after argument type")
+	}
+	if isChoiceType(n.Type) {
+		if n.Type == "pluralrange" && !strings.Contains(n.Name, ":") {
+			return n, p.errorf("pluralrange argument " + n.Name + " is not named from:to")
+		}
+		if n.Type == "pluralforms" {
+			expr, err := p.parsePluralFormsExpr()
+			if err != nil {
+				return n, err
+			}
+			n.Style = expr
+		}
+		return n, p.parseOptions(&n)
+	}
+	style, err := p.parseStyle()
+	n.Style = style
+	return n, err
+}
+
+// parseOptions Parses the options of a plural or select argument up to its
+// closing brace.
+func (p *patternParser) parseOptions(n *Node) error {
+	inPlural := n.Type != "select"
+	hasOther := false
 	for {
-		if pos+1 > length {
-			break
+		p.skipSpace()
+		if p.pos >= len(p.pattern) {
+			return p.errorf("unclosed '{'")
 		}
-		open := strings.Index(pattern[pos+1:], "{")
-		closing := strings.Index(pattern[pos+1:], "}")
-		if open == -1 && closing == -1 {
+		if p.consume('}') {
 			break
 		}
-		if open == -1 {
-			open = length
+		selector := p.parseSelector()
+		if selector == "" {
+			return p.errorf("missing selector")
+		}
+		if strings.HasPrefix(selector, "offset:") {
+			offset, err := strconv.Atoi(selector[len("offset:"):])
+			if err != nil || offset < 0 || n.Type != "plural" || len(n.Variants) > 0 {
+				return p.errorf("invalid " + selector + " of " + n.Type + " argument " + n.Name)
+			}
+			n.Offset = offset
+			continue
+		}
+		if strings.HasPrefix(selector, "=") {
+			if _, err := strconv.ParseFloat(selector[1:], 64); err != nil || !inPlural {
+				return p.errorf("invalid selector " + selector)
+			}
 		}
-		if closing > open {
+		p.skipSpace()
+		if !p.consume('{') {
+			return p.errorf("expected '{' after selector " + selector)
+		}
+		if p.depth++; p.depth > maxPatternDepth {
+			return p.errorf("arguments nested too deeply")
+		}
+		nodes, err := p.parseMessage(inPlural, true)
+		if err != nil {
+			return err
+		}
+		p.depth--
+		p.pos++ // }
+		n.Variants = append(n.Variants, Variant{Selector: selector, Nodes: nodes})
+		hasOther = hasOther || selector == "other"
+	}
+	if !hasOther {
+		return p.errorf("missing 'other' option of " + n.Type + " argument " + n.Name)
+	}
+	return nil
+}
+
+// parsePluralFormsExpr Returns the Plural-Forms expression of a pluralforms
+// argument up to the comma before its options.
+func (p *patternParser) parsePluralFormsExpr() (string, error) {
+	start := p.pos
+	end := strings.IndexAny(p.pattern[p.pos:], ",{}")
+	if end == -1 || p.pattern[p.pos+end] != ',' {
+		return "", p.errorf("missing expression of pluralforms argument")
+	}
+	p.pos += end + 1
+	expr := strings.TrimSpace(p.pattern[start : p.pos-1])
+	if _, err := compilePluralExpr(expr); err != nil {
+		return "", p.errorf(err.Error())
+	}
+	return expr, nil
+}
+
+// parseStyle Returns the style of a simple argument up to its closing brace,
+// which may contain quoted text and balanced braces.
+func (p *patternParser) parseStyle() (string, error) {
+	start, depth := p.pos, 0
+	for p.pos < len(p.pattern) {
+		switch p.pattern[p.pos] {
+		case '\'':
+			if end := strings.IndexByte(p.pattern[p.pos+1:], '\''); end != -1 {
+				p.pos += end + 1
+			}
+		case '{':
 			depth++
-			pos = open
-		} else {
+		case '}':
+			if depth == 0 {
+				style := strings.TrimSpace(p.pattern[start:p.pos])
+				p.pos++
+				return style, nil
+			}
 			depth--
-			pos = closing
 		}
-		if depth == 0 {
-			start = pos + 1
-			tokens = append(tokens, pattern[start:open])
-			start = open
+		p.pos++
+	}
+	return "", p.errorf("unclosed '{'")
+}
+
+// parseWord Returns the identifier at the current position.
+func (p *patternParser) parseWord() string {
+	start := p.pos
+	for p.pos < len(p.pattern) {
+		c := p.pattern[p.pos]
+		if c == '{' || c == '}' || c == ',' || c == '\'' || c == '#' || isSpace(c) {
+			break
 		}
-		if depth != 0 && (open == -1 || closing == -1) {
+		p.pos++
+	}
+	return p.pattern[start:p.pos]
+}
+
+// parseSelector Returns the selector at the current position.
+func (p *patternParser) parseSelector() string {
+	start := p.pos
+	for p.pos < len(p.pattern) {
+		c := p.pattern[p.pos]
+		if c == '{' || c == '}' || isSpace(c) {
 			break
 		}
+		p.pos++
 	}
-	if depth != 0 {
-		return nil
+	return p.pattern[start:p.pos]
+}
+
+func (p *patternParser) skipSpace() {
+	for p.pos < len(p.pattern) && isSpace(p.pattern[p.pos]) {
+		p.pos++
+	}
+}
+
+// consume Advances past c if it is the next character.
+func (p *patternParser) consume(c byte) bool {
+	if p.pos < len(p.pattern) && p.pattern[p.pos] == c {
+		p.pos++
+		return true
 	}
+	return false
+}
+
+// isChoiceType Reports whether arguments of type t have options.
+func isChoiceType(t string) bool {
+	return t == "plural" || t == "select" || t == "selectordinal" || t == "pluralrange" || t == "pluralforms"
+}
 
-	return tokens
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
 }
@@ -0,0 +1,625 @@
+package ii18n
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	f := NewFormatter()
+	tests := []struct {
+		pattern  string
+		params   map[string]string
+		expected string
+	}{
+		{"Hello {name}", map[string]string{"name": "Ana"}, "Hello Ana"},
+		{"Hello {name}", nil, "Hello {name}"},
+		{"{n, plural, one {# file} other {# files}}", map[string]string{"n": "1"}, "1 file"},
+		{"{n, plural, one {# file} other {# files}}", map[string]string{"n": "3"}, "3 files"},
+		{"{n, plural, =0 {no files} one {# file} other {# files}}", map[string]string{"n": "0"}, "no files"},
+		{"{n, plural, offset:1 =0 {Nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", map[string]string{"n": "1", "host": "Ana"}, "Ana"},
+		{"{n, plural, offset:1 =0 {Nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", map[string]string{"n": "2", "host": "Ana"}, "Ana and 1 other"},
+		{"{n, plural, offset:1 =0 {Nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", map[string]string{"n": "4", "host": "Ana"}, "Ana and 3 others"},
+		{"{n, plural, one {# litre} other {# litres}}", map[string]string{"n": "1.0"}, "1.0 litres"},
+		{"{n, plural, one {# litre} other {# litres}}", map[string]string{"n": "1234.50"}, "1,234.50 litres"},
+		{"{g, select, female {She} other {They}} left", map[string]string{"g": "female"}, "She left"},
+		{"{g, select, female {She} other {They}} left", map[string]string{"g": "x"}, "They left"},
+		{"It''s '{literal}' {n, number}", map[string]string{"n": "5"}, "It's {literal} 5"},
+		{"Don't", nil, "Don't"},
+	}
+	for _, test := range tests {
+		actual, err := f.format(test.pattern, test.params, "en-US")
+		if err != nil || actual != test.expected {
+			t.Errorf("format(%q) = %q, %v, want %q", test.pattern, actual, err, test.expected)
+		}
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	names, err := Placeholders("{a} {n, plural, one {{b}} other {#}} {a}")
+	if err != nil || !reflect.DeepEqual(names, []string{"a", "b", "n"}) {
+		t.Errorf("Placeholders = %v, %v", names, err)
+	}
+	for _, pattern := range []string{"{", "}", "{}", "{n, plural, one {x}}", "{n, select, other {x}", "{a b}", "{n, plural, =x {a} other {b}}", "{n, select, offset:1 other {x}}", "{n, plural, one {a} offset:1 other {b}}"} {
+		if _, err := Placeholders(pattern); err == nil {
+			t.Errorf("Placeholders(%q) succeeded", pattern)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		lang     string
+		opts     *NumberOptions
+		expected string
+	}{
+		{1234567.891, "en-US", nil, "1,234,567.891"},
+		{"2.5", "en", &NumberOptions{MinFractionDigits: 2, MaxFractionDigits: 2}, "2.50"},
+		{2.5, "en", &NumberOptions{}, "2"},
+		{3.5, "en", &NumberOptions{}, "4"},
+		{2.5, "en", &NumberOptions{RoundingMode: RoundHalfUp}, "3"},
+		{"9.999", "en", &NumberOptions{MaxFractionDigits: 2}, "10"},
+		{"-0.001", "en", &NumberOptions{MaxFractionDigits: 2}, "0"},
+		{"2.345", "en", &NumberOptions{MinFractionDigits: 2}, "2.34"},
+		{2, "en", &NumberOptions{MinFractionDigits: 2, MaxFractionDigits: 1}, "2.00"},
+		{1.01, "en", &NumberOptions{RoundingMode: RoundCeiling}, "2"},
+		{-1.01, "en", &NumberOptions{RoundingMode: RoundFloor}, "-2"},
+		{1234, "en", &NumberOptions{NoGrouping: true}, "1234"},
+	}
+	for _, test := range tests {
+		actual, err := FormatNumber(test.value, test.lang, test.opts)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatNumber(%v, %q) = %q, %v, want %q", test.value, test.lang, actual, err, test.expected)
+		}
+	}
+	if _, err := FormatNumber("1,5", "en", nil); err == nil {
+		t.Error("FormatNumber(\"1,5\") succeeded")
+	}
+	if _, err := FormatNumber(1.5, "en", &NumberOptions{MaxFractionDigits: -1}); err == nil {
+		t.Error("FormatNumber() with negative fraction digits succeeded")
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		amount   interface{}
+		code     string
+		lang     string
+		opts     *CurrencyOptions
+		expected string
+	}{
+		{1234.5, "USD", "en-US", nil, "$1,234.50"},
+		{1.2346, "KWD", "en", nil, "KWD\u00a01.235"},
+		{-5, "USD", "en", nil, "-$5.00"},
+		{-5, "USD", "en", &CurrencyOptions{Accounting: true}, "($5.00)"},
+		{5, "USD", "en", &CurrencyOptions{Style: CurrencyCode}, "USD\u00a05.00"},
+		{1, "USD", "en", &CurrencyOptions{Style: CurrencyName}, "1.00 US dollars"},
+		{1, "JPY", "en", &CurrencyOptions{Style: CurrencyName}, "1 Japanese yen"},
+	}
+	for _, test := range tests {
+		actual, err := FormatCurrency(test.amount, test.code, test.lang, test.opts)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatCurrency(%v, %s, %q) = %q, %v, want %q", test.amount, test.code, test.lang, actual, err, test.expected)
+		}
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	tm := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+	tests := []struct {
+		style    DateStyle
+		lang     string
+		format   func(time.Time, DateStyle, string) (string, error)
+		expected string
+	}{
+		{DateFull, "en-US", FormatDate, "Tuesday, March 5, 2024"},
+		{DateMedium, "en-US", FormatDate, "Mar 5, 2024"},
+		{DateShort, "en-US", FormatDate, "3/5/24"},
+		{DateShort, "en-US", FormatTime, "2:07\u202fPM"},
+		{DateMedium, "en-US", FormatDateTime, "Mar 5, 2024, 2:07:09\u202fPM"},
+		{"yMMMd", "en", FormatDate, "Mar 5, 2024"},
+		{"yMMMdHm", "en", FormatDateTime, "Mar 5, 2024, 14:07"},
+	}
+	for _, test := range tests {
+		actual, err := test.format(tm, test.style, test.lang)
+		if err != nil || actual != test.expected {
+			t.Errorf("format(%s, %q) = %q, %v, want %q", test.style, test.lang, actual, err, test.expected)
+		}
+	}
+	if _, err := FormatDate(tm, "yQQQ", "en"); err == nil {
+		t.Error("FormatDate(yQQQ) succeeded")
+	}
+	f := NewFormatter()
+	actual, _ := f.format("Due {d, date, ::yMMMd}", map[string]string{"d": "2024-03-05T14:07:09Z"}, "en")
+	if actual != "Due Mar 5, 2024" {
+		t.Errorf("format date = %q", actual)
+	}
+	// Values of unsupported skeletons are written unchanged.
+	actual, _ = f.format("on {d, date, ::yQQQ} x", map[string]string{"d": "0"}, "en")
+	if actual != "on 0 x" {
+		t.Errorf("format date of an unsupported skeleton = %q", actual)
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	ref := time.Date(2024, time.March, 5, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t        time.Time
+		lang     string
+		width    RelativeWidth
+		expected string
+	}{
+		{ref, "en", RelativeLong, "now"},
+		{ref.Add(-30 * time.Second), "en", RelativeLong, "30 seconds ago"},
+		{ref.Add(2 * time.Hour), "en", RelativeLong, "in 2 hours"},
+		{ref.Add(2 * time.Hour), "en", RelativeNarrow, "in 2h"},
+		{ref.AddDate(0, 0, -1), "en", RelativeLong, "yesterday"},
+		{ref.AddDate(0, 0, -14), "en", RelativeShort, "2 wk. ago"},
+	}
+	for _, test := range tests {
+		actual := FormatRelativeTime(test.t, ref, test.lang, test.width)
+		if actual != test.expected {
+			t.Errorf("FormatRelativeTime(%v, %q) = %q, want %q", test.t, test.lang, actual, test.expected)
+		}
+	}
+	if actual, _ := FormatRelative(-1, RelativeDay, "en", RelativeLong, true); actual != "1 day ago" {
+		t.Errorf("FormatRelative numeric = %q", actual)
+	}
+	if _, err := FormatRelative(1, "fortnight", "en", RelativeLong, false); err == nil {
+		t.Error("FormatRelative(fortnight) succeeded")
+	}
+}
+
+func TestFormatList(t *testing.T) {
+	tests := []struct {
+		items    []string
+		lang     string
+		style    ListStyle
+		expected string
+	}{
+		{nil, "en", ListAnd, ""},
+		{[]string{"A"}, "en", ListAnd, "A"},
+		{[]string{"A", "B"}, "en", ListAnd, "A and B"},
+		{[]string{"A", "B", "C", "D"}, "en-US", ListAnd, "A, B, C, and D"},
+		{[]string{"A", "B", "C"}, "en-GB", ListAnd, "A, B and C"},
+		{[]string{"A", "B", "C"}, "en", ListOr, "A, B, or C"},
+		{[]string{"A", "B", "C"}, "en", ListAndShort, "A, B, & C"},
+		{[]string{"A", "B", "C"}, "en", ListStyle(42), "A, B, and C"},
+		{[]string{"A", "B"}, "en", ListStyle(-1), "A and B"},
+	}
+	for _, test := range tests {
+		actual := FormatList(test.items, test.lang, test.style)
+		if actual != test.expected {
+			t.Errorf("FormatList(%v, %q) = %q, want %q", test.items, test.lang, actual, test.expected)
+		}
+	}
+}
+
+func TestDisplayNames(t *testing.T) {
+	tests := []struct {
+		actual   string
+		expected string
+	}{
+		{LanguageName("de", "en"), "German"},
+		{LanguageName("de", "fr-FR"), "allemand"},
+		{LanguageName("pt-BR", "en"), "Brazilian Portuguese"},
+		{LanguageName("de-CH", "en"), "German (Switzerland)"},
+		{LanguageName("sr-Latn-RS", "en"), "sr-Latn-RS"},
+		{LanguageName("zh-Hans-CN", "ja"), "中国語 (簡体字、中国)"},
+		{LanguageName("pl", "pl"), "polski"},
+		{LanguageName("de-AT", "zh"), "德语（奥地利）"},
+		{RegionName("BR", "de"), "Brasilien"},
+		{RegionName("br", "ko"), "Brazil"},
+		{RegionName("XX", "en"), "XX"},
+		{ScriptName("cyrl", "ru"), "кириллица"},
+	}
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("display name = %q, want %q", test.actual, test.expected)
+		}
+	}
+}
+
+func TestCasing(t *testing.T) {
+	tests := []struct {
+		actual   string
+		expected string
+	}{
+		{ToUpper("istanbul", "tr-TR"), "İSTANBUL"},
+		{ToUpper("istanbul", "en"), "ISTANBUL"},
+		{ToLower("DİYARBAKIR", "tr"), "diyarbakır"},
+		{ToLower("DİYARBAKIR", "en"), "diyarbakir"},
+		{ToUpper("Καλημέρα", "el"), "ΚΑΛΗΜΕΡΑ"},
+		{ToUpper("Καλημέρα", "en"), "ΚΑΛΗΜΈΡΑ"},
+		{ToLower("ΟΔΟΣ ΣΟΦΙΑΣ", "el"), "οδο\u03c2 σοφια\u03c2"},
+		{ToLower("Σ", "el"), "σ"},
+		{ToLower("ÍJ\u0300", "lt"), "i\u0307\u0301j\u0307\u0300"},
+		{ToUpper("i\u0307\u0301", "lt"), "I\u0301"},
+		{ToUpper("Straße", "de"), "STRASSE"},
+		{ToTitle("the lord of the rings", "en"), "The Lord of the Rings"},
+		{ToTitle("what it's made of", "en-GB"), "What It's Made Of"},
+		{ToTitle("iPhone tips: a guide to NASA", "en"), "iPhone Tips: A Guide to NASA"},
+		{ToTitle("self-driving cars in (new) york", "en"), "Self-Driving Cars in (New) York"},
+		{ToTitle("le seigneur des anneaux: la communauté", "fr"), "Le seigneur des anneaux: la communauté"},
+		{ToTitle("ijsselmeer bij nacht", "nl"), "IJsselmeer bij nacht"},
+		{ToTitle("istanbul gecesi", "tr"), "İstanbul gecesi"},
+		{ToTitle("東京 travel guide", "ja"), "東京 Travel Guide"},
+	}
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("casing = %+q, want %+q", test.actual, test.expected)
+		}
+	}
+}
+
+func TestCollation(t *testing.T) {
+	words := []string{"Zebra", "Öl", "ostern", "Äpfel", "apfel", "Apfel", "über", "Ufer", "Straße", "Strasse", "2"}
+	tests := []struct {
+		lang     string
+		expected []string
+	}{
+		{"de", []string{"2", "apfel", "Apfel", "Äpfel", "Öl", "ostern", "Strasse", "Straße", "über", "Ufer", "Zebra"}},
+		{"sv-SE", []string{"2", "apfel", "Apfel", "ostern", "Strasse", "Straße", "Ufer", "über", "Zebra", "Äpfel", "Öl"}},
+		{"es", []string{"2", "apfel", "Apfel", "Äpfel", "Öl", "ostern", "Strasse", "Straße", "über", "Ufer", "Zebra"}},
+	}
+	for _, test := range tests {
+		actual := slices.Clone(words)
+		SortStrings(actual, test.lang)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("SortStrings(%s) = %q, want %q", test.lang, actual, test.expected)
+		}
+	}
+	for _, test := range []struct {
+		a, b, lang string
+	}{
+		{"nube", "ñandú", "es"}, {"ñandú", "oso", "es"}, {"hal", "ısı", "tr"}, {"ısı", "iğne", "tr"},
+		{"hrad", "chata", "cs"}, {"chata", "ihla", "cs"}, {"zoo", "æble", "da"}, {"role", "Role", "fr"},
+		{"Role", "rôle", "fr"}, {"rôle", "roles", "fr"}, {"άλφα", "βήτα", "el"},
+	} {
+		if !Less(test.a, test.b, test.lang) || Less(test.b, test.a, test.lang) {
+			t.Errorf("Less(%q, %q, %s) = false", test.a, test.b, test.lang)
+		}
+	}
+	c := NewCollator("de")
+	c.Strength = CollateSecondary
+	if c.Compare("Müller", "müller") != 0 || c.Compare("Müller", "muller") != 1 {
+		t.Error("CollateSecondary does not ignore case only")
+	}
+	c.Strength = CollatePrimary
+	if c.Compare("Müller", "muller") != 0 || c.Compare("ΟΔΟΣ", "οδος") != 0 {
+		t.Error("CollatePrimary does not ignore accents and case")
+	}
+}
+
+func TestTimeZoneName(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		actual   string
+		expected string
+	}{
+		{TimeZoneName(berlin, "de", TimeZoneDaylight), "Mitteleuropäische Sommerzeit"},
+		{TimeZoneName(berlin, "en", TimeZoneGeneric), "Central European Time"},
+		{TimeZoneName(berlin, "ja", TimeZoneCity), "ベルリン"},
+		{TimeZoneName(time.UTC, "fr", TimeZoneStandard), "temps universel coordonné"},
+		{TimeZoneName(time.FixedZone("Asia/Kathmandu", 0), "en", TimeZoneGeneric), "Kathmandu Time"},
+	}
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("TimeZoneName = %q, want %q", test.actual, test.expected)
+		}
+	}
+}
+
+func TestFormatDateCalendars(t *testing.T) {
+	tests := []struct {
+		t        time.Time
+		lang     string
+		expected string
+	}{
+		{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "en-u-ca-buddhist", "March 5, 2567 BE"},
+		{time.Date(2024, 3, 19, 0, 0, 0, 0, time.UTC), "en-u-ca-persian", "Esfand 29, 1402 AP"},
+		{time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), "en-IR", "Farvardin 1, 1403 AP"},
+		{time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), "en-u-ca-islamic-umalqura", "Ramadan 1, 1445 AH"},
+		{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "en-u-ca-hebrew", "Adar I 25, 5784 AM"},
+		{time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC), "en-u-ca-hebrew", "Adar II 14, 5784 AM"},
+		{time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC), "en-u-ca-hebrew", "Adar 14, 5783 AM"},
+		{time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC), "en-u-ca-hebrew", "Tishri 1, 5785 AM"},
+		// Locales without date patterns are formatted in English.
+		{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "th-TH", "March 5, 2024"},
+		{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "fa-IR", "March 5, 2024"},
+		{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "he-u-ca-hebrew", "March 5, 2024"},
+		{time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), "ar-SA", "March 11, 2024"},
+	}
+	for _, test := range tests {
+		actual, err := FormatDate(test.t, DateLong, test.lang)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatDate(%v, %q) = %q, %v, want %q", test.t, test.lang, actual, err, test.expected)
+		}
+	}
+}
+
+func TestFormatDateInterval(t *testing.T) {
+	day := func(m time.Month, d int, h int, min int) time.Time {
+		return time.Date(2025, m, d, h, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		from     time.Time
+		to       time.Time
+		skeleton string
+		lang     string
+		expected string
+	}{
+		{day(1, 3, 0, 0), day(1, 5, 0, 0), "yMMMd", "en", "Jan 3\u2009–\u20095, 2025"},
+		{day(1, 3, 0, 0), day(2, 5, 0, 0), "yMMMd", "en", "Jan 3\u2009–\u2009Feb 5, 2025"},
+		{day(1, 3, 0, 0), day(1, 3, 9, 0), "yMMMd", "en", "Jan 3, 2025"},
+		{day(1, 3, 10, 0), day(1, 3, 11, 30), "Hm", "en", "10:00\u2009–\u200911:30"},
+		{day(1, 3, 10, 0), day(1, 3, 13, 30), "hm", "en", "10:00\u202fAM\u2009–\u20091:30\u202fPM"},
+		{day(1, 3, 10, 0), day(1, 3, 11, 30), "yMMMdHm", "en", "Jan 3, 2025, 10:00\u2009–\u200911:30"},
+		{day(1, 3, 10, 0), day(1, 4, 11, 30), "yMMMdHm", "en", "Jan 3, 2025, 10:00\u2009–\u2009Jan 4, 2025, 11:30"},
+	}
+	for _, test := range tests {
+		actual, err := FormatDateInterval(test.from, test.to, test.skeleton, test.lang)
+		if err != nil || actual != test.expected {
+			t.Errorf("FormatDateInterval(%s, %q) = %q, %v, want %q", test.skeleton, test.lang, actual, err, test.expected)
+		}
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		lang     string
+		n        interface{}
+		expected string
+	}{
+		{"en", 1, "one"},
+		{"en", "1.0", "other"},
+		{"en", 2, "other"},
+		{"fr", 0, "one"},
+		{"fr", 1.5, "one"},
+		{"fr", 1000000, "many"},
+		{"ru", 1, "one"},
+		{"ru", 3, "few"},
+		{"ru", 11, "many"},
+		{"ru", 21, "one"},
+		{"ru", "1.5", "other"},
+		{"pl", 22, "few"},
+		{"pl", 25, "many"},
+		{"ar", 0, "zero"},
+		{"ar", 2, "two"},
+		{"ar", 105, "few"},
+		{"ar", 111, "many"},
+		{"cy", 6, "many"},
+		{"lv", 0, "zero"},
+		{"he", 2, "two"},
+		{"ja", 1, "other"},
+		{"pt-PT", 0, "other"},
+		{"pt-BR", 0, "one"},
+		{"xx", 1, "other"},
+		{"en", "x", "other"},
+	}
+	for _, test := range tests {
+		if actual := PluralCategory(test.lang, test.n); actual != test.expected {
+			t.Errorf("PluralCategory(%q, %v) = %q, want %q", test.lang, test.n, actual, test.expected)
+		}
+	}
+	f := NewFormatter()
+	pattern := "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}"
+	if actual, _ := f.format(pattern, map[string]string{"n": "5"}, "ru"); actual != "5 файлов" {
+		t.Errorf("format ru plural = %q", actual)
+	}
+}
+
+func TestOrdinalCategory(t *testing.T) {
+	tests := []struct {
+		lang     string
+		n        interface{}
+		expected string
+	}{
+		{"en", 1, "one"},
+		{"en", 2, "two"},
+		{"en", 3, "few"},
+		{"en", 11, "other"},
+		{"en", 22, "two"},
+		{"en", 113, "other"},
+		{"cy", 0, "zero"},
+		{"cy", 5, "many"},
+		{"uk", 3, "few"},
+		{"uk", 13, "other"},
+		{"de", 1, "other"},
+	}
+	for _, test := range tests {
+		if actual := OrdinalCategory(test.lang, test.n); actual != test.expected {
+			t.Errorf("OrdinalCategory(%q, %v) = %q, want %q", test.lang, test.n, actual, test.expected)
+		}
+	}
+	f := NewFormatter()
+	pattern := "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}"
+	if actual, _ := f.format(pattern, map[string]string{"n": "23"}, "en"); actual != "23rd" {
+		t.Errorf("format selectordinal = %q", actual)
+	}
+}
+
+func TestPluralRangeCategory(t *testing.T) {
+	tests := []struct {
+		lang     string
+		from, to interface{}
+		expected string
+	}{
+		{"de", 1, 3, "other"},
+		{"de", 0, 1, "one"},
+		{"en", 0, 1, "other"},
+		{"ru", 1, 2, "few"},
+		{"ro", 2, 1, "few"},
+		{"lv", 1, 1, "other"},
+		{"ja", 1, 3, "other"},
+	}
+	for _, test := range tests {
+		if actual := PluralRangeCategory(test.lang, test.from, test.to); actual != test.expected {
+			t.Errorf("PluralRangeCategory(%q, %v, %v) = %q, want %q", test.lang, test.from, test.to, actual, test.expected)
+		}
+	}
+	f := NewFormatter()
+	pattern := "{min:max, pluralrange, one {# Tag} other {# Tage}}"
+	if actual, _ := f.format(pattern, map[string]string{"min": "1", "max": "3"}, "de"); actual != "1–3 Tage" {
+		t.Errorf("format pluralrange = %q", actual)
+	}
+	if _, err := ParsePattern("{n, pluralrange, other {#}}"); err == nil {
+		t.Error("expected error for pluralrange without from:to")
+	}
+	names, _ := Placeholders(pattern)
+	if !reflect.DeepEqual(names, []string{"max", "min"}) {
+		t.Errorf("Placeholders = %v", names)
+	}
+}
+
+func TestRegisterPluralRule(t *testing.T) {
+	RegisterPluralRule("tlh", func(ops Operands) Category {
+		if ops.I == 1 && ops.V == 0 {
+			return PluralOne
+		}
+		return PluralOther
+	})
+	defer delete(customPlurals, "tlh")
+	if actual := PluralCategory("tlh-Latn", 1); actual != "one" {
+		t.Errorf("PluralCategory(tlh-Latn, 1) = %q", actual)
+	}
+	if actual := PluralCategory("tlh", "1.0"); actual != "other" {
+		t.Errorf("PluralCategory(tlh, 1.0) = %q", actual)
+	}
+}
+
+func TestPluralSamples(t *testing.T) {
+	tests := []struct {
+		lang     string
+		category Category
+		expected []string
+	}{
+		{"en", PluralOne, []string{"1"}},
+		{"en", PluralOther, []string{"0", "2", "3", "4", "5", "6", "7", "8", "0.0", "0.1", "0.2", "0.3", "0.4", "0.5", "0.6", "0.7"}},
+		{"ru", PluralFew, []string{"2", "3", "4", "22", "23", "24", "32", "33"}},
+		{"fr", PluralMany, []string{"1000000", "10000000"}},
+		{"ja", PluralOne, nil},
+	}
+	for _, test := range tests {
+		if actual := PluralSamples(test.lang, test.category); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("PluralSamples(%q, %q) = %q, want %q", test.lang, test.category, actual, test.expected)
+		}
+	}
+}
+
+func TestCheckGrammar(t *testing.T) {
+	if g := ParseGender(" F "); g != GenderFemale {
+		t.Errorf("ParseGender = %q", g)
+	}
+	pattern := "{gender, select, female {Sie} other {Er}} sah {case, select, dative {dem Hund} nominative {der Hund} vocative {Hund} other {den Hund}}"
+	problems, err := CheckGrammar(pattern, "de-AT")
+	expected := []string{"case: missing accusative", "case: missing genitive", "case: unknown vocative", "gender: missing male"}
+	if err != nil || !reflect.DeepEqual(problems, expected) {
+		t.Errorf("CheckGrammar = %q, %v, want %q", problems, err, expected)
+	}
+	if problems, _ := CheckGrammar("{case, select, foo {x} other {y}}", "en"); len(problems) != 0 {
+		t.Errorf("CheckGrammar(en) = %q", problems)
+	}
+}
+
+func FuzzTokenizePattern(f *testing.F) {
+	for _, seed := range []string{
+		"Hello {name}",
+		"{n, plural, offset:1 =0 {none} one {# item} other {# items}}",
+		"{n, selectordinal, one {#st} two {#nd} other {#th}}",
+		"{g, select, male {he} female {she} other {they}}",
+		"{n, pluralforms, n != 1, 0 {one} other {many}}",
+		"{a:b, pluralrange, one {# day} other {# days}}",
+		"'{literal}' it''s {n, number, ::currency/EUR}",
+		"{d, date, long} {d, time, short}",
+	} {
+		f.Add(seed)
+	}
+	params := map[string]string{"n": "3", "name": "Ann", "g": "female", "a": "1", "b": "4", "d": "2024-03-05T14:07:00Z"}
+	f.Fuzz(func(t *testing.T, pattern string) {
+		if _, err := parsePattern(pattern); err != nil {
+			if !errors.Is(err, ErrInvalidPattern) {
+				t.Fatalf("parsePattern(%q) = %v, not ErrInvalidPattern", pattern, err)
+			}
+			return
+		}
+		Placeholders(pattern)
+		NewFormatter().Format(pattern, params, "en")
+	})
+}
+
+func TestNestingLimit(t *testing.T) {
+	deep := strings.Repeat("{a, select, other {", 100) + strings.Repeat("}}", 100)
+	if _, err := parsePattern(deep); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("parsePattern(100 nested selects) = %v", err)
+	}
+	if _, err := parsePattern(strings.Repeat("{a, select, other {", 10) + "x" + strings.Repeat("}}", 10)); err != nil {
+		t.Errorf("parsePattern(10 nested selects) = %v", err)
+	}
+	for _, expr := range []string{strings.Repeat("(", 1000) + "n" + strings.Repeat(")", 1000), strings.Repeat("!", 1000) + "n"} {
+		if _, err := ParsePluralForms("nplurals=2; plural=" + expr + ";"); err == nil {
+			t.Errorf("ParsePluralForms(%.10s...) succeeded", expr)
+		}
+	}
+}
+
+func BenchmarkFormatPlural(b *testing.B) {
+	f := NewFormatter()
+	pattern := "{n, plural, one {# file} few {# files} many {# files} other {# files}} in {dir}"
+	params := map[string]string{"n": "23", "dir": "docs"}
+	for n := 0; n < b.N; n++ {
+		f.Format(pattern, params, "ru")
+	}
+}
+
+func TestICUConformance(t *testing.T) {
+	data, err := os.ReadFile("testdata/icu_messageformat.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suite struct {
+		Cases []struct {
+			Pattern   string
+			Params    map[string]string
+			Lang      string
+			Expected  string
+			Error     bool
+			Deviation *struct {
+				Actual string
+				Reason string
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFormatter()
+	for _, c := range suite.Cases {
+		if !localeData && c.Lang != "en" {
+			continue
+		}
+		actual, err := f.Format(c.Pattern, c.Params, c.Lang)
+		switch {
+		case c.Error:
+			if !errors.Is(err, ErrInvalidPattern) {
+				t.Errorf("Format(%q) = %q, %v, want ErrInvalidPattern", c.Pattern, actual, err)
+			}
+		case c.Deviation != nil:
+			if actual != c.Deviation.Actual || err != nil {
+				t.Errorf("Format(%q, %s) = %q, %v, documented deviation %q (ICU %q)", c.Pattern, c.Lang, actual, err, c.Deviation.Actual, c.Expected)
+			}
+		case actual != c.Expected || err != nil:
+			t.Errorf("Format(%q, %s) = %q, %v, ICU %q", c.Pattern, c.Lang, actual, err, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package ii18n
+
+import "strings"
+
+// CatalogFunc Returns the catalog of the category named name, the category
+// without its prefix, in lang, or an error wrapping ErrCatalogNotFound when
+// there is none.
+type CatalogFunc func(lang string, name string) (TMsgs, error)
+
+// Type FuncSource serves the catalogs returned by a CatalogFunc, e.g.
+// catalogs fetched from a remote service, with the fallbacks, key
+// normalization and Reload of the file sources. BasePath only prefixes
+// catalog names.
+type FuncSource struct {
+	MessageSource
+	fn CatalogFunc
+}
+
+// NewFuncSource Returns the SourceNewFunc of a FuncSource serving the
+// catalogs of fn. Catalogs are cached once loaded, Reload calls fn again.
+func NewFuncSource(fn CatalogFunc) func(*Config) Source {
+	return func(conf *Config) Source {
+		s := &FuncSource{fn: fn}
+		s.init(conf, "", nil)
+		s.loadFunc = s.loadCatalog
+		return s
+	}
+}
+
+// loadCatalog Returns the catalog of the message file path.
+func (s *FuncSource) loadCatalog(filename string) (TMsgs, error) {
+	lang, name, _ := strings.Cut(strings.TrimPrefix(filename, s.BasePath+"/"), "/")
+	msgs, err := s.fn(lang, strings.ReplaceAll(name, "/", "."))
+	if err != nil {
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	return msgs, nil
+}
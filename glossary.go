@@ -0,0 +1,180 @@
+package ii18n
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Glossary the approved terminology of a product, read by LoadGlossary:
+//
+//	{"terms": [
+//		{"term": "ii18n", "dnt": true},
+//		{"term": "cart", "translations": {"de": ["Warenkorb"], "fr": ["panier"]}}
+//	]}
+type Glossary struct {
+	Terms []GlossaryTerm `json:"terms"`
+}
+
+// GlossaryTerm a term of a Glossary, matched as a whole word, ignoring case
+// unless CaseSensitive.
+type GlossaryTerm struct {
+	Term string `json:"term"`
+	// DNT the term is not translated, e.g. a brand name: translations must
+	// keep it as it is.
+	DNT bool `json:"dnt"`
+	// Translations the approved translations by language, one of which
+	// translations of texts with the term must use. Languages without them,
+	// nor their base language, are not checked.
+	Translations  map[string][]string `json:"translations"`
+	CaseSensitive bool                `json:"caseSensitive"`
+}
+
+// GlossaryError a translation violating the glossary.
+type GlossaryError struct {
+	Term string
+	Lang string
+	// Want the terms the translation must contain one of.
+	Want []string
+}
+
+func (e *GlossaryError) Error() string {
+	if len(e.Want) == 1 && e.Want[0] == e.Term {
+		return "glossary: " + strconv.Quote(e.Term) + " must not be translated"
+	}
+	quoted := make([]string, len(e.Want))
+	for n, want := range e.Want {
+		quoted[n] = strconv.Quote(want)
+	}
+	return "glossary: " + strconv.Quote(e.Term) + " must be translated to " + e.Lang + " as " + strings.Join(quoted, " or ")
+}
+
+// LoadGlossary Reads the JSON glossary filename.
+func LoadGlossary(filename string) (*Glossary, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = DecodeText(data); err != nil {
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	var g Glossary
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, newLoadError(filename, err)
+	}
+	return &g, nil
+}
+
+// Check Returns the violations of the glossary by translation, the
+// translation of source to lang.
+func (g *Glossary) Check(source string, translation string, lang string) []*GlossaryError {
+	var errs []*GlossaryError
+	for _, t := range g.Terms {
+		if t.Term == "" || termIndex(source, t.Term, !t.CaseSensitive) == -1 {
+			continue
+		}
+		want := t.approved(lang)
+		if want == nil {
+			continue
+		}
+		found := false
+		for _, w := range want {
+			// Approved translations are matched ignoring case, as they may
+			// start a sentence.
+			if termIndex(translation, w, !t.DNT || !t.CaseSensitive) != -1 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, &GlossaryError{Term: t.Term, Lang: lang, Want: want})
+		}
+	}
+	return errs
+}
+
+// approved Returns the terms translations of t to lang must contain one of,
+// nil when lang is not checked.
+func (t *GlossaryTerm) approved(lang string) []string {
+	if t.DNT {
+		return []string{t.Term}
+	}
+	if want, ok := t.Translations[lang]; ok {
+		return want
+	}
+	return t.Translations[baseLang(lang)]
+}
+
+// termIndex Returns the byte index of the first whole word occurrence of
+// term in s, ignoring case when fold, or -1.
+func termIndex(s string, term string, fold bool) int {
+	for n := 0; n+len(term) <= len(s); {
+		candidate := s[n : n+len(term)]
+		if (candidate == term || fold && strings.EqualFold(candidate, term)) && isBoundary(s, n, n+len(term)) {
+			return n
+		}
+		_, size := utf8.DecodeRuneInString(s[n:])
+		n += size
+	}
+	return -1
+}
+
+// isBoundary Reports whether s[start:end] is not preceded nor followed by a
+// letter or digit.
+func isBoundary(s string, start int, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(s[:start]); start > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		return false
+	}
+	return true
+}
+
+// GlossaryProvider Returns provider enforcing g: the terms not to translate
+// are replaced by placeholders the provider keeps, and translations
+// violating the glossary fail with a *GlossaryError, so NewMTHandler falls
+// back to the original message.
+func GlossaryProvider(provider MTProvider, g *Glossary) MTProvider {
+	return &glossaryProvider{provider: provider, glossary: g}
+}
+
+// glossaryProvider an MTProvider enforcing a glossary.
+type glossaryProvider struct {
+	provider MTProvider
+	glossary *Glossary
+}
+
+func (p *glossaryProvider) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	protected := text
+	var kept []string
+	for _, t := range p.glossary.Terms {
+		if !t.DNT || t.Term == "" {
+			continue
+		}
+		for {
+			n := termIndex(protected, t.Term, !t.CaseSensitive)
+			if n == -1 {
+				break
+			}
+			token := "{_dnt" + strconv.Itoa(len(kept)) + "}"
+			kept = append(kept, protected[n:n+len(t.Term)])
+			protected = protected[:n] + token + protected[n+len(t.Term):]
+		}
+	}
+	val, err := p.provider.Translate(ctx, protected, from, to)
+	if err != nil {
+		return "", err
+	}
+	for n := len(kept) - 1; n >= 0; n-- {
+		val = strings.ReplaceAll(val, "{_dnt"+strconv.Itoa(n)+"}", kept[n])
+	}
+	if errs := p.glossary.Check(text, val, to); len(errs) > 0 {
+		return "", errs[0]
+	}
+	return val, nil
+}
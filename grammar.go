@@ -0,0 +1,103 @@
+package ii18n
+
+import (
+	"sort"
+	"strings"
+)
+
+// Gender the gender of a person referred to by a message, the value of the
+// conventional {gender, select, ...} argument.
+type Gender string
+
+// Genders.
+const (
+	GenderMale   Gender = "male"
+	GenderFemale Gender = "female"
+	GenderOther  Gender = "other"
+)
+
+// ParseGender Returns the Gender of s, accepting the usual spellings such as
+// "m", "F" or "masculine". Anything else is GenderOther.
+func ParseGender(s string) Gender {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "m", "male", "man", "masculine":
+		return GenderMale
+	case "f", "female", "woman", "feminine":
+		return GenderFemale
+	}
+	return GenderOther
+}
+
+// grammarArgs names of the conventional select arguments and their values
+// by language, besides "other". Languages not listed use the values of "".
+var grammarArgs = map[string]map[string][]string{
+	"gender": {
+		"": {"male", "female"},
+	},
+	"case": {
+		"de": {"nominative", "accusative", "dative", "genitive"},
+		"ru": {"nominative", "genitive", "dative", "accusative", "instrumental", "prepositional"},
+		"uk": {"nominative", "genitive", "dative", "accusative", "instrumental", "locative", "vocative"},
+		"pl": {"nominative", "genitive", "dative", "accusative", "instrumental", "locative", "vocative"},
+		"cs": {"nominative", "genitive", "dative", "accusative", "vocative", "locative", "instrumental"},
+		"fi": {"nominative", "genitive", "partitive", "inessive", "elative", "illative", "adessive", "ablative", "allative", "essive", "translative"},
+	},
+}
+
+// SetGrammarValues sets the values a {arg, select, ...} argument must cover
+// in lang, such as the cases of "case" in a language not listed. An empty
+// lang sets the default of every language. It is not safe to call
+// concurrently with GrammarValues.
+func SetGrammarValues(arg string, lang string, values []string) {
+	if grammarArgs[arg] == nil {
+		grammarArgs[arg] = make(map[string][]string)
+	}
+	grammarArgs[arg][lang] = values
+}
+
+// GrammarValues Returns the values a {arg, select, ...} argument must cover
+// in lang, none for arguments without a convention.
+func GrammarValues(arg string, lang string) []string {
+	return lookupLocale(grammarArgs[arg], lang, "")
+}
+
+// CheckGrammar Returns the problems of the conventional gender and case
+// select arguments of pattern in lang: values of GrammarValues without a
+// variant, and variants that are no such value. Problems are sorted.
+func CheckGrammar(pattern string, lang string) ([]string, error) {
+	nodes, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, n := range nodes {
+			if n.Kind != ArgNode {
+				continue
+			}
+			if values := GrammarValues(n.Name, lang); n.Type == "select" && len(values) > 0 {
+				selectors := make(map[string]bool, len(n.Variants))
+				for _, v := range n.Variants {
+					selectors[v.Selector] = true
+				}
+				for _, value := range values {
+					if !selectors[value] {
+						problems = append(problems, n.Name+": missing "+value)
+					}
+					delete(selectors, value)
+				}
+				delete(selectors, "other")
+				for selector := range selectors {
+					problems = append(problems, n.Name+": unknown "+selector)
+				}
+			}
+			for _, v := range n.Variants {
+				walk(v.Nodes)
+			}
+		}
+	}
+	walk(nodes)
+	sort.Strings(problems)
+	return problems, nil
+}
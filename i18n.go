@@ -1,13 +1,22 @@
 package ii18n
 
 import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 // DefaultOriginalLang default original language
 var DefaultOriginalLang = "en-US"
+
 // Translator translator
 var Translator *I18N
 
@@ -16,10 +25,95 @@ var Translator *I18N
 // 2. T('app.common', 'hot', [], 'zh-CN') // result same to 1.
 // 3. T('msg.a', 'hello', ['{foo}' => 'bar', '{key}' => 'val'] 'ja-JP')
 func T(category string, message string, params map[string]string, lang string) string {
+	return Translator.T(category, message, params, lang)
+}
+
+// TE T, also returning the ParamsError of a translation whose placeholders
+// params do not cover when WithParamCheck is strict.
+func TE(category string, message string, params map[string]string, lang string) (string, error) {
+	category = Translator.normalizeCategory(category)
+	return Translator.translate(context.Background(), category, message, params, lang)
+}
+
+// TranslateBatch Returns the translations of keys in lang, see
+// (*I18N) TranslateBatch.
+func TranslateBatch(category string, keys []string, lang string) (map[string]string, error) {
+	return Translator.TranslateBatch(category, keys, lang)
+}
+
+// TContext T, passing ctx to sources implementing ContextSource so catalog
+// loads are traced as part of the calling request.
+func TContext(ctx context.Context, category string, message string, params map[string]string, lang string) string {
+	category = Translator.normalizeCategory(category)
+	result, _ := Translator.translate(ctx, category, message, params, lang)
+	return result
+}
+
+// MustT T, panicking when message has no translation in lang other than
+// the original language of its category, or TE returns an error, for
+// strings translated once at init time.
+func MustT(category string, message string, params map[string]string, lang string) string {
+	category = Translator.normalizeCategory(category)
+	result, err := Translator.translate(context.Background(), category, message, params, lang)
+	if err != nil {
+		panic(err)
+	}
+	if conf := Translator.getConfig(category); lang != conf.OriginalLang || conf.ForceTranslation {
+		if _, err := Translator.Lookup(category, message, lang); err != nil {
+			panic(err)
+		}
+	}
+	return result
+}
+
+// Tf T without params, then fmt.Sprintf of the translation with args, e.g.
+// Tf("app", "%d files", "de", n) for catalogs with printf verbs.
+func Tf(category string, message string, lang string, args ...interface{}) string {
+	return fmt.Sprintf(T(category, message, nil, lang), args...)
+}
+
+// P Returns the params of names and values in turn, e.g.
+// P("name", name, "count", 3), formatting the values as Translate formats
+// fields. A name without a value is set to "".
+func P(args ...interface{}) map[string]string {
+	params := make(map[string]string, len(args)/2)
+	for n := 0; n < len(args); n += 2 {
+		var val string
+		if n+1 < len(args) {
+			val = paramString(reflect.ValueOf(args[n+1]))
+		}
+		params[paramString(reflect.ValueOf(args[n]))] = val
+	}
+	return params
+}
+
+// complexArg matches patterns with typed arguments, e.g. {n, plural, ...},
+// which need the Formatter; plain {name} placeholders are replaced directly.
+var complexArg = regexp.MustCompile(`\{\s*[\d\w]+\s*,`)
+
+// stripVariant Returns lang without its variant, "de" for "de@brandX".
+func stripVariant(lang string) string {
+	if pos := strings.IndexByte(lang, '@'); pos > 0 {
+		return lang[:pos]
+	}
+	return lang
+}
+
+// normalizeCategory Prefixes categories without a dot with "app.".
+func normalizeCategory(category string) string {
 	if strings.Index(category, ".") == -1 {
-		category = "app." + category
+		return "app." + category
+	}
+	return category
+}
+
+// normalizeCategory Returns the default category of i for "", see
+// WithDefaultCategory, or else normalizeCategory(category).
+func (i *I18N) normalizeCategory(category string) string {
+	if category == "" && i.defaultCategory != "" {
+		return i.defaultCategory
 	}
-	return Translator.translate(category, message, params, lang)
+	return normalizeCategory(category)
 }
 
 // Config config
@@ -29,20 +123,101 @@ type Config struct {
 	ForceTranslation bool
 	BasePath         string
 	FileMap          map[string]string
-	source           Source
+	// RecordMissing appends every key that has no translation to the catalog
+	// of the requested language, so new strings are collected while the
+	// application is exercised. Meant for development only.
+	RecordMissing bool
+	// MissingSidecar makes RecordMissing write to BasePath/missing_{lang}.json
+	// instead of the catalogs themselves.
+	MissingSidecar bool
+	// Fallbacks languages whose catalogs fall back through the listed
+	// languages in order, instead of their parent locales, e.g.
+	// {"pt-BR": {"pt-PT", "pt"}}.
+	Fallbacks map[string][]string
+	// NormalizeKeys normalizes the keys of catalogs when they are loaded and
+	// the messages looked up in them.
+	NormalizeKeys KeyNormalization
+	// MissingFiles how the source treats catalog files that do not exist,
+	// FallbackOnly by default.
+	MissingFiles MissingFilePolicy
+	// ValidateOnLoad checks every catalog when it is loaded, logging each
+	// ValidationIssue as EventInvalid.
+	ValidateOnLoad bool
+	// CompileOnLoad parses the ICU patterns of every catalog when it is
+	// loaded, so translating does not, logging those that fail to parse as
+	// EventInvalid. The time taken is reported by (*I18N) Stats.
+	CompileOnLoad bool
+	// LoadConcurrency the catalog files a source fetches concurrently, e.g.
+	// those of a language and its fallbacks, which it then merges. The files
+	// are fetched in turn when it is below 2.
+	LoadConcurrency int
+	// Version the initial version of versioned catalogs, laid out as
+	// BasePath/{version}/{lang}, see (*I18N) PinVersion. Versions are
+	// directories of whole releases, catalogs carry no version of their own.
+	// Empty for catalogs without versions.
+	Version string
+	// VerifyKey requires every catalog file to have a detached signature by
+	// the key, {file}.sig, verified before the catalog is used, see
+	// VerifySignature. Catalogs written by RecordMissing are not signed.
+	VerifyKey ed25519.PublicKey
+	// DecryptKeys decrypts the catalog files encrypted by EncryptCatalog
+	// when they are loaded; files in the clear load as usual.
+	DecryptKeys KeyProvider
+	// Manifest the file of SHA-256 checksums of the catalogs under
+	// BasePath, or the pinned version, e.g. "SHA256SUMS" as written by
+	// "sha256sum */*.json", see ParseManifest. Catalogs that do not match
+	// it, or are not in it, fail to load.
+	Manifest string
+	source   Source
+	observer *observer
+}
+
+// Miss describes a message without translation.
+type Miss struct {
+	Category     string
+	Message      string
+	Lang         string
+	OriginalLang string
 }
 
+// MissingHandler is called for a message without translation. It returns the
+// pattern to use instead and whether it handled the miss.
+type MissingHandler func(ctx context.Context, miss Miss) (string, bool)
+
 // I18N i18n
 type I18N struct {
-	Translations map[string]*Config
-	formatter    Formatter
-	mutex        sync.RWMutex
+	Translations    map[string]*Config
+	formatter       Formatter
+	missing         *missingRecorder
+	overrides       []*Config
+	overlays        map[string]*I18N
+	defaultLang     string
+	defaultCategory string
+	inherit         bool
+	globalParams    map[string]string
+	variantSelector VariantSelector
+	observer        *observer
+	missingHandler  MissingHandler
+	debugMarkers    bool
+	missingTemplate string
+	checkParams     bool
+	strictParams    bool
+	reloadInterval  time.Duration
+	stop            chan struct{}
+	closeOnce       sync.Once
+	pinMutex        sync.Mutex
+	mutex           sync.RWMutex
 }
 
 // NewI18N returns an instance of I18N.
-func NewI18N(config map[string]Config) *I18N {
+func NewI18N(config map[string]Config, opts ...Option) *I18N {
 	Translator = &I18N{
 		Translations: make(map[string]*Config),
+		missing:      &missingRecorder{},
+		observer:     &observer{},
+	}
+	for _, opt := range opts {
+		opt(Translator)
 	}
 	for key, conf := range config {
 		if conf.SourceNewFunc == nil {
@@ -60,46 +235,563 @@ func NewI18N(config map[string]Config) *I18N {
 		if conf.FileMap == nil {
 			panic("Config FileMap is illegal")
 		}
+		conf.observer = Translator.observer
 		if _, ok := Translator.Translations[key]; !ok {
 			Translator.Translations[key] = &conf
 		}
 	}
+	if Translator.reloadInterval > 0 {
+		Translator.stop = make(chan struct{})
+		go Translator.autoReload(Translator.reloadInterval, Translator.stop)
+	}
 	return Translator
 }
 
+// Clone Returns a copy of i configured by opts, e.g. with another default
+// language, miss policy or overrides for a tenant. The copy shares the
+// sources of i and their loaded catalogs, but not its reloading, and does not
+// replace Translator.
+func (i *I18N) Clone(opts ...Option) *I18N {
+	for _, prefix := range sortedKeys(i.Translations) {
+		// Create the sources so that copies never create them concurrently.
+		i.getSource(prefix + ".")
+	}
+	obs := *i.observer
+	obs.levels = maps.Clone(obs.levels)
+	c := &I18N{
+		Translations:    i.Translations,
+		formatter:       i.formatter,
+		missing:         i.missing,
+		observer:        &obs,
+		missingHandler:  i.missingHandler,
+		debugMarkers:    i.debugMarkers,
+		missingTemplate: i.missingTemplate,
+		checkParams:     i.checkParams,
+		strictParams:    i.strictParams,
+		overrides:       i.overrides[:len(i.overrides):len(i.overrides)],
+		defaultLang:     i.defaultLang,
+		defaultCategory: i.defaultCategory,
+		inherit:         i.inherit,
+		globalParams:    i.globalParams,
+		variantSelector: i.variantSelector,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.reloadInterval > 0 {
+		c.stop = make(chan struct{})
+		go c.autoReload(c.reloadInterval, c.stop)
+	}
+	return c
+}
+
+// T translate with i, see the function T.
+func (i *I18N) T(category string, message string, params map[string]string, lang string) string {
+	category = i.normalizeCategory(category)
+	result, _ := i.translate(context.Background(), category, message, params, lang)
+	return result
+}
+
+// autoReload Reloads the sources every interval until stop is closed.
+func (i *I18N) autoReload(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			i.Reload()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close stops reloading started by WithAutoReload.
+func (i *I18N) Close() {
+	i.closeOnce.Do(func() {
+		if i.stop != nil {
+			close(i.stop)
+		}
+	})
+}
+
 // translate
-func (i *I18N) translate(category string, message string, params map[string]string, lang string) string {
+func (i *I18N) translate(ctx context.Context, category string, message string, params map[string]string, lang string) (string, error) {
+	if lang == "" {
+		lang = i.defaultLang
+	}
+	result, err := i.render(ctx, category, message, params, lang)
+	if i.debugMarkers {
+		return "⟦" + category + ":" + message + "⟧" + result + "⟦/⟧", err
+	}
+	return result, err
+}
+
+// render Resolves and formats message.
+func (i *I18N) render(ctx context.Context, category string, message string, params map[string]string, lang string) (string, error) {
 	s, ol := i.getSource(category)
-	translation, err := s.Translate(category, message, lang)
+	if PseudoLang != "" && lang == PseudoLang {
+		i.observer.served(category, lang)
+		return i.format(category, Pseudolocalize(message), i.mergeGlobals(params), ol), nil
+	}
+	for n := len(i.overrides) - 1; n >= 0; n-- {
+		if translation, err := i.overrides[n].source.TranslateMsg(category, message, lang); err == nil && translation != "" {
+			i.observer.served(category, lang)
+			return i.checkedFormat(category, message, translation, params, lang, ol)
+		}
+	}
+	if i.variantSelector != nil {
+		if variant := i.variantSelector(ctx, category, message); variant != "" {
+			if translation, err := s.TranslateMsg(category, message+"|"+variant, lang); err == nil && translation != "" {
+				i.observer.served(category, lang)
+				return i.checkedFormat(category, message, translation, params, lang, ol)
+			}
+		}
+	}
+	translation, err := sourceTranslate(ctx, s, category, message, lang)
+	if err != nil && i.inherit {
+		for parent := category; strings.Contains(parent, "."); {
+			parent = parent[:strings.LastIndexByte(parent, '.')]
+			if t, perr := sourceTranslate(ctx, s, parent, message, lang); perr == nil && t != "" {
+				translation, err = t, nil
+				break
+			}
+		}
+	}
+	i.observer.served(category, lang)
 	if err != nil || translation == "" {
-		return i.format(message, params, ol)
+		if pattern, ok := i.handleMissing(ctx, category, message, lang, ol); ok {
+			return i.checkedFormat(category, message, pattern, params, lang, ol)
+		}
+		return i.checkedFormat(category, message, message, params, ol, ol)
+	}
+	return i.checkedFormat(category, message, translation, params, lang, ol)
+}
+
+// sourceTranslate Returns the translation of message by s, passing ctx to
+// a ContextSource.
+func sourceTranslate(ctx context.Context, s Source, category string, message string, lang string) (string, error) {
+	if cs, ok := s.(ContextSource); ok {
+		return cs.TranslateContext(ctx, category, message, lang)
+	}
+	return s.Translate(category, message, lang)
+}
+
+// checkedFormat Formats pattern, the resolution of message in lang, checking
+// that params cover its placeholders when WithParamCheck is set. In strict
+// mode a pattern missing params is replaced by the original message.
+func (i *I18N) checkedFormat(category string, message string, pattern string, params map[string]string, lang string, ol string) (string, error) {
+	merged := i.mergeGlobals(params)
+	if !i.checkParams {
+		return i.format(category, pattern, merged, lang), nil
+	}
+	err := checkParams(pattern, params, i.globalParams)
+	if err == nil {
+		return i.format(category, pattern, merged, lang), nil
+	}
+	err.Category, err.Message, err.Lang = category, message, lang
+	i.observer.params(err)
+	if !i.strictParams || len(err.Missing) == 0 {
+		return i.format(category, pattern, merged, lang), nil
+	}
+	return i.format(category, message, merged, ol), err
+}
+
+// mergeGlobals Returns params with the global params of i they do not set,
+// see WithGlobalParams.
+func (i *I18N) mergeGlobals(params map[string]string) map[string]string {
+	if len(i.globalParams) == 0 {
+		return params
+	}
+	merged := make(map[string]string, len(i.globalParams)+len(params))
+	for name, val := range i.globalParams {
+		merged[name] = val
+	}
+	for name, val := range params {
+		merged[name] = val
+	}
+	return merged
+}
+
+// checkParams Returns the ParamsError of formatting pattern with params and
+// globals, nil when they cover its placeholders and params are all used or
+// pattern is invalid.
+func checkParams(pattern string, params map[string]string, globals map[string]string) *ParamsError {
+	names, err := Placeholders(pattern)
+	if err != nil {
+		return nil
+	}
+	var perr ParamsError
+	for _, name := range names {
+		_, ok := params[name]
+		if _, global := globals[name]; !ok && !global {
+			perr.Missing = append(perr.Missing, name)
+		}
+	}
+	for _, name := range sortedKeys(params) {
+		if !contains(names, name) {
+			perr.Extra = append(perr.Extra, name)
+		}
+	}
+	if perr.Missing == nil && perr.Extra == nil {
+		return nil
+	}
+	return &perr
+}
+
+// handleMissing Reports and records a message without translation and asks
+// the MissingHandler for a replacement pattern.
+func (i *I18N) handleMissing(ctx context.Context, category string, message string, lang string, ol string) (string, bool) {
+	conf := i.getConfig(category)
+	if !conf.ForceTranslation && stripVariant(lang) == ol {
+		return "", false
+	}
+	i.observer.missed(category, message, lang)
+	if conf.RecordMissing {
+		if err := i.missing.record(conf, category, message, lang); err != nil {
+			i.observer.recordFailed(category, lang, err)
+		}
+	}
+	if i.missingHandler != nil {
+		if pattern, ok := i.missingHandler(ctx, Miss{Category: category, Message: message, Lang: lang, OriginalLang: ol}); ok {
+			return pattern, true
+		}
 	}
-	return i.format(translation, params, lang)
+	if i.missingTemplate != "" {
+		return strings.NewReplacer("{category}", category, "{key}", message, "{lang}", lang).Replace(i.missingTemplate), true
+	}
+	return "", false
 }
 
-func (i *I18N) format(message string, params map[string]string, lang string) string {
+func (i *I18N) format(category string, message string, params map[string]string, lang string) string {
 	if params == nil {
 		return message
 	}
-	if ok, _ := regexp.MatchString(`~{\s*[\d\w]+\s*,~u`, message); ok {
-		result, err := i.formatter.format(message, params, lang)
+	if complexArg.MatchString(message) {
+		s, _ := i.getSource(category)
+		if pc, ok := s.(patternCompiler); ok {
+			if nodes, ok := pc.compiledPattern(category, lang, message); ok {
+				return i.formatter.formatParsed(nodes, params, stripVariant(lang))
+			}
+		}
+		result, err := i.formatter.format(message, params, stripVariant(lang))
 		if err != nil {
 			return message
 		}
 		return result
 	}
-	oldnew := make([]string, len(params)*2)
+	oldnew := make([]string, 0, len(params)*2)
 	for name, val := range params {
 		oldnew = append(oldnew, "{"+name+"}", val)
 	}
 	return strings.NewReplacer(oldnew...).Replace(message)
 }
 
+// TranslateBatch Returns the translations of keys in lang as T without
+// params would, e.g. to localize a whole view model. When the source of
+// category implements BatchSource and no overrides or variants apply, the
+// keys are resolved in one pass over the catalog and its fallbacks; keys
+// without translation in it are resolved by T. The error is the first TE
+// would return.
+func (i *I18N) TranslateBatch(category string, keys []string, lang string) (map[string]string, error) {
+	category = i.normalizeCategory(category)
+	if lang == "" {
+		lang = i.defaultLang
+	}
+	s, ol := i.getSource(category)
+	var translations TMsgs
+	if bs, ok := s.(BatchSource); ok && len(i.overrides) == 0 && i.variantSelector == nil && !i.debugMarkers &&
+		(PseudoLang == "" || lang != PseudoLang) {
+		// On error, every key is resolved by T, which handles it.
+		translations, _ = bs.TranslateBatch(category, keys, lang)
+	}
+	results := make(map[string]string, len(keys))
+	var firstErr error
+	for _, key := range keys {
+		var result string
+		var err error
+		if translation := translations[key]; translation != "" {
+			i.observer.served(category, lang)
+			result, err = i.checkedFormat(category, key, translation, nil, lang, ol)
+		} else {
+			result, err = i.translate(context.Background(), category, key, nil, lang)
+		}
+		results[key] = result
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return results, firstErr
+}
+
+// Lookup Returns the translation of message in lang from the source of
+// category, without formatting, missing handling or falling back to the
+// original message. Messages without translation are a
+// *MissingTranslationError.
+func (i *I18N) Lookup(category string, message string, lang string) (string, error) {
+	category = i.normalizeCategory(category)
+	s, _ := i.getSource(category)
+	return s.TranslateMsg(category, message, lang)
+}
+
+// Categories Returns the categories of the sources implementing
+// CategoryLister, sorted.
+func (i *I18N) Categories() ([]string, error) {
+	var categories []string
+	for _, prefix := range sortedKeys(i.Translations) {
+		s, _ := i.getSource(prefix + ".")
+		cl, ok := s.(CategoryLister)
+		if !ok {
+			continue
+		}
+		names, err := cl.CategoryNames()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			categories = append(categories, prefix+"."+name)
+		}
+	}
+	sort.Strings(categories)
+	return categories, nil
+}
+
+// Reload asks every loaded source implementing Reloader to refresh its
+// catalogs, by prefix. Sources keep serving their previous catalogs when
+// reloading fails.
+func (i *I18N) Reload() error {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	var errs []error
+	for _, prefix := range sortedKeys(i.Translations) {
+		r, ok := i.Translations[prefix].source.(Reloader)
+		if !ok {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			i.observer.reloadFailed(prefix, err)
+			errs = append(errs, err)
+			continue
+		}
+		i.observer.reloaded(prefix)
+	}
+	for _, conf := range i.overrides {
+		if err := reloadOverrides(conf, i.observer, "overrides"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, tenantID := range sortedKeys(i.overlays) {
+		overlay := i.overlays[tenantID]
+		if err := reloadOverrides(overlay.overrides[len(overlay.overrides)-1], i.observer, "overlay "+tenantID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadCatalog Refreshes the catalogs of category in lang, and in the
+// languages falling back to it, or in every language when lang is "", e.g.
+// when a translation management system reports a change. Sources without
+// CatalogReloader are reloaded entirely.
+func (i *I18N) ReloadCatalog(category string, lang string) error {
+	category = i.normalizeCategory(category)
+	prefix, _, err := splitCategory(category)
+	if err != nil {
+		return err
+	}
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	conf, ok := i.Translations[prefix]
+	if !ok {
+		return fmt.Errorf("%w %q: no source", ErrInvalidCategory, category)
+	}
+	switch r := conf.source.(type) {
+	case CatalogReloader:
+		err = r.ReloadCatalog(category, lang)
+	case Reloader:
+		err = r.Reload()
+	default:
+		return nil
+	}
+	if err != nil {
+		i.observer.reloadFailed(prefix, err)
+		return err
+	}
+	i.observer.reloaded(prefix)
+	return nil
+}
+
+// Preload Loads the catalogs of categories in langs ahead of their first
+// use, e.g. at startup or before a request needing several categories. The
+// catalogs of a source load concurrently, at most Config.LoadConcurrency at
+// a time.
+func (i *I18N) Preload(ctx context.Context, categories []string, langs []string) error {
+	type job struct {
+		source   preloader
+		category string
+		lang     string
+	}
+	jobs := make(map[string][]job)
+	for _, category := range categories {
+		category = i.normalizeCategory(category)
+		prefix, _, err := splitCategory(category)
+		if err != nil {
+			return err
+		}
+		i.mutex.RLock()
+		_, ok := i.Translations[prefix]
+		i.mutex.RUnlock()
+		if !ok {
+			return fmt.Errorf("%w %q: no source", ErrInvalidCategory, category)
+		}
+		s, _ := i.getSource(category)
+		p, ok := s.(preloader)
+		if !ok {
+			continue
+		}
+		for _, lang := range langs {
+			jobs[prefix] = append(jobs[prefix], job{p, category, lang})
+		}
+	}
+	var mutex sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for prefix, js := range jobs {
+		i.mutex.RLock()
+		sem := make(chan struct{}, max(i.Translations[prefix].LoadConcurrency, 1))
+		i.mutex.RUnlock()
+		for _, j := range js {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				if err := j.source.preload(ctx, j.category, j.lang); err != nil {
+					mutex.Lock()
+					errs = append(errs, err)
+					mutex.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// reloadOverrides Reloads the source of the overrides conf, reporting
+// failures as name.
+func reloadOverrides(conf *Config, o *observer, name string) error {
+	r, ok := conf.source.(Reloader)
+	if !ok {
+		return nil
+	}
+	if err := r.Reload(); err != nil {
+		o.reloadFailed(name, err)
+		return err
+	}
+	return nil
+}
+
+// WithOverlay Returns the I18N of the tenant tenantID: a Clone of i with the
+// source of conf stacked above its catalogs and overrides, see
+// WithOverrides. It is created by the first call for tenantID and returned
+// by the later ones, which ignore conf, so every tenant caches its own
+// catalogs once and shares the base catalogs. Reload also reloads the
+// overlays.
+func (i *I18N) WithOverlay(tenantID string, conf Config) *I18N {
+	i.mutex.RLock()
+	overlay, ok := i.overlays[tenantID]
+	i.mutex.RUnlock()
+	if ok {
+		return overlay
+	}
+	overlay = i.Clone(WithOverrides(conf))
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if existing, ok := i.overlays[tenantID]; ok {
+		return existing
+	}
+	if i.overlays == nil {
+		i.overlays = make(map[string]*I18N)
+	}
+	i.overlays[tenantID] = overlay
+	return overlay
+}
+
+// RemoveOverlay Drops the overlay of tenantID and its catalogs, so the next
+// WithOverlay creates it again.
+func (i *I18N) RemoveOverlay(tenantID string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.overlays, tenantID)
+}
+
+// PinVersion Switches every source whose Config has a Version to the
+// catalogs of version, e.g. to roll a translation release out or back. When
+// a source cannot switch, the sources already switched are pinned back to
+// their previous version. Each source switches atomically, but the sources
+// switch in turn: a lookup during PinVersion may see one source at the new
+// version and another at the previous one.
+func (i *I18N) PinVersion(version string) error {
+	i.pinMutex.Lock()
+	defer i.pinMutex.Unlock()
+	type pinned struct {
+		v        Versioner
+		previous string
+	}
+	var done []pinned
+	for _, prefix := range sortedKeys(i.Translations) {
+		if i.Translations[prefix].Version == "" {
+			continue
+		}
+		s, _ := i.getSource(prefix + ".")
+		v, ok := s.(Versioner)
+		if !ok {
+			continue
+		}
+		previous := v.Version()
+		if err := v.PinVersion(version); err != nil {
+			for _, p := range done {
+				p.v.PinVersion(p.previous)
+			}
+			return err
+		}
+		done = append(done, pinned{v, previous})
+	}
+	return nil
+}
+
+// Version Returns the version of the first versioned source by prefix, ""
+// without versioned sources.
+func (i *I18N) Version() string {
+	for _, prefix := range sortedKeys(i.Translations) {
+		if i.Translations[prefix].Version == "" {
+			continue
+		}
+		s, _ := i.getSource(prefix + ".")
+		if v, ok := s.(Versioner); ok {
+			return v.Version()
+		}
+	}
+	return ""
+}
+
 // getFormatter Get the the message formatter.
 func (i *I18N) getFormatter(category string) Formatter {
 	return i.formatter
 }
 
+// getConfig Get the config for the given category.
+func (i *I18N) getConfig(category string) *Config {
+	prefix := strings.Split(category, ".")[0]
+	if val, ok := i.Translations[prefix]; ok {
+		return val
+	}
+	panic("Unable to locate message source for category " + category + ".")
+}
+
 // getSource Get the message source for the given category.
 func (i *I18N) getSource(category string) (Source, string) {
 	prefix := strings.Split(category, ".")[0]
@@ -1,10 +1,31 @@
 package ii18n
 
 import (
+	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/quick"
+	"time"
+	"unsafe"
 )
 
+// quietLogger Returns the Option discarding the log of the I18N under test.
+func quietLogger() Option {
+	return WithLogger(slog.New(slog.DiscardHandler))
+}
+
 func TestTranslate(t *testing.T) {
 	config := map[string]Config{
 		"app": Config{
@@ -21,3 +42,1764 @@ func TestTranslate(t *testing.T) {
 	res := T("app", "hello", nil, "zh-CN")
 	fmt.Println(res)
 }
+
+func TestRecordMissing(t *testing.T) {
+	dir := t.TempDir()
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc:  NewJSONSource,
+			BasePath:       dir,
+			FileMap:        map[string]string{"app": "app.json"},
+			RecordMissing:  true,
+			MissingSidecar: true,
+		},
+	}
+	NewI18N(config)
+	T("app", "b", nil, "zh-CN")
+	T("app", "a", nil, "zh-CN")
+	T("app", "a", nil, "zh-CN")
+	data, err := os.ReadFile(dir + "/missing_zh-CN.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n\t\"app.app\": {\n\t\t\"a\": \"\",\n\t\t\"b\": \"\"\n\t}\n}\n"
+	if string(data) != expected {
+		t.Errorf("sidecar = %q, want %q", data, expected)
+	}
+}
+
+func TestRecordMissingCatalog(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/zh-CN", 0755)
+	os.WriteFile(dir+"/zh-CN/app.json", []byte(`{"hello": "你好"}`), 0644)
+	NewI18N(map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      dir,
+			FileMap:       map[string]string{"app": "app.json"},
+			RecordMissing: true,
+		},
+	}, quietLogger())
+	for _, key := range []string{"b", "hello", "a", "b"} {
+		T("app", key, nil, "zh-CN")
+	}
+	T("app", "c", nil, "en-US")
+	data, err := os.ReadFile(dir + "/zh-CN/app.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n\t\"a\": \"\",\n\t\"b\": \"\",\n\t\"hello\": \"你好\"\n}\n"
+	if string(data) != expected {
+		t.Errorf("catalog = %q, want %q", data, expected)
+	}
+	if res := T("app", "hello", nil, "zh-CN"); res != "你好" {
+		t.Errorf("T(hello) after recording = %q", res)
+	}
+}
+
+// recordingMetrics a Metrics counting the events it receives.
+type recordingMetrics struct {
+	served, missed, loads, loadErrors, hits, misses, reloadErrors int
+	mutex                                                         sync.Mutex
+}
+
+func (m *recordingMetrics) Served(category string, lang string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.served++
+}
+
+func (m *recordingMetrics) Missed(category string, lang string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.missed++
+}
+
+func (m *recordingMetrics) Loaded(category string, lang string, elapsed time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err != nil {
+		m.loadErrors++
+	}
+	m.loads++
+}
+
+func (m *recordingMetrics) CacheLookup(hit bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if hit {
+		m.hits++
+	} else {
+		m.misses++
+	}
+}
+
+func (m *recordingMetrics) ReloadFailed(category string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.reloadErrors++
+}
+
+func TestMetrics(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": "Warenkorb"}`), 0644)
+	m := &recordingMetrics{}
+	i := NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, WithMetrics(m), quietLogger())
+	T("app.shop", "Cart", nil, "de")
+	T("app.shop", "Cart", nil, "de")
+	T("app.shop", "Checkout", nil, "de")
+	if m.served != 3 || m.missed != 1 {
+		t.Errorf("served %d, missed %d, want 3 and 1", m.served, m.missed)
+	}
+	if m.misses != 1 || m.hits != 2 || m.loads == 0 || m.loadErrors != 0 {
+		t.Errorf("cache hits %d, misses %d, loads %d, load errors %d", m.hits, m.misses, m.loads, m.loadErrors)
+	}
+
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": `), 0644)
+	if err := i.Reload(); err == nil {
+		t.Error("Reload() of a broken catalog = nil")
+	}
+	if m.reloadErrors != 1 || m.loadErrors == 0 {
+		t.Errorf("reload errors %d, load errors %d, want 1 and some", m.reloadErrors, m.loadErrors)
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": "Warenkorb"}`), 0644)
+	os.MkdirAll(dir+"/fr", 0755)
+	os.WriteFile(dir+"/fr/shop.json", []byte(`{"Cart": `), 0644)
+	var logs strings.Builder
+	NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))),
+		WithLogLevel(EventMissing, slog.LevelWarn), WithLogLevel(EventLoadFailed, slog.LevelDebug))
+	T("app.shop", "Checkout", nil, "de")
+	T("app.shop", "Cart", nil, "fr")
+	out := logs.String()
+	if !strings.Contains(out, "level=WARN msg=\"ii18n: missing translation\" category=app.shop message=Checkout lang=de") {
+		t.Errorf("log %q lacks the missing message at WARN", out)
+	}
+	if strings.Contains(out, "load failed") {
+		t.Errorf("log %q has the load failure lowered below the handler level", out)
+	}
+}
+
+// recordingTracer a Tracer keeping the spans it starts.
+type recordingTracer struct {
+	spans []*recordingSpan
+	mutex sync.Mutex
+}
+
+// recordingSpan a span with its attributes and errors.
+type recordingSpan struct {
+	name  string
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (tr *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+	span := &recordingSpan{name: name, attrs: make(map[string]any)}
+	tr.spans = append(tr.spans, span)
+	return ctx, span
+}
+
+func (s *recordingSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *recordingSpan) RecordError(err error)              { s.err = err }
+func (s *recordingSpan) End()                               { s.ended = true }
+
+func TestTracer(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": "Warenkorb"}`), 0644)
+	tracer := &recordingTracer{}
+	i := NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, WithTracer(tracer), quietLogger())
+	s, _ := i.getSource("app.shop")
+	if _, err := s.LoadMsgs("app.shop", "de"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadMsgs("app.shop", "xx"); err == nil {
+		t.Fatal("LoadMsgs(xx) = nil error")
+	}
+	if len(tracer.spans) < 2 {
+		t.Fatalf("%d spans, want one per load", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	expected := map[string]any{"ii18n.category": "app.shop", "ii18n.lang": "de", "ii18n.source": "json", "ii18n.bytes": int64(21)}
+	if span.name != "ii18n.LoadMsgs" || !reflect.DeepEqual(span.attrs, expected) || span.err != nil || !span.ended {
+		t.Errorf("span = %+v, want the attributes %v", span, expected)
+	}
+	if last := tracer.spans[len(tracer.spans)-1]; !errors.Is(last.err, ErrCatalogNotFound) || !last.ended {
+		t.Errorf("span of the failed load = %+v", last)
+	}
+}
+
+func TestDebugMarkers(t *testing.T) {
+	NewI18N(map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewMemSource(map[string]TMsgs{"de/ui": {"save": "Speichern", "hi": "Hallo {name}"}}),
+			BasePath:      "mem",
+			FileMap:       map[string]string{},
+		},
+	}, WithDebugMarkers(), quietLogger())
+	tests := []struct {
+		key      string
+		params   map[string]string
+		lang     string
+		expected string
+	}{
+		{"save", nil, "de", "⟦app.ui:save⟧Speichern⟦/⟧"},
+		{"hi", map[string]string{"name": "Ada"}, "de", "⟦app.ui:hi⟧Hallo Ada⟦/⟧"},
+		{"cancel", nil, "de", "⟦app.ui:cancel⟧cancel⟦/⟧"},
+	}
+	for _, test := range tests {
+		if actual := T("app.ui", test.key, test.params, test.lang); actual != test.expected {
+			t.Errorf("T(%q) = %q, want %q", test.key, actual, test.expected)
+		}
+	}
+}
+
+func TestPseudolocalize(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"", "[]"},
+		{"Save", "[Šåṽé ~~]"},
+		{"Hi {name}", "[Ĥî {name} ~~~]"},
+	}
+	for _, test := range tests {
+		if actual := Pseudolocalize(test.pattern); actual != test.expected {
+			t.Errorf("Pseudolocalize(%q) = %q, want %q", test.pattern, actual, test.expected)
+		}
+	}
+}
+
+func TestMissingTemplate(t *testing.T) {
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      "./testdata",
+			FileMap:       map[string]string{"app": "app.json"},
+		},
+	}
+	NewI18N(config, WithMissingTemplate("!missing:{category}.{key}!"))
+	if res := T("app", "bye", nil, "zh-CN"); res != "!missing:app.app.bye!" {
+		t.Errorf("missing key = %q", res)
+	}
+	if res := T("app", "bye", nil, "en-US"); res != "bye" {
+		t.Errorf("original language = %q", res)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      "./testdata",
+			FileMap:       map[string]string{"app": "app.json"},
+		},
+	}
+	i := NewI18N(config, WithDefaultCategory("app.app"))
+	expected := CoverageStats{Total: 2, Translated: 2, Percent: 100}
+	for _, category := range []string{"app", ""} {
+		stats, err := i.Coverage(category)
+		if err != nil || len(stats) != 2 || stats["zh-CN"] != expected || stats["en-US"] != expected {
+			t.Errorf("Coverage(%q) = %+v, %v", category, stats, err)
+		}
+	}
+	if _, err := i.Coverage("shop.cart"); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("Coverage() of an unknown prefix = %v, want ErrInvalidCategory", err)
+	}
+
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/en-US", 0755)
+	os.WriteFile(dir+"/en-US/app.json", []byte(`{"a": "A"}`), 0644)
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"a": `), 0644)
+	os.MkdirAll(dir+"/fr", 0755)
+	i = NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{"app": "app.json"}},
+	}, quietLogger())
+	var loadErr *LoadError
+	if _, err := i.Coverage("app"); !errors.As(err, &loadErr) {
+		t.Errorf("Coverage() with a broken catalog = %v, want a LoadError", err)
+	}
+	os.WriteFile(dir+"/de/app.json", []byte(`{}`), 0644)
+	stats, err := i.Coverage("app")
+	if err != nil || stats["fr"] != (CoverageStats{Total: 1, Missing: 1}) {
+		t.Errorf("Coverage() without the fr catalog = %+v, %v", stats, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: "./testdata", FileMap: map[string]string{"app": "app.json"}})
+	_, err := s.LoadMsgs("app.app", "xx")
+	var loadErr *LoadError
+	if !errors.Is(err, ErrCatalogNotFound) || !errors.As(err, &loadErr) || loadErr.Path != "./testdata/xx/app.json" {
+		t.Errorf("LoadMsgs(xx) = %v", err)
+	}
+	_, err = s.TranslateMsg("app.app", "no such key", "zh-CN")
+	var missing *MissingTranslationError
+	if !errors.As(err, &missing) || missing.Lang != "zh-CN" {
+		t.Errorf("TranslateMsg(missing) = %v", err)
+	}
+	if _, err := ParsePattern("{n, plural, one {x}}"); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("ParsePattern = %v", err)
+	}
+	if _, err := FormatNumber("1x", "en", nil); !errors.Is(err, ErrInvalidNumber) {
+		t.Errorf("FormatNumber = %v", err)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: "base", FileMap: map[string]string{"legacy": "old/Legacy.json"}})
+	for category, expected := range map[string]string{
+		"app":              "base/de/app.json",
+		"app.app":          "base/de/app.json",
+		"app.admin.users":  "base/de/admin/users.json",
+		"app.admin\\users": "base/de/admin/users.json",
+		"app.legacy":       "base/de/old/Legacy.json",
+	} {
+		if actual := s.GetMsgFilePath(category, "de"); actual != expected {
+			t.Errorf("GetMsgFilePath(%q) = %q, want %q", category, actual, expected)
+		}
+	}
+	for _, category := range []string{"", "app.", ".app", "app..x"} {
+		if _, err := s.TranslateMsg(category, "hello", "de"); !errors.Is(err, ErrInvalidCategory) {
+			t.Errorf("TranslateMsg(%q) = %v", category, err)
+		}
+	}
+}
+
+func TestMissCache(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"a": "A", "b": ""}`), 0644)
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{"app": "app.json"}})
+	for _, message := range []string{"x", "b", "a"} {
+		s.TranslateMsg("app.app", message, "de")
+	}
+	if msg, err := s.TranslateMsg("app.app", "a", "de"); msg != "A" || err != nil {
+		t.Errorf("TranslateMsg(a) after misses = %q, %v", msg, err)
+	}
+
+	var missing *MissingTranslationError
+	if _, err := s.TranslateMsg("app.app", "a", "fr"); !errors.As(err, &missing) {
+		t.Errorf("TranslateMsg(fr) = %v", err)
+	}
+	os.MkdirAll(dir+"/fr", 0755)
+	os.WriteFile(dir+"/fr/app.json", []byte(`{"a": "Á"}`), 0644)
+	if _, err := s.TranslateMsg("app.app", "a", "fr"); !errors.As(err, &missing) {
+		t.Errorf("TranslateMsg(fr) before Reload = %v", err)
+	}
+	if err := s.(Reloader).Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ := s.TranslateMsg("app.app", "a", "fr"); msg != "Á" {
+		t.Errorf("TranslateMsg(fr) after Reload = %q", msg)
+	}
+}
+
+func TestLoadMsgsFallback(t *testing.T) {
+	dir := t.TempDir()
+	for lang, data := range map[string]string{
+		"en-US":      `{"a": "a-US"}`,
+		"en":         `{"a": "a-en", "b": "b-en"}`,
+		"en-GB":      `{"b": "b-GB", "c": ""}`,
+		"zh":         `{"a": "a-zh", "b": "b-zh"}`,
+		"zh-Hant":    `{"b": "b-Hant"}`,
+		"zh-Hant-TW": `{"c": "c-TW"}`,
+	} {
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.json", []byte(data), 0644)
+	}
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{"app": "app.json"}})
+	tests := []struct {
+		lang     string
+		expected TMsgs
+	}{
+		{"en", TMsgs{"a": "a-en", "b": "b-en"}},
+		{"en-US", TMsgs{"a": "a-US", "b": "b-en"}},
+		{"en-GB", TMsgs{"a": "a-en", "b": "b-GB", "c": ""}},
+		{"zh-Hant-TW", TMsgs{"a": "a-zh", "b": "b-Hant", "c": "c-TW"}},
+		{"zh-Hant-HK", TMsgs{"a": "a-zh", "b": "b-Hant"}},
+	}
+	for _, test := range tests {
+		msgs, err := s.LoadMsgs("app.app", test.lang)
+		if err != nil || !reflect.DeepEqual(msgs, test.expected) {
+			t.Errorf("LoadMsgs(%s) = %v, %v, want %v", test.lang, msgs, err, test.expected)
+		}
+	}
+	if _, err := s.LoadMsgs("app.app", "fr-FR"); !errors.Is(err, ErrCatalogNotFound) {
+		t.Errorf("LoadMsgs(fr-FR) = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	original := TMsgs{"greeting": "Hello {name}"}
+	msgs := TMsgs{
+		"greeting":  "Hallo {nom}",
+		"bye":       "Tschüss ",
+		"empty":     "",
+		"broken":    "{n, plural, one {x}}",
+		"\xffbad":   "x",
+		"{a} and b": "{a} und b",
+	}
+	issues := ValidateMsgs("de", msgs, original)
+	expected := []ValidationIssue{
+		{"de", "broken", IssueInvalidPattern, "message pattern is invalid: missing 'other' option of plural argument n at offset 20"},
+		{"de", "bye", IssueWhitespace, "leading or trailing whitespace differs from bye"},
+		{"de", "empty", IssueEmpty, ""},
+		{"de", "greeting", IssuePlaceholders, "missing name, unknown nom"},
+		{"de", "\xffbad", IssueInvalidUTF8, ""},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("ValidateMsgs =\n%q\nwant\n%q", issues, expected)
+	}
+}
+
+func TestValidateCategory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/en", 0755)
+	os.WriteFile(dir+"/en/shop.json", []byte(`{"hi": "Hi {name}"}`), 0644)
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"hi": "Hallo"}`), 0644)
+	os.MkdirAll(dir+"/fr", 0755)
+	i := NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewJSONSource, OriginalLang: "en", BasePath: dir, FileMap: map[string]string{}},
+	}, WithDefaultCategory("app.shop"), quietLogger())
+	expected := []ValidationIssue{{"de", "hi", IssuePlaceholders, "missing name"}}
+	for _, category := range []string{"app.shop", ""} {
+		if issues, err := i.Validate(category); err != nil || !reflect.DeepEqual(issues, expected) {
+			t.Errorf("Validate(%q) = %v, %v, want %v", category, issues, err, expected)
+		}
+	}
+	if _, err := i.Validate("admin.users"); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("Validate() of an unknown prefix = %v, want ErrInvalidCategory", err)
+	}
+	os.WriteFile(dir+"/fr/shop.json", []byte(`{"hi": `), 0644)
+	var loadErr *LoadError
+	if _, err := i.Validate("app.shop"); !errors.As(err, &loadErr) {
+		t.Errorf("Validate() with a broken catalog = %v, want a LoadError", err)
+	}
+}
+
+func TestMissingFilePolicy(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"a": "A"}`), 0644)
+	tests := []struct {
+		policy   MissingFilePolicy
+		lang     string
+		expected TMsgs
+		err      error
+	}{
+		{FallbackOnly, "de-AT", TMsgs{"a": "A"}, nil},
+		{FallbackOnly, "fr", nil, ErrCatalogNotFound},
+		{IgnoreMissingFile, "fr", TMsgs{}, nil},
+		{ErrorOnMissingFile, "de-AT", nil, ErrCatalogNotFound},
+		{ErrorOnMissingFile, "de", TMsgs{"a": "A"}, nil},
+	}
+	for _, test := range tests {
+		s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, MissingFiles: test.policy})
+		msgs, err := s.LoadMsgs("app.app", test.lang)
+		if !reflect.DeepEqual(msgs, test.expected) || !errors.Is(err, test.err) {
+			t.Errorf("policy %d LoadMsgs(%s) = %v, %v, want %v, %v", test.policy, test.lang, msgs, err, test.expected, test.err)
+		}
+	}
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, MissingFiles: ErrorOnMissingFile})
+	if _, err := s.Translate("app.app", "a", "de-AT"); !errors.Is(err, ErrCatalogNotFound) {
+		t.Errorf("strict Translate(de-AT) = %v", err)
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	n := NormalizeTrim | NormalizeSpace | NormalizeCase
+	if got := n.Normalize("  Hello,\t\n World "); got != "hello, world" {
+		t.Errorf("Normalize = %q", got)
+	}
+	if got := NormalizeSpace.Normalize(" a  b "); got != " a b " {
+		t.Errorf("NormalizeSpace.Normalize = %q", got)
+	}
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"Hello  World ": "Hallo Welt"}`), 0644)
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, NormalizeKeys: n})
+	for _, message := range []string{"Hello World", "hello world", " HELLO\tworld"} {
+		if msg, err := s.Translate("app.app", message, "de"); msg != "Hallo Welt" || err != nil {
+			t.Errorf("Translate(%q) = %q, %v", message, msg, err)
+		}
+	}
+}
+
+func TestParamCheck(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"Hi {name}": "Hallo {nme}"}`), 0644)
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      dir,
+			FileMap:       map[string]string{},
+		},
+	}
+	params := map[string]string{"name": "Ann"}
+	NewI18N(config, WithParamCheck(false))
+	if res, err := TE("app", "Hi {name}", params, "de"); res != "Hallo {nme}" || err != nil {
+		t.Errorf("lenient TE = %q, %v", res, err)
+	}
+	NewI18N(config, WithParamCheck(true))
+	res, err := TE("app", "Hi {name}", params, "de")
+	var perr *ParamsError
+	if res != "Hi Ann" || !errors.As(err, &perr) {
+		t.Fatalf("strict TE = %q, %v", res, err)
+	}
+	if !reflect.DeepEqual(perr.Missing, []string{"nme"}) || !reflect.DeepEqual(perr.Extra, []string{"name"}) {
+		t.Errorf("ParamsError = %+v", perr)
+	}
+	if _, err := TE("app", "Hi {name}", map[string]string{"name": "Ann", "x": "1"}, "en-US"); err != nil {
+		t.Errorf("extra params TE = %v", err)
+	}
+}
+
+func TestCategoryNames(t *testing.T) {
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      "./testdata",
+			FileMap:       map[string]string{"app": "app.json"},
+		},
+	}
+	i := NewI18N(config)
+	categories, err := i.Categories()
+	if !reflect.DeepEqual(categories, []string{"app.app", "app.error"}) || err != nil {
+		t.Errorf("Categories() = %v, %v", categories, err)
+	}
+	if msg, err := i.Lookup("app", "hello", "zh-CN"); msg != "世界" || err != nil {
+		t.Errorf("Lookup(hello) = %q, %v", msg, err)
+	}
+	var missing *MissingTranslationError
+	if _, err := i.Lookup("app", "bye", "zh-CN"); !errors.As(err, &missing) {
+		t.Errorf("Lookup(bye) = %v", err)
+	}
+}
+
+func BenchmarkTranslateHit(b *testing.B) {
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      "./testdata",
+			FileMap:       map[string]string{"app": "app.json"},
+		},
+	}
+	NewI18N(config, quietLogger())
+	for n := 0; n < b.N; n++ {
+		T("app", "hello", nil, "zh-CN")
+	}
+}
+
+func BenchmarkTranslateMissFallback(b *testing.B) {
+	config := map[string]Config{
+		"app": Config{
+			SourceNewFunc: NewJSONSource,
+			BasePath:      "./testdata",
+			FileMap:       map[string]string{"app": "app.json"},
+		},
+	}
+	NewI18N(config, quietLogger())
+	params := map[string]string{"name": "Ann"}
+	for n := 0; n < b.N; n++ {
+		T("app", "Bye {name}", params, "zh-CN-HK")
+	}
+}
+
+// BenchmarkLoadFallbackLanguages loads 30 languages whose catalogs copy the
+// 1000 messages of the catalog they fall back to, collecting garbage between
+// the loads, reporting the heap the catalogs retain.
+func BenchmarkLoadFallbackLanguages(b *testing.B) {
+	dir := b.TempDir()
+	en := make(TMsgs)
+	for n := 0; n < 1000; n++ {
+		en["message "+strconv.Itoa(n)] = "The message number " + strconv.Itoa(n) + " of the application"
+	}
+	data, _ := jsonCodec{}.Encode(en, "en-US")
+	os.MkdirAll(dir+"/en-US", 0755)
+	os.WriteFile(dir+"/en-US/app.json", data, 0644)
+	fallbacks := make(map[string][]string)
+	var langs []string
+	for n := 0; n < 30; n++ {
+		lang := "x" + strconv.Itoa(n)
+		langs = append(langs, lang)
+		fallbacks[lang] = []string{"en-US"}
+		// Untranslated messages copied from the source catalog.
+		msgs := maps.Clone(en)
+		msgs["message 1"] = "translated"
+		data, _ := jsonCodec{}.Encode(msgs, lang)
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.json", data, 0644)
+	}
+	conf := &Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{"app": "app.json"}, Fallbacks: fallbacks}
+	var retained []TMsgs
+	var before, after runtime.MemStats
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s := NewJSONSource(conf)
+		// The files read by the sources of the last iterations are shared.
+		s.(Reloader).Reload()
+		retained = nil
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		for _, lang := range langs {
+			msgs, err := s.LoadMsgs("app.app", lang)
+			if err != nil {
+				b.Fatal(err)
+			}
+			retained = append(retained, msgs)
+			runtime.GC()
+		}
+		runtime.ReadMemStats(&after)
+	}
+	b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc)), "retained-B")
+	runtime.KeepAlive(retained)
+}
+
+func TestInternAcrossGC(t *testing.T) {
+	dir := t.TempDir()
+	for _, lang := range []string{"de", "fr"} {
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.json", []byte(`{"Intern across GC": "Intern across GC"}`), 0644)
+	}
+	s := NewJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{"app": "app.json"}})
+	de, err := s.LoadMsgs("app.app", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The catalogs read after a collection share the strings of those
+	// read before.
+	runtime.GC()
+	runtime.GC()
+	fr, err := s.LoadMsgs("app.app", "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsafe.StringData(de["Intern across GC"]) != unsafe.StringData(fr["Intern across GC"]) {
+		t.Error("the messages of fr do not share the interned strings of de")
+	}
+}
+
+func TestDeterministicOrder(t *testing.T) {
+	dir := t.TempDir()
+	config := make(map[string]Config)
+	for _, prefix := range []string{"e", "d", "c", "b", "a"} {
+		for _, lang := range []string{"de", "en-US"} {
+			os.MkdirAll(dir+"/"+prefix+"/"+lang, 0755)
+			os.WriteFile(dir+"/"+prefix+"/"+lang+"/x.json", []byte(`{"k": "v"}`), 0644)
+		}
+		config[prefix] = Config{SourceNewFunc: NewJSONSource, BasePath: dir + "/" + prefix, FileMap: map[string]string{}}
+	}
+	i := NewI18N(config, quietLogger())
+	for _, prefix := range []string{"c", "a", "e", "b", "d"} {
+		T(prefix+".x", "k", nil, "de")
+		os.WriteFile(dir+"/"+prefix+"/de/x.json", []byte(`{`), 0644)
+	}
+	first := i.Reload()
+	for n := 0; n < 10; n++ {
+		if err := i.Reload(); err == nil || err.Error() != first.Error() {
+			t.Fatalf("Reload() = %v, then %v", first, err)
+		}
+	}
+	if !strings.HasPrefix(first.Error(), dir+"/a/") {
+		t.Errorf("Reload() = %v, want errors by prefix", first)
+	}
+	categories, _ := i.Categories()
+	if !reflect.DeepEqual(categories, []string{"a.x", "b.x", "c.x", "d.x", "e.x"}) {
+		t.Errorf("Categories() = %v", categories)
+	}
+}
+
+// fallbackCatalogs Returns the de-AT and de catalogs generated from specific
+// and generic, with few keys so they overlap and a third of values empty.
+func fallbackCatalogs(specific map[uint8]uint8, generic map[uint8]uint8) (TMsgs, TMsgs) {
+	msgs := func(m map[uint8]uint8) TMsgs {
+		msgs := make(TMsgs, len(m))
+		for k, v := range m {
+			msgs["k"+strconv.Itoa(int(k%16))] = ""
+			if v%3 != 0 {
+				msgs["k"+strconv.Itoa(int(k%16))] = "v" + strconv.Itoa(int(v))
+			}
+		}
+		return msgs
+	}
+	return msgs(specific), msgs(generic)
+}
+
+func TestFallbackMergeProperties(t *testing.T) {
+	merge := func(specific TMsgs, generic TMsgs) (Source, TMsgs) {
+		s := NewMemSource(map[string]TMsgs{"de-AT/shop": specific, "de/shop": generic})(&Config{OriginalLang: "en-US", BasePath: "mem", FileMap: map[string]string{}})
+		merged, err := s.LoadMsgs("app.shop", "de-AT")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s, merged
+	}
+	specificWins := func(sm, gm map[uint8]uint8) bool {
+		specific, generic := fallbackCatalogs(sm, gm)
+		_, merged := merge(specific, generic)
+		for key, val := range specific {
+			if val != "" && merged[key] != val {
+				return false
+			}
+		}
+		return true
+	}
+	emptyNeverOverwrites := func(sm, gm map[uint8]uint8) bool {
+		specific, generic := fallbackCatalogs(sm, gm)
+		_, merged := merge(specific, generic)
+		for key, val := range generic {
+			if val != "" && merged[key] == "" {
+				return false
+			}
+		}
+		for key, val := range merged {
+			if val == "" && (specific[key] != "" || generic[key] != "") {
+				return false
+			}
+			if _, ok := specific[key]; !ok && val != generic[key] {
+				return false
+			}
+		}
+		return true
+	}
+	idempotent := func(sm, gm map[uint8]uint8) bool {
+		specific, generic := fallbackCatalogs(sm, gm)
+		s, merged := merge(specific, generic)
+		again := make(TMsgs, len(merged))
+		for key, val := range merged {
+			again[key] = val
+		}
+		again, err := s.LoadFallbackMsgs("app.shop", "de", again, "")
+		return err == nil && reflect.DeepEqual(again, merged)
+	}
+	if err := quick.Check(specificWins, nil); err != nil {
+		t.Errorf("specific messages win: %v", err)
+	}
+	if err := quick.Check(emptyNeverOverwrites, nil); err != nil {
+		t.Errorf("empty messages never overwrite: %v", err)
+	}
+	if err := quick.Check(idempotent, nil); err != nil {
+		t.Errorf("merging is idempotent: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	for lang, data := range map[string]string{
+		"pt-BR": `{"Cart": "Carrinho"}`,
+		"pt-PT": `{"Cart": "Cesto", "Checkout": "Finalizar"}`,
+		"pt":    `{"Checkout": "Pagar", "Help": "Ajuda"}`,
+	} {
+		os.MkdirAll(dir+"/locales/"+lang, 0755)
+		os.WriteFile(dir+"/locales/"+lang+"/shop.json", []byte(data), 0644)
+	}
+	configs := map[string]string{
+		"i18n.json": `{"sources": {"app": {"format": "json", "basePath": "locales",
+			"fallbacks": {"pt-BR": ["pt-PT", "pt"]}, "normalizeKeys": ["trim"]}},
+			"reload": "1h", "paramCheck": "warn"}`,
+		"i18n.yaml": `# i18n
+sources:
+  app:
+    format: json
+    basePath: locales
+    forceTranslation: false
+    loadConcurrency: 4
+    fallbacks:
+      pt-BR: [pt-PT, "pt"]
+      pt-AO:
+      - pt
+    normalizeKeys:
+      - trim
+reload: 1h
+paramCheck: warn
+`,
+	}
+	for name, data := range configs {
+		os.WriteFile(dir+"/"+name, []byte(data), 0644)
+		i, err := LoadConfig(dir+"/"+name, quietLogger())
+		if err != nil {
+			t.Fatalf("LoadConfig(%s) error: %v", name, err)
+		}
+		for key, want := range map[string]string{"Cart": "Carrinho", " Checkout": "Finalizar", "Help": "Ajuda"} {
+			if got := T("app.shop", key, nil, "pt-BR"); got != want {
+				t.Errorf("%s: T(%q) = %q, want %q", name, key, got, want)
+			}
+		}
+		i.Close()
+		i.Close()
+	}
+
+	for _, data := range []string{
+		`{"sources": {}}`,
+		`{"sources": {"app": {"format": "toml", "basePath": "locales"}}}`,
+		`{"sources": {"app": {"format": "json"}}}`,
+		`{"sources": {"app": {"format": "json", "basePath": "locales", "missingFiles": "skip"}}}`,
+		`{"sources": {"app": {"format": "json", "basePath": "locales"}}, "reload": "often"}`,
+	} {
+		os.WriteFile(dir+"/bad.json", []byte(data), 0644)
+		if _, err := LoadConfig(dir + "/bad.json"); err == nil {
+			t.Errorf("LoadConfig(%s) = nil error", data)
+		}
+	}
+	for data, expected := range map[string]string{
+		"sources:\n  app:\n      format: json\n    basePath: x\n":                "line 4: unexpected indentation",
+		"sources:\n  app:\n    format: json\n    loadConcurrency: four\n":        "line 4: sources.app.loadConcurrency: expected an integer",
+		"sources:\n  app:\n    format: json\n    recordMissing: \"true\"\n":      "line 4: sources.app.recordMissing: expected true or false",
+		"sources:\n  app:\n    format: json\n    fallbacks:\n      de: x\n":      "line 5: sources.app.fallbacks.de: expected a sequence",
+		"sources:\n  app:\n    format: json\n    normalizeKeys:\n    - [trim]\n": "line 5: sources.app.normalizeKeys[0]: expected a string",
+	} {
+		os.WriteFile(dir+"/bad.yaml", []byte(data), 0644)
+		_, err := LoadConfig(dir + "/bad.yaml")
+		if expected = dir + "/bad.yaml: " + expected; err == nil || err.Error() != expected {
+			t.Errorf("LoadConfig(%q) = %v, want %s", data, err, expected)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	dir := t.TempDir()
+	for path, data := range map[string]string{
+		"base/de/shop.json":      `{"Cart": "Warenkorb", "Checkout": "Kasse"}`,
+		"tenant/de/shop.json":    `{"Cart": "Einkaufswagen"}`,
+		"tenant/en-US/shop.json": `{"Checkout": "Pay now"}`,
+	} {
+		os.MkdirAll(dir+"/"+path[:strings.LastIndex(path, "/")], 0755)
+		os.WriteFile(dir+"/"+path, []byte(data), 0644)
+	}
+	logger := quietLogger()
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir + "/base", FileMap: map[string]string{}},
+	}, logger)
+	c := i.Clone(WithDefaultLang("de"), WithMissingTemplate("!{key}!"),
+		WithOverrides(Config{SourceNewFunc: NewJSONSource, BasePath: dir + "/tenant"}))
+	if Translator != i {
+		t.Fatal("Clone() replaced Translator")
+	}
+	tests := []struct {
+		i         *I18N
+		key, lang string
+		want      string
+	}{
+		{i, "Cart", "de", "Warenkorb"},
+		{i, "Cart", "en-US", "Cart"},
+		{i, "Help", "de", "Help"},
+		{c, "Cart", "", "Einkaufswagen"},
+		{c, "Checkout", "", "Kasse"},
+		{c, "Checkout", "en-US", "Pay now"},
+		{c, "Help", "", "!Help!"},
+	}
+	for _, tt := range tests {
+		if got := tt.i.T("app.shop", tt.key, nil, tt.lang); got != tt.want {
+			t.Errorf("T(%q, %q) = %q, want %q", tt.key, tt.lang, got, tt.want)
+		}
+	}
+	if i.Translations["app"].source != c.Translations["app"].source {
+		t.Error("Clone() does not share sources")
+	}
+}
+
+type welcomeMsg struct {
+	Name   string
+	Count  int `param:"n"`
+	secret string
+	Note   string `param:"-"`
+}
+
+func (welcomeMsg) MessageKey() (string, string) {
+	return "app.home", "Welcome, {name}! {n, plural, one {# message} other {# messages}}"
+}
+
+func TestTranslateTyped(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/home.json", []byte(`{"Welcome, {name}! {n, plural, one {# message} other {# messages}}": "Willkommen, {name}! {n, plural, one {# Nachricht} other {# Nachrichten}}"}`), 0644)
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	ctx := WithLang(context.Background(), "de")
+	if got, want := Translate(ctx, welcomeMsg{Name: "Ana", Count: 3}), "Willkommen, Ana! 3 Nachrichten"; got != want {
+		t.Errorf("Translate() = %q, want %q", got, want)
+	}
+	if got, want := Translate(context.Background(), &welcomeMsg{Name: "Ana", Count: 1}), "Welcome, Ana! 1 message"; got != want {
+		t.Errorf("Translate() = %q, want %q", got, want)
+	}
+	params := messageParams(welcomeMsg{Name: "Ana", Count: 1, secret: "s", Note: "x"})
+	if !reflect.DeepEqual(params, map[string]string{"name": "Ana", "n": "1"}) {
+		t.Errorf("messageParams() = %v", params)
+	}
+	due := time.Date(2024, time.March, 5, 14, 7, 9, 500, time.FixedZone("", 3600))
+	params = messageParams(dueMsg{Due: due, Sent: &due})
+	if !reflect.DeepEqual(params, map[string]string{"due": "2024-03-05T14:07:09.0000005+01:00", "sent": "2024-03-05T14:07:09.0000005+01:00"}) {
+		t.Errorf("messageParams() = %v", params)
+	}
+	if got, want := Translate(context.Background(), dueMsg{Due: due}), "Due Mar 5, 2024"; got != want {
+		t.Errorf("Translate() = %q, want %q", got, want)
+	}
+}
+
+type dueMsg struct {
+	Due  time.Time
+	Sent *time.Time
+}
+
+func (dueMsg) MessageKey() (string, string) {
+	return "app.home", "Due {due, date, ::yMMMd}"
+}
+
+func TestLocalizeStruct(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": "Warenkorb"}`), 0644)
+	os.WriteFile(dir+"/de/status.json", []byte(`{"active": "aktiv", "closed": "geschlossen"}`), 0644)
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	type item struct {
+		Status string `i18n:"status,value"`
+	}
+	type page struct {
+		Title  string            `i18n:"app.shop.Cart"`
+		Labels map[string]string `i18n:"status"`
+		Items  []item
+		Next   *page
+		Raw    string
+	}
+	p := &page{Labels: map[string]string{"active": "", "closed": ""}, Items: []item{{"active"}, {""}}, Raw: "Cart"}
+	p.Next = p
+	if err := LocalizeStruct(p, "de"); err != nil {
+		t.Fatal(err)
+	}
+	want := &page{Title: "Warenkorb", Labels: map[string]string{"active": "aktiv", "closed": "geschlossen"}, Items: []item{{"aktiv"}, {""}}, Raw: "Cart"}
+	want.Next = want
+	if p.Title != want.Title || !reflect.DeepEqual(p.Labels, want.Labels) || !reflect.DeepEqual(p.Items, want.Items) || p.Raw != want.Raw {
+		t.Errorf("LocalizeStruct() = %+v, want %+v", p, want)
+	}
+
+	var bad struct {
+		N int `i18n:"app.shop.Cart"`
+	}
+	for _, v := range []interface{}{page{}, nil, &bad, &struct {
+		S string `i18n:"Cart"`
+	}{}} {
+		if err := LocalizeStruct(v, "de"); err == nil {
+			t.Errorf("LocalizeStruct(%T) = nil error", v)
+		}
+	}
+}
+
+func TestLocalizedError(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/errors.json", []byte(`{"order {id} not found": "Bestellung {id} nicht gefunden", "lookup failed": "Suche fehlgeschlagen"}`), 0644)
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	errNotFound := Errorf("errors", "order {id} not found")
+	cause := io.ErrUnexpectedEOF
+	err := fmt.Errorf("handler: %w", Errorf("errors", "lookup failed", Errorf("errors", "order {id} not found", "id", 42, cause)))
+
+	if got, want := err.Error(), "handler: lookup failed: order 42 not found: unexpected EOF"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	var le *LocalizedError
+	if !errors.As(err, &le) {
+		t.Fatal("errors.As(*LocalizedError) = false")
+	}
+	if got, want := le.Localize("de"), "Suche fehlgeschlagen: Bestellung 42 nicht gefunden: unexpected EOF"; got != want {
+		t.Errorf("Localize(de) = %q, want %q", got, want)
+	}
+	if !errors.Is(err, errNotFound) || !errors.Is(err, cause) || errors.Is(err, Errorf("errors", "other")) {
+		t.Error("errors.Is() does not match the chain")
+	}
+	if got := Errorf("unknown.errors", "failed").Error(); got != "failed" {
+		t.Errorf("Error() of an unknown category = %q", got)
+	}
+}
+
+type orderStatus int
+
+func (s orderStatus) String() string {
+	return "status" + strconv.Itoa(int(s))
+}
+
+func TestLocalizedString(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/orders.json", []byte(`{"Pending": "Ausstehend", "Shipped": "Versandt"}`), 0644)
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	RegisterEnum("orders", map[orderStatus]string{0: "Pending", 1: "Shipped"})
+	tests := []struct {
+		v    interface{}
+		lang string
+		want string
+	}{
+		{orderStatus(0), "de", "Ausstehend"},
+		{orderStatus(1), "de", "Versandt"},
+		{orderStatus(1), "en-US", "Shipped"},
+		{orderStatus(2), "de", "status2"},
+		{0, "de", "0"},
+	}
+	for _, tt := range tests {
+		if got := LocalizedString(tt.v, tt.lang); got != tt.want {
+			t.Errorf("LocalizedString(%#v, %s) = %q, want %q", tt.v, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryInheritance(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de/checkout", 0755)
+	os.WriteFile(dir+"/de/checkout/payment.json", []byte(`{"Card": "Karte"}`), 0644)
+	os.WriteFile(dir+"/de/checkout.json", []byte(`{"Pay": "Bezahlen"}`), 0644)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"Cancel": "Abbrechen"}`), 0644)
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger(), WithDefaultCategory("app.checkout.payment"), WithCategoryInheritance())
+	for key, want := range map[string]string{"Card": "Karte", "Pay": "Bezahlen", "Cancel": "Abbrechen", "Help": "Help"} {
+		if got := T("", key, nil, "de"); got != want {
+			t.Errorf("T(%q) = %q, want %q", key, got, want)
+		}
+	}
+	if got := i.Clone(WithDefaultCategory("checkout")).T("", "Card", nil, "de"); got != "Card" {
+		t.Errorf("T(Card) in the parent category = %q, want Card", got)
+	}
+	if got := i.Clone(func(c *I18N) { c.inherit = false }).T("", "Pay", nil, "de"); got != "Pay" {
+		t.Errorf("T(Pay) without inheritance = %q, want Pay", got)
+	}
+}
+
+func TestGlobalParams(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"Welcome to {appName}, {name}": "Willkommen bei {appName}, {name}"}`), 0644)
+	var logs strings.Builder
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))), WithParamCheck(true),
+		WithGlobalParams(map[string]string{"appName": "Shop", "supportEmail": "help@example.com"}))
+	tests := []struct {
+		params map[string]string
+		lang   string
+		want   string
+	}{
+		{map[string]string{"name": "Ana"}, "de", "Willkommen bei Shop, Ana"},
+		{map[string]string{"name": "Ana", "appName": "Store"}, "de", "Willkommen bei Store, Ana"},
+		{nil, "en-US", "Welcome to Shop, {name}"},
+	}
+	for _, tt := range tests {
+		got, _ := TE("app", "Welcome to {appName}, {name}", tt.params, tt.lang)
+		if got != tt.want {
+			t.Errorf("TE(%v, %s) = %q, want %q", tt.params, tt.lang, got, tt.want)
+		}
+	}
+	if strings.Contains(logs.String(), "supportEmail") || strings.Contains(logs.String(), "missing=[appName]") {
+		t.Errorf("global params reported: %s", logs.String())
+	}
+}
+
+func TestConvenienceWrappers(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"%d files": "%d Dateien", "Hi {name}, {count}": "Hallo {name}, {count}"}`), 0644)
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	if got := Tf("app", "%d files", "de", 3); got != "3 Dateien" {
+		t.Errorf("Tf() = %q", got)
+	}
+	params := P("name", "Ana", "count", 2.5, "flag")
+	if !reflect.DeepEqual(params, map[string]string{"name": "Ana", "count": "2.5", "flag": ""}) {
+		t.Errorf("P() = %v", params)
+	}
+	if got := MustT("app", "Hi {name}, {count}", params, "de"); got != "Hallo Ana, 2.5" {
+		t.Errorf("MustT() = %q", got)
+	}
+	if got := MustT("app", "Untranslated", nil, "en-US"); got != "Untranslated" {
+		t.Errorf("MustT() in the original language = %q", got)
+	}
+	defer func() {
+		var err *MissingTranslationError
+		if e, _ := recover().(error); !errors.As(e, &err) {
+			t.Errorf("MustT() of a missing translation panicked with %v", e)
+		}
+	}()
+	MustT("app", "Untranslated", nil, "de")
+}
+
+func TestOverlay(t *testing.T) {
+	dir := t.TempDir()
+	for path, data := range map[string]string{
+		"base/de/app.json":      `{"Project": "Projekt", "Task": "Aufgabe"}`,
+		"acme/en-US/app.json":   `{"Project": "Case"}`,
+		"acme/de/app.json":      `{"Project": "Fall"}`,
+		"globex/en-US/app.json": `{"Task": "Ticket"}`,
+	} {
+		os.MkdirAll(dir+"/"+path[:strings.LastIndex(path, "/")], 0755)
+		os.WriteFile(dir+"/"+path, []byte(data), 0644)
+	}
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir + "/base", FileMap: map[string]string{}},
+	}, quietLogger())
+	acme := i.WithOverlay("acme", Config{SourceNewFunc: NewJSONSource, BasePath: dir + "/acme"})
+	globex := i.WithOverlay("globex", Config{SourceNewFunc: NewJSONSource, BasePath: dir + "/globex"})
+	if i.WithOverlay("acme", Config{}) != acme {
+		t.Error("WithOverlay() created the overlay again")
+	}
+	tests := []struct {
+		i         *I18N
+		key, lang string
+		want      string
+	}{
+		{i, "Project", "en-US", "Project"},
+		{i, "Project", "de", "Projekt"},
+		{acme, "Project", "en-US", "Case"},
+		{acme, "Project", "de", "Fall"},
+		{acme, "Task", "de", "Aufgabe"},
+		{globex, "Project", "en-US", "Project"},
+		{globex, "Task", "en-US", "Ticket"},
+		{globex.Clone(WithOverrides(Config{SourceNewFunc: NewJSONSource, BasePath: dir + "/acme"})), "Task", "en-US", "Ticket"},
+	}
+	for _, tt := range tests {
+		if got := tt.i.T("app", tt.key, nil, tt.lang); got != tt.want {
+			t.Errorf("T(%q, %s) = %q, want %q", tt.key, tt.lang, got, tt.want)
+		}
+	}
+
+	os.WriteFile(dir+"/acme/en-US/app.json", []byte(`{"Project": "Matter"}`), 0644)
+	if err := i.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := acme.T("app", "Project", nil, "en-US"); got != "Matter" {
+		t.Errorf("T(Project) after Reload() = %q, want Matter", got)
+	}
+	i.RemoveOverlay("acme")
+	if i.WithOverlay("acme", Config{SourceNewFunc: NewJSONSource, BasePath: dir + "/globex"}) == acme {
+		t.Error("WithOverlay() after RemoveOverlay() = the removed overlay")
+	}
+}
+
+func TestBrandVariant(t *testing.T) {
+	dir := t.TempDir()
+	for path, data := range map[string]string{
+		"de/app.json":           `{"Project": "Projekt", "Task": "Aufgabe", "{n, plural, one {# task} other {# tasks}}": "{n, plural, one {# Aufgabe} other {# Aufgaben}}"}`,
+		"de-AT@brandX/app.json": `{"Task": "Vorgang"}`,
+		"en-US@brandX/app.json": `{"Project": "Case"}`,
+	} {
+		os.MkdirAll(dir+"/"+path[:strings.LastIndex(path, "/")], 0755)
+		os.WriteFile(dir+"/"+path, []byte(data), 0644)
+	}
+	var logs strings.Builder
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	tests := []struct {
+		key, lang string
+		params    map[string]string
+		want      string
+	}{
+		{"Task", "de-AT@brandX", nil, "Vorgang"},
+		{"Project", "de-AT@brandX", nil, "Projekt"},
+		{"Task", "de@brandY", nil, "Aufgabe"},
+		{"{n, plural, one {# task} other {# tasks}}", "de-AT@brandX", map[string]string{"n": "2"}, "2 Aufgaben"},
+		{"Project", "en-US@brandX", nil, "Case"},
+		{"Task", "en-US@brandX", nil, "Task"},
+	}
+	for _, tt := range tests {
+		if got := T("app", tt.key, tt.params, tt.lang); got != tt.want {
+			t.Errorf("T(%q, %s) = %q, want %q", tt.key, tt.lang, got, tt.want)
+		}
+	}
+	if strings.Contains(logs.String(), "en-US@brandX") {
+		t.Errorf("the brand of the original language reported: %s", logs.String())
+	}
+}
+
+func TestPinVersion(t *testing.T) {
+	dir := t.TempDir()
+	for version, data := range map[string]string{"v1": `{"Cart": "Einkaufswagen"}`, "v2": `{"Cart": "Warenkorb"}`} {
+		os.MkdirAll(dir+"/"+version+"/de", 0755)
+		os.WriteFile(dir+"/"+version+"/de/shop.json", []byte(data), 0644)
+	}
+	os.MkdirAll(dir+"/plain/de", 0755)
+	i := NewI18N(map[string]Config{
+		"app":   {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}, Version: "v1"},
+		"admin": {SourceNewFunc: NewJSONSource, BasePath: dir + "/plain", FileMap: map[string]string{}},
+	}, quietLogger())
+	if got := T("app.shop", "Cart", nil, "de"); got != "Einkaufswagen" || i.Version() != "v1" {
+		t.Errorf("T(Cart) = %q in %q, want Einkaufswagen in v1", got, i.Version())
+	}
+	if err := i.PinVersion("v2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("app.shop", "Cart", nil, "de"); got != "Warenkorb" || i.Version() != "v2" {
+		t.Errorf("T(Cart) = %q in %q, want Warenkorb in v2", got, i.Version())
+	}
+	for _, version := range []string{"v3", "../v1", ""} {
+		if err := i.PinVersion(version); err == nil {
+			t.Errorf("PinVersion(%q) = nil error", version)
+		}
+	}
+	if err := i.PinVersion("v1"); err != nil || T("app.shop", "Cart", nil, "de") != "Einkaufswagen" {
+		t.Errorf("PinVersion(v1) = %v, T(Cart) = %q", err, T("app.shop", "Cart", nil, "de"))
+	}
+
+	i = NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}, Version: "v1"},
+		"web": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}, Version: "v1"},
+	}, quietLogger())
+	var wg sync.WaitGroup
+	for _, version := range []string{"v1", "v2", "v1", "v2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				i.PinVersion(version)
+			}
+		}()
+	}
+	wg.Wait()
+	app, _ := i.getSource("app.")
+	web, _ := i.getSource("web.")
+	if app.(Versioner).Version() != web.(Versioner).Version() {
+		t.Errorf("concurrent PinVersion left app in %s and web in %s", app.(Versioner).Version(), web.(Versioner).Version())
+	}
+}
+
+func TestVariantSelector(t *testing.T) {
+	dir := t.TempDir()
+	for lang, data := range map[string]string{
+		"en-US": `{"Buy now|b": "Get it today"}`,
+		"de":    `{"Buy now": "Jetzt kaufen", "Buy now|b": "Heute holen"}`,
+	} {
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.json", []byte(data), 0644)
+	}
+	NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger(), WithVariantSelector(NewExperimentSelector(map[string]Experiment{
+		"Buy now": {ID: "cta", Weights: map[string]int{"": 50, "b": 50}},
+	})))
+	served := map[string]int{}
+	for n := 0; n < 200; n++ {
+		ctx := WithBucket(context.Background(), "user"+strconv.Itoa(n))
+		got := TContext(ctx, "app", "Buy now", nil, "en-US")
+		if again := TContext(ctx, "app", "Buy now", nil, "en-US"); again != got {
+			t.Fatalf("user%d served %q, then %q", n, got, again)
+		}
+		if de := TContext(ctx, "app", "Buy now", nil, "de"); (got == "Get it today") != (de == "Heute holen") {
+			t.Fatalf("user%d served %q and %q", n, got, de)
+		}
+		served[got]++
+	}
+	if len(served) != 2 || served["Buy now"] < 60 || served["Get it today"] < 60 {
+		t.Errorf("served %v, want both variants about evenly", served)
+	}
+	if got := TContext(context.Background(), "app", "Buy now", nil, "de"); got != "Jetzt kaufen" {
+		t.Errorf("TContext() without bucket = %q", got)
+	}
+}
+
+func TestFuncSource(t *testing.T) {
+	var mutex sync.Mutex
+	remote := map[string]TMsgs{"de/admin.users": {"Save": "Speichern"}}
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewFuncSource(func(lang string, name string) (TMsgs, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			msgs, ok := remote[lang+"/"+name]
+			if !ok {
+				return nil, ErrCatalogNotFound
+			}
+			return msgs, nil
+		}), BasePath: "remote", FileMap: map[string]string{}},
+	}, quietLogger())
+	if got := i.T("app.admin.users", "Save", nil, "de-AT"); got != "Speichern" {
+		t.Errorf("T() through the fallback = %q", got)
+	}
+	mutex.Lock()
+	remote["de/admin.users"] = TMsgs{"Save": "Sichern"}
+	mutex.Unlock()
+	if err := i.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := i.T("app.admin.users", "Save", nil, "de-AT"); got != "Sichern" {
+		t.Errorf("T() after Reload = %q", got)
+	}
+}
+
+func TestReloadCatalog(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	os.MkdirAll(dir+"/fr", 0755)
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": "Warenkorb"}`), 0644)
+	os.WriteFile(dir+"/fr/shop.json", []byte(`{"Cart": "Panier"}`), 0644)
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	i.T("app.shop", "Cart", nil, "de-AT")
+	i.T("app.shop", "Cart", nil, "fr")
+	os.WriteFile(dir+"/de/shop.json", []byte(`{"Cart": "Einkaufswagen"}`), 0644)
+	os.WriteFile(dir+"/fr/shop.json", []byte(`{"Cart": "Chariot"}`), 0644)
+	if err := i.ReloadCatalog("app.shop", "de"); err != nil {
+		t.Fatal(err)
+	}
+	if got := i.T("app.shop", "Cart", nil, "de-AT"); got != "Einkaufswagen" {
+		t.Errorf("T() in a language falling back to the reloaded one = %q", got)
+	}
+	if got := i.T("app.shop", "Cart", nil, "fr"); got != "Panier" {
+		t.Errorf("T() in another language = %q, want the cached catalog", got)
+	}
+}
+
+// mtFunc an MTProvider of a function.
+type mtFunc func(text string, to string) string
+
+func (f mtFunc) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	return f(text, to), nil
+}
+
+func TestMTHandler(t *testing.T) {
+	release := make(chan struct{})
+	var calls int
+	var callsMutex sync.Mutex
+	provider := mtFunc(func(text string, to string) string {
+		callsMutex.Lock()
+		calls++
+		callsMutex.Unlock()
+		<-release
+		return "Warenkorb"
+	})
+	NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewMemSource(map[string]TMsgs{}), BasePath: "mem", FileMap: map[string]string{}},
+	}, WithMissingHandler(NewMTHandler(provider, 0)), quietLogger())
+
+	// Misses while the translation is pending serve the original message
+	// without waiting or sending another request.
+	for n := 0; n < 3; n++ {
+		if got := T("app.shop", "Cart", nil, "de"); got != "Cart" {
+			t.Fatalf("T() while translating = %q, want the original message", got)
+		}
+	}
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for T("app.shop", "Cart", nil, "de") != "Warenkorb" {
+		if time.Now().After(deadline) {
+			t.Fatal("T() never served the machine translation")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	callsMutex.Lock()
+	defer callsMutex.Unlock()
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1", calls)
+	}
+}
+
+func TestTranslatePattern(t *testing.T) {
+	var sent []string
+	provider := mtFunc(func(text string, to string) string {
+		sent = append(sent, text)
+		return strings.NewReplacer("Hello", "Hallo", "file", "Datei", "and", "und", "twice {0}", "{0} {0}", "drop {1}", "").Replace(text)
+	})
+	tests := []struct {
+		pattern  string
+		expected string
+		sent     []string
+		err      bool
+	}{
+		{"Hello {name}", "Hallo {name}", []string{"Hello {0}"}, false},
+		{"{a, number, ::percent} and {b, date, short}", "{a, number, ::percent} und {b, date, short}", []string{"{0} and {1}"}, false},
+		{
+			"{n, plural, offset:1 =0 {none} one {# file} other {# file, '{'x'}'}}",
+			"{n, plural, offset:1 =0 {none} one {# Datei} other {# Datei, '{'x'}'}}",
+			[]string{"none", "{0} file", "{0} file, {x}"}, false,
+		},
+		{"{g, select, male {{n}} other {file}}", "{g, select, male {{n}} other {Datei}}", []string{"file"}, false},
+		{"twice {a}", "", []string{"twice {0}"}, true},
+		{"{a} drop {b}", "", []string{"{0} drop {1}"}, true},
+		{"Hello {", "Hallo {", []string{"Hello {"}, false},
+	}
+	for _, test := range tests {
+		sent = nil
+		actual, err := TranslatePattern(context.Background(), provider, test.pattern, "en", "de")
+		if test.err != errors.Is(err, ErrMTPlaceholders) || actual != test.expected {
+			t.Errorf("TranslatePattern(%q) = %q, %v, want %q", test.pattern, actual, err, test.expected)
+		}
+		if !reflect.DeepEqual(sent, test.sent) {
+			t.Errorf("TranslatePattern(%q) sent %q, want %q", test.pattern, sent, test.sent)
+		}
+	}
+}
+
+func TestMTHandlerPlaceholders(t *testing.T) {
+	var calls atomic.Int32
+	provider := mtFunc(func(text string, to string) string {
+		calls.Add(1)
+		return "Hallo {name}"
+	})
+	NewI18N(map[string]Config{
+		"app": Config{SourceNewFunc: NewMemSource(map[string]TMsgs{}), BasePath: "mem", FileMap: map[string]string{}},
+	}, WithMissingHandler(NewMTHandler(provider, 0)), quietLogger())
+
+	// A translation losing the arguments is not cached.
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() < 2 {
+		if got := T("app.shop", "Hello {0}", map[string]string{"0": "Ann"}, "de"); got != "Hello Ann" {
+			t.Fatalf("T() = %q, want the original message", got)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the failed translation was never retried")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGlossary(t *testing.T) {
+	g := &Glossary{Terms: []GlossaryTerm{
+		{Term: "ii18n", DNT: true},
+		{Term: "cart", Translations: map[string][]string{"de": {"Warenkorb"}}},
+	}}
+	if errs := g.Check("Your cart", "Ihr Warenkorb", "de-AT"); len(errs) != 0 {
+		t.Errorf("Check() of an approved translation = %v", errs)
+	}
+	if errs := g.Check("Carts by ii18n", "Körbe von i18n", "de"); len(errs) != 1 || errs[0].Term != "ii18n" {
+		t.Errorf("Check() = %v, want the translated brand only (carts is another word)", errs)
+	}
+	if errs := g.Check("Your cart", "Votre chariot", "fr"); len(errs) != 0 {
+		t.Errorf("Check() in a language without approved terms = %v", errs)
+	}
+
+	p := GlossaryProvider(mtFunc(func(text string, to string) string {
+		return strings.NewReplacer("ii18n", "i18n", "cart", "Korb", "Powered by", "Betrieben von").Replace(text)
+	}), g)
+	if got, err := p.Translate(context.Background(), "Powered by ii18n", "en", "de"); err != nil || got != "Betrieben von ii18n" {
+		t.Errorf("Translate() = %q, %v", got, err)
+	}
+	var glossaryErr *GlossaryError
+	if _, err := p.Translate(context.Background(), "Your cart", "en", "de"); !errors.As(err, &glossaryErr) {
+		t.Errorf("Translate() without the approved term = %v, want a GlossaryError", err)
+	}
+}
+
+func TestTranslationMemory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/en-US", 0755)
+	os.MkdirAll(dir+"/de", 0755)
+	os.WriteFile(dir+"/en-US/files.json", []byte(`{"file.save": "Save the file", "Delete": ""}`), 0644)
+	os.WriteFile(dir+"/de/files.json", []byte(`{"file.save": "Datei speichern", "Delete": "Löschen", "Quit": ""}`), 0644)
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{}},
+	}, quietLogger())
+	tm, err := i.TranslationMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := tm.Suggest("Save the files", "de", 3)
+	if len(got) != 1 || got[0].Translation != "Datei speichern" || got[0].Category != "app.files" || got[0].Score < 0.9 || got[0].Score >= 1 {
+		t.Errorf("Suggest() = %+v", got)
+	}
+	if got := tm.Suggest("delete", "de", 3); len(got) != 1 || got[0].Score != 1 {
+		t.Errorf("Suggest() ignoring case = %+v", got)
+	}
+	if got := tm.Suggest("Save the file", "fr", 3); got != nil {
+		t.Errorf("Suggest() in a language without translations = %+v", got)
+	}
+}
+
+func TestMappedSource(t *testing.T) {
+	dir := t.TempDir()
+	write := func(lang string, msgs TMsgs) {
+		data, _ := binaryCodec{}.Encode(msgs, lang)
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.bin.tmp", data, 0644)
+		os.Rename(dir+"/"+lang+"/app.bin.tmp", dir+"/"+lang+"/app.bin")
+	}
+	write("de", TMsgs{"Save": "Speichern", "Open": "Öffnen", "Close": ""})
+	write("de-AT", TMsgs{"Open": "Aufmachen", "Close": ""})
+	s := NewMappedSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{"app": "app.bin"}})
+	for message, want := range map[string]string{"Save": "Speichern", "Open": "Aufmachen"} {
+		if got, err := s.TranslateMsg("app.app", message, "de-AT"); got != want || err != nil {
+			t.Errorf("TranslateMsg(%q) = %q, %v, want %q", message, got, err, want)
+		}
+	}
+	var missing *MissingTranslationError
+	for _, lang := range []string{"de-AT", "fr"} {
+		if _, err := s.TranslateMsg("app.app", "Close", lang); !errors.As(err, &missing) {
+			t.Errorf("TranslateMsg(Close, %s) = %v", lang, err)
+		}
+	}
+
+	write("de", TMsgs{"Save": "Sichern"})
+	if got, _ := s.TranslateMsg("app.app", "Save", "de-AT"); got != "Speichern" {
+		t.Errorf("TranslateMsg() before ReloadCatalog = %q", got)
+	}
+	if err := s.(CatalogReloader).ReloadCatalog("app.app", "de"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.TranslateMsg("app.app", "Save", "de-AT"); got != "Sichern" {
+		t.Errorf("TranslateMsg() after ReloadCatalog = %q", got)
+	}
+	if msgs, err := s.LoadMsgs("app.app", "de-AT"); err != nil || msgs["Open"] != "Aufmachen" || msgs["Save"] != "Sichern" {
+		t.Errorf("LoadMsgs() = %q, %v", msgs, err)
+	}
+}
+
+func TestMappedSourceChecks(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	for name, conf := range map[string]Config{
+		"VerifyKey":   {VerifyKey: pub},
+		"DecryptKeys": {DecryptKeys: StaticKeys(map[string][]byte{"k1": make([]byte, 32)})},
+		"Manifest":    {Manifest: "manifest.sha256"},
+	} {
+		conf.OriginalLang, conf.BasePath, conf.FileMap = "en-US", t.TempDir(), map[string]string{}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewMappedSource() with %s did not panic", name)
+				}
+			}()
+			NewMappedSource(&conf)
+		}()
+	}
+}
+
+func TestLazyJSONSource(t *testing.T) {
+	dir := t.TempDir()
+	write := func(lang string, data string) {
+		os.MkdirAll(dir+"/"+lang, 0755)
+		os.WriteFile(dir+"/"+lang+"/app.json", []byte(data), 0644)
+	}
+	write("de", `{"Save": "Speichern", "Open": "\u00d6ffnen", "Say \"hi\"": "Sag \"Hallo\"", "Close": ""}`)
+	write("de-AT", "{\n\t\"Open\": \"Aufmachen\",\n\t\"Close\": \"\"\n}\n")
+	s := NewLazyJSONSource(&Config{OriginalLang: "en-US", BasePath: dir, FileMap: map[string]string{"app": "app.json"}})
+	for message, want := range map[string]string{"Save": "Speichern", "Open": "Aufmachen", `Say "hi"`: `Sag "Hallo"`} {
+		if got, err := s.TranslateMsg("app.app", message, "de-AT"); got != want || err != nil {
+			t.Errorf("TranslateMsg(%q) = %q, %v, want %q", message, got, err, want)
+		}
+	}
+	if got, _ := s.TranslateMsg("app.app", "Open", "de"); got != "Öffnen" {
+		t.Errorf("TranslateMsg(Open, de) = %q", got)
+	}
+	var missing *MissingTranslationError
+	if _, err := s.TranslateMsg("app.app", "Close", "de-AT"); !errors.As(err, &missing) {
+		t.Errorf("TranslateMsg(Close) = %v", err)
+	}
+
+	write("de", `{"Save": "Sichern",}`)
+	if err := s.(CatalogReloader).ReloadCatalog("app.app", "de"); err != nil {
+		t.Fatal(err)
+	}
+	var loadErr *LoadError
+	if _, err := s.TranslateMsg("app.app", "Save", "de-AT"); !errors.As(err, &loadErr) {
+		t.Errorf("TranslateMsg() of a malformed catalog = %v", err)
+	}
+	write("de", `{"Save": "Sichern"}`)
+	if err := s.(CatalogReloader).ReloadCatalog("app.app", "de"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.TranslateMsg("app.app", "Save", "de-AT"); got != "Sichern" {
+		t.Errorf("TranslateMsg() after ReloadCatalog = %q", got)
+	}
+	if msgs, err := s.LoadMsgs("app.app", "de-AT"); err != nil || msgs["Open"] != "Aufmachen" || msgs["Save"] != "Sichern" {
+		t.Errorf("LoadMsgs() = %q, %v", msgs, err)
+	}
+}
+
+func TestTranslateBatch(t *testing.T) {
+	dir := t.TempDir()
+	for path, data := range map[string]string{
+		"de/app.json":    `{"Save": "Speichern", "Open": "Öffnen", "Hi {name}": "Hallo {name}"}`,
+		"de-AT/app.json": `{"Open": "Aufmachen"}`,
+	} {
+		os.MkdirAll(dir+"/"+path[:strings.LastIndex(path, "/")], 0755)
+		os.WriteFile(dir+"/"+path, []byte(data), 0644)
+	}
+	keys := []string{"Save", "Open", "Hi {name}", "Missing"}
+	for _, newSource := range []func(*Config) Source{NewJSONSource, NewLazyJSONSource} {
+		i := NewI18N(map[string]Config{
+			"app": {SourceNewFunc: newSource, BasePath: dir, FileMap: map[string]string{}},
+		}, quietLogger())
+		for _, lang := range []string{"de-AT", "en-US"} {
+			got, err := i.TranslateBatch("app", keys, lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := make(map[string]string)
+			for _, key := range keys {
+				want[key] = i.T("app", key, nil, lang)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("TranslateBatch(%s) = %q, want %q", lang, got, want)
+			}
+		}
+	}
+}
+
+func TestCompileOnLoad(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{
+		"# files": "{n, plural, one {# Datei} other {# Dateien}}",
+		"Hi {name}": "Hallo {name}",
+		"Broken": "{n, plural, one {x}",
+		"Save": "Speichern"
+	}`), 0644)
+	var logs strings.Builder
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}, CompileOnLoad: true},
+	}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if got := i.T("app", "# files", map[string]string{"n": "3"}, "de"); got != "3 Dateien" {
+		t.Errorf("T() = %q", got)
+	}
+	stats := i.Stats()
+	if len(stats.Catalogs) != 1 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	c := stats.Catalogs[0]
+	if c.Category != "app.app" || c.Lang != "de" || c.Messages != 4 || c.Patterns != 3 || c.InvalidPatterns != 1 || c.CompileTime <= 0 {
+		t.Errorf("Stats().Catalogs[0] = %+v", c)
+	}
+	if !strings.Contains(logs.String(), "key=Broken kind=invalid-pattern") {
+		t.Errorf("invalid pattern not logged: %s", logs.String())
+	}
+	s, _ := i.getSource("app.app")
+	if _, ok := s.(patternCompiler).compiledPattern("app.app", "de", "{n, plural, one {# Datei} other {# Dateien}}"); !ok {
+		t.Error("plural pattern not compiled")
+	}
+}
+
+func TestLoadConcurrency(t *testing.T) {
+	var mutex sync.Mutex
+	running, peak, calls := 0, 0, 0
+	remote := map[string]TMsgs{
+		"x/app":     {"a": "x"},
+		"pt-PT/app": {"a": "pt-PT", "b": "pt-PT"},
+		"pt/app":    {"b": "pt", "c": "pt"},
+		"de/app":    {"d": "de"},
+	}
+	i := NewI18N(map[string]Config{
+		"app": {SourceNewFunc: NewFuncSource(func(lang string, name string) (TMsgs, error) {
+			mutex.Lock()
+			calls++
+			running++
+			peak = max(peak, running)
+			mutex.Unlock()
+			defer func() {
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+			}()
+			time.Sleep(10 * time.Millisecond)
+			msgs, ok := remote[lang+"/"+name]
+			if !ok {
+				return nil, ErrCatalogNotFound
+			}
+			return maps.Clone(msgs), nil
+		}), BasePath: "remote", FileMap: map[string]string{}, LoadConcurrency: 2,
+			Fallbacks: map[string][]string{"x": {"pt-PT", "pt"}}},
+	}, quietLogger())
+	s, _ := i.getSource("app.app")
+	msgs, err := s.LoadMsgs("app.app", "x")
+	if want := (TMsgs{"a": "x", "b": "pt-PT", "c": "pt"}); err != nil || !reflect.DeepEqual(msgs, want) {
+		t.Errorf("LoadMsgs() = %q, %v, want %q", msgs, err, want)
+	}
+	if peak != 2 {
+		t.Errorf("%d concurrent fetches, want 2", peak)
+	}
+
+	peak, calls = 0, 0
+	if err := i.Preload(context.Background(), []string{"app.app"}, []string{"x", "de", "pt"}); err != nil {
+		t.Fatal(err)
+	}
+	// The catalogs read by LoadMsgs are cached, pt's shared with x.
+	if calls != 1 {
+		t.Errorf("Preload() made %d fetches, want 1", calls)
+	}
+	if got := i.T("app.app", "d", nil, "de"); got != "de" {
+		t.Errorf("T() after Preload = %q", got)
+	}
+	if calls != 1 {
+		t.Errorf("T() after Preload fetched again")
+	}
+
+	calls = 0
+	remote["pt/app"] = TMsgs{"c": "pt2"}
+	if err := i.ReloadCatalog("app.app", "pt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := i.T("app.app", "c", nil, "x"); got != "pt2" || calls != 1 {
+		t.Errorf("T() after ReloadCatalog = %q with %d fetches, want %q with 1", got, calls, "pt2")
+	}
+}
+
+func TestSharedCatalogs(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(dir+"/de", 0755)
+	os.WriteFile(dir+"/de/app.json", []byte(`{"Cart": "Warenkorb"}`), 0644)
+	logger := quietLogger()
+	newI18N := func() *I18N {
+		return NewI18N(map[string]Config{
+			"app": {SourceNewFunc: NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+		}, logger)
+	}
+	key := "json\x00" + dir + "\x00\x00"
+	func() {
+		i1, i2 := newI18N(), newI18N()
+		if got := i1.T("app.app", "Cart", nil, "de"); got != "Warenkorb" {
+			t.Fatalf("T() = %q", got)
+		}
+		os.WriteFile(dir+"/de/app.json", []byte(`{"Cart": "Einkaufswagen"}`), 0644)
+		if got := i2.T("app.app", "Cart", nil, "de"); got != "Warenkorb" {
+			t.Errorf("T() of a second I18N = %q, want the catalog read by the first", got)
+		}
+		if err := i2.ReloadCatalog("app.app", "de"); err != nil {
+			t.Fatal(err)
+		}
+		if err := i1.Reload(); err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range []*I18N{i1, i2} {
+			if got := i.T("app.app", "Cart", nil, "de"); got != "Einkaufswagen" {
+				t.Errorf("T() after reload = %q", got)
+			}
+		}
+		sharedStores.mutex.Lock()
+		refs := sharedStores.stores[key].refs
+		sharedStores.mutex.Unlock()
+		if refs != 2 {
+			t.Errorf("store shared by %d sources, want 2", refs)
+		}
+	}()
+	Translator = nil
+	for range 100 {
+		runtime.GC()
+		sharedStores.mutex.Lock()
+		_, ok := sharedStores.stores[key]
+		sharedStores.mutex.Unlock()
+		if !ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("store kept after its sources were collected")
+}
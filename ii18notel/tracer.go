@@ -0,0 +1,66 @@
+// Package ii18notel records ii18n catalog loads as OpenTelemetry spans.
+//
+//	ii18n.NewI18N(config, ii18n.WithTracer(ii18notel.NewTracer(otel.Tracer("ii18n"))))
+package ii18notel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/syyongx/ii18n"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ ii18n.Tracer = (*Tracer)(nil)
+
+// Tracer adapts an OpenTelemetry tracer to ii18n.Tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a Tracer starting spans with tracer.
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// Start implements ii18n.Tracer.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, ii18n.Span) {
+	ctx, span := t.tracer.Start(ctx, name)
+	return ctx, &Span{span: span}
+}
+
+// Span adapts an OpenTelemetry span to ii18n.Span.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttribute implements ii18n.Span.
+func (s *Span) SetAttribute(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	case float64:
+		s.span.SetAttributes(attribute.Float64(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+// RecordError implements ii18n.Span.
+func (s *Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements ii18n.Span.
+func (s *Span) End() {
+	s.span.End()
+}
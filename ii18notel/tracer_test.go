@@ -0,0 +1,48 @@
+package ii18notel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(provider.Tracer("ii18n"))
+
+	_, span := tracer.Start(context.Background(), "ii18n.LoadMsgs")
+	span.SetAttribute("ii18n.category", "app.shop")
+	span.SetAttribute("ii18n.bytes", int64(21))
+	span.SetAttribute("ii18n.cached", true)
+	span.RecordError(errors.New("broken"))
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("%d spans ended, want 1", len(ended))
+	}
+	got := ended[0]
+	if got.Name() != "ii18n.LoadMsgs" || got.Status().Code != codes.Error || got.Status().Description != "broken" {
+		t.Errorf("span %q with status %+v", got.Name(), got.Status())
+	}
+	expected := []attribute.KeyValue{
+		attribute.String("ii18n.category", "app.shop"),
+		attribute.Int64("ii18n.bytes", 21),
+		attribute.Bool("ii18n.cached", true),
+	}
+	attrs := got.Attributes()
+	if len(attrs) != len(expected) {
+		t.Fatalf("attributes = %v, want %v", attrs, expected)
+	}
+	for n, kv := range expected {
+		if attrs[n] != kv {
+			t.Errorf("attribute %d = %v, want %v", n, attrs[n], kv)
+		}
+	}
+}
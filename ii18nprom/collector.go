@@ -0,0 +1,114 @@
+// Package ii18nprom exposes ii18n translation activity as Prometheus metrics.
+//
+//	c := ii18nprom.NewCollector("myapp")
+//	prometheus.MustRegister(c)
+//	ii18n.NewI18N(config, ii18n.WithMetrics(c))
+package ii18nprom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/syyongx/ii18n"
+)
+
+var _ ii18n.Metrics = (*Collector)(nil)
+
+// Collector records ii18n events and implements prometheus.Collector.
+// The cache hit ratio is
+// rate(ii18n_cache_lookups_total{result="hit"}) / rate(ii18n_cache_lookups_total).
+type Collector struct {
+	served       *prometheus.CounterVec
+	missed       *prometheus.CounterVec
+	loads        *prometheus.HistogramVec
+	cacheLookups *prometheus.CounterVec
+	reloadErrors *prometheus.CounterVec
+}
+
+// NewCollector returns a Collector whose metrics are prefixed with namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		served: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ii18n",
+			Name:      "translations_served_total",
+			Help:      "Messages returned by the translator.",
+		}, []string{"category", "lang"}),
+		missed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ii18n",
+			Name:      "translations_missed_total",
+			Help:      "Messages without a translation in the requested language.",
+		}, []string{"category", "lang"}),
+		loads: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "ii18n",
+			Name:      "catalog_load_duration_seconds",
+			Help:      "Duration of catalog loads.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 8),
+		}, []string{"category", "lang", "result"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ii18n",
+			Name:      "cache_lookups_total",
+			Help:      "Catalog cache lookups by result.",
+		}, []string{"result"}),
+		reloadErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ii18n",
+			Name:      "reload_errors_total",
+			Help:      "Failed catalog reloads.",
+		}, []string{"category"}),
+	}
+}
+
+// Served implements ii18n.Metrics.
+func (c *Collector) Served(category string, lang string) {
+	c.served.WithLabelValues(category, lang).Inc()
+}
+
+// Missed implements ii18n.Metrics.
+func (c *Collector) Missed(category string, lang string) {
+	c.missed.WithLabelValues(category, lang).Inc()
+}
+
+// Loaded implements ii18n.Metrics.
+func (c *Collector) Loaded(category string, lang string, elapsed time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	c.loads.WithLabelValues(category, lang, result).Observe(elapsed.Seconds())
+}
+
+// CacheLookup implements ii18n.Metrics.
+func (c *Collector) CacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// ReloadFailed implements ii18n.Metrics.
+func (c *Collector) ReloadFailed(category string, err error) {
+	c.reloadErrors.WithLabelValues(category).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.served.Describe(ch)
+	c.missed.Describe(ch)
+	c.loads.Describe(ch)
+	c.cacheLookups.Describe(ch)
+	c.reloadErrors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.served.Collect(ch)
+	c.missed.Collect(ch)
+	c.loads.Collect(ch)
+	c.cacheLookups.Collect(ch)
+	c.reloadErrors.Collect(ch)
+}
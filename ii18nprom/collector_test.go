@@ -0,0 +1,45 @@
+package ii18nprom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("test")
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatal(err)
+	}
+	c.Served("app.shop", "de")
+	c.Served("app.shop", "de")
+	c.Missed("app.shop", "de")
+	c.Loaded("app.shop", "de", time.Millisecond, nil)
+	c.Loaded("app.shop", "fr", time.Millisecond, errors.New("broken"))
+	c.CacheLookup(true)
+	c.CacheLookup(false)
+	c.ReloadFailed("app", errors.New("broken"))
+
+	if n := testutil.ToFloat64(c.served.WithLabelValues("app.shop", "de")); n != 2 {
+		t.Errorf("served = %v, want 2", n)
+	}
+	if n := testutil.ToFloat64(c.missed.WithLabelValues("app.shop", "de")); n != 1 {
+		t.Errorf("missed = %v, want 1", n)
+	}
+	if n := testutil.ToFloat64(c.cacheLookups.WithLabelValues("hit")); n != 1 {
+		t.Errorf("cache hits = %v, want 1", n)
+	}
+	if n := testutil.ToFloat64(c.reloadErrors.WithLabelValues("app")); n != 1 {
+		t.Errorf("reload errors = %v, want 1", n)
+	}
+	if n := testutil.CollectAndCount(c, "test_ii18n_catalog_load_duration_seconds"); n != 2 {
+		t.Errorf("load histograms = %d, want one per result", n)
+	}
+	if problems, err := testutil.GatherAndLint(registry); err != nil || len(problems) != 0 {
+		t.Errorf("GatherAndLint() = %v, %v", problems, err)
+	}
+}
@@ -0,0 +1,93 @@
+package ii18ntest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/syyongx/ii18n"
+)
+
+// SourceFactory Returns the SourceNewFunc of a source serving catalogs,
+// keyed like ii18n.NewMemSource by "{lang}/{name}". Sources reading files
+// write them to a directory of t and set the BasePath of the Config.
+type SourceFactory func(t *testing.T, catalogs map[string]ii18n.TMsgs) func(*ii18n.Config) ii18n.Source
+
+// contractCatalogs the catalogs of category "app.shop" served in the
+// contract tests. The original language is en-US.
+var contractCatalogs = map[string]ii18n.TMsgs{
+	"en-US/shop": {"Cart": "Cart", "Pay": "Pay", "Empty": "Empty"},
+	"de/shop":    {"Cart": "Warenkorb", "Pay": "Bezahlen", "Empty": "Leer"},
+	"de-AT/shop": {"Cart": "Einkaufswagen", "Pay": ""},
+}
+
+// SourceContractTest Runs the semantics every Source is expected to share
+// with the sources of ii18n as subtests on the sources of factory: lookup,
+// fallback to parent locales with specific messages merged over generic
+// ones, and the errors of missing translations, catalogs and invalid
+// categories.
+func SourceContractTest(t *testing.T, factory SourceFactory) {
+	newSource := func(t *testing.T) ii18n.Source {
+		catalogs := make(map[string]ii18n.TMsgs, len(contractCatalogs))
+		for name, msgs := range contractCatalogs {
+			catalogs[name] = make(ii18n.TMsgs, len(msgs))
+			for key, val := range msgs {
+				catalogs[name][key] = val
+			}
+		}
+		conf := &ii18n.Config{OriginalLang: "en-US", BasePath: "contract", FileMap: map[string]string{}}
+		return factory(t, catalogs)(conf)
+	}
+	translations := []struct {
+		name     string
+		message  string
+		lang     string
+		expected string
+	}{
+		{"Translate", "Cart", "de", "Warenkorb"},
+		{"SpecificWins", "Cart", "de-AT", "Einkaufswagen"},
+		{"EmptyFallsBack", "Pay", "de-AT", "Bezahlen"},
+		{"AbsentFallsBack", "Empty", "de-AT", "Leer"},
+		{"FallbackChain", "Cart", "de-AT-1996", "Einkaufswagen"},
+	}
+	for _, tt := range translations {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := newSource(t).TranslateMsg("app.shop", tt.message, tt.lang)
+			if msg != tt.expected || err != nil {
+				t.Errorf("TranslateMsg(%s, %s) = %q, %v, want %q", tt.message, tt.lang, msg, err, tt.expected)
+			}
+		})
+	}
+	t.Run("OriginalLang", func(t *testing.T) {
+		if msg, err := newSource(t).Translate("app.shop", "Cart", "en-US"); msg != "" || err != nil {
+			t.Errorf("Translate(en-US) = %q, %v, want no translation", msg, err)
+		}
+	})
+	t.Run("LoadMsgs", func(t *testing.T) {
+		msgs, err := newSource(t).LoadMsgs("app.shop", "de-AT")
+		expected := ii18n.TMsgs{"Cart": "Einkaufswagen", "Pay": "Bezahlen", "Empty": "Leer"}
+		if !reflect.DeepEqual(msgs, expected) || err != nil {
+			t.Errorf("LoadMsgs(de-AT) = %v, %v, want %v", msgs, err, expected)
+		}
+	})
+	t.Run("MissingTranslation", func(t *testing.T) {
+		var missing *ii18n.MissingTranslationError
+		if _, err := newSource(t).TranslateMsg("app.shop", "Checkout", "de"); !errors.As(err, &missing) {
+			t.Errorf("TranslateMsg(Checkout) = %v, want *MissingTranslationError", err)
+		}
+	})
+	t.Run("MissingCatalog", func(t *testing.T) {
+		s := newSource(t)
+		if _, err := s.LoadMsgs("app.shop", "fr"); !errors.Is(err, ii18n.ErrCatalogNotFound) {
+			t.Errorf("LoadMsgs(fr) = %v, want ErrCatalogNotFound", err)
+		}
+		if msg, err := s.TranslateMsg("app.shop", "Cart", "fr"); err == nil {
+			t.Errorf("TranslateMsg(fr) = %q, want an error", msg)
+		}
+	})
+	t.Run("InvalidCategory", func(t *testing.T) {
+		if _, err := newSource(t).TranslateMsg("app..shop", "Cart", "de"); !errors.Is(err, ii18n.ErrInvalidCategory) {
+			t.Errorf("TranslateMsg(app..shop) = %v, want ErrInvalidCategory", err)
+		}
+	})
+}
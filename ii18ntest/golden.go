@@ -0,0 +1,70 @@
+package ii18ntest
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syyongx/ii18n"
+)
+
+// update rewrites golden files instead of comparing with them, prefixed so
+// as not to clash with the -update flag of the tests using the package.
+var update = flag.Bool("ii18ntest.update", false, "rewrite ii18ntest golden files")
+
+// Case a pattern formatted by Golden.
+type Case struct {
+	// Name unique name of the case in its golden file.
+	Name    string
+	Pattern string
+	Params  map[string]string
+	Lang    string
+}
+
+// Golden Formats every case and compares the output with the golden file
+// filename, a JSON object of outputs by case name. Running the test with
+// -ii18ntest.update rewrites the file. Patterns that fail to format are
+// recorded as "error: " and the error.
+func Golden(t testing.TB, filename string, cases []Case) {
+	t.Helper()
+	f := ii18n.NewFormatter()
+	got := make(map[string]string, len(cases))
+	for _, c := range cases {
+		out, err := f.Format(c.Pattern, c.Params, c.Lang)
+		if err != nil {
+			out = "error: " + err.Error()
+		}
+		got[c.Name] = out
+	}
+	if *update {
+		data, err := json.MarshalIndent(got, "", "\t")
+		if err != nil {
+			t.Fatalf("ii18ntest: %v", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("ii18ntest: %v", err)
+		}
+		if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+			t.Fatalf("ii18ntest: %v", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ii18ntest: %v; run with -ii18ntest.update to create it", err)
+	}
+	var want map[string]string
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("ii18ntest: %s: %v", filename, err)
+	}
+	for _, c := range cases {
+		expected, ok := want[c.Name]
+		if !ok {
+			t.Errorf("ii18ntest: %s has no case %q; run with -ii18ntest.update to add it", filename, c.Name)
+		} else if got[c.Name] != expected {
+			t.Errorf("ii18ntest: %s: %q in %s = %q, golden %q", c.Name, c.Pattern, c.Lang, got[c.Name], expected)
+		}
+	}
+}
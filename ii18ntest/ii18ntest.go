@@ -0,0 +1,151 @@
+// Package ii18ntest provides in-memory catalogs and assertions for the tests
+// of applications using ii18n.
+//
+//	i := ii18ntest.NewSource("en-US").
+//		Add("de", "app.shop", ii18n.TMsgs{"Cart": "Warenkorb"}).
+//		I18N()
+//	ii18ntest.AssertTranslated(t, i, "app.shop", "Cart", "de")
+//	ii18ntest.RequireNoMissing(t, i, "de")
+package ii18ntest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/syyongx/ii18n"
+)
+
+// Builder collects the catalogs of in-memory sources, one per category
+// prefix.
+type Builder struct {
+	originalLang string
+	// catalogs by prefix, then by "{lang}/{name}" as NewMemSource expects.
+	catalogs map[string]map[string]ii18n.TMsgs
+}
+
+// NewSource Returns a Builder of sources whose original language is
+// originalLang.
+func NewSource(originalLang string) *Builder {
+	return &Builder{originalLang: originalLang, catalogs: make(map[string]map[string]ii18n.TMsgs)}
+}
+
+// Add Adds msgs to the catalog of category in lang. Categories without a dot
+// are resolved like T does.
+func (b *Builder) Add(lang string, category string, msgs ii18n.TMsgs) *Builder {
+	prefix, name, ok := strings.Cut(category, ".")
+	if !ok {
+		prefix, name = "app", category
+	}
+	if b.catalogs[prefix] == nil {
+		b.catalogs[prefix] = make(map[string]ii18n.TMsgs)
+	}
+	key := lang + "/" + name
+	if b.catalogs[prefix][key] == nil {
+		b.catalogs[prefix][key] = make(ii18n.TMsgs, len(msgs))
+	}
+	for message, translation := range msgs {
+		b.catalogs[prefix][key][message] = translation
+	}
+	return b
+}
+
+// LoadFixtures Returns a Builder of the catalogs under dir, laid out as
+// {dir}/{lang}/{name}.{format} for every format of ii18n.Formats. Names are
+// categories of prefix "app", directories being dots: de/admin/users.json is
+// category "app.admin.users" in de. The original language is
+// ii18n.DefaultOriginalLang.
+func LoadFixtures(dir string) (*Builder, error) {
+	b := NewSource(ii18n.DefaultOriginalLang)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		lang, name, ok := strings.Cut(filepath.ToSlash(rel), "/")
+		ext := filepath.Ext(name)
+		codec, known := ii18n.GetCodec(strings.TrimPrefix(ext, "."))
+		if !ok || !known {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			data, err = ii18n.DecodeText(data)
+		}
+		if err != nil {
+			return err
+		}
+		msgs, err := codec.Decode(data)
+		if err != nil {
+			return &ii18n.LoadError{Path: path, Err: err}
+		}
+		b.Add(lang, "app."+strings.ReplaceAll(strings.TrimSuffix(name, ext), "/", "."), msgs)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Config Returns the configuration of a MemSource per category prefix.
+func (b *Builder) Config() map[string]ii18n.Config {
+	config := make(map[string]ii18n.Config, len(b.catalogs))
+	for prefix, catalogs := range b.catalogs {
+		config[prefix] = ii18n.Config{
+			SourceNewFunc: ii18n.NewMemSource(catalogs),
+			OriginalLang:  b.originalLang,
+			BasePath:      "mem",
+			FileMap:       map[string]string{},
+		}
+	}
+	return config
+}
+
+// I18N Returns a new I18N serving the catalogs, see ii18n.NewI18N.
+func (b *Builder) I18N(opts ...ii18n.Option) *ii18n.I18N {
+	return ii18n.NewI18N(b.Config(), opts...)
+}
+
+// AssertTranslated Reports an error for every language of langs in which key
+// of category has no translation.
+func AssertTranslated(t testing.TB, i *ii18n.I18N, category string, key string, langs ...string) {
+	t.Helper()
+	for _, lang := range langs {
+		if msg, err := i.Lookup(category, key, lang); err != nil || msg == "" {
+			t.Errorf("ii18ntest: %q of %s is not translated in %s", key, category, lang)
+		}
+	}
+}
+
+// RequireNoMissing Fails the test now unless every message of every category
+// listed by i.Categories has a translation in lang.
+func RequireNoMissing(t testing.TB, i *ii18n.I18N, lang string) {
+	t.Helper()
+	categories, err := i.Categories()
+	if err != nil {
+		t.Fatalf("ii18ntest: listing categories: %v", err)
+	}
+	var problems []string
+	for _, category := range categories {
+		coverage, err := i.Coverage(category)
+		if err != nil {
+			t.Fatalf("ii18ntest: coverage of %s: %v", category, err)
+		}
+		stats, ok := coverage[lang]
+		if !ok {
+			problems = append(problems, category+": no catalogs")
+		} else if stats.Missing > 0 {
+			problems = append(problems, category+": "+strconv.Itoa(stats.Missing)+" of "+strconv.Itoa(stats.Total)+" messages missing")
+		}
+	}
+	if len(problems) > 0 {
+		t.Fatalf("ii18ntest: missing translations in %s:\n%s", lang, strings.Join(problems, "\n"))
+	}
+}
@@ -0,0 +1,103 @@
+package ii18ntest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syyongx/ii18n"
+)
+
+func TestBuilder(t *testing.T) {
+	i := NewSource("en-US").
+		Add("en-US", "app.shop", ii18n.TMsgs{"Cart": "Cart", "Pay": "Pay"}).
+		Add("de", "app.shop", ii18n.TMsgs{"Cart": "Warenkorb"}).
+		Add("de", "shop", ii18n.TMsgs{"Pay": "Bezahlen"}).
+		Add("de", "app.admin.users", ii18n.TMsgs{"Users": "Benutzer"}).
+		I18N()
+	if res := ii18n.T("app.shop", "Pay", nil, "de-AT"); res != "Bezahlen" {
+		t.Errorf("T(de-AT) = %q", res)
+	}
+	AssertTranslated(t, i, "shop", "Cart", "de", "en-US")
+	AssertTranslated(t, i, "app.admin.users", "Users", "de")
+	RequireNoMissing(t, i, "de")
+	if categories, _ := i.Categories(); len(categories) != 2 || categories[0] != "app.admin.users" {
+		t.Errorf("Categories() = %v", categories)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "testdata/format.golden.json", []Case{
+		{Name: "plural-ru", Pattern: "{n, plural, one {# файл} few {# файла} other {# файлов}}", Params: map[string]string{"n": "3"}, Lang: "ru"},
+		{Name: "number-en", Pattern: "{n, number}", Params: map[string]string{"n": "1234.5"}, Lang: "en"},
+		{Name: "invalid", Pattern: "{n, plural,", Lang: "en"},
+	})
+}
+
+func TestSourceContract(t *testing.T) {
+	t.Run("MemSource", func(t *testing.T) {
+		SourceContractTest(t, func(t *testing.T, catalogs map[string]ii18n.TMsgs) func(*ii18n.Config) ii18n.Source {
+			return ii18n.NewMemSource(catalogs)
+		})
+	})
+	t.Run("JSONSource", func(t *testing.T) {
+		SourceContractTest(t, func(t *testing.T, catalogs map[string]ii18n.TMsgs) func(*ii18n.Config) ii18n.Source {
+			dir := t.TempDir()
+			for name, msgs := range catalogs {
+				data, _ := json.Marshal(msgs)
+				os.MkdirAll(filepath.Dir(dir+"/"+name), 0755)
+				os.WriteFile(dir+"/"+name+".json", data, 0644)
+			}
+			return func(conf *ii18n.Config) ii18n.Source {
+				conf.BasePath = dir
+				return ii18n.NewJSONSource(conf)
+			}
+		})
+	})
+}
+
+func TestMockSource(t *testing.T) {
+	mock := &MockSource{TranslateFunc: func(category, message, lang string) (string, error) {
+		return "Hallo {name}", nil
+	}}
+	ii18n.NewI18N(map[string]ii18n.Config{
+		"app": {SourceNewFunc: mock.New, BasePath: "mock", FileMap: map[string]string{}},
+	})
+	if res := ii18n.T("app.ui", "Hi {name}", map[string]string{"name": "Ann"}, "de"); res != "Hallo Ann" {
+		t.Errorf("T = %q", res)
+	}
+	calls := mock.Calls()
+	if len(calls) != 1 || calls[0].Method != "Translate" || calls[0].Args[2] != "de" {
+		t.Errorf("Calls() = %v", calls)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	b, err := LoadFixtures("testdata/locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.I18N()
+	tests := []struct {
+		category string
+		message  string
+		lang     string
+		n        string
+		expected string
+	}{
+		{"app.shop", "Cart", "de", "", "Warenkorb"},
+		{"app.shop", "Cart", "fr-CA", "", "Panier"},
+		{"app.shop", "{n, plural, one {# item} other {# items}}", "de", "3", "3 Artikel"},
+		{"app.shop", "{n, plural, one {# item} other {# items}}", "fr", "1", "1 article"},
+		{"app.admin.users", "Users", "de", "", "Benutzer"},
+	}
+	for _, tt := range tests {
+		if res := ii18n.T(tt.category, tt.message, map[string]string{"n": tt.n}, tt.lang); res != tt.expected {
+			t.Errorf("T(%s, %s) = %q, want %q", tt.message, tt.lang, res, tt.expected)
+		}
+	}
+	if _, err := LoadFixtures("testdata/missing"); err == nil {
+		t.Error("LoadFixtures(missing) succeeded")
+	}
+}
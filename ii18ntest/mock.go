@@ -0,0 +1,89 @@
+package ii18ntest
+
+import (
+	"sync"
+
+	"github.com/syyongx/ii18n"
+)
+
+var _ ii18n.Source = (*MockSource)(nil)
+
+// Call a call of a MockSource method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockSource a Source for unit tests calling the function set for each
+// method and recording every call. Methods without a function return no
+// translation and a *MissingTranslationError, or no messages.
+type MockSource struct {
+	TranslateFunc        func(category string, message string, lang string) (string, error)
+	TranslateMsgFunc     func(category string, message string, lang string) (string, error)
+	GetMsgFilePathFunc   func(category string, lang string) string
+	LoadMsgsFunc         func(category string, lang string) (ii18n.TMsgs, error)
+	LoadFallbackMsgsFunc func(category string, fallbackLang string, msgs ii18n.TMsgs, originalMsgFile string) (ii18n.TMsgs, error)
+
+	calls []Call
+	mutex sync.Mutex
+}
+
+// New Returns m, for use as the SourceNewFunc of a Config.
+func (m *MockSource) New(*ii18n.Config) ii18n.Source {
+	return m
+}
+
+// Calls Returns the calls made so far, in order.
+func (m *MockSource) Calls() []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *MockSource) record(method string, args ...interface{}) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func (m *MockSource) Translate(category string, message string, lang string) (string, error) {
+	m.record("Translate", category, message, lang)
+	if m.TranslateFunc != nil {
+		return m.TranslateFunc(category, message, lang)
+	}
+	return "", &ii18n.MissingTranslationError{Category: category, Message: message, Lang: lang}
+}
+
+func (m *MockSource) TranslateMsg(category string, message string, lang string) (string, error) {
+	m.record("TranslateMsg", category, message, lang)
+	if m.TranslateMsgFunc != nil {
+		return m.TranslateMsgFunc(category, message, lang)
+	}
+	return "", &ii18n.MissingTranslationError{Category: category, Message: message, Lang: lang}
+}
+
+func (m *MockSource) GetMsgFilePath(category string, lang string) string {
+	m.record("GetMsgFilePath", category, lang)
+	if m.GetMsgFilePathFunc != nil {
+		return m.GetMsgFilePathFunc(category, lang)
+	}
+	return ""
+}
+
+func (m *MockSource) LoadMsgs(category string, lang string) (ii18n.TMsgs, error) {
+	m.record("LoadMsgs", category, lang)
+	if m.LoadMsgsFunc != nil {
+		return m.LoadMsgsFunc(category, lang)
+	}
+	return ii18n.TMsgs{}, nil
+}
+
+func (m *MockSource) LoadFallbackMsgs(category string, fallbackLang string, msgs ii18n.TMsgs, originalMsgFile string) (ii18n.TMsgs, error) {
+	m.record("LoadFallbackMsgs", category, fallbackLang, msgs, originalMsgFile)
+	if m.LoadFallbackMsgsFunc != nil {
+		return m.LoadFallbackMsgsFunc(category, fallbackLang, msgs, originalMsgFile)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,153 @@
+// Package ii18nvalidator translates go-playground/validator errors with
+// ii18n catalogs instead of the validator's own translations.
+//
+//	a := ii18nvalidator.New("app.validation")
+//	if err := validate.Struct(form); err != nil {
+//		messages := a.TranslateValidationErrors(err, "de")
+//	}
+//
+// The keys of the catalogs are the English templates of Templates, e.g.
+// {"{field} is required": "{field} ist erforderlich"}.
+package ii18nvalidator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/syyongx/ii18n"
+)
+
+// Templates the default templates by validator tag, English patterns with
+// the params {field}, {param}, {value} and {tag}. Tags whose meaning depends
+// on the kind of the field have a template per kind, "{tag}.string",
+// "{tag}.number" and "{tag}.items", and "invalid" is the template of other
+// tags.
+var Templates = map[string]string{
+	"required":      "{field} is required",
+	"required_if":   "{field} is required",
+	"required_with": "{field} is required",
+	"email":         "{field} must be a valid email address",
+	"url":           "{field} must be a valid URL",
+	"uuid":          "{field} must be a valid UUID",
+	"alpha":         "{field} can only contain letters",
+	"alphanum":      "{field} can only contain letters and digits",
+	"numeric":       "{field} must be a number",
+	"oneof":         "{field} must be one of {param}",
+	"eqfield":       "{field} must be equal to {param}",
+	"nefield":       "{field} cannot be equal to {param}",
+	"len.string":    "{field} must be {param, plural, one {# character} other {# characters}} long",
+	"len.number":    "{field} must be equal to {param}",
+	"len.items":     "{field} must contain {param, plural, one {# item} other {# items}}",
+	"min.string":    "{field} must be at least {param, plural, one {# character} other {# characters}} long",
+	"min.number":    "{field} must be {param} or greater",
+	"min.items":     "{field} must contain at least {param, plural, one {# item} other {# items}}",
+	"max.string":    "{field} must be at most {param, plural, one {# character} other {# characters}} long",
+	"max.number":    "{field} must be {param} or less",
+	"max.items":     "{field} must contain at most {param, plural, one {# item} other {# items}}",
+	"gt.string":     "{field} must be longer than {param, plural, one {# character} other {# characters}}",
+	"gt.number":     "{field} must be greater than {param}",
+	"gt.items":      "{field} must contain more than {param, plural, one {# item} other {# items}}",
+	"gte.string":    "{field} must be at least {param, plural, one {# character} other {# characters}} long",
+	"gte.number":    "{field} must be {param} or greater",
+	"gte.items":     "{field} must contain at least {param, plural, one {# item} other {# items}}",
+	"lt.string":     "{field} must be shorter than {param, plural, one {# character} other {# characters}}",
+	"lt.number":     "{field} must be less than {param}",
+	"lt.items":      "{field} must contain less than {param, plural, one {# item} other {# items}}",
+	"lte.string":    "{field} must be at most {param, plural, one {# character} other {# characters}} long",
+	"lte.number":    "{field} must be {param} or less",
+	"lte.items":     "{field} must contain at most {param, plural, one {# item} other {# items}}",
+	"invalid":       "{field} is invalid",
+}
+
+// Adapter translates validator errors with the catalogs of a category.
+type Adapter struct {
+	// I18N the translator, ii18n.Translator when nil.
+	I18N *ii18n.I18N
+	// FieldCategory translates the field names when set, the keys of its
+	// catalogs being the names of the struct fields.
+	FieldCategory string
+	category      string
+	templates     map[string]string
+}
+
+// New Returns an Adapter translating the templates with the catalogs of
+// category.
+func New(category string) *Adapter {
+	templates := make(map[string]string, len(Templates))
+	for tag, tmpl := range Templates {
+		templates[tag] = tmpl
+	}
+	return &Adapter{category: category, templates: templates}
+}
+
+// Register Sets the template of tag, or of tag for a kind of field when tag
+// is "{tag}.string", "{tag}.number" or "{tag}.items", e.g. for custom
+// validations.
+func (a *Adapter) Register(tag string, template string) {
+	a.templates[tag] = template
+}
+
+// Translate Returns the message of fe in lang.
+func (a *Adapter) Translate(fe validator.FieldError, lang string) string {
+	i := a.I18N
+	if i == nil {
+		i = ii18n.Translator
+	}
+	field := fe.Field()
+	if a.FieldCategory != "" {
+		field = i.T(a.FieldCategory, field, nil, lang)
+	}
+	params := map[string]string{
+		"field": field,
+		"param": fe.Param(),
+		"value": fmt.Sprint(fe.Value()),
+		"tag":   fe.Tag(),
+	}
+	return i.T(a.category, a.template(fe), params, lang)
+}
+
+// template Returns the template of fe.
+func (a *Adapter) template(fe validator.FieldError) string {
+	if tmpl, ok := a.templates[fe.Tag()+"."+kindName(fe.Kind())]; ok {
+		return tmpl
+	}
+	if tmpl, ok := a.templates[fe.Tag()]; ok {
+		return tmpl
+	}
+	return a.templates["invalid"]
+}
+
+// kindName Returns the template kind of fields of kind k.
+func kindName(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return "number"
+}
+
+// TranslateValidationErrors Returns the messages of the validation errors
+// of err in lang by field namespace, e.g. "User.Name", nil when err does
+// not wrap validator.ValidationErrors.
+func (a *Adapter) TranslateValidationErrors(err error, lang string) map[string]string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	messages := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		messages[fe.Namespace()] = a.Translate(fe, lang)
+	}
+	return messages
+}
+
+// TranslateValidationErrors Returns the messages of the validation errors
+// of err in lang, translated with the catalogs of the app.validation
+// category.
+func TranslateValidationErrors(err error, lang string) map[string]string {
+	return New("app.validation").TranslateValidationErrors(err, lang)
+}
@@ -0,0 +1,99 @@
+package crowdin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// Connector Returns c as an integrations.Connector, the resources being the
+// paths of the files without the leading slash.
+func (c *Client) Connector() integrations.Connector {
+	return connector{c}
+}
+
+type connector struct {
+	c *Client
+}
+
+func (c connector) ListResources(ctx context.Context) ([]string, error) {
+	files, err := c.c.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]string, 0, len(files))
+	for p := range files {
+		resources = append(resources, strings.TrimPrefix(p, "/"))
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+func (c connector) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	uploaded, err := c.c.Push(ctx, base, sourceLang)
+	for n, p := range uploaded {
+		uploaded[n] = strings.TrimPrefix(p, "/")
+	}
+	return uploaded, err
+}
+
+func (c connector) Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error) {
+	return c.c.pull(ctx, base, langs)
+}
+
+// Webhook Returns the changes of the file events of r, batched or not, e.g.
+// file.translated and file.approved.
+func (c connector) Webhook(r *http.Request) ([]integrations.Change, error) {
+	secret := r.Header.Get("X-Webhook-Secret")
+	if c.c.WebhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(c.c.WebhookSecret)) != 1 {
+		return nil, integrations.ErrSignature
+	}
+	type event struct {
+		Event string `json:"event"`
+		File  struct {
+			Path string `json:"path"`
+		} `json:"file"`
+		TargetLanguage struct {
+			ID string `json:"id"`
+		} `json:"targetLanguage"`
+	}
+	var payload struct {
+		event
+		Events []event `json:"events"`
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	events := payload.Events
+	if len(events) == 0 {
+		events = []event{payload.event}
+	}
+	var changes []integrations.Change
+	for _, e := range events {
+		if !strings.HasPrefix(e.Event, "file.") {
+			continue
+		}
+		changes = append(changes, integrations.Change{Resource: strings.TrimPrefix(e.File.Path, "/"), Lang: c.c.lang(e.TargetLanguage.ID)})
+	}
+	return changes, nil
+}
+
+// lang Returns the ii18n language of the Crowdin language ID id.
+func (c *Client) lang(id string) string {
+	for lang, target := range c.Languages {
+		if target == id {
+			return lang
+		}
+	}
+	return id
+}
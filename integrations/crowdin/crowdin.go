@@ -0,0 +1,361 @@
+// Package crowdin syncs ii18n catalogs with a Crowdin project through the
+// Crowdin API v2.
+//
+// The catalogs of a base path laid out as {base}/{lang}/{file} map to the
+// Crowdin files of the same path without the language, so the category
+// "app.admin.users" of a JSON source is the Crowdin file /admin/users.json:
+//
+//	c := crowdin.New(os.Getenv("CROWDIN_TOKEN"), 42)
+//	c.Push(ctx, "./locales", "en-US")                  // upload the source catalogs
+//	c.Pull(ctx, "./locales", []string{"de", "pt-BR"}) // download the translations
+package crowdin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultEndpoint the API of crowdin.com; Crowdin Enterprise organizations
+// use https://{organization}.api.crowdin.com/api/v2.
+const DefaultEndpoint = "https://api.crowdin.com/api/v2"
+
+// Client a Crowdin project.
+type Client struct {
+	Token     string
+	ProjectID int
+	Endpoint  string
+	Client    *http.Client
+	// Languages the Crowdin language IDs of ii18n languages that differ,
+	// e.g. {"es-419": "es-MX"}.
+	Languages map[string]string
+	// WebhookSecret the value of the X-Webhook-Secret header added to the
+	// webhooks of the project, which Crowdin does not sign.
+	WebhookSecret string
+}
+
+// New returns a Client of the project projectID, authenticated by the
+// personal access token.
+func New(token string, projectID int) *Client {
+	return &Client{Token: token, ProjectID: projectID, Endpoint: DefaultEndpoint, Client: http.DefaultClient}
+}
+
+// File a file of the project.
+type File struct {
+	ID int `json:"id"`
+	// Path the path of the file in the project, e.g. "/admin/users.json".
+	Path string `json:"path"`
+}
+
+// Category Returns the category of the file in prefix, "app.admin.users"
+// for /admin/users.json in app.
+func (f File) Category(prefix string) string {
+	name := strings.TrimPrefix(f.Path, "/")
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return prefix + "." + strings.ReplaceAll(name, "/", ".")
+}
+
+// FilePath Returns the Crowdin path of the catalog of category in files
+// with suffix, "/admin/users.json" for "app.admin.users" and "json".
+func FilePath(category string, suffix string) string {
+	_, name, _ := strings.Cut(category, ".")
+	return "/" + strings.ReplaceAll(name, ".", "/") + "." + suffix
+}
+
+// Files Returns the files of the project, by path.
+func (c *Client) Files(ctx context.Context) (map[string]File, error) {
+	files := make(map[string]File)
+	for offset := 0; ; offset += 500 {
+		var res struct {
+			Data []struct {
+				Data File `json:"data"`
+			} `json:"data"`
+		}
+		if err := c.call(ctx, http.MethodGet, c.project("/files?limit=500&offset="+strconv.Itoa(offset)), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, item := range res.Data {
+			files[item.Data.Path] = item.Data
+		}
+		if len(res.Data) < 500 {
+			return files, nil
+		}
+	}
+}
+
+// Push Uploads the catalogs of sourceLang under base, {base}/{sourceLang},
+// adding the files the project does not have and updating the others, and
+// returns the paths uploaded.
+func (c *Client) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	files, err := c.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := c.directories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(base, sourceLang)
+	var uploaded []string
+	err = filepath.WalkDir(root, func(name string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		p := "/" + filepath.ToSlash(rel)
+		if err := c.upload(ctx, files, dirs, p, data); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		uploaded = append(uploaded, p)
+		return nil
+	})
+	return uploaded, err
+}
+
+// upload Adds or updates the file p of the project with data.
+func (c *Client) upload(ctx context.Context, files map[string]File, dirs map[string]int, p string, data []byte) error {
+	storageID, err := c.store(ctx, path.Base(p), data)
+	if err != nil {
+		return err
+	}
+	if f, ok := files[p]; ok {
+		return c.call(ctx, http.MethodPut, c.project("/files/"+strconv.Itoa(f.ID)), map[string]interface{}{"storageId": storageID}, nil)
+	}
+	body := map[string]interface{}{"storageId": storageID, "name": path.Base(p)}
+	if dir := path.Dir(p); dir != "/" {
+		dirID, err := c.directory(ctx, dirs, dir)
+		if err != nil {
+			return err
+		}
+		body["directoryId"] = dirID
+	}
+	return c.call(ctx, http.MethodPost, c.project("/files"), body, nil)
+}
+
+// store Uploads data to the storage and returns its ID.
+func (c *Client) store(ctx context.Context, name string, data []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint()+"/storages", bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Crowdin-API-FileName", name)
+	var res struct {
+		Data struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.do(req, &res); err != nil {
+		return 0, err
+	}
+	return res.Data.ID, nil
+}
+
+// directories Returns the IDs of the directories of the project by path,
+// "/" being 0.
+func (c *Client) directories(ctx context.Context) (map[string]int, error) {
+	dirs := map[string]int{"/": 0}
+	for offset := 0; ; offset += 500 {
+		var res struct {
+			Data []struct {
+				Data struct {
+					ID   int    `json:"id"`
+					Path string `json:"path"`
+				} `json:"data"`
+			} `json:"data"`
+		}
+		if err := c.call(ctx, http.MethodGet, c.project("/directories?limit=500&offset="+strconv.Itoa(offset)), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, item := range res.Data {
+			dirs[item.Data.Path] = item.Data.ID
+		}
+		if len(res.Data) < 500 {
+			return dirs, nil
+		}
+	}
+}
+
+// directory Returns the ID of the directory dir of the project, creating it
+// and its parents when missing from dirs, the IDs by path.
+func (c *Client) directory(ctx context.Context, dirs map[string]int, dir string) (int, error) {
+	if id, ok := dirs[dir]; ok {
+		return id, nil
+	}
+	body := map[string]interface{}{"name": path.Base(dir)}
+	if parent := path.Dir(dir); parent != "/" {
+		parentID, err := c.directory(ctx, dirs, parent)
+		if err != nil {
+			return 0, err
+		}
+		body["directoryId"] = parentID
+	}
+	var res struct {
+		Data struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodPost, c.project("/directories"), body, &res); err != nil {
+		return 0, err
+	}
+	dirs[dir] = res.Data.ID
+	return res.Data.ID, nil
+}
+
+// Pull Downloads the translations of every file of the project in langs to
+// {base}/{lang}/{path}.
+func (c *Client) Pull(ctx context.Context, base string, langs []string) error {
+	_, err := c.pull(ctx, base, langs)
+	return err
+}
+
+// pull Pulls the translations in langs and returns the files written.
+func (c *Client) pull(ctx context.Context, base string, langs []string) ([]string, error) {
+	files, err := c.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var written []string
+	for _, lang := range langs {
+		for _, p := range paths {
+			// The paths come from the API and the languages possibly from
+			// webhooks: neither may lead out of the language directory.
+			rel := filepath.FromSlash(strings.TrimPrefix(p, "/"))
+			if !filepath.IsLocal(lang) || !filepath.IsLocal(rel) {
+				return written, fmt.Errorf("crowdin: %s %s: path outside of the language directory", lang, p)
+			}
+			data, err := c.Download(ctx, files[p], lang)
+			if err != nil {
+				return written, fmt.Errorf("%s %s: %w", lang, p, err)
+			}
+			name := filepath.Join(base, lang, rel)
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return written, err
+			}
+			if err := os.WriteFile(name, data, 0644); err != nil {
+				return written, err
+			}
+			written = append(written, name)
+		}
+	}
+	return written, nil
+}
+
+// Download Returns the file f translated to lang.
+func (c *Client) Download(ctx context.Context, f File, lang string) ([]byte, error) {
+	target := lang
+	if id, ok := c.Languages[lang]; ok {
+		target = id
+	}
+	var res struct {
+		Data struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	}
+	err := c.call(ctx, http.MethodPost, c.project("/translations/builds/files/"+strconv.Itoa(f.ID)), map[string]interface{}{"targetLanguageId": target}, &res)
+	if err != nil {
+		return nil, err
+	}
+	// The URL is signed, without the token.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.Data.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crowdin: GET %s: %s", req.URL.Host, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// call Sends the JSON body, when not nil, to the API and decodes the
+// response into v, when not nil.
+func (c *Client) call(ctx context.Context, method string, url string, body interface{}, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(req, v)
+}
+
+// do Sends req with the token and decodes the response into v, when not
+// nil.
+func (c *Client) do(req *http.Request, v interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var res struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&res)
+		if res.Error.Message != "" {
+			return fmt.Errorf("crowdin: %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, res.Error.Message)
+		}
+		return fmt.Errorf("crowdin: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *Client) client() *http.Client {
+	if c.Client == nil {
+		return http.DefaultClient
+	}
+	return c.Client
+}
+
+func (c *Client) endpoint() string {
+	if c.Endpoint == "" {
+		return DefaultEndpoint
+	}
+	return strings.TrimSuffix(c.Endpoint, "/")
+}
+
+// project Returns the URL of the project resource p.
+func (c *Client) project(p string) string {
+	return c.endpoint() + "/projects/" + strconv.Itoa(c.ProjectID) + p
+}
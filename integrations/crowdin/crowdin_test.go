@@ -0,0 +1,171 @@
+package crowdin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// testProject Returns a Client of a fake Crowdin project with the files
+// paths, each translated to its path and language, and the changes made to
+// the project, "{method} {path} {body}" each.
+func testProject(t *testing.T, paths ...string) (*Client, *[]string) {
+	var srv *httptest.Server
+	var changes []string
+	var mutex sync.Mutex
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The download URLs are signed: the token is for the API only.
+		download := strings.HasPrefix(r.URL.Path, "/download/")
+		if token := r.Header.Get("Authorization"); download && token != "" || !download && token != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch p := r.URL.Path; {
+		case r.Method == http.MethodGet && p == "/projects/42/files":
+			var res struct {
+				Data []map[string]File `json:"data"`
+			}
+			for n, p := range paths {
+				res.Data = append(res.Data, map[string]File{"data": {ID: n + 1, Path: p}})
+			}
+			json.NewEncoder(w).Encode(res)
+		case r.Method == http.MethodGet && p == "/projects/42/directories":
+			io.WriteString(w, `{"data": [{"data": {"id": 7, "path": "/admin"}}]}`)
+		case r.Method == http.MethodPost && p == "/storages":
+			data, _ := io.ReadAll(r.Body)
+			mutex.Lock()
+			changes = append(changes, "storage "+r.Header.Get("Crowdin-API-FileName")+" "+string(data))
+			id := len(changes)
+			mutex.Unlock()
+			io.WriteString(w, `{"data": {"id": `+strconv.Itoa(id)+`}}`)
+		case r.Method != http.MethodGet && strings.HasPrefix(p, "/projects/42/files") || r.Method == http.MethodPost && p == "/projects/42/directories":
+			data, _ := io.ReadAll(r.Body)
+			mutex.Lock()
+			changes = append(changes, r.Method+" "+p+" "+strings.TrimSpace(string(data)))
+			mutex.Unlock()
+			io.WriteString(w, `{"data": {"id": 8}}`)
+		case r.Method == http.MethodPost && strings.HasPrefix(p, "/projects/42/translations/builds/files/"):
+			var body struct {
+				TargetLanguageID string `json:"targetLanguageId"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			url := srv.URL + "/download/" + path.Base(p) + "/" + body.TargetLanguageID
+			json.NewEncoder(w).Encode(map[string]map[string]string{"data": {"url": url}})
+		case r.Method == http.MethodGet && strings.HasPrefix(p, "/download/"):
+			id, lang, _ := strings.Cut(strings.TrimPrefix(p, "/download/"), "/")
+			n, _ := strconv.Atoi(id)
+			io.WriteString(w, `{"file": "`+paths[n-1]+`", "lang": "`+lang+`"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := New("token", 42)
+	c.Endpoint = srv.URL
+	return c, &changes
+}
+
+func TestPullPaths(t *testing.T) {
+	base := t.TempDir()
+	c, _ := testProject(t, "/admin/users.json", "/shop.json")
+	if err := c.Pull(context.Background(), base, []string{"de"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(base, "de", "admin", "users.json")); err != nil || !strings.Contains(string(data), `"lang": "de"`) {
+		t.Errorf("de/admin/users.json = %s, %v", data, err)
+	}
+
+	tests := []struct {
+		path string
+		lang string
+	}{
+		{"/../../evil.json", "de"},
+		{"/admin/../../evil.json", "de"},
+		{"/shop.json", "../.."},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "locales")
+		c, _ := testProject(t, test.path)
+		err := c.Pull(context.Background(), base, []string{test.lang})
+		if err == nil || !strings.Contains(err.Error(), "outside of the language directory") {
+			t.Errorf("Pull(%s, %s) = %v", test.path, test.lang, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "evil.json")); err == nil {
+			t.Errorf("Pull(%s, %s) wrote outside of %s", test.path, test.lang, base)
+		}
+	}
+}
+
+func TestConnector(t *testing.T) {
+	client, changes := testProject(t, "/admin/users.json", "/shop.json")
+	client.Languages = map[string]string{"es-419": "es-MX"}
+	client.WebhookSecret = "s3cr3t"
+	c := client.Connector()
+	ctx := context.Background()
+
+	resources, err := c.ListResources(ctx)
+	if err != nil || !reflect.DeepEqual(resources, []string{"admin/users.json", "shop.json"}) {
+		t.Errorf("ListResources() = %v, %v", resources, err)
+	}
+
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "en-US", "admin", "roles"), 0755)
+	os.WriteFile(filepath.Join(base, "en-US", "admin", "roles", "list.json"), []byte(`{"Roles": "Roles"}`), 0644)
+	os.WriteFile(filepath.Join(base, "en-US", "shop.json"), []byte(`{"Cart": "Cart"}`), 0644)
+	pushed, err := c.Push(ctx, base, "en-US")
+	if err != nil || !reflect.DeepEqual(pushed, []string{"admin/roles/list.json", "shop.json"}) {
+		t.Errorf("Push() = %v, %v", pushed, err)
+	}
+	// New files are added to their directory, created when missing, and the
+	// others updated.
+	expected := []string{
+		`storage list.json {"Roles": "Roles"}`,
+		`POST /projects/42/directories {"directoryId":7,"name":"roles"}`,
+		`POST /projects/42/files {"directoryId":8,"name":"list.json","storageId":1}`,
+		`storage shop.json {"Cart": "Cart"}`,
+		`PUT /projects/42/files/2 {"storageId":4}`,
+	}
+	if !reflect.DeepEqual(*changes, expected) {
+		t.Errorf("Push() changes = %q, want %q", *changes, expected)
+	}
+
+	written, err := c.Pull(ctx, base, "en-US", []string{"es-419"})
+	if err != nil || len(written) != 2 || written[0] != filepath.Join(base, "es-419", "admin", "users.json") {
+		t.Errorf("Pull() = %v, %v", written, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(base, "es-419", "shop.json")); !strings.Contains(string(data), `"lang": "es-MX"`) {
+		t.Errorf("es-419/shop.json = %s", data)
+	}
+
+	body := `{"events": [
+		{"event": "file.translated", "file": {"path": "/shop.json"}, "targetLanguage": {"id": "es-MX"}},
+		{"event": "project.built"},
+		{"event": "file.approved", "file": {"path": "/admin/users.json"}, "targetLanguage": {"id": "de"}}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/hooks/crowdin", strings.NewReader(body))
+	req.Header.Set("X-Webhook-Secret", "s3cr3t")
+	webhook, err := c.Webhook(req)
+	if err != nil || !reflect.DeepEqual(webhook, []integrations.Change{{Resource: "shop.json", Lang: "es-419"}, {Resource: "admin/users.json", Lang: "de"}}) {
+		t.Errorf("Webhook() = %v, %v", webhook, err)
+	}
+	for _, secret := range []string{"", "other"} {
+		req := httptest.NewRequest(http.MethodPost, "/hooks/crowdin", strings.NewReader(body))
+		req.Header.Set("X-Webhook-Secret", secret)
+		if _, err := c.Webhook(req); err != integrations.ErrSignature {
+			t.Errorf("Webhook() with secret %q = %v, want ErrSignature", secret, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package integrations defines Connector, the interface of the translation
+// management systems the catalogs of ii18n sync with, so the sync commands
+// of the ii18n CLI and the webhook Handler work with any of them, including
+// in-house platforms.
+//
+// The catalogs are laid out as {base}/{lang}/{resource}, a resource being
+// the path of a catalog relative to the language directory, e.g.
+// "admin/users.json" for the category "app.admin.users":
+//
+//	var c integrations.Connector = crowdin.New(os.Getenv("CROWDIN_TOKEN"), 42).Connector()
+//	c.Push(ctx, "./locales", "en-US")
+//	http.Handle("/hooks/crowdin", integrations.NewHandler(c))
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+// Connector a project of a translation management system.
+type Connector interface {
+	// ListResources Returns the resources of the project, sorted.
+	ListResources(ctx context.Context) ([]string, error)
+	// Push Uploads the source catalogs under base, {base}/{sourceLang}, and
+	// returns the resources uploaded.
+	Push(ctx context.Context, base string, sourceLang string) ([]string, error)
+	// Pull Downloads the translations of the project in langs to the
+	// catalogs {base}/{lang}/{resource} and returns the files written.
+	Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error)
+	// Webhook Returns the changes reported by the webhook request r, or an
+	// ErrSignature error when r is not signed by the project.
+	Webhook(r *http.Request) ([]Change, error)
+}
+
+// Change a change of the translations of a project.
+type Change struct {
+	// Resource the resource changed, every resource when empty.
+	Resource string
+	// Lang the language changed, every language when empty.
+	Lang string
+}
+
+// ErrSignature the error of webhook requests without a valid signature.
+var ErrSignature = errors.New("invalid webhook signature")
+
+// Category Returns the category of resource in prefix, "app.admin.users"
+// for "admin/users.json" in "app".
+func Category(prefix string, resource string) string {
+	name := strings.TrimPrefix(resource, "/")
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return prefix + "." + strings.ReplaceAll(name, "/", ".")
+}
+
+// Handler an http.Handler of the webhooks of a Connector, which reloads the
+// catalogs changed.
+type Handler struct {
+	Connector Connector
+	// I18N the translator, ii18n.Translator when nil.
+	I18N *ii18n.I18N
+	// Prefix the category prefix of the resources, "app" when empty.
+	Prefix string
+	// Sync updates the catalogs before the reload when set, e.g. with
+	// Connector.Pull.
+	Sync func(ctx context.Context, changes []Change) error
+}
+
+// NewHandler returns a Handler of the webhooks of c.
+func NewHandler(c Connector) *Handler {
+	return &Handler{Connector: c}
+}
+
+// ServeHTTP Reloads the catalogs of the changes reported by r, answering 204
+// No Content, or 401 Unauthorized when the signature does not verify.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	changes, err := h.Connector.Webhook(r)
+	if errors.Is(err, ErrSignature) {
+		// Do not tell unverified callers why.
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.Sync != nil && len(changes) > 0 {
+		if err := h.Sync(r.Context(), changes); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	i := h.I18N
+	if i == nil {
+		i = ii18n.Translator
+	}
+	prefix := h.Prefix
+	if prefix == "" {
+		prefix = "app"
+	}
+	for _, c := range changes {
+		var err error
+		if c.Resource == "" {
+			err = i.Reload()
+		} else {
+			err = i.ReloadCatalog(Category(prefix, c.Resource), c.Lang)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,138 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/syyongx/ii18n"
+)
+
+// fakeConnector a Connector whose webhooks report changes, or err.
+type fakeConnector struct {
+	changes []Change
+	err     error
+}
+
+func (c *fakeConnector) ListResources(ctx context.Context) ([]string, error) { return nil, nil }
+
+func (c *fakeConnector) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *fakeConnector) Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *fakeConnector) Webhook(r *http.Request) ([]Change, error) {
+	return c.changes, c.err
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		resource string
+		expected string
+	}{
+		{"app", "shop.json", "app.shop"},
+		{"app", "admin/users.json", "app.admin.users"},
+		{"web", "/admin/users.yaml", "web.admin.users"},
+	}
+	for _, test := range tests {
+		if actual := Category(test.prefix, test.resource); actual != test.expected {
+			t.Errorf("Category(%s, %s) = %s, want %s", test.prefix, test.resource, actual, test.expected)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"de/shop.json":        `{"Cart": "Warenkorb"}`,
+		"de/admin/users.json": `{"Delete": "Löschen"}`,
+	}
+	for name, data := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)
+		os.WriteFile(filepath.Join(dir, name), []byte(data), 0644)
+	}
+	i := ii18n.NewI18N(map[string]ii18n.Config{
+		"app": {SourceNewFunc: ii18n.NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, ii18n.WithLogger(slog.New(slog.DiscardHandler)))
+	i.T("app.shop", "Cart", nil, "de")
+	i.T("app.admin.users", "Delete", nil, "de")
+	os.WriteFile(filepath.Join(dir, "de", "shop.json"), []byte(`{"Cart": "Einkaufswagen"}`), 0644)
+	os.WriteFile(filepath.Join(dir, "de", "admin", "users.json"), []byte(`{"Delete": "Entfernen"}`), 0644)
+
+	c := &fakeConnector{}
+	h := NewHandler(c)
+	h.I18N = i
+	var synced []Change
+	serve := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/hooks/tms", strings.NewReader("{}")))
+		return w
+	}
+
+	if w := serve(http.MethodGet); w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("ServeHTTP() of GET = %d, Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+	c.err = errors.Join(ErrSignature, errors.New("signed with key 2"))
+	if w := serve(http.MethodPost); w.Code != http.StatusUnauthorized || w.Body.String() != "Unauthorized\n" {
+		t.Errorf("ServeHTTP() unverified = %d %q, want 401 without details", w.Code, w.Body.String())
+	}
+	c.err = errors.New("unexpected end of JSON input")
+	if w := serve(http.MethodPost); w.Code != http.StatusBadRequest {
+		t.Errorf("ServeHTTP() of an invalid webhook = %d", w.Code)
+	}
+	c.err = nil
+
+	// A change of a resource reloads its catalog only.
+	c.changes = []Change{{Resource: "admin/users.json", Lang: "de"}}
+	h.Sync = func(ctx context.Context, changes []Change) error {
+		synced = append(synced, changes...)
+		return nil
+	}
+	if w := serve(http.MethodPost); w.Code != http.StatusNoContent {
+		t.Fatalf("ServeHTTP() = %d %s", w.Code, w.Body)
+	}
+	if !reflect.DeepEqual(synced, c.changes) {
+		t.Errorf("Sync() got %v, want %v", synced, c.changes)
+	}
+	if got := i.T("app.admin.users", "Delete", nil, "de"); got != "Entfernen" {
+		t.Errorf("T(Delete) after the change = %q", got)
+	}
+	if got := i.T("app.shop", "Cart", nil, "de"); got != "Warenkorb" {
+		t.Errorf("T(Cart) of another resource = %q, want it cached", got)
+	}
+
+	h.Sync = func(ctx context.Context, changes []Change) error {
+		return errors.New("pull failed")
+	}
+	if w := serve(http.MethodPost); w.Code != http.StatusInternalServerError {
+		t.Errorf("ServeHTTP() of a failed sync = %d", w.Code)
+	}
+	h.Sync = nil
+
+	// A change of every resource reloads everything.
+	c.changes = []Change{{}}
+	if w := serve(http.MethodPost); w.Code != http.StatusNoContent {
+		t.Fatalf("ServeHTTP() = %d %s", w.Code, w.Body)
+	}
+	if got := i.T("app.shop", "Cart", nil, "de"); got != "Einkaufswagen" {
+		t.Errorf("T(Cart) after the reload = %q", got)
+	}
+
+	// The resources are categories of Prefix.
+	h.Prefix = "web"
+	c.changes = []Change{{Resource: "shop.json", Lang: "de"}}
+	if w := serve(http.MethodPost); w.Code != http.StatusInternalServerError {
+		t.Errorf("ServeHTTP() of a change without source = %d", w.Code)
+	}
+}
@@ -0,0 +1,99 @@
+package lokalise
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// Connector Returns c as an integrations.Connector pushing and pulling with
+// opts, the resources being the web filenames of the keys.
+func (c *Client) Connector(opts Options) integrations.Connector {
+	return connector{c: c, opts: opts}
+}
+
+type connector struct {
+	c    *Client
+	opts Options
+}
+
+func (c connector) ListResources(ctx context.Context) ([]string, error) {
+	keys, err := c.c.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	for _, k := range keys {
+		if k.File != "" {
+			files[k.File] = true
+		}
+	}
+	resources := make([]string, 0, len(files))
+	for file := range files {
+		resources = append(resources, file)
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+func (c connector) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	report, err := c.c.Push(ctx, base, sourceLang, c.opts)
+	if report == nil {
+		return nil, err
+	}
+	return report.Files, err
+}
+
+func (c connector) Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error) {
+	report, err := c.c.Pull(ctx, base, langs, c.opts)
+	if report == nil {
+		return nil, err
+	}
+	return report.Files, err
+}
+
+// Webhook Returns the change of the translation events of r, e.g.
+// project.translation.updated; other events change nothing.
+func (c connector) Webhook(r *http.Request) ([]integrations.Change, error) {
+	secret := r.Header.Get("X-Secret")
+	if c.c.WebhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(c.c.WebhookSecret)) != 1 {
+		return nil, integrations.ErrSignature
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	// Lokalise pings new webhooks with ["ping"].
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return nil, nil
+	}
+	var e struct {
+		Event string `json:"event"`
+		Key   struct {
+			Filenames map[string]string `json:"filenames"`
+		} `json:"key"`
+		Language struct {
+			ISO string `json:"iso"`
+		} `json:"language"`
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	switch e.Event {
+	case "project.translation.updated", "project.translation.proofread", "project.translations.updated":
+		change := integrations.Change{Resource: e.Key.Filenames["web"]}
+		if e.Language.ISO != "" {
+			change.Lang = c.c.lang(e.Language.ISO)
+		}
+		return []integrations.Change{change}, nil
+	case "project.imported":
+		return []integrations.Change{{}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,469 @@
+// Package lokalise syncs ii18n catalogs with a Lokalise project through the
+// Lokalise API v2.
+//
+// A key of the project is a catalog key of the file named by its web
+// filename, the path of the catalog under {base}/{lang}, e.g.
+// "admin/users.json" for the category "app.admin.users":
+//
+//	c := lokalise.New(os.Getenv("LOKALISE_TOKEN"), "3002780358964f9bab5a92.87762498")
+//	c.Push(ctx, "./locales", "en-US", lokalise.Options{})                 // add the new keys
+//	c.Pull(ctx, "./locales", []string{"de", "pt-BR"}, lokalise.Options{}) // fill in the translations
+package lokalise
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/syyongx/ii18n"
+)
+
+// DefaultEndpoint the Lokalise API.
+const DefaultEndpoint = "https://api.lokalise.com/api2"
+
+// pageSize the keys read or created by request, the maximum of the API.
+const pageSize = 500
+
+// Client a Lokalise project.
+type Client struct {
+	Token     string
+	ProjectID string
+	Endpoint  string
+	Client    *http.Client
+	// Languages the Lokalise language ISO codes of ii18n languages, e.g.
+	// {"es-419": "es_MX"}; other languages use their name with "_" for "-".
+	Languages map[string]string
+	// WebhookSecret the secret Lokalise sends in the X-Secret header of the
+	// webhooks of the project.
+	WebhookSecret string
+}
+
+// New returns a Client of the project projectID, authenticated by the API
+// token.
+func New(token string, projectID string) *Client {
+	return &Client{Token: token, ProjectID: projectID, Endpoint: DefaultEndpoint, Client: http.DefaultClient}
+}
+
+// Options of Push and Pull.
+type Options struct {
+	// DryRun reports the changes without making them.
+	DryRun bool
+	// Overwrite replaces the conflicting translations of local catalogs on
+	// Pull instead of reporting them.
+	Overwrite bool
+	// Descriptions of the keys pushed, by catalog key, shown to translators.
+	Descriptions map[string]string
+}
+
+// Key a key of the project.
+type Key struct {
+	ID int
+	// File the path of the catalog of the key, e.g. "admin/users.json".
+	File string
+	Name string
+	// Description for translators.
+	Description string
+	// Placeholders the names of the params of the source message, pushed as
+	// "placeholder:{name}" tags.
+	Placeholders []string
+	// Translations by ii18n language.
+	Translations map[string]string
+}
+
+// Conflict a key whose translation differs between the project and the
+// local catalog.
+type Conflict struct {
+	File   string
+	Key    string
+	Lang   string
+	Local  string
+	Remote string
+}
+
+// Report the changes of a Push or Pull, made unless Options.DryRun.
+type Report struct {
+	// Added the keys added to the project by Push, or the translations added
+	// to local catalogs by Pull, as "{file}: {key}".
+	Added []string
+	// Conflicts left as they are, or overwritten on Pull with
+	// Options.Overwrite.
+	Conflicts []Conflict
+	// Files the files of the keys added by Push, or the local catalogs
+	// changed by Pull.
+	Files []string
+}
+
+// Keys Returns the keys of the project with their translations.
+func (c *Client) Keys(ctx context.Context) ([]Key, error) {
+	isos := make(map[string]string)
+	var keys []Key
+	for page := 1; ; page++ {
+		var res struct {
+			Keys []struct {
+				KeyID        int               `json:"key_id"`
+				KeyName      map[string]string `json:"key_name"`
+				Filenames    map[string]string `json:"filenames"`
+				Description  string            `json:"description"`
+				Tags         []string          `json:"tags"`
+				Translations []struct {
+					LanguageISO string `json:"language_iso"`
+					Translation string `json:"translation"`
+				} `json:"translations"`
+			} `json:"keys"`
+		}
+		query := "/keys?include_translations=1&limit=" + strconv.Itoa(pageSize) + "&page=" + strconv.Itoa(page)
+		if err := c.call(ctx, http.MethodGet, c.project(query), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, k := range res.Keys {
+			key := Key{
+				ID:           k.KeyID,
+				File:         k.Filenames["web"],
+				Name:         k.KeyName["web"],
+				Description:  k.Description,
+				Translations: make(map[string]string, len(k.Translations)),
+			}
+			for _, tag := range k.Tags {
+				if name, ok := strings.CutPrefix(tag, "placeholder:"); ok {
+					key.Placeholders = append(key.Placeholders, name)
+				}
+			}
+			for _, t := range k.Translations {
+				lang, ok := isos[t.LanguageISO]
+				if !ok {
+					lang = c.lang(t.LanguageISO)
+					isos[t.LanguageISO] = lang
+				}
+				key.Translations[lang] = t.Translation
+			}
+			keys = append(keys, key)
+		}
+		if len(res.Keys) < pageSize {
+			return keys, nil
+		}
+	}
+}
+
+// Push Adds the keys of the catalogs of sourceLang under base,
+// {base}/{sourceLang}, that the project does not have, with their source
+// translation, description and placeholders. Keys whose source translation
+// differs from the project's are reported as conflicts and left as they are.
+func (c *Client) Push(ctx context.Context, base string, sourceLang string, opts Options) (*Report, error) {
+	remote, err := c.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]Key, len(remote))
+	for _, k := range remote {
+		byName[k.File+"\x00"+k.Name] = k
+	}
+	catalogs, err := readCatalogs(filepath.Join(base, sourceLang))
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	var added []Key
+	for _, file := range sortedKeys(catalogs) {
+		msgs := catalogs[file]
+		for _, name := range sortedKeys(msgs) {
+			// Catalogs of the source language may leave the message, the
+			// key, untranslated.
+			text := msgs[name]
+			if text == "" {
+				text = name
+			}
+			if k, ok := byName[file+"\x00"+name]; ok {
+				if remote, ok := k.Translations[sourceLang]; ok && remote != text {
+					report.Conflicts = append(report.Conflicts, Conflict{File: file, Key: name, Lang: sourceLang, Local: text, Remote: remote})
+				}
+				continue
+			}
+			placeholders, err := ii18n.Placeholders(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", file, name, err)
+			}
+			added = append(added, Key{
+				File:         file,
+				Name:         name,
+				Description:  opts.Descriptions[name],
+				Placeholders: placeholders,
+				Translations: map[string]string{sourceLang: text},
+			})
+			report.Added = append(report.Added, file+": "+name)
+			if n := len(report.Files); n == 0 || report.Files[n-1] != file {
+				report.Files = append(report.Files, file)
+			}
+		}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+	for len(added) > 0 {
+		n := min(len(added), pageSize)
+		if err := c.create(ctx, added[:n], sourceLang); err != nil {
+			return report, err
+		}
+		added = added[n:]
+	}
+	return report, nil
+}
+
+// create Adds keys to the project with their translation to sourceLang.
+func (c *Client) create(ctx context.Context, keys []Key, sourceLang string) error {
+	type translation struct {
+		LanguageISO string `json:"language_iso"`
+		Translation string `json:"translation"`
+	}
+	type newKey struct {
+		KeyName      string            `json:"key_name"`
+		Description  string            `json:"description,omitempty"`
+		Platforms    []string          `json:"platforms"`
+		Filenames    map[string]string `json:"filenames"`
+		Tags         []string          `json:"tags,omitempty"`
+		Translations []translation     `json:"translations"`
+	}
+	body := struct {
+		Keys []newKey `json:"keys"`
+	}{}
+	for _, k := range keys {
+		nk := newKey{
+			KeyName:      k.Name,
+			Description:  k.Description,
+			Platforms:    []string{"web"},
+			Filenames:    map[string]string{"web": k.File},
+			Translations: []translation{{c.iso(sourceLang), k.Translations[sourceLang]}},
+		}
+		for _, name := range k.Placeholders {
+			nk.Tags = append(nk.Tags, "placeholder:"+name)
+		}
+		body.Keys = append(body.Keys, nk)
+	}
+	return c.call(ctx, http.MethodPost, c.project("/keys"), body, nil)
+}
+
+// Pull Fills in the catalogs of langs under base, {base}/{lang}/{file}, with
+// the translations of the project. Translations that differ from non-empty
+// local ones are reported as conflicts and kept unless Options.Overwrite.
+func (c *Client) Pull(ctx context.Context, base string, langs []string, opts Options) (*Report, error) {
+	keys, err := c.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].File != keys[b].File {
+			return keys[a].File < keys[b].File
+		}
+		return keys[a].Name < keys[b].Name
+	})
+	report := &Report{}
+	for _, lang := range langs {
+		changed := make(map[string]ii18n.TMsgs)
+		for _, k := range keys {
+			remote := k.Translations[lang]
+			if k.File == "" || remote == "" {
+				continue
+			}
+			msgs, ok := changed[k.File]
+			if !ok {
+				// The files come from the API and the languages possibly
+				// from webhooks: neither may lead out of the language
+				// directory.
+				if !filepath.IsLocal(lang) || !filepath.IsLocal(filepath.FromSlash(k.File)) {
+					return report, fmt.Errorf("lokalise: %s %s: path outside of the language directory", lang, k.File)
+				}
+				if msgs, err = readCatalog(filepath.Join(base, lang, filepath.FromSlash(k.File))); err != nil {
+					return report, err
+				}
+			}
+			local := msgs[k.Name]
+			switch {
+			case local == remote:
+				continue
+			case local == "":
+				report.Added = append(report.Added, lang+"/"+k.File+": "+k.Name)
+			default:
+				report.Conflicts = append(report.Conflicts, Conflict{File: k.File, Key: k.Name, Lang: lang, Local: local, Remote: remote})
+				if !opts.Overwrite {
+					continue
+				}
+			}
+			msgs[k.Name] = remote
+			changed[k.File] = msgs
+		}
+		for _, file := range sortedKeys(changed) {
+			name := filepath.Join(base, lang, filepath.FromSlash(file))
+			report.Files = append(report.Files, name)
+			if opts.DryRun {
+				continue
+			}
+			if err := writeCatalog(name, changed[file], lang); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// iso Returns the Lokalise language ISO code of lang.
+func (c *Client) iso(lang string) string {
+	if iso, ok := c.Languages[lang]; ok {
+		return iso
+	}
+	return strings.ReplaceAll(lang, "-", "_")
+}
+
+// lang Returns the ii18n language of the Lokalise language ISO code iso.
+func (c *Client) lang(iso string) string {
+	for lang, id := range c.Languages {
+		if id == iso {
+			return lang
+		}
+	}
+	return strings.ReplaceAll(iso, "_", "-")
+}
+
+// codecOf Returns the codec of the catalog filename by its extension.
+func codecOf(filename string) (ii18n.Codec, error) {
+	codec, ok := ii18n.GetCodec(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown catalog format", filename)
+	}
+	return codec, nil
+}
+
+// readCatalogs Returns the catalogs under dir by slash-separated path
+// relative to it, skipping files of unknown formats.
+func readCatalogs(dir string) (map[string]ii18n.TMsgs, error) {
+	catalogs := make(map[string]ii18n.TMsgs)
+	err := filepath.WalkDir(dir, func(name string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, err := codecOf(name); err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		msgs, err := readCatalog(name)
+		if err != nil {
+			return err
+		}
+		catalogs[filepath.ToSlash(rel)] = msgs
+		return nil
+	})
+	return catalogs, err
+}
+
+// readCatalog Reads the catalog filename, an empty one if it does not exist.
+func readCatalog(filename string) (ii18n.TMsgs, error) {
+	codec, err := codecOf(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return make(ii18n.TMsgs), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data, err = ii18n.DecodeText(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	msgs, err := codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return msgs, nil
+}
+
+// writeCatalog Writes msgs, the catalog of lang, to filename.
+func writeCatalog(filename string, msgs ii18n.TMsgs, lang string) error {
+	codec, err := codecOf(filename)
+	if err != nil {
+		return err
+	}
+	data, err := codec.Encode(msgs, lang)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// call Sends the JSON body, when not nil, to the API and decodes the
+// response into v, when not nil.
+func (c *Client) call(ctx context.Context, method string, url string, body interface{}, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Api-Token", c.Token)
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var res struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&res)
+		if res.Error.Message != "" {
+			return fmt.Errorf("lokalise: %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, res.Error.Message)
+		}
+		return fmt.Errorf("lokalise: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// project Returns the URL of the project resource p.
+func (c *Client) project(p string) string {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/projects/" + c.ProjectID + p
+}
+
+// sortedKeys Returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+package lokalise
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// testProject Returns a Client of a fake Lokalise project with the
+// translations of keys to lang, by web filename and name, and the bodies of
+// the requests creating keys.
+func testProject(t *testing.T, lang string, keys map[string]map[string]string) (*Client, *[]string) {
+	var created []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/projects/p1/keys" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPost {
+			data, _ := io.ReadAll(r.Body)
+			created = append(created, string(data))
+			io.WriteString(w, `{"keys": []}`)
+			return
+		}
+		type translation struct {
+			LanguageISO string `json:"language_iso"`
+			Translation string `json:"translation"`
+		}
+		type key struct {
+			KeyID        int               `json:"key_id"`
+			KeyName      map[string]string `json:"key_name"`
+			Filenames    map[string]string `json:"filenames"`
+			Translations []translation     `json:"translations"`
+		}
+		var res struct {
+			Keys []key `json:"keys"`
+		}
+		for _, file := range sortedKeys(keys) {
+			for _, name := range sortedKeys(keys[file]) {
+				res.Keys = append(res.Keys, key{
+					KeyID:        len(res.Keys) + 1,
+					KeyName:      map[string]string{"web": name},
+					Filenames:    map[string]string{"web": file},
+					Translations: []translation{{lang, keys[file][name]}},
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	c := New("token", "p1")
+	c.Endpoint = srv.URL
+	return c, &created
+}
+
+func TestPullPaths(t *testing.T) {
+	base := t.TempDir()
+	c, _ := testProject(t, "de", map[string]map[string]string{"admin/users.json": {"Delete": "Löschen"}})
+	if _, err := c.Pull(context.Background(), base, []string{"de"}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(base, "de", "admin", "users.json")); err != nil || !strings.Contains(string(data), "Löschen") {
+		t.Errorf("de/admin/users.json = %s, %v", data, err)
+	}
+
+	tests := []struct {
+		file string
+		lang string
+	}{
+		{"../../evil.json", "de"},
+		{"admin/../../../evil.json", "de"},
+		{"/evil.json", "de"},
+		{"shop.json", "../.."},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "locales")
+		c, _ := testProject(t, test.lang, map[string]map[string]string{test.file: {"Cart": "Warenkorb"}})
+		_, err := c.Pull(context.Background(), base, []string{test.lang}, Options{})
+		if err == nil || !strings.Contains(err.Error(), "outside of the language directory") {
+			t.Errorf("Pull(%s, %s) = %v", test.file, test.lang, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "evil.json")); err == nil {
+			t.Errorf("Pull(%s, %s) wrote outside of %s", test.file, test.lang, base)
+		}
+	}
+}
+
+func TestConnector(t *testing.T) {
+	client, created := testProject(t, "pt_BR", map[string]map[string]string{
+		"admin/users.json": {"Delete": "Excluir"},
+		"shop.json":        {"Cart": "Carrinho"},
+	})
+	client.WebhookSecret = "s3cr3t"
+	c := client.Connector(Options{})
+	ctx := context.Background()
+
+	resources, err := c.ListResources(ctx)
+	if err != nil || !reflect.DeepEqual(resources, []string{"admin/users.json", "shop.json"}) {
+		t.Errorf("ListResources() = %v, %v", resources, err)
+	}
+
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "en-US"), 0755)
+	os.WriteFile(filepath.Join(base, "en-US", "shop.json"), []byte(`{"Cart": "Cart", "Hi {name}": ""}`), 0644)
+	pushed, err := c.Push(ctx, base, "en-US")
+	if err != nil || !reflect.DeepEqual(pushed, []string{"shop.json"}) {
+		t.Errorf("Push() = %v, %v", pushed, err)
+	}
+	// Only the key the project does not have is created.
+	expected := `{"keys":[{"key_name":"Hi {name}","platforms":["web"],"filenames":{"web":"shop.json"},"tags":["placeholder:name"],"translations":[{"language_iso":"en_US","translation":"Hi {name}"}]}]}`
+	if len(*created) != 1 || (*created)[0] != expected {
+		t.Errorf("Push() created %q, want %q", *created, expected)
+	}
+
+	written, err := c.Pull(ctx, base, "en-US", []string{"pt-BR"})
+	if err != nil || !reflect.DeepEqual(written, []string{filepath.Join(base, "pt-BR", "admin", "users.json"), filepath.Join(base, "pt-BR", "shop.json")}) {
+		t.Errorf("Pull() = %v, %v", written, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(base, "pt-BR", "shop.json")); !strings.Contains(string(data), "Carrinho") {
+		t.Errorf("pt-BR/shop.json = %s", data)
+	}
+
+	tests := []struct {
+		body     string
+		expected []integrations.Change
+	}{
+		{`{"event": "project.translation.updated", "key": {"filenames": {"web": "shop.json"}}, "language": {"iso": "pt_BR"}}`, []integrations.Change{{Resource: "shop.json", Lang: "pt-BR"}}},
+		{`{"event": "project.imported"}`, []integrations.Change{{}}},
+		{`{"event": "project.key.added"}`, nil},
+		{`["ping"]`, nil},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/hooks/lokalise", strings.NewReader(test.body))
+		req.Header.Set("X-Secret", "s3cr3t")
+		changes, err := c.Webhook(req)
+		if err != nil || !reflect.DeepEqual(changes, test.expected) {
+			t.Errorf("Webhook(%s) = %v, %v, want %v", test.body, changes, err, test.expected)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/hooks/lokalise", strings.NewReader(tests[0].body))
+	req.Header.Set("X-Secret", "other")
+	if _, err := c.Webhook(req); err != integrations.ErrSignature {
+		t.Errorf("Webhook() with another secret = %v, want ErrSignature", err)
+	}
+}
@@ -0,0 +1,55 @@
+package phrase
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for the releases of languages not fetched yet
+// while a Breaker holds the requests back.
+var ErrCircuitOpen = errors.New("phrase: circuit open")
+
+// Breaker a circuit breaker of the API: after Failures failed requests in a
+// row the circuit opens, no request is made for Cooldown and the releases at
+// hand are served, then a single request tells whether the API is back.
+type Breaker struct {
+	// Failures the failed requests in a row that open the circuit, 5 when
+	// zero.
+	Failures int
+	// Cooldown the time the circuit stays open, 30 seconds when zero.
+	Cooldown time.Duration
+
+	failures  int
+	openUntil time.Time
+	mutex     sync.Mutex
+}
+
+// Open Reports whether the circuit is open, holding requests back.
+func (b *Breaker) Open() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return time.Now().Before(b.openUntil)
+}
+
+// record Records the result of a request, opening the circuit after too many
+// failures. A failure after the cooldown opens it again at once.
+func (b *Breaker) record(failed bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if !failed {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	threshold, cooldown := b.Failures, b.Cooldown
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	if b.failures++; b.failures >= threshold {
+		b.openUntil = time.Now().Add(cooldown)
+	}
+}
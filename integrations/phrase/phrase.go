@@ -0,0 +1,345 @@
+// Package phrase serves ii18n catalogs from the over-the-air releases of a
+// Phrase Strings distribution, so copy edits ship without deploys.
+//
+// A release holds every key of a language, served as the catalog of every
+// category of the source's prefix. The releases are checked again on Reload,
+// e.g. on the schedule of WithAutoReload, retried as Retry says and kept in
+// CacheDir for starts without network. While the API fails, the releases at
+// hand are served and Staleness tells for how long:
+//
+//	ota := phrase.New("5f2b9c1d0e", os.Getenv("PHRASE_OTA_SECRET"))
+//	ota.CacheDir = "/var/cache/app/phrase"
+//	ota.Retry = &phrase.Retry{Attempts: 3, Jitter: 0.5}
+//	ota.Breaker = &phrase.Breaker{Failures: 5, Cooldown: time.Minute}
+//	i := ii18n.NewI18N(map[string]ii18n.Config{
+//		"web": {SourceNewFunc: ota.Source(), OriginalLang: "en-US", BasePath: "phrase", FileMap: map[string]string{}},
+//	}, ii18n.WithAutoReload(5*time.Minute))
+package phrase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syyongx/ii18n"
+)
+
+// Endpoints of the OTA API by data center.
+const (
+	DefaultEndpoint = "https://ota.phrase.com"
+	USEndpoint      = "https://ota.us.phrase.com"
+)
+
+// OTA the releases of a distribution.
+type OTA struct {
+	DistributionID string
+	// Secret the secret of the environment, development or production.
+	Secret   string
+	Endpoint string
+	// Format the file format of the releases, flat JSON, "simple_json" when
+	// empty.
+	Format string
+	// AppVersion the version of the application, which selects the releases
+	// made for it.
+	AppVersion string
+	// Locales the Phrase locale codes of ii18n languages that differ.
+	Locales map[string]string
+	// CacheDir keeps the last release of each language, {lang}.json, served
+	// when the API cannot be reached. None when empty.
+	CacheDir string
+	// CheckInterval the time a release is served without asking the API
+	// again, so the categories of a language share one request per Reload,
+	// 10 seconds when zero.
+	CheckInterval time.Duration
+	// Client the HTTP client of the requests, whose Transport may set up
+	// client certificates, e.g. an http.Transport with a TLSClientConfig, or
+	// a proxy.
+	Client *http.Client
+	// Authorize adds credentials to every request, e.g. those of an auth
+	// proxy in front of the API, see BearerToken. A request it fails is not
+	// sent.
+	Authorize func(*http.Request) error
+	// Retry retries the requests that fail with a network error or a
+	// retryable status before the release at hand is served, none when nil.
+	Retry *Retry
+	// Breaker stops the requests while the API keeps failing, the releases
+	// at hand served meanwhile, none when nil.
+	Breaker *Breaker
+	// Timeout bounds the loads of Source, retries included, 30 seconds when
+	// zero.
+	Timeout time.Duration
+
+	releases map[string]*release
+	// fetching the fetches in progress by language, which the other loads
+	// of the language wait for.
+	fetching map[string]*fetching
+	mutex    sync.Mutex
+}
+
+// fetching a fetch of the latest release of a language in progress.
+type fetching struct {
+	done chan struct{}
+	msgs ii18n.TMsgs
+	err  error
+}
+
+// release the release of a language.
+type release struct {
+	Version  string      `json:"version"`
+	Updated  int64       `json:"updated"`
+	Messages ii18n.TMsgs `json:"messages"`
+	checked  time.Time
+	// confirmed the time the release was last known to be the latest.
+	confirmed time.Time
+}
+
+// New returns the OTA of the distribution in the environment of secret.
+func New(distributionID string, secret string) *OTA {
+	return &OTA{DistributionID: distributionID, Secret: secret, Endpoint: DefaultEndpoint, Client: http.DefaultClient}
+}
+
+// Source Returns the SourceNewFunc of a source serving the releases.
+func (o *OTA) Source() func(*ii18n.Config) ii18n.Source {
+	return ii18n.NewFuncSource(func(lang string, name string) (ii18n.TMsgs, error) {
+		timeout := o.Timeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return o.Messages(ctx, lang)
+	})
+}
+
+// Messages Returns the messages of the latest release in lang, or of the
+// release last fetched or cached when the API cannot be reached. The
+// concurrent calls of a language share a request.
+func (o *OTA) Messages(ctx context.Context, lang string) (ii18n.TMsgs, error) {
+	if o.CacheDir != "" && !filepath.IsLocal(lang) {
+		return nil, fmt.Errorf("phrase: %s: path outside of the cache directory", lang)
+	}
+	o.mutex.Lock()
+	if o.releases == nil {
+		o.releases = make(map[string]*release)
+		o.fetching = make(map[string]*fetching)
+	}
+	r, ok := o.releases[lang]
+	if !ok {
+		r = o.readCache(lang)
+		o.releases[lang] = r
+	}
+	interval := o.CheckInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if r != nil && time.Since(r.checked) < interval {
+		o.mutex.Unlock()
+		return copyMsgs(r.Messages), nil
+	}
+	f, pending := o.fetching[lang]
+	if !pending {
+		f = &fetching{done: make(chan struct{})}
+		o.fetching[lang] = f
+	}
+	o.mutex.Unlock()
+
+	if !pending {
+		f.msgs, f.err = o.update(ctx, lang, r)
+		o.mutex.Lock()
+		delete(o.fetching, lang)
+		o.mutex.Unlock()
+		close(f.done)
+	}
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return copyMsgs(f.msgs), nil
+}
+
+// update Fetches the latest release in lang, current being the release at
+// hand, and returns its messages, or those of current when the API cannot
+// be reached. The lock is only held to update the releases.
+func (o *OTA) update(ctx context.Context, lang string, current *release) (ii18n.TMsgs, error) {
+	err := ErrCircuitOpen
+	var latest *release
+	if o.Breaker == nil || !o.Breaker.Open() {
+		latest, err = o.fetch(ctx, lang, current)
+		if o.Breaker != nil && ctx.Err() == nil {
+			// A language without release is an answer of the API.
+			o.Breaker.record(err != nil && !errors.Is(err, fs.ErrNotExist))
+		}
+	}
+	o.mutex.Lock()
+	if err != nil {
+		defer o.mutex.Unlock()
+		if current == nil || errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		// Serve the release at hand until the API is back.
+		current.checked = time.Now()
+		return current.Messages, nil
+	}
+	latest.checked = time.Now()
+	latest.confirmed = latest.checked
+	o.releases[lang] = latest
+	o.mutex.Unlock()
+	if latest != current {
+		o.writeCache(lang, latest)
+	}
+	return latest.Messages, nil
+}
+
+// BearerToken Returns an OTA.Authorize setting the Authorization header of
+// requests to the bearer token returned by token, e.g. a cached OAuth2
+// access token.
+func BearerToken(token func(ctx context.Context) (string, error)) func(*http.Request) error {
+	return func(req *http.Request) error {
+		t, err := token(req.Context())
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+t)
+		return nil
+	}
+}
+
+// Staleness Returns the time since the release served in lang was last known
+// to be the latest, e.g. for a metric of how long the API has been
+// unreachable, false when no release in lang was served.
+func (o *OTA) Staleness(lang string) (time.Duration, bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	r := o.releases[lang]
+	if r == nil {
+		return 0, false
+	}
+	return time.Since(r.confirmed), true
+}
+
+// fetch Returns the latest release in lang, current when it is still the
+// latest.
+func (o *OTA) fetch(ctx context.Context, lang string, current *release) (*release, error) {
+	locale := lang
+	if code, ok := o.Locales[lang]; ok {
+		locale = code
+	}
+	format := o.Format
+	if format == "" {
+		format = "simple_json"
+	}
+	endpoint := o.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	query := url.Values{}
+	if o.AppVersion != "" {
+		query.Set("app_version", o.AppVersion)
+	}
+	if current != nil {
+		query.Set("current_version", current.Version)
+		query.Set("last_update", strconv.FormatInt(current.Updated, 10))
+	}
+	u := strings.TrimSuffix(endpoint, "/") + "/" + url.PathEscape(o.DistributionID) + "/" + url.PathEscape(o.Secret) + "/" + url.PathEscape(locale) + "/" + url.PathEscape(format)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	// The API redirects to the file of the latest release, its version in
+	// the query of the redirect.
+	resp, err := o.do(ctx, client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err == nil && o.Authorize != nil {
+			if err = o.Authorize(req); err != nil {
+				err = fmt.Errorf("phrase: authorize: %w", err)
+			}
+		}
+		return req, err
+	})
+	if err != nil {
+		// The URL holds the secret.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("phrase: GET %s: %w", locale, urlErr.Err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && current != nil:
+		return current, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("phrase: no release in %s: %w", locale, fs.ErrNotExist)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("phrase: GET %s: %s", locale, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	codec, _ := ii18n.GetCodec("json")
+	msgs, err := codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("phrase: release in %s: %w", locale, err)
+	}
+	return &release{Version: resp.Request.URL.Query().Get("version"), Updated: time.Now().Unix(), Messages: msgs}, nil
+}
+
+// readCache Returns the cached release in lang, nil when there is none.
+func (o *OTA) readCache(lang string) *release {
+	if o.CacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(o.CacheDir, lang+".json"))
+	if err != nil {
+		return nil
+	}
+	var r release
+	if json.Unmarshal(data, &r) != nil || r.Messages == nil {
+		return nil
+	}
+	r.confirmed = time.Unix(r.Updated, 0)
+	return &r
+}
+
+// writeCache Caches r, the release in lang. The cache is best effort.
+func (o *OTA) writeCache(lang string, r *release) {
+	if o.CacheDir == "" {
+		return
+	}
+	data, err := json.Marshal(r)
+	if err != nil || os.MkdirAll(o.CacheDir, 0755) != nil {
+		return
+	}
+	name := filepath.Join(o.CacheDir, lang+".json")
+	if os.WriteFile(name+".tmp", data, 0644) == nil {
+		os.Rename(name+".tmp", name)
+	}
+}
+
+// copyMsgs Returns a copy of msgs, which the source may normalize.
+func copyMsgs(msgs ii18n.TMsgs) ii18n.TMsgs {
+	copied := make(ii18n.TMsgs, len(msgs))
+	for key, val := range msgs {
+		copied[key] = val
+	}
+	return copied
+}
@@ -0,0 +1,235 @@
+package phrase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testServer Returns an OTA of a server answering the requests after the
+// first failures with status, and the number of requests it got.
+func testServer(t *testing.T, failures int32, status int) (*OTA, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		io.WriteString(w, `{"Cart": "Warenkorb"}`)
+	}))
+	t.Cleanup(srv.Close)
+	ota := New("dist", "secret")
+	ota.Endpoint = srv.URL
+	return ota, &requests
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		failures int32
+		status   int
+		retry    *Retry
+		ok       bool
+		requests int32
+	}{
+		{2, http.StatusServiceUnavailable, &Retry{Attempts: 3, Backoff: time.Millisecond}, true, 3},
+		{3, http.StatusServiceUnavailable, &Retry{Attempts: 3, Backoff: time.Millisecond}, false, 3},
+		{2, http.StatusServiceUnavailable, nil, false, 1},
+		{1, http.StatusNotFound, &Retry{Attempts: 3, Backoff: time.Millisecond}, false, 1},
+		{1, http.StatusNotFound, &Retry{Attempts: 3, Backoff: time.Millisecond, Statuses: []int{http.StatusNotFound}}, true, 2},
+	}
+	for _, test := range tests {
+		ota, requests := testServer(t, test.failures, test.status)
+		ota.Retry = test.retry
+		msgs, err := ota.Messages(context.Background(), "de")
+		if ok := err == nil && msgs["Cart"] == "Warenkorb"; ok != test.ok || requests.Load() != test.requests {
+			t.Errorf("%d × %d, %+v: Messages() = %v, %v after %d requests", test.failures, test.status, test.retry, msgs, err, requests.Load())
+		}
+	}
+
+	ota, _ := testServer(t, 1, http.StatusServiceUnavailable)
+	ota.Retry = &Retry{Attempts: 3, Backoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := ota.Messages(ctx, "de"); err != context.DeadlineExceeded {
+		t.Errorf("Messages() = %v, want the context error while waiting", err)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	retryAfter := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+	tests := []struct {
+		retry    Retry
+		attempt  int
+		resp     *http.Response
+		expected time.Duration
+	}{
+		{Retry{}, 1, nil, 200 * time.Millisecond},
+		{Retry{Backoff: time.Second}, 3, nil, 4 * time.Second},
+		{Retry{Backoff: time.Second, MaxBackoff: 3 * time.Second}, 5, nil, 3 * time.Second},
+		{Retry{Backoff: time.Second}, 1, retryAfter, 2 * time.Second},
+		{Retry{Backoff: time.Second, MaxBackoff: time.Second}, 1, retryAfter, time.Second},
+	}
+	for _, test := range tests {
+		if actual := test.retry.wait(test.attempt, test.resp); actual != test.expected {
+			t.Errorf("%+v: wait(%d) = %v, want %v", test.retry, test.attempt, actual, test.expected)
+		}
+	}
+	jittered := Retry{Backoff: time.Second, Jitter: 0.5}
+	for n := 0; n < 100; n++ {
+		if actual := jittered.wait(1, nil); actual < 500*time.Millisecond || actual > time.Second {
+			t.Fatalf("wait() with jitter = %v", actual)
+		}
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	var down atomic.Bool
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		io.WriteString(w, `{"Cart": "Warenkorb"}`)
+	}))
+	defer srv.Close()
+	ota := New("dist", "secret")
+	ota.Endpoint = srv.URL
+	ota.CheckInterval = time.Nanosecond
+	ota.Breaker = &Breaker{Failures: 2, Cooldown: time.Hour}
+	ctx := context.Background()
+	if _, ok := ota.Staleness("de"); ok {
+		t.Error("Staleness() of no release = true")
+	}
+	if _, err := ota.Messages(ctx, "de"); err != nil {
+		t.Fatal(err)
+	}
+
+	down.Store(true)
+	for n := 0; n < 5; n++ {
+		if msgs, err := ota.Messages(ctx, "de"); err != nil || msgs["Cart"] != "Warenkorb" {
+			t.Fatalf("Messages() while down = %v, %v, want the release at hand", msgs, err)
+		}
+	}
+	if requests.Load() != 3 || !ota.Breaker.Open() {
+		t.Errorf("%d requests, circuit open %t, want 3 requests and an open circuit", requests.Load(), ota.Breaker.Open())
+	}
+	if _, err := ota.Messages(ctx, "fr"); err != ErrCircuitOpen {
+		t.Errorf("Messages(fr) = %v, want ErrCircuitOpen", err)
+	}
+	time.Sleep(time.Millisecond)
+	if staleness, ok := ota.Staleness("de"); !ok || staleness < time.Millisecond {
+		t.Errorf("Staleness() = %v, %t", staleness, ok)
+	}
+
+	// After the cooldown a request tells whether the API is back.
+	ota.Breaker.openUntil = time.Time{}
+	down.Store(false)
+	if _, err := ota.Messages(ctx, "fr"); err != nil || ota.Breaker.Open() || requests.Load() != 4 {
+		t.Errorf("Messages() after the cooldown = %v, circuit open %t after %d requests", err, ota.Breaker.Open(), requests.Load())
+	}
+	if staleness, _ := ota.Staleness("fr"); staleness >= time.Second {
+		t.Errorf("Staleness() = %v after a fetch", staleness)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"Cart": "Warenkorb"}`)
+	}))
+	defer srv.Close()
+	ota := New("dist", "secret")
+	ota.Endpoint = srv.URL
+	if _, err := ota.Messages(context.Background(), "de"); err == nil {
+		t.Error("Messages() without token = nil error")
+	}
+	var tokens atomic.Int32
+	ota.Authorize = BearerToken(func(ctx context.Context) (string, error) {
+		if tokens.Add(1) > 1 {
+			return "", errors.New("token expired")
+		}
+		return "t0k3n", nil
+	})
+	if msgs, err := ota.Messages(context.Background(), "de"); err != nil || msgs["Cart"] != "Warenkorb" {
+		t.Errorf("Messages() with token = %v, %v", msgs, err)
+	}
+	if _, err := ota.Messages(context.Background(), "fr"); err == nil || !strings.Contains(err.Error(), "token expired") {
+		t.Errorf("Messages() with a failing token = %v", err)
+	}
+}
+
+func TestConcurrentMessages(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/de/") {
+			requests.Add(1)
+			<-release
+		}
+		io.WriteString(w, `{"Cart": "Warenkorb"}`)
+	}))
+	defer srv.Close()
+	ota := New("dist", "secret")
+	ota.Endpoint = srv.URL
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for n := 0; n < 5; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if msgs, err := ota.Messages(ctx, "de"); err != nil || msgs["Cart"] != "Warenkorb" {
+				t.Errorf("Messages(de) = %v, %v", msgs, err)
+			}
+		}()
+	}
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// A hung request holds back neither the other languages nor Staleness.
+	if _, err := ota.Messages(ctx, "fr"); err != nil {
+		t.Errorf("Messages(fr) while de is fetched = %v", err)
+	}
+	if _, ok := ota.Staleness("fr"); !ok {
+		t.Error("Staleness(fr) = false")
+	}
+	waiting, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := ota.Messages(waiting, "de"); err != context.DeadlineExceeded {
+		t.Errorf("Messages(de) while de is fetched = %v, want the context error", err)
+	}
+	close(release)
+	wg.Wait()
+	if requests.Load() != 1 {
+		t.Errorf("%d requests of de, want 1 shared", requests.Load())
+	}
+}
+
+func TestMessagesErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	ota := New("dist", "s3cr3t")
+	ota.Endpoint = srv.URL
+	if _, err := ota.Messages(context.Background(), "de"); err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("Messages() of an unreachable API = %v, want an error without the secret", err)
+	}
+
+	ota.CacheDir = t.TempDir()
+	for _, lang := range []string{"../de", "/tmp/de", ""} {
+		if _, err := ota.Messages(context.Background(), lang); err == nil || !strings.Contains(err.Error(), "outside of the cache directory") {
+			t.Errorf("Messages(%q) = %v", lang, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package phrase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Retry the retries of the requests that fail with a network error or a
+// retryable status, waiting a backoff doubled at each attempt.
+type Retry struct {
+	// Attempts the requests made at most, including the first.
+	Attempts int
+	// Backoff the wait before the second attempt, 200 milliseconds when
+	// zero.
+	Backoff time.Duration
+	// MaxBackoff caps the wait and the Retry-After of the API, 10 seconds
+	// when zero.
+	MaxBackoff time.Duration
+	// Jitter the fraction of each wait drawn at random, from 0 to 1, so
+	// that instances do not retry in step.
+	Jitter float64
+	// Statuses the retryable statuses, 429, 500, 502, 503 and 504 when nil.
+	Statuses []int
+}
+
+// defaultStatuses the retryable statuses of a Retry without Statuses.
+var defaultStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retryable Reports whether the request that returned resp and err is
+// retried.
+func (r *Retry) retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	statuses := r.Statuses
+	if statuses == nil {
+		statuses = defaultStatuses
+	}
+	return slices.Contains(statuses, resp.StatusCode)
+}
+
+// wait Returns the wait after the attempt that returned resp, its
+// Retry-After in seconds when longer than the backoff.
+func (r *Retry) wait(attempt int, resp *http.Response) time.Duration {
+	backoff, limit := r.Backoff, r.MaxBackoff
+	if backoff <= 0 {
+		backoff = 200 * time.Millisecond
+	}
+	if limit <= 0 {
+		limit = 10 * time.Second
+	}
+	for n := 1; n < attempt && backoff < limit; n++ {
+		backoff *= 2
+	}
+	if jitter := min(max(r.Jitter, 0), 1); jitter > 0 {
+		backoff -= time.Duration(jitter * rand.Float64() * float64(backoff))
+	}
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(secs)*time.Second > backoff {
+			backoff = time.Duration(secs) * time.Second
+		}
+	}
+	return min(backoff, limit)
+}
+
+// do Sends the request of newRequest, again as o.Retry says while it fails.
+func (o *OTA) do(ctx context.Context, client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if o.Retry == nil || attempt >= o.Retry.Attempts || !o.Retry.retryable(resp, err) {
+			return resp, err
+		}
+		wait := o.Retry.wait(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
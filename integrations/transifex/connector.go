@@ -0,0 +1,128 @@
+package transifex
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// Connector Returns c as an integrations.Connector pulling with opts, the
+// resources being the JSON catalogs of the categories.
+func (c *Client) Connector(opts Options) integrations.Connector {
+	return connector{c: c, opts: opts}
+}
+
+type connector struct {
+	c    *Client
+	opts Options
+}
+
+func (c connector) ListResources(ctx context.Context) ([]string, error) {
+	var resources []string
+	p := "/resources?" + url.Values{"filter[project]": {c.c.projectID()}}.Encode()
+	for p != "" {
+		var res struct {
+			Data []struct {
+				Attributes struct {
+					Slug string `json:"slug"`
+				} `json:"attributes"`
+			} `json:"data"`
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
+		}
+		if err := c.c.call(ctx, http.MethodGet, p, nil, &res); err != nil {
+			return nil, err
+		}
+		for _, r := range res.Data {
+			resources = append(resources, c.c.file(r.Attributes.Slug))
+		}
+		p = ""
+		if next, err := url.Parse(res.Links.Next); err == nil && res.Links.Next != "" {
+			p = next.RequestURI()
+		}
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+func (c connector) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	pushed, err := c.c.Push(ctx, base, sourceLang)
+	for n, category := range pushed {
+		pushed[n] = c.c.file(c.c.Resource(category))
+	}
+	return pushed, err
+}
+
+func (c connector) Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error) {
+	return c.c.Pull(ctx, base, sourceLang, langs, c.opts)
+}
+
+// Webhook Returns the change of the resource and language of r, e.g. on
+// translation_completed or review_completed.
+func (c connector) Webhook(r *http.Request) ([]integrations.Change, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	// The signature covers the method, the URL of the webhook, the date and
+	// the MD5 of the body.
+	sum := md5.Sum(data)
+	message := r.Method + "\n" + r.Header.Get("X-TX-Url") + "\n" + r.Header.Get("Date") + "\n" + hex.EncodeToString(sum[:])
+	mac := hmac.New(sha256.New, []byte(c.c.WebhookSecret))
+	mac.Write([]byte(message))
+	want, err := base64.StdEncoding.DecodeString(r.Header.Get("X-TX-Signature-V2"))
+	if c.c.WebhookSecret == "" || err != nil || !hmac.Equal(want, mac.Sum(nil)) {
+		return nil, integrations.ErrSignature
+	}
+	var e struct {
+		Event    string `json:"event"`
+		Resource string `json:"resource"`
+		Language string `json:"language"`
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if e.Resource == "" {
+		return nil, nil
+	}
+	return []integrations.Change{{Resource: c.c.file(e.Resource), Lang: c.c.lang(e.Language)}}, nil
+}
+
+// file Returns the catalog file of the resource slug, the category of the
+// slug in Resources or else of the slug with "." for "-".
+func (c *Client) file(slug string) string {
+	category := ""
+	for cat, s := range c.Resources {
+		if s == slug {
+			category = cat
+			break
+		}
+	}
+	if category == "" {
+		category = "." + strings.ReplaceAll(slug, "-", ".")
+	}
+	_, name, _ := strings.Cut(category, ".")
+	return strings.ReplaceAll(name, ".", "/") + ".json"
+}
+
+// lang Returns the ii18n language of the Transifex language code.
+func (c *Client) lang(code string) string {
+	for lang, l := range c.Languages {
+		if l == code {
+			return lang
+		}
+	}
+	return strings.ReplaceAll(code, "_", "-")
+}
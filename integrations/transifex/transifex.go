@@ -0,0 +1,438 @@
+// Package transifex syncs the JSON catalogs of ii18n with a Transifex
+// project through the Transifex API v3, a resource of the project per
+// category.
+//
+// The category "app.admin.users", the catalog {base}/{lang}/admin/users.json,
+// is the resource admin-users unless Client.Resources maps it:
+//
+//	c := transifex.New(os.Getenv("TX_TOKEN"), "acme", "web")
+//	c.Push(ctx, "./locales", "en-US") // after ii18n extract
+//	c.Pull(ctx, "./locales", "en-US", []string{"de"}, transifex.Options{Reviewed: true}) // before the build
+package transifex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syyongx/ii18n"
+)
+
+// DefaultEndpoint the Transifex API.
+const DefaultEndpoint = "https://rest.api.transifex.com"
+
+// Client a Transifex project.
+type Client struct {
+	Token        string
+	Organization string
+	Project      string
+	Endpoint     string
+	Client       *http.Client
+	// Prefix the category prefix of the catalogs, "app" when empty.
+	Prefix string
+	// Resources the resource slugs of categories, e.g.
+	// {"app.admin.users": "users"}.
+	Resources map[string]string
+	// Languages the Transifex language codes of ii18n languages, e.g.
+	// {"es-419": "es_419"}; other languages use their name with "_" for "-".
+	Languages map[string]string
+	// PollInterval the interval of the polls of uploads and downloads, a
+	// second when zero.
+	PollInterval time.Duration
+	// WebhookSecret the secret of the webhooks of the project, which sign
+	// them with X-TX-Signature-V2.
+	WebhookSecret string
+}
+
+// New returns a Client of the project of the organization, both by slug,
+// authenticated by the API token.
+func New(token string, organization string, project string) *Client {
+	return &Client{Token: token, Organization: organization, Project: project, Endpoint: DefaultEndpoint, Client: http.DefaultClient}
+}
+
+// Options of Pull.
+type Options struct {
+	// Reviewed pulls only the reviewed translations, leaving the others as
+	// they are in the local catalogs.
+	Reviewed bool
+}
+
+// Category Returns the category of the catalog file, a path relative to
+// the language directory such as "admin/users.json".
+func (c *Client) Category(file string) string {
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = "app"
+	}
+	name := strings.TrimSuffix(filepath.ToSlash(file), filepath.Ext(file))
+	return prefix + "." + strings.ReplaceAll(name, "/", ".")
+}
+
+// Resource Returns the resource slug of category.
+func (c *Client) Resource(category string) string {
+	if slug, ok := c.Resources[category]; ok {
+		return slug
+	}
+	_, name, _ := strings.Cut(category, ".")
+	return strings.ReplaceAll(name, ".", "-")
+}
+
+// Push Uploads the source catalogs under base, {base}/{sourceLang}, to their
+// resources, creating the missing ones, and returns the categories
+// uploaded. Transifex adds the new strings, updates the changed ones and
+// removes the others.
+func (c *Client) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	root := filepath.Join(base, sourceLang)
+	files, err := catalogFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	var pushed []string
+	for _, file := range files {
+		category := c.Category(file)
+		data, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil {
+			return pushed, err
+		}
+		if data, err = ii18n.DecodeText(data); err != nil {
+			return pushed, fmt.Errorf("%s: %w", file, err)
+		}
+		if err := c.upload(ctx, category, data); err != nil {
+			return pushed, fmt.Errorf("%s: %w", category, err)
+		}
+		pushed = append(pushed, category)
+	}
+	return pushed, nil
+}
+
+// upload Replaces the source strings of the resource of category with the
+// catalog data.
+func (c *Client) upload(ctx context.Context, category string, data []byte) error {
+	resource := c.resourceID(category)
+	err := c.call(ctx, http.MethodGet, "/resources/"+resource, nil, nil)
+	if errors.Is(err, errNotFound) {
+		err = c.call(ctx, http.MethodPost, "/resources", object("resources", map[string]interface{}{
+			"name": category,
+			"slug": c.Resource(category),
+		}, map[string]interface{}{
+			"i18n_format": ref("i18n_formats", "KEYVALUEJSON"),
+			"project":     ref("projects", c.projectID()),
+		}), nil)
+	}
+	if err != nil {
+		return err
+	}
+	var res document
+	err = c.call(ctx, http.MethodPost, "/resource_strings_async_uploads", object("resource_strings_async_uploads", map[string]interface{}{
+		"content":          string(data),
+		"content_encoding": "text",
+	}, map[string]interface{}{
+		"resource": ref("resources", resource),
+	}), &res)
+	if err != nil {
+		return err
+	}
+	_, err = c.await(ctx, "/resource_strings_async_uploads/"+res.Data.ID)
+	return err
+}
+
+// Pull Downloads the translations of the resources of the source catalogs
+// under base, {base}/{sourceLang}, in langs and merges them into the
+// catalogs {base}/{lang}/{file}, returning the files changed. Strings
+// without a translation, or without a reviewed one with Options.Reviewed,
+// keep their local translation.
+func (c *Client) Pull(ctx context.Context, base string, sourceLang string, langs []string, opts Options) ([]string, error) {
+	files, err := catalogFiles(filepath.Join(base, sourceLang))
+	if err != nil {
+		return nil, err
+	}
+	mode := "onlytranslated"
+	if opts.Reviewed {
+		mode = "onlyreviewed"
+	}
+	codec, _ := ii18n.GetCodec("json")
+	var changed []string
+	for _, lang := range langs {
+		for _, file := range files {
+			// The languages come possibly from webhooks: they may not lead
+			// out of the base directory.
+			if !filepath.IsLocal(lang) || !filepath.IsLocal(file) {
+				return changed, fmt.Errorf("transifex: %s %s: path outside of the language directory", lang, file)
+			}
+			category := c.Category(file)
+			data, err := c.download(ctx, category, lang, mode)
+			if err != nil {
+				return changed, fmt.Errorf("%s %s: %w", lang, category, err)
+			}
+			remote, err := codec.Decode(data)
+			if err != nil {
+				return changed, fmt.Errorf("%s %s: %w", lang, category, err)
+			}
+			name := filepath.Join(base, lang, file)
+			local, err := readCatalog(name)
+			if err != nil {
+				return changed, err
+			}
+			n := 0
+			for key, val := range remote {
+				if val != "" && local[key] != val {
+					local[key] = val
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			if data, err = codec.Encode(local, lang); err != nil {
+				return changed, err
+			}
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return changed, err
+			}
+			if err := os.WriteFile(name, data, 0644); err != nil {
+				return changed, err
+			}
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+// download Returns the translations of the resource of category to lang in
+// the download mode.
+func (c *Client) download(ctx context.Context, category string, lang string, mode string) ([]byte, error) {
+	var res document
+	err := c.call(ctx, http.MethodPost, "/resource_translations_async_downloads", object("resource_translations_async_downloads", map[string]interface{}{
+		"mode":             mode,
+		"content_encoding": "text",
+		"file_type":        "default",
+	}, map[string]interface{}{
+		"resource": ref("resources", c.resourceID(category)),
+		"language": ref("languages", "l:"+c.language(lang)),
+	}), &res)
+	if err != nil {
+		return nil, err
+	}
+	return c.await(ctx, "/resource_translations_async_downloads/"+res.Data.ID)
+}
+
+// await Polls the asynchronous job p until it is done and returns the file
+// it redirects to, if any.
+func (c *Client) await(ctx context.Context, p string) ([]byte, error) {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		req, err := c.request(ctx, http.MethodGet, p, nil)
+		if err != nil {
+			return nil, err
+		}
+		// The client follows the redirect of a finished download to the
+		// file, which is not JSON:API.
+		resp, err := c.client().Do(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := responseError(req, resp, data); err != nil {
+			return nil, err
+		}
+		if !strings.Contains(resp.Header.Get("Content-Type"), "application/vnd.api+json") {
+			return data, nil
+		}
+		var res document
+		if err := json.Unmarshal(data, &res); err != nil {
+			return nil, err
+		}
+		switch res.Data.Attributes.Status {
+		case "succeeded":
+			return nil, nil
+		case "failed":
+			var details []string
+			for _, e := range res.Data.Attributes.Errors {
+				details = append(details, e.Detail)
+			}
+			return nil, errors.New("transifex: " + strings.Join(details, "; "))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// document a JSON:API response of the jobs.
+type document struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Status string `json:"status"`
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// object Returns the JSON:API document of a resource object.
+func object(typ string, attributes map[string]interface{}, relationships map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"data": map[string]interface{}{"type": typ, "attributes": attributes, "relationships": relationships}}
+}
+
+// ref Returns the JSON:API relationship to the object id of typ.
+func ref(typ string, id string) map[string]interface{} {
+	return map[string]interface{}{"data": map[string]string{"type": typ, "id": id}}
+}
+
+// projectID Returns the ID of the project, "o:{organization}:p:{project}".
+func (c *Client) projectID() string {
+	return "o:" + c.Organization + ":p:" + c.Project
+}
+
+// resourceID Returns the ID of the resource of category.
+func (c *Client) resourceID(category string) string {
+	return c.projectID() + ":r:" + c.Resource(category)
+}
+
+// language Returns the Transifex language code of lang.
+func (c *Client) language(lang string) string {
+	if code, ok := c.Languages[lang]; ok {
+		return code
+	}
+	return strings.ReplaceAll(lang, "-", "_")
+}
+
+// catalogFiles Returns the JSON catalogs under dir relative to it, sorted.
+func catalogFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(name string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(name) != ".json" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// readCatalog Reads the JSON catalog filename, an empty one if it does not
+// exist.
+func readCatalog(filename string) (ii18n.TMsgs, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return make(ii18n.TMsgs), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data, err = ii18n.DecodeText(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	codec, _ := ii18n.GetCodec("json")
+	msgs, err := codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return msgs, nil
+}
+
+// errNotFound the error of calls answered 404 Not Found.
+var errNotFound = errors.New("transifex: not found")
+
+// call Sends the JSON:API body, when not nil, to the API resource p and
+// decodes the response into v, when not nil.
+func (c *Client) call(ctx context.Context, method string, p string, body interface{}, v interface{}) error {
+	req, err := c.request(ctx, method, p, body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := responseError(req, resp, data); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// request Returns the request of the API resource p with the token.
+func (c *Client) request(ctx context.Context, method string, p string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(endpoint, "/")+p, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	return req, nil
+}
+
+// responseError Returns the error of the response resp to req with the body
+// data, nil on success.
+func responseError(req *http.Request, resp *http.Response, data []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	var res struct {
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	json.Unmarshal(data, &res)
+	if len(res.Errors) > 0 && res.Errors[0].Detail != "" {
+		return fmt.Errorf("transifex: %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, res.Errors[0].Detail)
+	}
+	return fmt.Errorf("transifex: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+}
+
+func (c *Client) client() *http.Client {
+	if c.Client == nil {
+		return http.DefaultClient
+	}
+	return c.Client
+}
@@ -0,0 +1,167 @@
+package transifex
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// testProject Returns a Client of a fake Transifex project with the
+// resource admin-users, listed a page per resource with shop, whose
+// resources are all translated to remote, and the changes made to the
+// project, "{path} {attributes}" each.
+func testProject(t *testing.T, remote string) (*Client, *[]string) {
+	var srv *httptest.Server
+	var changes []string
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/resources":
+			slug, next := "admin-users", srv.URL+"/resources?page=2"
+			if r.URL.Query().Get("page") == "2" {
+				slug, next = "shop", ""
+			} else if r.URL.Query().Get("filter[project]") != "o:acme:p:web" {
+				http.Error(w, `{"errors": [{"detail": "no project"}]}`, http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `{"data": [{"attributes": {"slug": "`+slug+`"}}], "links": {"next": "`+next+`"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/resources/o:acme:p:web:r:admin-users":
+			io.WriteString(w, `{"data": {}}`)
+		case r.Method == http.MethodPost && (r.URL.Path == "/resources" || r.URL.Path == "/resource_strings_async_uploads"):
+			var body struct {
+				Data struct {
+					Attributes map[string]string `json:"attributes"`
+				} `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			attributes, _ := json.Marshal(body.Data.Attributes)
+			changes = append(changes, r.URL.Path+" "+string(attributes))
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			io.WriteString(w, `{"data": {"id": "u1", "attributes": {"status": "pending"}}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/resource_strings_async_uploads/u1":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			io.WriteString(w, `{"data": {"id": "u1", "attributes": {"status": "succeeded"}}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/resource_translations_async_downloads":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			io.WriteString(w, `{"data": {"id": "d1", "attributes": {"status": "pending"}}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/resource_translations_async_downloads/d1":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, remote)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := New("token", "acme", "web")
+	c.Endpoint = srv.URL
+	return c, &changes
+}
+
+func TestPullPaths(t *testing.T) {
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "en-US", "admin"), 0755)
+	os.WriteFile(filepath.Join(base, "en-US", "admin", "users.json"), []byte(`{"Delete": "Delete"}`), 0644)
+	c, _ := testProject(t, `{"Delete": "Löschen"}`)
+	changed, err := c.Pull(context.Background(), base, "en-US", []string{"de"}, Options{})
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("Pull() = %v, %v", changed, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(base, "de", "admin", "users.json")); err != nil || !strings.Contains(string(data), "Löschen") {
+		t.Errorf("de/admin/users.json = %s, %v", data, err)
+	}
+
+	for _, lang := range []string{"../..", "../evil", "/tmp"} {
+		_, err := c.Pull(context.Background(), base, "en-US", []string{lang}, Options{})
+		if err == nil || !strings.Contains(err.Error(), "outside of the language directory") {
+			t.Errorf("Pull(%s) = %v", lang, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(base), "evil")); err == nil {
+		t.Errorf("Pull() wrote outside of %s", base)
+	}
+}
+
+func TestConnector(t *testing.T) {
+	client, changes := testProject(t, `{"Delete": "Löschen"}`)
+	client.WebhookSecret = "s3cr3t"
+	client.Languages = map[string]string{"es-419": "es_MX"}
+	c := client.Connector(Options{})
+	ctx := context.Background()
+
+	resources, err := c.ListResources(ctx)
+	if err != nil || !reflect.DeepEqual(resources, []string{"admin/users.json", "shop.json"}) {
+		t.Errorf("ListResources() = %v, %v", resources, err)
+	}
+
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "en-US", "admin"), 0755)
+	os.WriteFile(filepath.Join(base, "en-US", "admin", "users.json"), []byte(`{"Delete": "Delete"}`), 0644)
+	os.WriteFile(filepath.Join(base, "en-US", "shop.json"), []byte(`{"Cart": "Cart"}`), 0644)
+	pushed, err := c.Push(ctx, base, "en-US")
+	if err != nil || !reflect.DeepEqual(pushed, []string{"admin/users.json", "shop.json"}) {
+		t.Errorf("Push() = %v, %v", pushed, err)
+	}
+	// The missing resource is created before its strings are uploaded.
+	expected := []string{
+		`/resource_strings_async_uploads {"content":"{\"Delete\": \"Delete\"}","content_encoding":"text"}`,
+		`/resources {"name":"app.shop","slug":"shop"}`,
+		`/resource_strings_async_uploads {"content":"{\"Cart\": \"Cart\"}","content_encoding":"text"}`,
+	}
+	if !reflect.DeepEqual(*changes, expected) {
+		t.Errorf("Push() changes = %q, want %q", *changes, expected)
+	}
+
+	written, err := c.Pull(ctx, base, "en-US", []string{"de"})
+	if err != nil || !reflect.DeepEqual(written, []string{filepath.Join(base, "de", "admin", "users.json"), filepath.Join(base, "de", "shop.json")}) {
+		t.Errorf("Pull() = %v, %v", written, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(base, "de", "admin", "users.json")); !strings.Contains(string(data), "Löschen") {
+		t.Errorf("de/admin/users.json = %s", data)
+	}
+
+	body := `{"event": "translation_completed", "resource": "admin-users", "language": "es_MX"}`
+	req := signedRequest("s3cr3t", body)
+	webhook, err := c.Webhook(req)
+	if err != nil || !reflect.DeepEqual(webhook, []integrations.Change{{Resource: "admin/users.json", Lang: "es-419"}}) {
+		t.Errorf("Webhook() = %v, %v", webhook, err)
+	}
+	req = signedRequest("other", body)
+	if _, err := c.Webhook(req); err != integrations.ErrSignature {
+		t.Errorf("Webhook() signed with another secret = %v, want ErrSignature", err)
+	}
+	req = signedRequest("s3cr3t", body)
+	req.Header.Set("Date", "Thu, 01 Jan 1970 00:00:00 GMT")
+	if _, err := c.Webhook(req); err != integrations.ErrSignature {
+		t.Errorf("Webhook() of another date = %v, want ErrSignature", err)
+	}
+}
+
+// signedRequest Returns a webhook request of body signed with secret as
+// Transifex signs them.
+func signedRequest(secret string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/hooks/transifex", strings.NewReader(body))
+	req.Header.Set("X-TX-Url", "https://example.com/hooks/transifex")
+	req.Header.Set("Date", "Fri, 16 Oct 2026 12:00:00 GMT")
+	sum := md5.Sum([]byte(body))
+	mac := hmac.New(sha256.New, []byte(secret))
+	io.WriteString(mac, "POST\nhttps://example.com/hooks/transifex\nFri, 16 Oct 2026 12:00:00 GMT\n"+hex.EncodeToString(sum[:]))
+	req.Header.Set("X-TX-Signature-V2", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return req
+}
@@ -0,0 +1,271 @@
+package weblate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultEndpoint the API of Hosted Weblate; self-hosted instances serve
+// it at https://{host}/api.
+const DefaultEndpoint = "https://hosted.weblate.org/api"
+
+// Client a Weblate project through the Weblate REST API, its components
+// being the catalogs of the same path under every language directory.
+//
+// The resource of a component is its file mask after the language, so the
+// component with the file mask "locales/*/admin/users.json" is the catalog
+// {base}/{lang}/admin/users.json. Components whose file mask does not have
+// a language directory are left out.
+type Client struct {
+	Token   string
+	Project string
+	// Endpoint the URL of the API, DefaultEndpoint when empty.
+	Endpoint string
+	Client   *http.Client
+	// Languages the Weblate language codes of ii18n languages, e.g.
+	// {"es-419": "es_419"}; other languages use their name with "_" for "-".
+	Languages map[string]string
+	// WebhookSecret the secret the webhooks of the project are signed with,
+	// see Handler.Secret.
+	WebhookSecret string
+}
+
+// NewClient returns a Client of the project, by slug, authenticated by the
+// API token.
+func NewClient(token string, project string) *Client {
+	return &Client{Token: token, Project: project, Endpoint: DefaultEndpoint, Client: http.DefaultClient}
+}
+
+// Component a component of the project.
+type Component struct {
+	Slug string `json:"slug"`
+	// FileMask the path of the translation files in the repository, "*"
+	// standing for the language, e.g. "locales/*/admin/users.json".
+	FileMask string `json:"filemask"`
+}
+
+// Resource Returns the path of the catalog of the component relative to the
+// language directory, false when its file mask has no language directory.
+func (c Component) Resource() (string, bool) {
+	var rest string
+	if strings.HasPrefix(c.FileMask, "*/") {
+		rest = c.FileMask[2:]
+	} else if _, after, ok := strings.Cut(c.FileMask, "/*/"); ok {
+		rest = after
+	}
+	if rest == "" || strings.Contains(rest, "*") {
+		return "", false
+	}
+	return rest, true
+}
+
+// Components Returns the components of the project with a resource, by
+// resource.
+func (c *Client) Components(ctx context.Context) (map[string]Component, error) {
+	components := make(map[string]Component)
+	next := c.endpoint() + "/projects/" + url.PathEscape(c.Project) + "/components/"
+	for next != "" {
+		var res struct {
+			Next    string      `json:"next"`
+			Results []Component `json:"results"`
+		}
+		if err := c.call(ctx, http.MethodGet, next, nil, "", &res); err != nil {
+			return nil, err
+		}
+		for _, component := range res.Results {
+			if resource, ok := component.Resource(); ok {
+				components[resource] = component
+			}
+		}
+		// The token is only sent to the endpoint.
+		if next = res.Next; next != "" && !strings.HasPrefix(next, c.endpoint()+"/") {
+			return nil, fmt.Errorf("weblate: next page %s outside of the endpoint", next)
+		}
+	}
+	return components, nil
+}
+
+// Push Uploads the catalogs of sourceLang under base, {base}/{sourceLang},
+// replacing the source strings of their components, and returns the
+// resources uploaded. Catalogs without a component are left out: Weblate
+// creates components from a repository.
+func (c *Client) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	components, err := c.Components(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var uploaded []string
+	for _, resource := range sortedKeys(components) {
+		name := filepath.Join(base, sourceLang, filepath.FromSlash(resource))
+		data, err := os.ReadFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return uploaded, err
+		}
+		if err := c.Upload(ctx, components[resource], sourceLang, path.Base(resource), data); err != nil {
+			return uploaded, fmt.Errorf("%s: %w", resource, err)
+		}
+		uploaded = append(uploaded, resource)
+	}
+	return uploaded, nil
+}
+
+// Pull Downloads the translations of the components in langs to
+// {base}/{lang}/{resource} and returns the files written. Languages a
+// component is not translated to are skipped.
+func (c *Client) Pull(ctx context.Context, base string, langs []string) ([]string, error) {
+	components, err := c.Components(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, lang := range langs {
+		for _, resource := range sortedKeys(components) {
+			// The file masks come from the API and the languages possibly
+			// from webhooks: neither may lead out of the language directory.
+			rel := filepath.FromSlash(resource)
+			if !filepath.IsLocal(lang) || !filepath.IsLocal(rel) {
+				return written, fmt.Errorf("weblate: %s %s: path outside of the language directory", lang, resource)
+			}
+			data, err := c.Download(ctx, components[resource], lang)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return written, fmt.Errorf("%s %s: %w", lang, resource, err)
+			}
+			name := filepath.Join(base, lang, rel)
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return written, err
+			}
+			if err := os.WriteFile(name, data, 0644); err != nil {
+				return written, err
+			}
+			written = append(written, name)
+		}
+	}
+	return written, nil
+}
+
+// Download Returns the translation file of the component in lang, an
+// fs.ErrNotExist error when the component is not translated to lang.
+func (c *Client) Download(ctx context.Context, component Component, lang string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.call(ctx, http.MethodGet, c.translation(component, lang), nil, "", &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Upload Replaces the translation file of the component in lang with data,
+// the file name, the source strings when lang is the source language.
+func (c *Client) Upload(ctx context.Context, component Component, lang string, name string, data []byte) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("method", "replace")
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	part.Write(data)
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.call(ctx, http.MethodPost, c.translation(component, lang), &body, w.FormDataContentType(), nil)
+}
+
+// call Sends body, when not nil, of contentType to the API and decodes the
+// JSON response into v, or copies it when v is a *bytes.Buffer.
+func (c *Client) call(ctx context.Context, method string, u string, body io.Reader, contentType string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Token "+c.Token)
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("weblate: %s %s: %w", req.Method, req.URL.Path, fs.ErrNotExist)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var res struct {
+			Detail string `json:"detail"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&res)
+		if res.Detail != "" {
+			return fmt.Errorf("weblate: %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, res.Detail)
+		}
+		return fmt.Errorf("weblate: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err := v.ReadFrom(resp.Body)
+		return err
+	default:
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+}
+
+func (c *Client) endpoint() string {
+	if c.Endpoint == "" {
+		return DefaultEndpoint
+	}
+	return strings.TrimSuffix(c.Endpoint, "/")
+}
+
+// translation Returns the URL of the translation file of the component in
+// lang.
+func (c *Client) translation(component Component, lang string) string {
+	code := strings.ReplaceAll(lang, "-", "_")
+	if id, ok := c.Languages[lang]; ok {
+		code = id
+	}
+	return c.endpoint() + "/translations/" + url.PathEscape(c.Project) + "/" + url.PathEscape(component.Slug) + "/" + url.PathEscape(code) + "/file/"
+}
+
+// lang Returns the ii18n language of the Weblate language code.
+func (c *Client) lang(code string) string {
+	for lang, id := range c.Languages {
+		if id == code {
+			return lang
+		}
+	}
+	return strings.ReplaceAll(code, "_", "-")
+}
+
+// sortedKeys Returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package weblate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// Connector Returns c as an integrations.Connector, the resources being
+// those of the components, see Client.
+func (c *Client) Connector() integrations.Connector {
+	return &connector{c: c}
+}
+
+type connector struct {
+	c *Client
+	// resources the resources of the components by slug, listed again when
+	// a webhook reports an unknown component.
+	resources map[string]string
+	mutex     sync.Mutex
+}
+
+func (c *connector) ListResources(ctx context.Context) ([]string, error) {
+	components, err := c.c.Components(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	c.resources = make(map[string]string, len(components))
+	for resource, component := range components {
+		c.resources[component.Slug] = resource
+	}
+	c.mutex.Unlock()
+	return sortedKeys(components), nil
+}
+
+func (c *connector) Push(ctx context.Context, base string, sourceLang string) ([]string, error) {
+	return c.c.Push(ctx, base, sourceLang)
+}
+
+func (c *connector) Pull(ctx context.Context, base string, sourceLang string, langs []string) ([]string, error) {
+	return c.c.Pull(ctx, base, langs)
+}
+
+// Webhook Returns the change of the component and language of r, signed as
+// Client.WebhookSecret says. The change of an event without component, or
+// of a component without resource, is every resource.
+func (c *connector) Webhook(r *http.Request) ([]integrations.Change, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(c.c.WebhookSecret, 0, r.Header, body); err != nil {
+		return nil, err
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+	change := integrations.Change{Lang: e.Lang}
+	if change.Lang == "" && e.Translation != "" {
+		change.Lang = c.c.lang(e.Translation)
+	}
+	if e.Component != "" {
+		if change.Resource, err = c.resource(r.Context(), e.Component); err != nil {
+			return nil, err
+		}
+	}
+	return []integrations.Change{change}, nil
+}
+
+// resource Returns the resource of the component slug, "" when it has none.
+func (c *connector) resource(ctx context.Context, slug string) (string, error) {
+	c.mutex.Lock()
+	resource, ok := c.resources[slug]
+	c.mutex.Unlock()
+	if ok {
+		return resource, nil
+	}
+	if _, err := c.ListResources(ctx); err != nil {
+		return "", err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.resources[slug], nil
+}
@@ -0,0 +1,208 @@
+// Package weblate reloads ii18n catalogs when Weblate, or another
+// translation management system, reports a change through a webhook.
+//
+// The handler verifies the signature of the request with the shared secret,
+// maps the Weblate component and language to a category and a language, and
+// reloads their catalogs with (*ii18n.I18N) ReloadCatalog:
+//
+//	h := weblate.New(os.Getenv("WEBHOOK_SECRET"))
+//	h.Sync = func(ctx context.Context, e weblate.Event) error {
+//		return exec.CommandContext(ctx, "git", "-C", "locales", "pull", "--ff-only").Run()
+//	}
+//	http.Handle("/hooks/weblate", h)
+//
+// Weblate signs its webhooks as Standard Webhooks, with the webhook-id,
+// webhook-timestamp and webhook-signature headers and a "whsec_" secret.
+// Other systems may sign the body as X-Hub-Signature-256, "sha256={hex}", and
+// send the category and language directly: {"category": "app.shop",
+// "lang": "de"}.
+//
+// Client syncs the catalogs with a Weblate project through the API and, as
+// an integrations.Connector, with the sync command and integrations.Handler.
+package weblate
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syyongx/ii18n"
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// maxBody the size limit of webhook requests.
+const maxBody = 1 << 20
+
+// Event the change reported by a webhook.
+type Event struct {
+	// Action the Weblate change, e.g. "New translation".
+	Action    string `json:"action"`
+	Project   string `json:"project"`
+	Component string `json:"component"`
+	// Translation the Weblate language code, e.g. "pt_BR".
+	Translation string `json:"translation"`
+	// Category and Lang of the change, set by other systems or else mapped
+	// from Component and Translation.
+	Category string `json:"category"`
+	Lang     string `json:"lang"`
+}
+
+// Handler an http.Handler of webhooks, which reloads the catalogs changed.
+type Handler struct {
+	// I18N the translator, ii18n.Translator when nil.
+	I18N *ii18n.I18N
+	// Secret the secret shared with the sender.
+	Secret string
+	// Prefix the category prefix of components, "app" when empty.
+	Prefix string
+	// Categories the categories of components that differ from
+	// {Prefix}.{component}, e.g. {"admin-users": "app.admin.users"}.
+	Categories map[string]string
+	// Languages the ii18n languages of Weblate language codes that differ
+	// from the code with "-" for "_".
+	Languages map[string]string
+	// Sync updates the catalogs before the reload when set, e.g. pulls the
+	// repository Weblate commits to.
+	Sync func(ctx context.Context, e Event) error
+	// Tolerance the accepted age of Standard Webhooks timestamps, 5 minutes
+	// when zero.
+	Tolerance time.Duration
+}
+
+// New returns a Handler of the webhooks signed with secret.
+func New(secret string) *Handler {
+	return &Handler{Secret: secret}
+}
+
+// ServeHTTP Reloads the catalogs of the change reported by r, answering 204
+// No Content, or 401 Unauthorized when the signature does not verify.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The errors of requests not verified yet do not tell why.
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := verify(h.Secret, h.Tolerance, r.Header, body); err != nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	category, lang := h.resolve(e)
+	if category == "" {
+		http.Error(w, "no category or component", http.StatusUnprocessableEntity)
+		return
+	}
+	e.Category, e.Lang = category, lang
+	if h.Sync != nil {
+		if err := h.Sync(r.Context(), e); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	i := h.I18N
+	if i == nil {
+		i = ii18n.Translator
+	}
+	if err := i.ReloadCatalog(category, lang); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolve Returns the category and language of e, "" for every language.
+func (h *Handler) resolve(e Event) (string, string) {
+	category, lang := e.Category, e.Lang
+	if category == "" && e.Component != "" {
+		var ok bool
+		if category, ok = h.Categories[e.Component]; !ok {
+			prefix := h.Prefix
+			if prefix == "" {
+				prefix = "app"
+			}
+			category = prefix + "." + e.Component
+		}
+	}
+	if lang == "" && e.Translation != "" {
+		var ok bool
+		if lang, ok = h.Languages[e.Translation]; !ok {
+			lang = strings.ReplaceAll(e.Translation, "_", "-")
+		}
+	}
+	return category, lang
+}
+
+// verify Checks the signature of body in header with secret, Standard
+// Webhooks, whose timestamp may be off by tolerance, or X-Hub-Signature-256.
+// Its errors wrap integrations.ErrSignature.
+func verify(secret string, tolerance time.Duration, header http.Header, body []byte) error {
+	errSignature := integrations.ErrSignature
+	if secret == "" {
+		return fmt.Errorf("%w: no webhook secret configured", errSignature)
+	}
+	if sig := header.Get("X-Hub-Signature-256"); sig != "" {
+		want, err := hex.DecodeString(strings.TrimPrefix(sig, "sha256="))
+		if err != nil || !hmac.Equal(want, sign([]byte(secret), body)) {
+			return errSignature
+		}
+		return nil
+	}
+	id, timestamp := header.Get("webhook-id"), header.Get("webhook-timestamp")
+	if id == "" || timestamp == "" {
+		return errSignature
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errSignature
+	}
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+	if age := time.Since(time.Unix(sec, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", errSignature)
+	}
+	key := []byte(secret)
+	if s, ok := strings.CutPrefix(secret, "whsec_"); ok {
+		if key, err = base64.StdEncoding.DecodeString(s); err != nil {
+			return fmt.Errorf("%w: invalid webhook secret", errSignature)
+		}
+	}
+	mac := sign(key, []byte(id+"."+timestamp+"."+string(body)))
+	// The header lists the signatures of every valid key, "v1,{base64}".
+	for _, sig := range strings.Fields(header.Get("webhook-signature")) {
+		version, value, _ := strings.Cut(sig, ",")
+		if version != "v1" {
+			continue
+		}
+		if want, err := base64.StdEncoding.DecodeString(value); err == nil && hmac.Equal(want, mac) {
+			return nil
+		}
+	}
+	return errSignature
+}
+
+// sign Returns the HMAC-SHA256 of data with key.
+func sign(key []byte, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
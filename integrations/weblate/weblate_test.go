@@ -0,0 +1,226 @@
+package weblate
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syyongx/ii18n"
+	"github.com/syyongx/ii18n/integrations"
+)
+
+// secret a Standard Webhooks secret, whsec_ and the base64 of the key.
+var secret = "whsec_" + base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+
+// standardHeader Returns the Standard Webhooks headers of body sent at ts,
+// signed with key.
+func standardHeader(key string, ts time.Time, body string) http.Header {
+	id, timestamp := "msg_1", strconv.FormatInt(ts.Unix(), 10)
+	mac := sign([]byte(key), []byte(id+"."+timestamp+"."+body))
+	return http.Header{
+		"Webhook-Id":        {id},
+		"Webhook-Timestamp": {timestamp},
+		"Webhook-Signature": {"v1,bm90IHRoaXMgb25l v1," + base64.StdEncoding.EncodeToString(mac)},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := `{"component": "shop", "translation": "pt_BR"}`
+	now := time.Now()
+	hub := http.Header{"X-Hub-Signature-256": {"sha256=" + hex.EncodeToString(sign([]byte("s3cr3t"), []byte(body)))}}
+	tests := []struct {
+		name   string
+		secret string
+		header http.Header
+		body   string
+		ok     bool
+	}{
+		{"standard", secret, standardHeader("0123456789abcdef", now, body), body, true},
+		{"standard plain secret", "s3cr3t", standardHeader("s3cr3t", now, body), body, true},
+		{"standard other key", secret, standardHeader("fedcba9876543210", now, body), body, false},
+		{"standard other body", secret, standardHeader("0123456789abcdef", now, body), body + " ", false},
+		{"standard stale", secret, standardHeader("0123456789abcdef", now.Add(-time.Hour), body), body, false},
+		{"standard future", secret, standardHeader("0123456789abcdef", now.Add(time.Hour), body), body, false},
+		{"standard invalid secret", "whsec_!", standardHeader("!", now, body), body, false},
+		{"hub", "s3cr3t", hub, body, true},
+		{"hub other body", "s3cr3t", hub, body + " ", false},
+		{"hub other secret", "other", hub, body, false},
+		{"unsigned", secret, http.Header{}, body, false},
+		{"no secret", "", hub, body, false},
+	}
+	for _, test := range tests {
+		err := verify(test.secret, 0, test.header, []byte(test.body))
+		if ok := err == nil; ok != test.ok || !ok && !errors.Is(err, integrations.ErrSignature) {
+			t.Errorf("%s: verify() = %v", test.name, err)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "pt-BR"), 0755)
+	os.WriteFile(filepath.Join(dir, "pt-BR", "shop.json"), []byte(`{"Cart": "Carrinho"}`), 0644)
+	i := ii18n.NewI18N(map[string]ii18n.Config{
+		"app": {SourceNewFunc: ii18n.NewJSONSource, BasePath: dir, FileMap: map[string]string{}},
+	}, ii18n.WithLogger(slog.New(slog.DiscardHandler)))
+	i.T("app.shop", "Cart", nil, "pt-BR")
+	os.WriteFile(filepath.Join(dir, "pt-BR", "shop.json"), []byte(`{"Cart": "Cesto"}`), 0644)
+
+	h := New(secret)
+	h.I18N = i
+	var synced []Event
+	h.Sync = func(ctx context.Context, e Event) error {
+		synced = append(synced, e)
+		return nil
+	}
+	body := `{"action": "New translation", "component": "shop", "translation": "pt_BR"}`
+	for _, header := range []http.Header{
+		{},
+		standardHeader("0123456789abcdef", time.Now().Add(-time.Hour), body),
+		standardHeader("fedcba9876543210", time.Now(), body),
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/hooks/weblate", strings.NewReader(body))
+		req.Header = header
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized || w.Body.String() != "Unauthorized\n" {
+			t.Errorf("ServeHTTP() unverified = %d %q, want 401 without details", w.Code, w.Body.String())
+		}
+	}
+	if synced != nil || i.T("app.shop", "Cart", nil, "pt-BR") != "Carrinho" {
+		t.Fatal("unverified webhook synced or reloaded")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks/weblate", strings.NewReader(body))
+	req.Header = standardHeader("0123456789abcdef", time.Now(), body)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("ServeHTTP() = %d %s", w.Code, w.Body)
+	}
+	if len(synced) != 1 || synced[0].Category != "app.shop" || synced[0].Lang != "pt-BR" {
+		t.Errorf("Sync() got %+v", synced)
+	}
+	if got := i.T("app.shop", "Cart", nil, "pt-BR"); got != "Cesto" {
+		t.Errorf("T(Cart) after the webhook = %q", got)
+	}
+}
+
+// testProject Returns a Client of a fake Weblate project with the
+// components of file masks, by slug, and files by "{component}/{code}",
+// recording the files uploaded in it.
+func testProject(t *testing.T, masks map[string]string, files map[string]string) *Client {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token t0k3n" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch p := r.URL.Path; {
+		case p == "/api/projects/web/components/":
+			// A page per component.
+			slugs := sortedKeys(masks)
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			next := ""
+			if page+1 < len(slugs) {
+				next = srv.URL + "/api/projects/web/components/?page=" + strconv.Itoa(page+1)
+			}
+			io.WriteString(w, `{"next": "`+next+`", "results": [{"slug": "`+slugs[page]+`", "filemask": "`+masks[slugs[page]]+`"}]}`)
+		case strings.HasPrefix(p, "/api/translations/web/") && strings.HasSuffix(p, "/file/"):
+			file := strings.TrimSuffix(strings.TrimPrefix(p, "/api/translations/web/"), "/file/")
+			if r.Method == http.MethodPost {
+				f, _, err := r.FormFile("file")
+				if err != nil || r.FormValue("method") != "replace" {
+					http.Error(w, `{"detail": "bad upload"}`, http.StatusBadRequest)
+					return
+				}
+				data, _ := io.ReadAll(f)
+				files[file] = string(data)
+				io.WriteString(w, `{"result": true}`)
+				return
+			}
+			data, ok := files[file]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			io.WriteString(w, data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient("t0k3n", "web")
+	c.Endpoint = srv.URL + "/api"
+	c.WebhookSecret = secret
+	return c
+}
+
+func TestConnector(t *testing.T) {
+	masks := map[string]string{
+		"shop":        "locales/*/shop.json",
+		"admin-users": "locales/*/admin/users.json",
+		"docs":        "docs/*.po",
+	}
+	files := map[string]string{
+		"shop/de":           `{"Cart": "Warenkorb"}`,
+		"admin-users/de":    `{"Delete": "Löschen"}`,
+		"admin-users/pt_BR": `{"Delete": "Excluir"}`,
+	}
+	c := testProject(t, masks, files).Connector()
+	ctx := context.Background()
+
+	resources, err := c.ListResources(ctx)
+	if err != nil || !reflect.DeepEqual(resources, []string{"admin/users.json", "shop.json"}) {
+		t.Errorf("ListResources() = %v, %v", resources, err)
+	}
+
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "en-US"), 0755)
+	os.WriteFile(filepath.Join(base, "en-US", "shop.json"), []byte(`{"Cart": "Cart"}`), 0644)
+	os.WriteFile(filepath.Join(base, "en-US", "orphan.json"), []byte(`{}`), 0644)
+	pushed, err := c.Push(ctx, base, "en-US")
+	if err != nil || !reflect.DeepEqual(pushed, []string{"shop.json"}) || files["shop/en_US"] != `{"Cart": "Cart"}` {
+		t.Errorf("Push() = %v, %v, uploaded %q", pushed, err, files["shop/en_US"])
+	}
+
+	written, err := c.Pull(ctx, base, "en-US", []string{"de", "pt-BR"})
+	expected := []string{
+		filepath.Join(base, "de", "admin", "users.json"),
+		filepath.Join(base, "de", "shop.json"),
+		filepath.Join(base, "pt-BR", "admin", "users.json"),
+	}
+	if err != nil || !reflect.DeepEqual(written, expected) {
+		t.Errorf("Pull() = %v, %v, want %v", written, err, expected)
+	}
+	if data, _ := os.ReadFile(filepath.Join(base, "pt-BR", "admin", "users.json")); string(data) != `{"Delete": "Excluir"}` {
+		t.Errorf("pt-BR/admin/users.json = %s", data)
+	}
+	if _, err := c.Pull(ctx, base, "en-US", []string{"../.."}); err == nil {
+		t.Error("Pull(../..) = nil error")
+	}
+
+	body := `{"action": "New translation", "component": "admin-users", "translation": "pt_BR"}`
+	req := httptest.NewRequest(http.MethodPost, "/hooks/weblate", strings.NewReader(body))
+	req.Header = standardHeader("0123456789abcdef", time.Now(), body)
+	changes, err := c.Webhook(req)
+	if err != nil || !reflect.DeepEqual(changes, []integrations.Change{{Resource: "admin/users.json", Lang: "pt-BR"}}) {
+		t.Errorf("Webhook() = %v, %v", changes, err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/hooks/weblate", strings.NewReader(body))
+	if _, err := c.Webhook(req); !errors.Is(err, integrations.ErrSignature) {
+		t.Errorf("Webhook() unsigned = %v, want ErrSignature", err)
+	}
+}
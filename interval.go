@@ -0,0 +1,133 @@
+package ii18n
+
+import (
+	"strings"
+	"time"
+)
+
+// intervalFormats CLDR interval patterns by locale, skeleton and the
+// greatest field that differs between the ends of the interval. The
+// pattern is split where a field repeats: the first part shows the start,
+// the rest the end.
+var intervalFormats = map[string]map[string]map[byte]string{
+	"en": {
+		"yMMMd":  {'d': "MMM d\u2009–\u2009d, y", 'M': "MMM d\u2009–\u2009MMM d, y", 'y': "MMM d, y\u2009–\u2009MMM d, y"},
+		"yMMMMd": {'d': "MMMM d\u2009–\u2009d, y", 'M': "MMMM d\u2009–\u2009MMMM d, y", 'y': "MMMM d, y\u2009–\u2009MMMM d, y"},
+		"yMd":    {'d': "M/d/y\u2009–\u2009M/d/y", 'M': "M/d/y\u2009–\u2009M/d/y", 'y': "M/d/y\u2009–\u2009M/d/y"},
+		"yMMM":   {'M': "MMM\u2009–\u2009MMM y", 'y': "MMM y\u2009–\u2009MMM y"},
+		"MMMd":   {'d': "MMM d\u2009–\u2009d", 'M': "MMM d\u2009–\u2009MMM d"},
+		"Hm":     {'h': "HH:mm\u2009–\u2009HH:mm", 'm': "HH:mm\u2009–\u2009HH:mm"},
+		"hm":     {'a': "h:mm\u202fa\u2009–\u2009h:mm\u202fa", 'h': "h:mm\u2009–\u2009h:mm\u202fa", 'm': "h:mm\u2009–\u2009h:mm\u202fa"},
+	},
+}
+
+// intervalFallbacks patterns joining the start {0} and end {1} of
+// intervals without a pattern of their own.
+var intervalFallbacks = map[string]string{
+	"en": "{0}\u2009–\u2009{1}",
+}
+
+// FormatDateInterval Formats the interval from from to to in lang with the
+// fields of skeleton, showing the fields the ends share once: "Jan 3 – 5,
+// 2025" for "yMMMd" in English. Ends equal in every field of skeleton are
+// formatted as a single date.
+func FormatDateInterval(from time.Time, to time.Time, skeleton string, lang string) (string, error) {
+	cal, lang := calendarLocale(lang)
+	to = to.In(from.Location())
+	field := greatestDifference(from, to, skeleton)
+	if field == 0 {
+		return formatSkeleton(from, skeleton, cal, lang)
+	}
+	split := strings.IndexAny(skeleton, "hHkKjmsaz")
+	if split > 0 && field != 'y' && field != 'M' && field != 'd' {
+		// same day: the date once, then the interval of the times
+		date, err := formatSkeleton(from, skeleton[:split], cal, lang)
+		if err != nil {
+			return "", err
+		}
+		clock, err := FormatDateInterval(from, to, skeleton[split:], lang)
+		if err != nil {
+			return "", err
+		}
+		return joinDateTime(cal.dateTimePatterns[2], date, clock), nil
+	}
+	if calendarNamesOf(calendarOf(lang), lang) == nil {
+		if patterns, ok := lookupLocale(intervalFormats, lang, "")[skeleton]; ok {
+			if pattern, ok := patterns[field]; ok {
+				pos := intervalSplit(pattern)
+				return formatDatePattern(from, pattern[:pos], cal, lang) + formatDatePattern(to, pattern[pos:], cal, lang), nil
+			}
+		}
+	}
+	start, err := formatSkeleton(from, skeleton, cal, lang)
+	if err != nil {
+		return "", err
+	}
+	end, err := formatSkeleton(to, skeleton, cal, lang)
+	if err != nil {
+		return "", err
+	}
+	fallback := lookupLocale(intervalFallbacks, lang, "")
+	if fallback == "" {
+		fallback = "{0}\u2009–\u2009{1}"
+	}
+	return strings.NewReplacer("{0}", start, "{1}", end).Replace(fallback), nil
+}
+
+// greatestDifference Returns the largest field of skeleton, as 'y', 'M',
+// 'd', 'a', 'h' or 'm', in which from and to differ, or 0.
+func greatestDifference(from time.Time, to time.Time, skeleton string) byte {
+	hasHour12 := strings.ContainsAny(skeleton, "hK")
+	fields := []struct {
+		field   byte
+		letters string
+		differs bool
+	}{
+		{'y', "yGMLdE", from.Year() != to.Year()},
+		{'M', "MLdE", from.Month() != to.Month()},
+		{'d', "dE", from.Day() != to.Day()},
+		{'a', "hK", hasHour12 && from.Hour()/12 != to.Hour()/12},
+		{'h', "hHkK", from.Hour() != to.Hour()},
+		{'m', "m", from.Minute() != to.Minute()},
+	}
+	for _, f := range fields {
+		if f.differs && strings.ContainsAny(skeleton, f.letters) {
+			return f.field
+		}
+	}
+	return 0
+}
+
+// intervalSplit Returns the position of the first field of pattern that
+// repeats an earlier one, where the end of an interval starts.
+func intervalSplit(pattern string) int {
+	seen := map[byte]bool{}
+	quoted := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == '\'' {
+			quoted = !quoted
+			continue
+		}
+		if quoted || !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			continue
+		}
+		if i > 0 && pattern[i-1] == c {
+			continue
+		}
+		field := c
+		switch c {
+		case 'L':
+			field = 'M'
+		case 'c':
+			field = 'E'
+		case 'H', 'k', 'K':
+			field = 'h'
+		}
+		if seen[field] {
+			return i
+		}
+		seen[field] = true
+	}
+	return len(pattern)
+}
@@ -1,9 +1,8 @@
 package ii18n
 
 import (
+	"bytes"
 	"encoding/json"
-	"io/ioutil"
-	"strings"
 )
 
 // Type JSONSource
@@ -14,40 +13,31 @@ type JSONSource struct {
 // New JSONSource
 func NewJSONSource(conf *Config) Source {
 	s := &JSONSource{}
-	s.OriginalLang = conf.OriginalLang
-	s.BasePath = conf.BasePath
-	s.ForceTranslation = conf.ForceTranslation
-	s.FileMap = conf.FileMap
-	s.messages = make(map[string]TMsgs)
-	s.fileSuffix = "json"
-	s.loadFunc = loadMsgsFromJSONFile
+	s.init(conf, "json", jsonCodec{})
 
 	return s
 }
 
-// Get messages file path.
-func (js *JSONSource) GetMsgFilePath(category string, lang string) string {
-	suffix := strings.Split(category, ".")[1]
-	path := js.BasePath + "/" + lang + "/"
-	if v, ok := js.FileMap[suffix]; !ok {
-		path += v
-	} else {
-		path += strings.Replace(suffix, "\\", "/", -1)
+// jsonCodec JSON objects of message to translation.
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(data []byte) (TMsgs, error) {
+	var msgs TMsgs
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, err
 	}
-	return path
+	return msgs, nil
 }
 
-// Get messages file path.
-func loadMsgsFromJSONFile(filename string) (TMsgs, error) {
-	data, err := ioutil.ReadFile(filename)
-	if err != nil {
+// Encode Encodes msgs with sorted keys, tab indented, leaving HTML
+// characters unescaped.
+func (jsonCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(msgs); err != nil {
 		return nil, err
 	}
-	var msgs TMsgs
-	e := json.Unmarshal(data, &msgs)
-	if e != nil {
-		return nil, e
-	}
-
-	return msgs, nil
+	return b.Bytes(), nil
 }
@@ -0,0 +1,311 @@
+package ii18n
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Type LazyJSONSource serves JSON catalogs of tens of megabytes decoding
+// only the messages translated: a catalog is indexed when first used, each
+// key mapped to the offsets of its value in the file, and a value is sliced
+// out of the file, or decoded when it has escapes, when first translated.
+// Loading pays for scanning the file and indexing the keys but not for the
+// strings and map entries of the values, at the cost of a little latency on
+// the first use of a message. LoadMsgs and LoadCatalog decode the whole
+// catalog.
+type LazyJSONSource struct {
+	JSONSource
+	catalogs map[string]*lazyCatalog
+	// catalogsMutex guards the catalogs, which Reload drops.
+	catalogsMutex sync.RWMutex
+}
+
+// New LazyJSONSource
+func NewLazyJSONSource(conf *Config) Source {
+	s := &LazyJSONSource{catalogs: make(map[string]*lazyCatalog)}
+	s.init(conf, "json", jsonCodec{})
+
+	return s
+}
+
+// lazyCatalog an indexed JSON catalog, without index when the file does not
+// exist.
+type lazyCatalog struct {
+	category string
+	lang     string
+	// data the file, which the keys and the values without escapes are
+	// sliced from.
+	data  string
+	index map[string]lazySpan
+	// decoded the values with escapes decoded.
+	decoded sync.Map
+}
+
+// lazySpan the offsets of a JSON string literal, quotes included.
+type lazySpan struct {
+	start, end uint32
+}
+
+// Translate
+func (s *LazyJSONSource) Translate(category string, message string, lang string) (string, error) {
+	return s.TranslateContext(context.Background(), category, message, lang)
+}
+
+// TranslateContext Translate; the context is not used.
+func (s *LazyJSONSource) TranslateContext(ctx context.Context, category string, message string, lang string) (string, error) {
+	if s.ForceTranslation || lang != s.OriginalLang {
+		return s.TranslateMsg(category, message, lang)
+	}
+	return "", nil
+}
+
+// TranslateMsg Returns the first non-empty translation of message in the
+// catalogs of lang and its fallbacks, in the order LoadMsgs merges them.
+func (s *LazyJSONSource) TranslateMsg(category string, message string, lang string) (string, error) {
+	if _, _, err := splitCategory(category); err != nil {
+		return "", err
+	}
+	catalogs, err := s.chain(category, lang)
+	if err != nil {
+		return "", err
+	}
+	val, err := s.lookup(catalogs, message)
+	if err != nil || val != "" {
+		return val, err
+	}
+	return "", &MissingTranslationError{Category: category, Message: message, Lang: lang}
+}
+
+// TranslateBatch Returns the translations of messages in lang, resolving
+// the catalogs of lang and its fallbacks once.
+func (s *LazyJSONSource) TranslateBatch(category string, messages []string, lang string) (TMsgs, error) {
+	translations := make(TMsgs, len(messages))
+	if !s.ForceTranslation && lang == s.OriginalLang {
+		return translations, nil
+	}
+	if _, _, err := splitCategory(category); err != nil {
+		return nil, err
+	}
+	catalogs, err := s.chain(category, lang)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		val, err := s.lookup(catalogs, message)
+		if err != nil {
+			return nil, err
+		}
+		if val != "" {
+			translations[message] = val
+		}
+	}
+	return translations, nil
+}
+
+// chain Returns the existing catalogs of category in lang and its
+// fallbacks, in the order LoadMsgs merges them.
+func (s *LazyJSONSource) chain(category string, lang string) ([]*lazyCatalog, error) {
+	var catalogs []*lazyCatalog
+	for n, l := range s.fallbackChain(lang) {
+		c, err := s.catalog(category, l)
+		if err != nil {
+			return nil, err
+		}
+		if c.index == nil {
+			if n == 0 && s.missingFiles == ErrorOnMissingFile && l != s.OriginalLang {
+				return nil, &LoadError{Path: s.GetMsgFilePath(category, l), Err: ErrCatalogNotFound}
+			}
+			continue
+		}
+		catalogs = append(catalogs, c)
+	}
+	return catalogs, nil
+}
+
+// lookup Returns the first non-empty value of message in catalogs.
+func (s *LazyJSONSource) lookup(catalogs []*lazyCatalog, message string) (string, error) {
+	key := s.normalizeKeys.Normalize(message)
+	for _, c := range catalogs {
+		val, err := c.lookup(key)
+		if err != nil {
+			return "", &LoadError{Path: s.GetMsgFilePath(c.category, c.lang), Err: err}
+		}
+		if val != "" {
+			return val, nil
+		}
+	}
+	return "", nil
+}
+
+// catalog Returns the catalog of category and lang, indexing it on first
+// use.
+func (s *LazyJSONSource) catalog(category string, lang string) (*lazyCatalog, error) {
+	filename := s.GetMsgFilePath(category, lang)
+	s.catalogsMutex.RLock()
+	c, ok := s.catalogs[filename]
+	s.catalogsMutex.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	s.catalogsMutex.Lock()
+	defer s.catalogsMutex.Unlock()
+	if c, ok := s.catalogs[filename]; ok {
+		return c, nil
+	}
+	c = &lazyCatalog{category: category, lang: lang}
+	data, err := s.readFile(filename)
+	if err != nil && !errors.Is(err, ErrCatalogNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		c.data = string(data)
+		if c.index, err = indexJSON(c.data, s.normalizeKeys); err != nil {
+			return nil, &LoadError{Path: filename, Err: err}
+		}
+	}
+	s.catalogs[filename] = c
+	return c, nil
+}
+
+// preload Indexes the catalogs of category in lang and its fallbacks.
+func (s *LazyJSONSource) preload(ctx context.Context, category string, lang string) error {
+	for _, l := range s.fallbackChain(lang) {
+		if _, err := s.catalog(category, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup Returns the value of key in c, decoding it on first use.
+func (c *lazyCatalog) lookup(key string) (string, error) {
+	span, ok := c.index[key]
+	if !ok {
+		return "", nil
+	}
+	lit := c.data[span.start:span.end]
+	if !strings.Contains(lit, `\`) {
+		return lit[1 : len(lit)-1], nil
+	}
+	if val, ok := c.decoded.Load(span); ok {
+		return val.(string), nil
+	}
+	var val string
+	if err := json.Unmarshal([]byte(lit), &val); err != nil {
+		return "", err
+	}
+	c.decoded.Store(span, val)
+	return val, nil
+}
+
+// indexJSON Returns the offsets of the values of the JSON object of strings
+// data by key, the keys normalized by norm.
+func indexJSON(data string, norm KeyNormalization) (map[string]lazySpan, error) {
+	if uint64(len(data)) > 1<<32-1 {
+		return nil, errors.New("JSON catalog larger than 4 GiB")
+	}
+	pos := skipSpace(data, 0)
+	if pos == len(data) || data[pos] != '{' {
+		return nil, errors.New("JSON catalog is not an object")
+	}
+	index := make(map[string]lazySpan)
+	pos = skipSpace(data, pos+1)
+	if pos < len(data) && data[pos] == '}' {
+		return index, nil
+	}
+	for {
+		keyEnd, err := scanString(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		key := data[pos+1 : keyEnd-1]
+		if strings.Contains(key, `\`) {
+			if err := json.Unmarshal([]byte(data[pos:keyEnd]), &key); err != nil {
+				return nil, err
+			}
+		}
+		if pos = skipSpace(data, keyEnd); pos == len(data) || data[pos] != ':' {
+			return nil, errors.New("JSON catalog: expected ':' after key " + key)
+		}
+		pos = skipSpace(data, pos+1)
+		valEnd, err := scanString(data, pos)
+		if err != nil {
+			return nil, errors.New("JSON catalog: value of " + key + ": " + err.Error())
+		}
+		index[norm.Normalize(key)] = lazySpan{uint32(pos), uint32(valEnd)}
+		if pos = skipSpace(data, valEnd); pos == len(data) {
+			return nil, errors.New("JSON catalog: unexpected end")
+		}
+		switch data[pos] {
+		case ',':
+			pos = skipSpace(data, pos+1)
+		case '}':
+			if skipSpace(data, pos+1) != len(data) {
+				return nil, errors.New("JSON catalog: data after the object")
+			}
+			return index, nil
+		default:
+			return nil, errors.New("JSON catalog: expected ',' or '}'")
+		}
+	}
+}
+
+// scanString Returns the end of the JSON string literal at pos.
+func scanString(data string, pos int) (int, error) {
+	if pos == len(data) || data[pos] != '"' {
+		return 0, errors.New("expected a string")
+	}
+	for i := pos + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		default:
+			if data[i] < 0x20 {
+				return 0, errors.New("control character in string")
+			}
+		}
+	}
+	return 0, errors.New("unterminated string")
+}
+
+// skipSpace Returns the position of the first non-space byte of data from
+// pos.
+func skipSpace(data string, pos int) int {
+	for pos < len(data) && (data[pos] == ' ' || data[pos] == '\t' || data[pos] == '\n' || data[pos] == '\r') {
+		pos++
+	}
+	return pos
+}
+
+// Reload Drops the catalogs, which are indexed again on their next use.
+func (s *LazyJSONSource) Reload() error {
+	s.drop(func(c *lazyCatalog) bool { return true })
+	return s.JSONSource.Reload()
+}
+
+// ReloadCatalog Drops the catalog of category in lang, of every language
+// when lang is empty. The languages falling back to lang look up its
+// catalog, so they need no reload.
+func (s *LazyJSONSource) ReloadCatalog(category string, lang string) error {
+	s.drop(func(c *lazyCatalog) bool {
+		return c.category == category && (lang == "" || c.lang == lang)
+	})
+	return s.JSONSource.ReloadCatalog(category, lang)
+}
+
+// drop Drops the catalogs matched.
+func (s *LazyJSONSource) drop(match func(c *lazyCatalog) bool) {
+	s.catalogsMutex.Lock()
+	defer s.catalogsMutex.Unlock()
+	for filename, c := range s.catalogs {
+		if match(c) {
+			delete(s.catalogs, filename)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package ii18n
+
+import (
+	"strings"
+)
+
+// ListStyle the CLDR list pattern type and width of FormatList.
+type ListStyle int
+
+// List styles.
+const (
+	// ListAnd "A, B, and C".
+	ListAnd ListStyle = iota
+	// ListAndShort "A, B, & C".
+	ListAndShort
+	// ListAndNarrow "A, B, C".
+	ListAndNarrow
+	// ListOr "A, B, or C".
+	ListOr
+	ListOrShort
+	ListOrNarrow
+	// ListUnit "3 feet, 7 inches".
+	ListUnit
+	ListUnitShort
+	// ListUnitNarrow "3′ 7″".
+	ListUnitNarrow
+)
+
+// listPattern CLDR list patterns: two joins a pair, start, middle and end
+// join the first, inner and last items of longer lists.
+type listPattern struct {
+	two    string
+	start  string
+	middle string
+	end    string
+}
+
+// listPatterns list patterns by locale and style. Styles missing in a
+// locale use the long width of their type.
+var listPatterns = map[string]map[ListStyle]listPattern{
+	"en": {
+		ListAnd:        {"{0} and {1}", "{0}, {1}", "{0}, {1}", "{0}, and {1}"},
+		ListAndShort:   {"{0} & {1}", "{0}, {1}", "{0}, {1}", "{0}, & {1}"},
+		ListAndNarrow:  {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0}, {1}"},
+		ListOr:         {"{0} or {1}", "{0}, {1}", "{0}, {1}", "{0}, or {1}"},
+		ListUnit:       {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0}, {1}"},
+		ListUnitNarrow: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+	},
+	"en-GB": {
+		ListAnd:        {"{0} and {1}", "{0}, {1}", "{0}, {1}", "{0} and {1}"},
+		ListAndShort:   {"{0} and {1}", "{0}, {1}", "{0}, {1}", "{0} and {1}"},
+		ListAndNarrow:  {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0}, {1}"},
+		ListOr:         {"{0} or {1}", "{0}, {1}", "{0}, {1}", "{0} or {1}"},
+		ListUnit:       {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0}, {1}"},
+		ListUnitNarrow: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+	},
+}
+
+// FormatList Joins items with the list patterns of lang for style, "A, B,
+// and C" for ListAnd in English. Unknown styles are ListAnd.
+func FormatList(items []string, lang string, style ListStyle) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	if style < ListAnd || style > ListUnitNarrow {
+		style = ListAnd
+	}
+	styles := lookupLocale(listPatterns, lang, "en")
+	pattern, ok := styles[style]
+	if !ok {
+		// styles come in threes, the long width first
+		pattern, ok = styles[style-style%3]
+		if !ok {
+			pattern = listPatterns["en"][style-style%3]
+		}
+	}
+	spanish := strings.HasPrefix(lang, "es")
+	join := func(p string, a string, b string) string {
+		if spanish {
+			p = spanishConjunction(p, b)
+		}
+		return strings.NewReplacer("{0}", a, "{1}", b).Replace(p)
+	}
+	n := len(items)
+	if n == 2 {
+		return join(pattern.two, items[0], items[1])
+	}
+	out := join(pattern.end, items[n-2], items[n-1])
+	for i := n - 3; i > 0; i-- {
+		out = join(pattern.middle, items[i], out)
+	}
+	return join(pattern.start, items[0], out)
+}
+
+// spanishConjunction Returns the Spanish pattern p with "y" replaced by "e"
+// before words starting with an i sound, and "o" by "u" before an o sound.
+func spanishConjunction(p string, next string) string {
+	word := strings.ToLower(next)
+	// "hie" and "hia" start with a y sound: "agua y hielo".
+	iSound := strings.HasPrefix(word, "i") ||
+		strings.HasPrefix(word, "hi") && !strings.HasPrefix(word, "hie") && !strings.HasPrefix(word, "hia")
+	oSound := strings.HasPrefix(word, "o") || strings.HasPrefix(word, "ho") || strings.HasPrefix(word, "8")
+	switch {
+	case iSound && strings.Contains(p, " y "):
+		return strings.Replace(p, " y ", " e ", 1)
+	case oSound && strings.Contains(p, " o "):
+		return strings.Replace(p, " o ", " u ", 1)
+	}
+	return p
+}
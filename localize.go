@@ -0,0 +1,100 @@
+package ii18n
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// LocalizeStruct Fills the tagged fields of the struct v points to with
+// translations to lang, see (*I18N) LocalizeStruct.
+func LocalizeStruct(v interface{}, lang string) error {
+	return Translator.LocalizeStruct(v, lang)
+}
+
+// LocalizeStruct Fills the tagged fields of the struct v points to with
+// translations to lang, descending into nested structs, pointers, slices and
+// arrays:
+//
+//	Title  string            `i18n:"app.shop.Cart"`   // the key Cart of app.shop
+//	Status string            `i18n:"app.status,value"` // the field's value is the key
+//	Labels map[string]string `i18n:"app.status"`       // every key of the map
+//
+// A category without a dot is in app, as for T.
+func (i *I18N) LocalizeStruct(v interface{}, lang string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("LocalizeStruct of " + rv.Kind().String() + ", want a pointer to a struct")
+	}
+	return i.localizeValue(rv, lang, make(map[uintptr]bool))
+}
+
+// localizeValue Localizes the structs reachable from v, visiting pointers
+// once.
+func (i *I18N) localizeValue(v reflect.Value, lang string, seen map[uintptr]bool) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return nil
+		}
+		seen[v.Pointer()] = true
+		return i.localizeValue(v.Elem(), lang, seen)
+	case reflect.Interface:
+		if v.IsNil() || v.Elem().Kind() != reflect.Pointer {
+			return nil
+		}
+		return i.localizeValue(v.Elem(), lang, seen)
+	case reflect.Slice, reflect.Array:
+		for n := 0; n < v.Len(); n++ {
+			if err := i.localizeValue(v.Index(n), lang, seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for n := 0; n < t.NumField(); n++ {
+			sf := t.Field(n)
+			if !sf.IsExported() {
+				continue
+			}
+			tag, ok := sf.Tag.Lookup("i18n")
+			if !ok {
+				if err := i.localizeValue(v.Field(n), lang, seen); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := i.localizeField(v.Field(n), t.Name()+"."+sf.Name, tag, lang); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// localizeField Sets the field named name, tagged tag, to its translation.
+func (i *I18N) localizeField(f reflect.Value, name string, tag string, lang string) error {
+	ref, opt, _ := strings.Cut(tag, ",")
+	switch {
+	case f.Kind() == reflect.Map && f.Type().Key().Kind() == reflect.String && f.Type().Elem().Kind() == reflect.String:
+		category := normalizeCategory(ref)
+		for _, key := range f.MapKeys() {
+			f.SetMapIndex(key, reflect.ValueOf(i.T(category, key.String(), nil, lang)).Convert(f.Type().Elem()))
+		}
+	case f.Kind() != reflect.String:
+		return errors.New("LocalizeStruct: " + name + " is a " + f.Type().String() + ", want a string or map[string]string")
+	case opt == "value":
+		if f.String() != "" {
+			f.SetString(i.T(ref, f.String(), nil, lang))
+		}
+	case opt != "":
+		return errors.New("LocalizeStruct: " + name + ": unknown option " + opt)
+	default:
+		pos := strings.LastIndexByte(ref, '.')
+		if pos <= 0 || pos == len(ref)-1 {
+			return errors.New("LocalizeStruct: " + name + ": want category.key, got " + ref)
+		}
+		f.SetString(i.T(ref[:pos], ref[pos+1:], nil, lang))
+	}
+	return nil
+}
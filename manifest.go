@@ -0,0 +1,77 @@
+package ii18n
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseManifest Returns the SHA-256 checksums in hex of a manifest by
+// catalog path: a JSON object such as {"de/app.json": "9f86d0…"}, or the
+// output of sha256sum, lines of a checksum, two spaces or " *" and a path.
+func ParseManifest(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &sums); err != nil {
+			return nil, err
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || line[0] == '#' {
+				continue
+			}
+			sum, path, ok := strings.Cut(line, " ")
+			if !ok {
+				return nil, fmt.Errorf("line %d: want a checksum and a path", n)
+			}
+			sums[strings.TrimPrefix(strings.TrimLeft(path, " "), "*")] = sum
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	normalized := make(map[string]string, len(sums))
+	for path, sum := range sums {
+		if len(sum) != hex.EncodedLen(sha256.Size) {
+			return nil, fmt.Errorf("%s: invalid checksum %q", path, sum)
+		}
+		normalized[filepath.ToSlash(filepath.Clean(path))] = strings.ToLower(sum)
+	}
+	return normalized, nil
+}
+
+// verifyChecksum Verifies data, the catalog filename, against the manifest
+// of dir, the directory filename is relative to, wrapping
+// ErrChecksumMismatch when data does not match or the manifest does not
+// list the catalog.
+func verifyChecksum(dir string, manifest string, filename string, data []byte) error {
+	content, err := os.ReadFile(dir + "/" + manifest)
+	if err != nil {
+		return &LoadError{Path: filename, Err: fmt.Errorf("%w: reading manifest: %s", ErrChecksumMismatch, err.Error())}
+	}
+	sums, err := ParseManifest(content)
+	if err != nil {
+		return &LoadError{Path: filename, Err: fmt.Errorf("%w: manifest %s: %s", ErrChecksumMismatch, manifest, err.Error())}
+	}
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil {
+		return &LoadError{Path: filename, Err: err}
+	}
+	want, ok := sums[filepath.ToSlash(rel)]
+	if !ok {
+		return &LoadError{Path: filename, Err: fmt.Errorf("%w: not in manifest %s", ErrChecksumMismatch, manifest)}
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return &LoadError{Path: filename, Err: fmt.Errorf("%w: sha256 %s, manifest %s", ErrChecksumMismatch, got, want)}
+	}
+	return nil
+}
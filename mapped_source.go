@@ -0,0 +1,238 @@
+package ii18n
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Type MappedSource serves the binary catalogs of NewBinarySource from
+// memory-mapped files, for catalogs of hundreds of megabytes: messages are
+// looked up in place and copied into Go strings only when translated, so the
+// catalogs take neither the time nor the heap of decoding. Keys are matched
+// as they are, without Config.NormalizeKeys. Signed, encrypted or
+// manifest-checked catalogs are not supported: NewMappedSource panics with
+// Config.VerifyKey, DecryptKeys or Manifest rather than serve them
+// unchecked. LoadMsgs and LoadCatalog decode the whole catalog.
+//
+// Mapped files must not change in place: deploy new catalogs by renaming
+// them over the old ones, then Reload.
+type MappedSource struct {
+	BinarySource
+	files map[string]*mappedCatalog
+	// filesMutex guards the mappings, which Reload unmaps.
+	filesMutex sync.RWMutex
+}
+
+// New MappedSource
+func NewMappedSource(conf *Config) Source {
+	if conf.VerifyKey != nil || conf.DecryptKeys != nil || conf.Manifest != "" {
+		panic("Config VerifyKey, DecryptKeys and Manifest are not supported by NewMappedSource")
+	}
+	s := &MappedSource{files: make(map[string]*mappedCatalog)}
+	s.init(conf, "bin", binaryCodec{})
+
+	return s
+}
+
+// mappedCatalog a mapped binary catalog, without data when the file does
+// not exist.
+type mappedCatalog struct {
+	category string
+	lang     string
+	data     []byte
+	entries  []byte
+	strs     []byte
+}
+
+// Translate
+func (s *MappedSource) Translate(category string, message string, lang string) (string, error) {
+	return s.TranslateContext(context.Background(), category, message, lang)
+}
+
+// TranslateContext Translate; the context is not used, mapped catalogs
+// being read without loads to trace.
+func (s *MappedSource) TranslateContext(ctx context.Context, category string, message string, lang string) (string, error) {
+	if s.ForceTranslation || lang != s.OriginalLang {
+		return s.TranslateMsg(category, message, lang)
+	}
+	return "", nil
+}
+
+// TranslateMsg Returns the first non-empty translation of message in the
+// catalogs of lang and its fallbacks, in the order LoadMsgs merges them.
+func (s *MappedSource) TranslateMsg(category string, message string, lang string) (string, error) {
+	if _, _, err := splitCategory(category); err != nil {
+		return "", err
+	}
+	key := []byte(message)
+	for n, l := range s.fallbackChain(lang) {
+		val, err := s.lookup(category, l, key)
+		if errors.Is(err, ErrCatalogNotFound) {
+			if n == 0 && s.missingFiles == ErrorOnMissingFile && l != s.OriginalLang {
+				return "", err
+			}
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if val != "" {
+			return val, nil
+		}
+	}
+	return "", &MissingTranslationError{Category: category, Message: message, Lang: lang}
+}
+
+// TranslateBatch Returns the translations of messages in lang, as
+// TranslateMsg.
+func (s *MappedSource) TranslateBatch(category string, messages []string, lang string) (TMsgs, error) {
+	translations := make(TMsgs, len(messages))
+	if !s.ForceTranslation && lang == s.OriginalLang {
+		return translations, nil
+	}
+	var missing *MissingTranslationError
+	for _, message := range messages {
+		val, err := s.TranslateMsg(category, message, lang)
+		if errors.As(err, &missing) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		translations[message] = val
+	}
+	return translations, nil
+}
+
+// lookup Returns a copy of the value of key in the catalog of category and
+// lang, mapping it on first use.
+func (s *MappedSource) lookup(category string, lang string, key []byte) (string, error) {
+	filename := s.GetMsgFilePath(category, lang)
+	s.filesMutex.RLock()
+	if c, ok := s.files[filename]; ok {
+		defer s.filesMutex.RUnlock()
+		if c.data == nil {
+			return "", &LoadError{Path: filename, Err: ErrCatalogNotFound}
+		}
+		val, _ := c.lookup(key)
+		return string(val), nil
+	}
+	s.filesMutex.RUnlock()
+
+	s.filesMutex.Lock()
+	if _, ok := s.files[filename]; !ok {
+		c, err := mapCatalog(filename)
+		if errors.Is(err, ErrCatalogNotFound) {
+			c, err = &mappedCatalog{}, nil
+		}
+		if err != nil {
+			s.filesMutex.Unlock()
+			return "", err
+		}
+		c.category, c.lang = category, lang
+		s.files[filename] = c
+	}
+	s.filesMutex.Unlock()
+	return s.lookup(category, lang, key)
+}
+
+// preload Maps the catalogs of category in lang and its fallbacks.
+func (s *MappedSource) preload(ctx context.Context, category string, lang string) error {
+	for _, l := range s.fallbackChain(lang) {
+		if _, err := s.lookup(category, l, nil); err != nil && !errors.Is(err, ErrCatalogNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapCatalog Maps the binary catalog filename, nil with ErrCatalogNotFound
+// when it does not exist.
+func mapCatalog(filename string) (*mappedCatalog, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	if info.Size() < int64(binaryHeaderSize) || int64(int(info.Size())) != info.Size() {
+		return nil, &LoadError{Path: filename, Err: errors.New("not a binary catalog")}
+	}
+	data, err := mmapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	entries, strs, err := binaryEntries(data)
+	if err != nil {
+		munmapFile(data)
+		return nil, &LoadError{Path: filename, Err: err}
+	}
+	return &mappedCatalog{data: data, entries: entries, strs: strs}, nil
+}
+
+// lookup Returns the value of key in c, in the mapping.
+func (c *mappedCatalog) lookup(key []byte) ([]byte, bool) {
+	n := len(c.entries) / 16
+	pos := sort.Search(n, func(i int) bool {
+		k, _ := c.str(c.entries[i*16:])
+		return bytes.Compare(k, key) >= 0
+	})
+	if pos == n {
+		return nil, false
+	}
+	if k, _ := c.str(c.entries[pos*16:]); !bytes.Equal(k, key) {
+		return nil, false
+	}
+	return c.str(c.entries[pos*16+8:])
+}
+
+// str Returns the string of the offset and length at the start of e, false
+// when out of range.
+func (c *mappedCatalog) str(e []byte) ([]byte, bool) {
+	offset, length := uint64(binary.LittleEndian.Uint32(e)), uint64(binary.LittleEndian.Uint32(e[4:]))
+	if offset+length > uint64(len(c.strs)) {
+		return nil, false
+	}
+	return c.strs[offset : offset+length], true
+}
+
+// Reload Unmaps the catalogs, which are mapped again on their next use.
+func (s *MappedSource) Reload() error {
+	return s.unmap(func(*mappedCatalog) bool { return true })
+}
+
+// ReloadCatalog Unmaps the catalog of category in lang, of every language
+// when lang is empty. The languages falling back to lang read its catalog
+// in place, so they need no reload.
+func (s *MappedSource) ReloadCatalog(category string, lang string) error {
+	return s.unmap(func(c *mappedCatalog) bool {
+		return c.category == category && (lang == "" || c.lang == lang)
+	})
+}
+
+// unmap Unmaps the catalogs matched.
+func (s *MappedSource) unmap(match func(c *mappedCatalog) bool) error {
+	s.filesMutex.Lock()
+	defer s.filesMutex.Unlock()
+	var errs []error
+	for filename, c := range s.files {
+		if !match(c) {
+			continue
+		}
+		delete(s.files, filename)
+		if c.data != nil {
+			if err := munmapFile(c.data); err != nil {
+				errs = append(errs, &LoadError{Path: filename, Err: err})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package ii18n
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Type MemSource serves catalogs held in memory, for tests and catalogs
+// built at runtime. BasePath only prefixes catalog names.
+type MemSource struct {
+	MessageSource
+	catalogs map[string]TMsgs
+	mutex    sync.RWMutex
+}
+
+// NewMemSource Returns the SourceNewFunc of a MemSource serving catalogs,
+// keyed by "{lang}/{name}" with name the category without its prefix, e.g.
+// "de/admin.users" for "app.admin.users" in de. Saved messages are kept in
+// catalogs.
+func NewMemSource(catalogs map[string]TMsgs) func(*Config) Source {
+	return func(conf *Config) Source {
+		s := &MemSource{catalogs: catalogs}
+		s.init(conf, "", nil)
+		s.loadFunc = s.loadMem
+		s.saveFunc = s.saveMem
+		return s
+	}
+}
+
+// memName Returns the catalog name of the message file path.
+func (s *MemSource) memName(filename string) string {
+	lang, name, _ := strings.Cut(strings.TrimPrefix(filename, s.BasePath+"/"), "/")
+	return lang + "/" + strings.ReplaceAll(name, "/", ".")
+}
+
+// loadMem Returns a copy of the catalog of the message file path.
+func (s *MemSource) loadMem(filename string) (TMsgs, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	msgs, ok := s.catalogs[s.memName(filename)]
+	if !ok {
+		return nil, &LoadError{Path: filename, Err: fs.ErrNotExist}
+	}
+	copied := make(TMsgs, len(msgs))
+	for key, val := range msgs {
+		copied[key] = val
+	}
+	return copied, nil
+}
+
+// saveMem Replaces the catalog of the message file path with msgs.
+func (s *MemSource) saveMem(filename string, lang string, msgs TMsgs) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.catalogs[s.memName(filename)] = msgs
+	return nil
+}
+
+// Returns the languages with catalogs, sorted.
+func (s *MemSource) AvailableLanguages() ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var langs []string
+	for key := range s.catalogs {
+		lang, _, _ := strings.Cut(key, "/")
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs, nil
+}
+
+// Returns the names of the catalogs of every language, sorted.
+func (s *MemSource) CategoryNames() ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var names []string
+	for key := range s.catalogs {
+		_, name, _ := strings.Cut(key, "/")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
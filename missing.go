@@ -0,0 +1,74 @@
+package ii18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// missingRecorder collects keys without translation and writes them as empty
+// entries, either into the catalogs through the Saver interface or into a
+// sidecar file per language.
+type missingRecorder struct {
+	seen  map[string]bool
+	mutex sync.Mutex
+}
+
+// record Writes message as missing for category and lang, once per process.
+func (r *missingRecorder) record(conf *Config, category string, message string, lang string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := category + "/" + lang + "/" + message
+	if r.seen[key] {
+		return nil
+	}
+	var err error
+	if conf.MissingSidecar {
+		err = r.writeSidecar(conf.BasePath+"/missing_"+lang+".json", category, message)
+	} else if saver, ok := conf.source.(Saver); ok {
+		err = saver.SaveMsgs(category, lang, TMsgs{message: ""})
+	} else {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if r.seen == nil {
+		r.seen = make(map[string]bool)
+	}
+	r.seen[key] = true
+	return nil
+}
+
+// writeSidecar Adds message to the sidecar file, grouped by category.
+// encoding/json sorts map keys, so the file stays stable between runs.
+func (r *missingRecorder) writeSidecar(filename string, category string, message string) error {
+	missing := make(map[string]TMsgs)
+	data, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &missing); err != nil {
+			return err
+		}
+	}
+	if missing[category] == nil {
+		missing[category] = make(TMsgs)
+	}
+	if _, ok := missing[category][message]; ok {
+		return nil
+	}
+	missing[category][message] = ""
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(missing); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b.Bytes(), 0644)
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package ii18n
+
+import (
+	"io"
+	"os"
+)
+
+// mmapFile Reads the size bytes of f, on systems without mmap.
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// munmapFile Releases data, read by mmapFile.
+func munmapFile(data []byte) error {
+	return nil
+}
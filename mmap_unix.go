@@ -0,0 +1,18 @@
+//go:build unix
+
+package ii18n
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile Maps the size bytes of f read-only.
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmapFile Unmaps data, mapped by mmapFile.
+func munmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
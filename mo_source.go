@@ -0,0 +1,130 @@
+package ii18n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// Type MOSource reads compiled gettext MO catalogs.
+type MOSource struct {
+	MessageSource
+}
+
+// New MOSource
+func NewMOSource(conf *Config) Source {
+	s := &MOSource{}
+	s.init(conf, "mo", moCodec{})
+
+	return s
+}
+
+// moMagic magic number of MO files.
+const moMagic = 0x950412de
+
+// moCodec gettext MO files. Context prefixes are dropped from keys and
+// plural entries become a pluralforms argument on n selected by the
+// Plural-Forms header.
+type moCodec struct{}
+
+func (moCodec) Decode(data []byte) (TMsgs, error) {
+	if len(data) < 28 {
+		return nil, errors.New("mo file too short")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint32(data) != moMagic {
+		order = binary.BigEndian
+		if order.Uint32(data) != moMagic {
+			return nil, errors.New("not a mo file")
+		}
+	}
+	n := order.Uint32(data[8:])
+	origTable, transTable := order.Uint32(data[12:]), order.Uint32(data[16:])
+	str := func(table uint32, i uint32) (string, error) {
+		pos := uint64(table) + uint64(i)*8
+		if pos+8 > uint64(len(data)) {
+			return "", errors.New("mo string table out of range")
+		}
+		length, offset := uint64(order.Uint32(data[pos:])), uint64(order.Uint32(data[pos+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", errors.New("mo string out of range")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+	msgs := make(TMsgs, n)
+	pluralForms := defaultPluralForms
+	plurals := make(map[string][]string)
+	for i := uint32(0); i < n; i++ {
+		id, err := str(origTable, i)
+		if err != nil {
+			return nil, err
+		}
+		val, err := str(transTable, i)
+		if err != nil {
+			return nil, err
+		}
+		if pos := strings.IndexByte(id, 4); pos != -1 {
+			id = id[pos+1:]
+		}
+		if id == "" {
+			for _, line := range strings.Split(val, "\n") {
+				if key, field, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == "Plural-Forms" {
+					pluralForms = strings.TrimSpace(field)
+				}
+			}
+			continue
+		}
+		if pos := strings.IndexByte(id, 0); pos != -1 {
+			id = id[:pos]
+			if forms := strings.Split(val, "\x00"); len(forms) > 1 {
+				plurals[id] = forms
+			}
+		}
+		if pos := strings.IndexByte(val, 0); pos != -1 {
+			val = val[:pos]
+		}
+		msgs[id] = val
+	}
+	for id, forms := range plurals {
+		msgs[id] = gettextPattern(pluralForms, forms)
+	}
+	return msgs, nil
+}
+
+// Encode Encodes a little-endian MO file without hash table.
+func (moCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	header := "Content-Type: text/plain; charset=UTF-8\n"
+	if lang != "" {
+		header += "Language: " + strings.Replace(lang, "-", "_", 1) + "\n"
+	}
+	ids := append([]string{""}, sortedKeys(msgs)...)
+	vals := make([]string, len(ids))
+	vals[0] = header
+	for i, id := range ids[1:] {
+		vals[i+1] = msgs[id]
+	}
+	n := uint32(len(ids))
+	origTable := uint32(28)
+	transTable := origTable + n*8
+	offset := transTable + n*8
+
+	var table, strs bytes.Buffer
+	write := func(s string) {
+		binary.Write(&table, binary.LittleEndian, [2]uint32{uint32(len(s)), offset})
+		strs.WriteString(s)
+		strs.WriteByte(0)
+		offset += uint32(len(s)) + 1
+	}
+	for _, id := range ids {
+		write(id)
+	}
+	for _, val := range vals {
+		write(val)
+	}
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, [7]uint32{moMagic, 0, n, origTable, transTable, 0, offset})
+	b.Write(table.Bytes())
+	b.Write(strs.Bytes())
+	return b.Bytes(), nil
+}
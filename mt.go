@@ -0,0 +1,214 @@
+package ii18n
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MTProvider translates text with a machine translation service. See the mt
+// package for Google and DeepL implementations.
+type MTProvider interface {
+	Translate(ctx context.Context, text string, from string, to string) (string, error)
+}
+
+// NewMTHandler returns a MissingHandler translating missing messages with
+// provider in the background: the first miss of a message starts a request
+// and falls through to the original message, so T never waits on the
+// network, and the misses after the translation arrived are served from a
+// cache kept for the lifetime of the handler. At most perSecond requests are
+// sent to the provider, no limit when perSecond <= 0; misses over the limit
+// start no request. Failed requests are retried on a later miss. Messages
+// are sent with TranslatePattern, so their arguments are kept.
+func NewMTHandler(provider MTProvider, perSecond float64) MissingHandler {
+	h := &mtHandler{
+		provider: provider,
+		cache:    make(map[string]string),
+		pending:  make(map[string]bool),
+	}
+	if perSecond > 0 {
+		h.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return h.handle
+}
+
+// mtTimeout bounds a background request to the provider.
+const mtTimeout = 30 * time.Second
+
+// mtHandler machine translation with caching and rate limiting.
+type mtHandler struct {
+	provider MTProvider
+	cache    map[string]string
+	// pending the messages being translated.
+	pending  map[string]bool
+	interval time.Duration
+	next     time.Time
+	mutex    sync.Mutex
+}
+
+func (h *mtHandler) handle(ctx context.Context, miss Miss) (string, bool) {
+	key := miss.OriginalLang + "/" + miss.Lang + "/" + miss.Message
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if val, ok := h.cache[key]; ok {
+		return val, true
+	}
+	now := time.Now()
+	if h.pending[key] || now.Before(h.next) {
+		return "", false
+	}
+	h.next = now.Add(h.interval)
+	h.pending[key] = true
+	// The request outlives the translation that missed, not its values.
+	go h.fetch(context.WithoutCancel(ctx), key, miss)
+	return "", false
+}
+
+// fetch Translates the missed message and caches the result under key.
+func (h *mtHandler) fetch(ctx context.Context, key string, miss Miss) {
+	ctx, cancel := context.WithTimeout(ctx, mtTimeout)
+	defer cancel()
+	val, err := TranslatePattern(ctx, h.provider, miss.Message, miss.OriginalLang, miss.Lang)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.pending, key)
+	if err == nil && val != "" {
+		h.cache[key] = val
+	}
+}
+
+// mtToken matches the tokens standing for the arguments of a message sent to
+// a provider.
+var mtToken = regexp.MustCompile(`\{([0-9]+)\}`)
+
+// TranslatePattern Translates the ICU message pattern from the language from
+// to to with provider, keeping its arguments: simple arguments and '#' are
+// sent as the tokens {0}, {1}..., and the messages of plural and select
+// arguments are translated one by one and put back in their options. A
+// translation that drops, repeats or adds tokens returns an error wrapping
+// ErrMTPlaceholders. Text that is not a valid pattern is sent as it is.
+func TranslatePattern(ctx context.Context, provider MTProvider, pattern string, from string, to string) (string, error) {
+	nodes, err := parsePattern(pattern)
+	if err != nil {
+		return provider.Translate(ctx, pattern, from, to)
+	}
+	val, err := translateNodes(ctx, provider, nodes, false, from, to)
+	if err != nil {
+		return "", err
+	}
+	expected, _ := Placeholders(pattern)
+	if actual, err := Placeholders(val); err != nil || !slices.Equal(actual, expected) {
+		return "", fmt.Errorf("%w: %q", ErrMTPlaceholders, val)
+	}
+	return val, nil
+}
+
+// translateNodes Translates the message nodes, returning the pattern of the
+// translation. inPlural tells whether nodes are an option of a plural
+// argument, where '#' is special.
+func translateNodes(ctx context.Context, provider MTProvider, nodes []Node, inPlural bool, from string, to string) (string, error) {
+	var text strings.Builder
+	var args []string
+	hasText := false
+	for _, n := range nodes {
+		switch n.Kind {
+		case TextNode:
+			text.WriteString(n.Text)
+			hasText = hasText || strings.TrimSpace(n.Text) != ""
+			continue
+		case PoundNode:
+			args = append(args, "#")
+		default:
+			arg, err := translateArg(ctx, provider, n, from, to)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+		text.WriteString("{" + strconv.Itoa(len(args)-1) + "}")
+	}
+	val := text.String()
+	if hasText {
+		var err error
+		if val, err = provider.Translate(ctx, val, from, to); err != nil {
+			return "", err
+		}
+	}
+	var b strings.Builder
+	used := make([]bool, len(args))
+	last := 0
+	for _, m := range mtToken.FindAllStringSubmatchIndex(val, -1) {
+		i, err := strconv.Atoi(val[m[2]:m[3]])
+		if err != nil || i >= len(args) || used[i] {
+			return "", fmt.Errorf("%w: %q", ErrMTPlaceholders, val)
+		}
+		used[i] = true
+		b.WriteString(quotePatternText(val[last:m[0]], inPlural))
+		b.WriteString(args[i])
+		last = m[1]
+	}
+	if slices.Contains(used, false) {
+		return "", fmt.Errorf("%w: %q", ErrMTPlaceholders, val)
+	}
+	b.WriteString(quotePatternText(val[last:], inPlural))
+	return b.String(), nil
+}
+
+// translateArg Returns the pattern of the argument n, with the messages of
+// its options translated.
+func translateArg(ctx context.Context, provider MTProvider, n Node, from string, to string) (string, error) {
+	b := strings.Builder{}
+	b.WriteString("{" + n.Name)
+	if n.Type != "" {
+		b.WriteString(", " + n.Type)
+	}
+	if !isChoiceType(n.Type) {
+		if n.Style != "" {
+			b.WriteString(", " + n.Style)
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	}
+	b.WriteString(",")
+	if n.Type == "pluralforms" {
+		b.WriteString(" " + n.Style + ",")
+	}
+	if n.Offset > 0 {
+		b.WriteString(" offset:" + strconv.Itoa(n.Offset))
+	}
+	for _, v := range n.Variants {
+		val, err := translateNodes(ctx, provider, v.Nodes, n.Type != "select", from, to)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(" " + v.Selector + " {" + val + "}")
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// quotePatternText Returns the literal text s quoted for a message pattern:
+// braces, and '#' in plural options, are quoted, and apostrophes that would
+// start a quote are doubled.
+func quotePatternText(s string, inPlural bool) string {
+	special := func(c byte) bool {
+		return c == '{' || c == '}' || c == '|' || c == '\'' || c == '#' && inPlural
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' && i+1 < len(s) && special(s[i+1]):
+			b.WriteString("''")
+		case c != '\'' && c != '|' && special(c):
+			b.WriteString("'" + string(c) + "'")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+// Package awstranslate provides an Amazon Translate provider for
+// ii18n.NewMTHandler and "ii18n mt-fill", with the credentials and region of
+// the standard AWS SDK chain: environment, shared config and profiles, web
+// identity, container and instance roles.
+//
+//	p, err := awstranslate.New(ctx)
+//	i := ii18n.NewI18N(config, ii18n.WithMissingHandler(ii18n.NewMTHandler(p, 5)))
+package awstranslate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/translate"
+)
+
+// regional the language codes Amazon Translate accepts with their region;
+// other languages are sent without it.
+var regional = map[string]bool{
+	"es-MX": true, "fr-CA": true, "pt-PT": true, "zh-TW": true,
+}
+
+// Translator translates with Amazon Translate.
+type Translator struct {
+	Client *translate.Client
+}
+
+// New returns a Translator loading the default AWS configuration, adjusted
+// by opts, e.g. config.WithRegion("eu-west-1").
+func New(ctx context.Context, opts ...func(*config.LoadOptions) error) (*Translator, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromConfig(cfg), nil
+}
+
+// NewFromConfig returns a Translator of the AWS configuration cfg.
+func NewFromConfig(cfg aws.Config) *Translator {
+	return &Translator{Client: translate.NewFromConfig(cfg)}
+}
+
+// Translate implements ii18n.MTProvider.
+func (t *Translator) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	out, err := t.Client.TranslateText(ctx, &translate.TranslateTextInput{
+		Text:               aws.String(text),
+		SourceLanguageCode: aws.String(languageCode(from)),
+		TargetLanguageCode: aws.String(languageCode(to)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.TranslatedText == nil {
+		return "", fmt.Errorf("mt: amazon translate returned no translation")
+	}
+	return *out.TranslatedText, nil
+}
+
+// languageCode Returns the Amazon Translate code of lang, "zh-TW" for
+// "zh-Hant" and the language without region unless it is regional.
+func languageCode(lang string) string {
+	lang = strings.ReplaceAll(lang, "_", "-")
+	if strings.EqualFold(lang, "zh-Hant") || strings.HasPrefix(lang, "zh-Hant-") {
+		return "zh-TW"
+	}
+	if regional[lang] {
+		return lang
+	}
+	return strings.SplitN(lang, "-", 2)[0]
+}
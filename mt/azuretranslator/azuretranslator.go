@@ -0,0 +1,131 @@
+// Package azuretranslator provides an Azure AI Translator provider for
+// ii18n.NewMTHandler and "ii18n mt-fill", authenticating with a resource key
+// or with Microsoft Entra ID through the standard Azure SDK chain:
+// environment, workload identity, managed identity and developer tools.
+//
+//	p, err := azuretranslator.NewDefault(resourceID, "westeurope")
+//	i := ii18n.NewI18N(config, ii18n.WithMissingHandler(ii18n.NewMTHandler(p, 5)))
+package azuretranslator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// DefaultEndpoint the global endpoint of the Translator API.
+const DefaultEndpoint = "https://api.cognitive.microsofttranslator.com"
+
+// scope the scope of the Entra ID tokens of Azure AI services.
+const scope = "https://cognitiveservices.azure.com/.default"
+
+// targets the language codes Translator accepts with their script or
+// region, by lower case ii18n language; other languages are sent without
+// them.
+var targets = map[string]string{
+	"fr-ca": "fr-CA", "pt-pt": "pt-PT", "pt-br": "pt",
+	"zh-hans": "zh-Hans", "zh-cn": "zh-Hans", "zh-hant": "zh-Hant", "zh-tw": "zh-Hant",
+	"sr-latn": "sr-Latn", "sr-cyrl": "sr-Cyrl",
+}
+
+// Translator translates with Azure AI Translator.
+type Translator struct {
+	Endpoint string
+	// Region of the resource, required for regional and multi-service
+	// resources.
+	Region string
+	// Key a key of the resource, or else Credential and ResourceID.
+	Key        string
+	Credential azcore.TokenCredential
+	// ResourceID the Azure resource ID of the Translator resource, required
+	// with Credential.
+	ResourceID string
+	Client     *http.Client
+}
+
+// New returns a Translator authenticating with a key of the resource in
+// region.
+func New(key string, region string) *Translator {
+	return &Translator{Endpoint: DefaultEndpoint, Region: region, Key: key, Client: http.DefaultClient}
+}
+
+// NewDefault returns a Translator of the resource resourceID in region,
+// authenticating with the default Azure credential chain.
+func NewDefault(resourceID string, region string) (*Translator, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Translator{Endpoint: DefaultEndpoint, Region: region, Credential: cred, ResourceID: resourceID, Client: http.DefaultClient}, nil
+}
+
+// Translate implements ii18n.MTProvider.
+func (t *Translator) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	body, err := json.Marshal([]map[string]string{{"Text": text}})
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{"api-version": {"3.0"}, "from": {languageCode(from)}, "to": {languageCode(to)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(t.Endpoint, "/")+"/translate?"+query.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.Region != "" {
+		req.Header.Set("Ocp-Apim-Subscription-Region", t.Region)
+	}
+	if t.Credential != nil {
+		tok, err := t.Credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{scope}})
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+tok.Token)
+		req.Header.Set("Ocp-Apim-ResourceId", t.ResourceID)
+	} else {
+		req.Header.Set("Ocp-Apim-Subscription-Key", t.Key)
+	}
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mt: %s %s: %s", req.Method, req.URL.Host, resp.Status)
+	}
+	var res []struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", err
+	}
+	if len(res) == 0 || len(res[0].Translations) == 0 {
+		return "", fmt.Errorf("mt: azure translator returned no translation")
+	}
+	return res[0].Translations[0].Text, nil
+}
+
+// languageCode Returns the Translator code of lang.
+func languageCode(lang string) string {
+	lang = strings.ReplaceAll(lang, "_", "-")
+	lower := strings.ToLower(lang)
+	for prefix, code := range targets {
+		if lower == prefix || strings.HasPrefix(lower, prefix+"-") {
+			return code
+		}
+	}
+	return strings.SplitN(lang, "-", 2)[0]
+}
@@ -0,0 +1,72 @@
+package mt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DeepL endpoints for free and pro accounts.
+const (
+	DeepLFreeEndpoint = "https://api-free.deepl.com/v2/translate"
+	DeepLProEndpoint  = "https://api.deepl.com/v2/translate"
+)
+
+// deeplTargets target languages DeepL only accepts with their region.
+var deeplTargets = map[string]bool{
+	"EN-GB": true, "EN-US": true, "PT-BR": true, "PT-PT": true, "ZH-HANS": true, "ZH-HANT": true,
+}
+
+// DeepL translates with the DeepL API.
+type DeepL struct {
+	AuthKey  string
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewDeepL returns a DeepL provider. Keys of free accounts end in ":fx" and
+// use the free endpoint.
+func NewDeepL(authKey string) *DeepL {
+	endpoint := DeepLProEndpoint
+	if strings.HasSuffix(authKey, ":fx") {
+		endpoint = DeepLFreeEndpoint
+	}
+	return &DeepL{AuthKey: authKey, Endpoint: endpoint, Client: http.DefaultClient}
+}
+
+// Translate implements ii18n.MTProvider.
+func (d *DeepL) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	target := strings.ToUpper(to)
+	if !deeplTargets[target] {
+		target = strings.SplitN(target, "-", 2)[0]
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"text":        []string{text},
+		"source_lang": strings.ToUpper(strings.SplitN(from, "-", 2)[0]),
+		"target_lang": target,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.AuthKey)
+	var res struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := do(d.Client, req, &res); err != nil {
+		return "", err
+	}
+	if len(res.Translations) == 0 {
+		return "", fmt.Errorf("mt: deepl returned no translation")
+	}
+	return res.Translations[0].Text, nil
+}
@@ -0,0 +1,75 @@
+// Package mt provides machine translation providers for ii18n.NewMTHandler.
+package mt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+)
+
+// GoogleEndpoint Cloud Translation API v2 endpoint.
+const GoogleEndpoint = "https://translation.googleapis.com/language/translate/v2"
+
+// Google translates with the Google Cloud Translation API (v2).
+type Google struct {
+	APIKey   string
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewGoogle returns a Google provider authenticating with apiKey.
+func NewGoogle(apiKey string) *Google {
+	return &Google{APIKey: apiKey, Endpoint: GoogleEndpoint, Client: http.DefaultClient}
+}
+
+// Translate implements ii18n.MTProvider.
+func (g *Google) Translate(ctx context.Context, text string, from string, to string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"q":      text,
+		"source": from,
+		"target": to,
+		"format": "text",
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.Endpoint+"?key="+url.QueryEscape(g.APIKey), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var res struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	if err := do(g.Client, req, &res); err != nil {
+		return "", err
+	}
+	if len(res.Data.Translations) == 0 {
+		return "", fmt.Errorf("mt: google returned no translation")
+	}
+	return html.UnescapeString(res.Data.Translations[0].TranslatedText), nil
+}
+
+// do Sends req and decodes a JSON response into v.
+func do(client *http.Client, req *http.Request, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mt: %s %s: %s", req.Method, req.URL.Host, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
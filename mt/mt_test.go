@@ -0,0 +1,145 @@
+package mt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// request a request received by a fake provider.
+type request struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	body   map[string]interface{}
+}
+
+// fakeServer Returns a server recording its requests in last and replying
+// status and body.
+func fakeServer(t *testing.T, last *request, status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		*last = request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, header: r.Header}
+		if err := json.Unmarshal(data, &last.body); err != nil {
+			t.Errorf("request body %q: %v", data, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewDeepL(t *testing.T) {
+	if d := NewDeepL("key:fx"); d.Endpoint != DeepLFreeEndpoint {
+		t.Errorf("NewDeepL() of a free key = %s", d.Endpoint)
+	}
+	if d := NewDeepL("key"); d.Endpoint != DeepLProEndpoint {
+		t.Errorf("NewDeepL() of a pro key = %s", d.Endpoint)
+	}
+}
+
+func TestDeepL(t *testing.T) {
+	var last request
+	server := fakeServer(t, &last, http.StatusOK, `{"translations": [{"detected_source_language": "EN", "text": "Hallo {0}"}]}`)
+	d := &DeepL{AuthKey: "secret", Endpoint: server.URL + "/v2/translate"}
+	got, err := d.Translate(context.Background(), "Hello {0}", "en-US", "de-AT")
+	if err != nil || got != "Hallo {0}" {
+		t.Fatalf("Translate() = %q, %v", got, err)
+	}
+	if last.method != http.MethodPost || last.path != "/v2/translate" {
+		t.Errorf("request = %s %s", last.method, last.path)
+	}
+	if auth := last.header.Get("Authorization"); auth != "DeepL-Auth-Key secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	expected := map[string]interface{}{"text": []interface{}{"Hello {0}"}, "source_lang": "EN", "target_lang": "DE"}
+	if !reflect.DeepEqual(last.body, expected) {
+		t.Errorf("request body = %v, want %v", last.body, expected)
+	}
+
+	// Some targets keep their region.
+	tests := map[string]string{"pt-br": "PT-BR", "zh-Hant": "ZH-HANT", "en-GB": "EN-GB", "fr-CA": "FR"}
+	for to, target := range tests {
+		if _, err := d.Translate(context.Background(), "Hello", "en", to); err != nil || last.body["target_lang"] != target {
+			t.Errorf("Translate() to %s: target_lang = %v, %v, want %s", to, last.body["target_lang"], err, target)
+		}
+	}
+}
+
+func TestDeepLErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		err    string
+	}{
+		{http.StatusOK, `{"translations": []}`, "mt: deepl returned no translation"},
+		{http.StatusForbidden, `{"message": "Wrong key"}`, "403 Forbidden"},
+		{456, `{"message": "Quota exceeded"}`, "456"},
+		{http.StatusOK, `<html>`, "invalid character"},
+	}
+	for _, test := range tests {
+		var last request
+		server := fakeServer(t, &last, test.status, test.body)
+		d := &DeepL{AuthKey: "secret", Endpoint: server.URL}
+		got, err := d.Translate(context.Background(), "Hello", "en", "de")
+		if err == nil || !strings.Contains(err.Error(), test.err) || got != "" {
+			t.Errorf("Translate() of %d %s = %q, %v, want %q", test.status, test.body, got, err, test.err)
+		}
+		if err != nil && strings.Contains(err.Error(), "secret") {
+			t.Errorf("Translate() error %q holds the key", err)
+		}
+	}
+}
+
+func TestGoogle(t *testing.T) {
+	var last request
+	server := fakeServer(t, &last, http.StatusOK, `{"data": {"translations": [{"translatedText": "Tom &amp; Jerry&#39;s {0}"}]}}`)
+	g := NewGoogle("k&1")
+	g.Endpoint = server.URL + "/language/translate/v2"
+	got, err := g.Translate(context.Background(), "Tom & Jerry's {0}", "en", "de")
+	if err != nil || got != "Tom & Jerry's {0}" {
+		t.Fatalf("Translate() = %q, %v, want the text unescaped", got, err)
+	}
+	if last.method != http.MethodPost || last.path != "/language/translate/v2" || last.query != "key=k%261" {
+		t.Errorf("request = %s %s?%s", last.method, last.path, last.query)
+	}
+	if ct := last.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	expected := map[string]interface{}{"q": "Tom & Jerry's {0}", "source": "en", "target": "de", "format": "text"}
+	if !reflect.DeepEqual(last.body, expected) {
+		t.Errorf("request body = %v, want %v", last.body, expected)
+	}
+}
+
+func TestGoogleErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		err    string
+	}{
+		{http.StatusOK, `{"data": {"translations": []}}`, "mt: google returned no translation"},
+		{http.StatusBadRequest, `{"error": {"message": "API key not valid"}}`, "400 Bad Request"},
+		{http.StatusTooManyRequests, `{}`, "429 Too Many Requests"},
+	}
+	for _, test := range tests {
+		var last request
+		server := fakeServer(t, &last, test.status, test.body)
+		g := &Google{APIKey: "secret", Endpoint: server.URL}
+		got, err := g.Translate(context.Background(), "Hello", "en", "de")
+		if err == nil || !strings.Contains(err.Error(), test.err) || got != "" {
+			t.Errorf("Translate() of %d %s = %q, %v, want %q", test.status, test.body, got, err, test.err)
+		}
+		if err != nil && strings.Contains(err.Error(), "secret") {
+			t.Errorf("Translate() error %q holds the key", err)
+		}
+	}
+}
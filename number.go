@@ -0,0 +1,281 @@
+package ii18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode how numbers are rounded to their maximum fraction digits.
+type RoundingMode int
+
+// Rounding modes, as in ICU.
+const (
+	// RoundHalfEven rounds ties to the even neighbour, the CLDR default.
+	RoundHalfEven RoundingMode = iota
+	RoundHalfUp
+	RoundHalfDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundDown truncates towards zero.
+	RoundDown
+	RoundCeiling
+	RoundFloor
+)
+
+// NumberOptions options of FormatNumber.
+type NumberOptions struct {
+	MinFractionDigits int
+	// MaxFractionDigits below MinFractionDigits is MinFractionDigits.
+	MaxFractionDigits int
+	// NoGrouping disables grouping separators.
+	NoGrouping   bool
+	RoundingMode RoundingMode
+}
+
+// defaultNumberOptions options used when none are given.
+var defaultNumberOptions = NumberOptions{MaxFractionDigits: 3}
+
+// numberSymbols CLDR number symbols of a locale.
+type numberSymbols struct {
+	decimal string
+	group   string
+	minus   string
+	// primary and secondary grouping sizes, 3;3 for most locales and 3;2
+	// for Indian locales.
+	primary   int
+	secondary int
+	// minGrouping minimum integer digits before grouping is used, 2 means
+	// 1234 stays ungrouped.
+	minGrouping int
+	// digits native digits zero to nine, empty for ASCII digits.
+	digits string
+}
+
+// numberData number symbols by locale, resolved from the most specific tag.
+var numberData = map[string]numberSymbols{
+	"en":    {".", ",", "-", 3, 3, 1, ""},
+	"en-IN": {".", ",", "-", 3, 2, 1, ""},
+}
+
+// lookupLocale Returns the entry of data for the most specific match of
+// lang: the full tag, then the language with its script or region, then the
+// language alone, then fallback.
+func lookupLocale[T any](data map[string]T, lang string, fallback string) T {
+	lang = strings.Replace(lang, "_", "-", -1)
+	for tag := lang; tag != ""; {
+		if val, ok := data[tag]; ok {
+			return val
+		}
+		pos := strings.LastIndex(tag, "-")
+		if pos == -1 {
+			break
+		}
+		tag = tag[:pos]
+	}
+	return data[fallback]
+}
+
+// FormatNumber Formats value, an integer, float or decimal string, with the
+// symbols and grouping of lang. A nil opts uses grouping with up to three
+// fraction digits rounded half-even.
+func FormatNumber(value interface{}, lang string, opts *NumberOptions) (string, error) {
+	d, err := toDecimal(value)
+	if err != nil {
+		return "", err
+	}
+	if opts == nil {
+		opts = &defaultNumberOptions
+	}
+	if opts.MinFractionDigits < 0 || opts.MaxFractionDigits < 0 {
+		return "", fmt.Errorf("ii18n: negative fraction digits %d, %d", opts.MinFractionDigits, opts.MaxFractionDigits)
+	}
+	return formatDecimal(d, lookupLocale(numberData, lang, "en"), opts), nil
+}
+
+// decimal an exact decimal number: its digits without leading zeros in the
+// integer part and the fraction digits.
+type decimal struct {
+	neg  bool
+	intg string
+	frac string
+}
+
+// toDecimal Converts integers, floats and numeric strings to a decimal.
+func toDecimal(value interface{}) (decimal, error) {
+	var s string
+	switch v := value.(type) {
+	case int:
+		s = strconv.Itoa(v)
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		s = strings.TrimSpace(v)
+	default:
+		return decimal{}, fmt.Errorf("%w: cannot format %T", ErrInvalidNumber, value)
+	}
+	return parseDecimal(s)
+}
+
+// parseDecimal Parses a decimal string such as "-1234.50" or "1.5e3".
+func parseDecimal(s string) (decimal, error) {
+	var d decimal
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return d, fmt.Errorf("%w %q", ErrInvalidNumber, s)
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if strings.HasPrefix(s, "-") {
+		d.neg, s = true, s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+	intg, frac := s, ""
+	if pos := strings.IndexByte(s, '.'); pos != -1 {
+		intg, frac = s[:pos], s[pos+1:]
+	}
+	if intg == "" && frac == "" {
+		return d, fmt.Errorf("%w %q", ErrInvalidNumber, s)
+	}
+	for _, part := range []string{intg, frac} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return d, fmt.Errorf("%w %q", ErrInvalidNumber, s)
+			}
+		}
+	}
+	d.intg = strings.TrimLeft(intg, "0")
+	d.frac = frac
+	return d, nil
+}
+
+// isZero Whether all digits of d are zero.
+func (d decimal) isZero() bool {
+	return d.intg == "" && strings.Trim(d.frac, "0") == ""
+}
+
+// round Returns d rounded to at most n fraction digits.
+func (d decimal) round(n int, mode RoundingMode) decimal {
+	if len(d.frac) <= n {
+		return d
+	}
+	kept, dropped := d.frac[:n], d.frac[n:]
+	up := false
+	nonZeroDropped := strings.Trim(dropped, "0") != ""
+	switch mode {
+	case RoundUp:
+		up = nonZeroDropped
+	case RoundDown:
+		up = false
+	case RoundCeiling:
+		up = nonZeroDropped && !d.neg
+	case RoundFloor:
+		up = nonZeroDropped && d.neg
+	default:
+		first, rest := dropped[0], strings.Trim(dropped[1:], "0") != ""
+		switch {
+		case first > '5' || (first == '5' && rest):
+			up = true
+		case first == '5':
+			switch mode {
+			case RoundHalfUp:
+				up = true
+			case RoundHalfEven:
+				last := byte('0')
+				if n > 0 {
+					last = kept[n-1]
+				} else if d.intg != "" {
+					last = d.intg[len(d.intg)-1]
+				}
+				up = (last-'0')%2 == 1
+			}
+		}
+	}
+	digits := []byte(d.intg + kept)
+	if up {
+		i := len(digits) - 1
+		for ; i >= 0; i-- {
+			if digits[i] != '9' {
+				digits[i]++
+				break
+			}
+			digits[i] = '0'
+		}
+		if i < 0 {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	split := len(digits) - n
+	return decimal{neg: d.neg, intg: strings.TrimLeft(string(digits[:split]), "0"), frac: string(digits[split:])}
+}
+
+// formatDecimal Formats d with symbols and opts.
+func formatDecimal(d decimal, symbols numberSymbols, opts *NumberOptions) string {
+	d = d.round(max(opts.MaxFractionDigits, opts.MinFractionDigits), opts.RoundingMode)
+	frac := strings.TrimRight(d.frac, "0")
+	for len(frac) < opts.MinFractionDigits {
+		frac += "0"
+	}
+	intg := d.intg
+	if intg == "" {
+		intg = "0"
+	}
+	var b strings.Builder
+	if d.neg && !(d.intg == "" && strings.Trim(frac, "0") == "") {
+		b.WriteString(symbols.minus)
+	}
+	b.WriteString(group(intg, symbols, opts.NoGrouping))
+	if frac != "" {
+		b.WriteString(symbols.decimal)
+		b.WriteString(frac)
+	}
+	return localizeDigits(b.String(), symbols.digits)
+}
+
+// group Inserts the group separator of symbols into the integer digits.
+func group(intg string, symbols numberSymbols, noGrouping bool) string {
+	if noGrouping || len(intg) < symbols.primary+symbols.minGrouping {
+		return intg
+	}
+	parts := []string{intg[len(intg)-symbols.primary:]}
+	rest := intg[:len(intg)-symbols.primary]
+	for len(rest) > symbols.secondary {
+		parts = append(parts, rest[len(rest)-symbols.secondary:])
+		rest = rest[:len(rest)-symbols.secondary]
+	}
+	if rest != "" {
+		parts = append(parts, rest)
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, symbols.group)
+}
+
+// localizeDigits Replaces ASCII digits of s by the native digits.
+func localizeDigits(s string, digits string) string {
+	if digits == "" {
+		return s
+	}
+	native := []rune(digits)
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return native[r-'0']
+		}
+		return r
+	}, s)
+}
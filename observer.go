@@ -0,0 +1,173 @@
+package ii18n
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Event identifies a kind of log record emitted by I18N.
+type Event int
+
+// Logged events.
+const (
+	// EventMissing a message has no translation in the requested language.
+	EventMissing Event = iota
+	// EventLoadFailed a catalog could not be loaded.
+	EventLoadFailed
+	// EventFallback messages of a fallback language were used.
+	EventFallback
+	// EventReload a source reloaded its catalogs.
+	EventReload
+	// EventReloadFailed a source failed to reload its catalogs.
+	EventReloadFailed
+	// EventRecordFailed a missing message could not be recorded.
+	EventRecordFailed
+	// EventInvalid a loaded message failed validation, see
+	// Config.ValidateOnLoad.
+	EventInvalid
+	// EventParams the params of a translation do not match its
+	// placeholders, see WithParamCheck.
+	EventParams
+)
+
+// defaultLevels log levels of events without a configured level.
+var defaultLevels = map[Event]slog.Level{
+	EventMissing:      slog.LevelDebug,
+	EventLoadFailed:   slog.LevelWarn,
+	EventFallback:     slog.LevelDebug,
+	EventReload:       slog.LevelInfo,
+	EventReloadFailed: slog.LevelError,
+	EventRecordFailed: slog.LevelError,
+	EventInvalid:      slog.LevelWarn,
+	EventParams:       slog.LevelWarn,
+}
+
+// Metrics receives translation activity. Implementations must be safe for
+// concurrent use; see the ii18nprom package for a Prometheus collector.
+type Metrics interface {
+	// Served is called for every message returned by T.
+	Served(category string, lang string)
+	// Missed is called when a message has no translation in lang.
+	Missed(category string, lang string)
+	// Loaded is called after every catalog load with its duration and result.
+	Loaded(category string, lang string, elapsed time.Duration, err error)
+	// CacheLookup is called for every catalog cache lookup of a source.
+	CacheLookup(hit bool)
+	// ReloadFailed is called when a source fails to reload its catalogs.
+	ReloadFailed(category string, err error)
+}
+
+// Tracer starts spans around catalog loads. See the ii18notel package for an
+// OpenTelemetry implementation.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is the part of a tracing span used by ii18n.
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// noopSpan is returned when no Tracer is configured.
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value any) {}
+func (noopSpan) RecordError(err error)              {}
+func (noopSpan) End()                               {}
+
+// observer fans events out to the optional hooks of an I18N. It is shared by
+// the manager and its sources; a nil observer ignores every event.
+type observer struct {
+	metrics Metrics
+	logger  *slog.Logger
+	levels  map[Event]slog.Level
+	tracer  Tracer
+}
+
+// tracing Whether spans are recorded.
+func (o *observer) tracing() bool {
+	return o != nil && o.tracer != nil
+}
+
+// startSpan Starts a span named name, a no-op span when tracing is off.
+func (o *observer) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if !o.tracing() {
+		return ctx, noopSpan{}
+	}
+	return o.tracer.Start(ctx, name)
+}
+
+// log Writes a record for event to the configured logger, slog.Default()
+// when none is set.
+func (o *observer) log(event Event, msg string, args ...any) {
+	if o == nil {
+		return
+	}
+	level, ok := o.levels[event]
+	if !ok {
+		level = defaultLevels[event]
+	}
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(context.Background(), level, msg, args...)
+}
+
+func (o *observer) served(category string, lang string) {
+	if o != nil && o.metrics != nil {
+		o.metrics.Served(category, lang)
+	}
+}
+
+func (o *observer) missed(category string, message string, lang string) {
+	if o != nil && o.metrics != nil {
+		o.metrics.Missed(category, lang)
+	}
+	o.log(EventMissing, "ii18n: missing translation", "category", category, "message", message, "lang", lang)
+}
+
+func (o *observer) loaded(category string, lang string, start time.Time, err error) {
+	if o != nil && o.metrics != nil {
+		o.metrics.Loaded(category, lang, time.Since(start), err)
+	}
+	if err != nil {
+		o.log(EventLoadFailed, "ii18n: catalog load failed", "category", category, "lang", lang, "error", err)
+	}
+}
+
+func (o *observer) fallback(category string, fallbackLang string) {
+	o.log(EventFallback, "ii18n: using fallback messages", "category", category, "fallback", fallbackLang)
+}
+
+func (o *observer) reloaded(category string) {
+	o.log(EventReload, "ii18n: catalogs reloaded", "category", category)
+}
+
+func (o *observer) recordFailed(category string, lang string, err error) {
+	o.log(EventRecordFailed, "ii18n: recording missing message failed", "category", category, "lang", lang, "error", err)
+}
+
+func (o *observer) invalid(category string, issue ValidationIssue) {
+	o.log(EventInvalid, "ii18n: invalid message", "category", category, "lang", issue.Lang, "key", issue.Key, "kind", string(issue.Kind), "detail", issue.Detail)
+}
+
+func (o *observer) params(err *ParamsError) {
+	o.log(EventParams, "ii18n: params do not match placeholders", "category", err.Category, "message", err.Message, "lang", err.Lang, "missing", err.Missing, "extra", err.Extra)
+}
+
+func (o *observer) cacheLookup(hit bool) {
+	if o != nil && o.metrics != nil {
+		o.metrics.CacheLookup(hit)
+	}
+}
+
+func (o *observer) reloadFailed(category string, err error) {
+	if o != nil && o.metrics != nil {
+		o.metrics.ReloadFailed(category, err)
+	}
+	o.log(EventReloadFailed, "ii18n: catalog reload failed", "category", category, "error", err)
+}
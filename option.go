@@ -0,0 +1,147 @@
+package ii18n
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option configures an I18N.
+type Option func(*I18N)
+
+// WithMetrics reports translation activity to m.
+func WithMetrics(m Metrics) Option {
+	return func(i *I18N) {
+		i.observer.metrics = m
+	}
+}
+
+// WithLogger writes missing translations, load failures, fallback usage and
+// reloads to logger instead of slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(i *I18N) {
+		i.observer.logger = logger
+	}
+}
+
+// WithLogLevel logs event at level. Use a level below the handler's minimum
+// to silence an event.
+func WithLogLevel(event Event, level slog.Level) Option {
+	return func(i *I18N) {
+		if i.observer.levels == nil {
+			i.observer.levels = make(map[Event]slog.Level)
+		}
+		i.observer.levels[event] = level
+	}
+}
+
+// WithTracer records a span for every catalog load.
+func WithTracer(tracer Tracer) Option {
+	return func(i *I18N) {
+		i.observer.tracer = tracer
+	}
+}
+
+// WithMissingHandler calls handler for every message without translation,
+// before falling back to the original message.
+func WithMissingHandler(handler MissingHandler) Option {
+	return func(i *I18N) {
+		i.missingHandler = handler
+	}
+}
+
+// WithDebugMarkers wraps every translated string in markers naming its
+// category and key, e.g. "⟦app.ui:save⟧Save⟦/⟧", so reviewers can tell which
+// key produced a string on screen.
+func WithDebugMarkers() Option {
+	return func(i *I18N) {
+		i.debugMarkers = true
+	}
+}
+
+// WithParamCheck checks that the params of every translation cover the
+// placeholders of its pattern, logging missing and extra params as
+// EventParams. When strict, a translation missing params is replaced by the
+// original message and TE returns a *ParamsError.
+func WithParamCheck(strict bool) Option {
+	return func(i *I18N) {
+		i.checkParams = true
+		i.strictParams = strict
+	}
+}
+
+// WithAutoReload reloads the sources every interval, see Reload, until
+// Close is called.
+func WithAutoReload(interval time.Duration) Option {
+	return func(i *I18N) {
+		i.reloadInterval = interval
+	}
+}
+
+// WithDefaultLang translates messages requested without a language to lang.
+func WithDefaultLang(lang string) Option {
+	return func(i *I18N) {
+		i.defaultLang = lang
+	}
+}
+
+// WithDefaultCategory translates messages requested with the category ""
+// in category, e.g. T("", "Pay", nil, "de") in "app.checkout".
+func WithDefaultCategory(category string) Option {
+	return func(i *I18N) {
+		i.defaultCategory = normalizeCategory(category)
+	}
+}
+
+// WithCategoryInheritance looks messages without translation in a category
+// up in its parent categories, "app.checkout.payment" falling back to
+// "app.checkout", then to "app", the catalog named after the prefix.
+func WithCategoryInheritance() Option {
+	return func(i *I18N) {
+		i.inherit = true
+	}
+}
+
+// WithGlobalParams adds params to the params of every message, e.g.
+// {"appName": "Shop"} for "Welcome to {appName}". The params of a call take
+// precedence.
+func WithGlobalParams(params map[string]string) Option {
+	return func(i *I18N) {
+		i.globalParams = make(map[string]string, len(params))
+		for name, val := range params {
+			i.globalParams[name] = val
+		}
+	}
+}
+
+// WithVariantSelector serves the variants of messages chosen by selector,
+// e.g. by NewExperimentSelector, to A/B test copy. Pass the request context
+// with TContext.
+func WithVariantSelector(selector VariantSelector) Option {
+	return func(i *I18N) {
+		i.variantSelector = selector
+	}
+}
+
+// WithOverrides looks messages up in the source of conf before the source of
+// their category, in every language including the original one, e.g. to
+// customize the wording of a tenant. Its catalogs are named by the
+// categories they override. Overrides stack, the last given looked up first.
+func WithOverrides(conf Config) Option {
+	return func(i *I18N) {
+		if conf.FileMap == nil {
+			conf.FileMap = map[string]string{}
+		}
+		conf.observer = i.observer
+		conf.source = conf.SourceNewFunc(&conf)
+		i.overrides = append(i.overrides, &conf)
+	}
+}
+
+// WithMissingTemplate returns tmpl for messages without translation instead
+// of the original message, e.g. "!missing:{category}.{key}!". The template
+// may use {category}, {key} and {lang}.
+func WithMissingTemplate(tmpl string) Option {
+	return func(i *I18N) {
+		i.missingTemplate = tmpl
+	}
+}
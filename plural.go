@@ -0,0 +1,340 @@
+package ii18n
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Category a CLDR plural category.
+type Category string
+
+// Plural categories.
+const (
+	PluralZero  Category = "zero"
+	PluralOne   Category = "one"
+	PluralTwo   Category = "two"
+	PluralFew   Category = "few"
+	PluralMany  Category = "many"
+	PluralOther Category = "other"
+)
+
+// Operands the CLDR plural operands of a number.
+type Operands struct {
+	// N absolute value of the number.
+	N float64
+	// I integer digits of N.
+	I int64
+	// V number of visible fraction digits, with trailing zeros.
+	V int
+	// W number of visible fraction digits, without trailing zeros.
+	W int
+	// F visible fraction digits, with trailing zeros.
+	F int64
+	// T visible fraction digits, without trailing zeros.
+	T int64
+	// E exponent of the compact decimal notation, "1.2c6" for 1.2 million.
+	E int
+}
+
+// NewOperands Returns the operands of n, an integer, float or decimal
+// string. Strings keep their visible fraction digits: "1.0" has V = 1.
+// Floats have none beyond their shortest representation, 1.0 has V = 0;
+// format them with the wanted precision to select on fraction digits.
+func NewOperands(n interface{}) (Operands, error) {
+	d, err := toDecimal(n)
+	if err != nil {
+		return Operands{}, err
+	}
+	return d.operands(), nil
+}
+
+// operands Returns the plural operands of d. Integer digits beyond the
+// range of int64 are dropped from I, keeping its remainders.
+func (d decimal) operands() Operands {
+	var ops Operands
+	intg := d.intg
+	if len(intg) > 18 {
+		intg = intg[len(intg)-18:]
+	}
+	ops.I, _ = strconv.ParseInt("0"+intg, 10, 64)
+	ops.N, _ = strconv.ParseFloat("0"+d.intg+"."+d.frac+"0", 64)
+	frac := d.frac
+	if len(frac) > 18 {
+		frac = frac[:18]
+	}
+	ops.V = len(frac)
+	ops.F, _ = strconv.ParseInt("0"+frac, 10, 64)
+	trimmed := strings.TrimRight(frac, "0")
+	ops.W = len(trimmed)
+	ops.T, _ = strconv.ParseInt("0"+trimmed, 10, 64)
+	return ops
+}
+
+// value Returns the operand named by c.
+func (ops Operands) value(c byte) float64 {
+	switch c {
+	case 'n':
+		return ops.N
+	case 'i':
+		return float64(ops.I)
+	case 'v':
+		return float64(ops.V)
+	case 'w':
+		return float64(ops.W)
+	case 'f':
+		return float64(ops.F)
+	case 't':
+		return float64(ops.T)
+	case 'e', 'c':
+		return float64(ops.E)
+	}
+	return 0
+}
+
+// pluralCategories the categories in the order rules are tried, "other"
+// matching when no rule does.
+var pluralCategories = []string{"zero", "one", "two", "few", "many"}
+
+// pluralRule a parsed CLDR plural condition: a disjunction of
+// conjunctions of relations.
+type pluralRule [][]pluralRelation
+
+// pluralRelation "operand % mod = ranges" or, negated, "!=".
+type pluralRelation struct {
+	operand byte
+	mod     float64
+	negate  bool
+	ranges  [][2]float64
+}
+
+// match Whether ops satisfy the rule.
+func (r pluralRule) match(ops Operands) bool {
+	for _, and := range r {
+		ok := true
+		for _, rel := range and {
+			if !rel.match(ops) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// match Whether ops satisfy the relation. Ranges only hold integers, so a
+// fractional n is in none of them.
+func (rel pluralRelation) match(ops Operands) bool {
+	val := ops.value(rel.operand)
+	if rel.mod != 0 {
+		val = math.Mod(val, rel.mod)
+	}
+	in := false
+	if val == math.Trunc(val) {
+		for _, r := range rel.ranges {
+			if val >= r[0] && val <= r[1] {
+				in = true
+				break
+			}
+		}
+	}
+	return in != rel.negate
+}
+
+// parsePluralRule Parses a CLDR plural condition such as
+// "v = 0 and i % 10 = 2..4 and i % 100 != 12..14".
+func parsePluralRule(rule string) (pluralRule, error) {
+	var r pluralRule
+	if strings.TrimSpace(rule) == "" {
+		return r, nil
+	}
+	for _, or := range strings.Split(rule, " or ") {
+		var and []pluralRelation
+		for _, relation := range strings.Split(or, " and ") {
+			rel, err := parsePluralRelation(strings.TrimSpace(relation))
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, rel)
+		}
+		r = append(r, and)
+	}
+	return r, nil
+}
+
+// parsePluralRelation Parses "operand [% mod] (=|!=) range_list".
+func parsePluralRelation(s string) (pluralRelation, error) {
+	var rel pluralRelation
+	expr, list, ok := strings.Cut(s, "!=")
+	if ok {
+		rel.negate = true
+	} else if expr, list, ok = strings.Cut(s, "="); !ok {
+		return rel, errors.New("ii18n: invalid plural relation " + strconv.Quote(s))
+	}
+	expr = strings.TrimSpace(expr)
+	operand, mod, hasMod := strings.Cut(expr, "%")
+	operand = strings.TrimSpace(operand)
+	if len(operand) != 1 || !strings.Contains("niwvftec", operand) {
+		return rel, errors.New("ii18n: invalid plural operand in " + strconv.Quote(s))
+	}
+	rel.operand = operand[0]
+	if hasMod {
+		m, err := strconv.ParseFloat(strings.TrimSpace(mod), 64)
+		if err != nil || m <= 0 {
+			return rel, errors.New("ii18n: invalid plural modulus in " + strconv.Quote(s))
+		}
+		rel.mod = m
+	}
+	for _, item := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(item), "..")
+		if !isRange {
+			hi = lo
+		}
+		from, err1 := strconv.ParseFloat(lo, 64)
+		to, err2 := strconv.ParseFloat(hi, 64)
+		if err1 != nil || err2 != nil || from > to {
+			return rel, errors.New("ii18n: invalid plural range in " + strconv.Quote(s))
+		}
+		rel.ranges = append(rel.ranges, [2]float64{from, to})
+	}
+	return rel, nil
+}
+
+// pluralRules compiled rules of a locale by category.
+type pluralRules map[string]pluralRule
+
+// category Returns the first category whose rule ops satisfy, or "other".
+func (rules pluralRules) category(ops Operands) string {
+	for _, category := range pluralCategories {
+		if rule, ok := rules[category]; ok && rule.match(ops) {
+			return category
+		}
+	}
+	return "other"
+}
+
+// customPlurals cardinal rules registered by RegisterPluralRule by locale.
+var customPlurals = map[string]func(ops Operands) Category{}
+
+// RegisterPluralRule registers fn as the cardinal plural rule of lang and
+// the locales under it, replacing the embedded CLDR rule. It is not safe to
+// call concurrently with PluralCategory.
+func RegisterPluralRule(lang string, fn func(ops Operands) Category) {
+	customPlurals[lang] = fn
+}
+
+var (
+	cardinalOnce     sync.Once
+	compiledCardinal map[string]pluralRules
+	ordinalOnce      sync.Once
+	compiledOrdinal  map[string]pluralRules
+)
+
+// compileRuleSets Parses rule sets into rules by locale. The embedded data
+// is known to be valid, so parse errors panic.
+func compileRuleSets(sets []pluralRuleSet) map[string]pluralRules {
+	compiled := make(map[string]pluralRules)
+	for _, set := range sets {
+		rules := make(pluralRules, len(set.rules))
+		for category, rule := range set.rules {
+			r, err := parsePluralRule(rule)
+			if err != nil {
+				panic(err)
+			}
+			rules[category] = r
+		}
+		for _, locale := range strings.Fields(set.locales) {
+			compiled[locale] = rules
+		}
+	}
+	return compiled
+}
+
+// PluralCategory Returns the CLDR cardinal plural category of n in lang:
+// "zero", "one", "two", "few", "many" or "other". n is an integer, float or
+// decimal string; strings keep their visible fraction digits. Invalid
+// numbers and unknown languages are "other".
+func PluralCategory(lang string, n interface{}) string {
+	ops, err := NewOperands(n)
+	if err != nil {
+		return "other"
+	}
+	if fn := lookupLocale(customPlurals, lang, ""); fn != nil {
+		return string(fn(ops))
+	}
+	cardinalOnce.Do(func() { compiledCardinal = compileRuleSets(cardinalRules) })
+	return lookupLocale(compiledCardinal, lang, "root").category(ops)
+}
+
+// OrdinalCategory Returns the CLDR ordinal category of n in lang, "two"
+// for 2 in English as in "2nd". n is an integer, float or decimal string.
+// Invalid numbers and unknown languages are "other".
+func OrdinalCategory(lang string, n interface{}) string {
+	ops, err := NewOperands(n)
+	if err != nil {
+		return "other"
+	}
+	ordinalOnce.Do(func() { compiledOrdinal = compileRuleSets(ordinalRules) })
+	return lookupLocale(compiledOrdinal, lang, "root").category(ops)
+}
+
+// pluralSampleLimit the number of integer and of decimal samples returned
+// by PluralSamples.
+const pluralSampleLimit = 8
+
+// pluralSampleCandidates Returns the numbers tried by PluralSamples, smallest first:
+// integers, then decimals with one and two fraction digits.
+func pluralSampleCandidates() (ints, decimals []string) {
+	for i := 0; i <= 200; i++ {
+		ints = append(ints, strconv.Itoa(i))
+	}
+	for i := 1000; i <= 10000000; i *= 10 {
+		ints = append(ints, strconv.Itoa(i))
+	}
+	for i := 0; i <= 30; i++ {
+		for f := 0; f <= 9; f++ {
+			decimals = append(decimals, strconv.Itoa(i)+"."+strconv.Itoa(f))
+		}
+	}
+	for _, d := range []string{"0.01", "0.21", "1.00", "1.01", "100.0", "1000.0", "1000000.0"} {
+		decimals = append(decimals, d)
+	}
+	return ints, decimals
+}
+
+// PluralSamples Returns example numbers of the cardinal plural category of
+// lang, integers first, then decimals, to show translators which numbers
+// use each form. Categories lang does not use have none.
+func PluralSamples(lang string, category Category) []string {
+	ints, decimals := pluralSampleCandidates()
+	var samples []string
+	for _, candidates := range [][]string{ints, decimals} {
+		found := 0
+		for _, n := range candidates {
+			if found == pluralSampleLimit {
+				break
+			}
+			if PluralCategory(lang, n) == string(category) {
+				samples = append(samples, n)
+				found++
+			}
+		}
+	}
+	return samples
+}
+
+// PluralRangeCategory Returns the CLDR plural category of the range from..to
+// in lang, such as "other" for "1–3 Tage" in German. Invalid numbers are
+// "other".
+func PluralRangeCategory(lang string, from, to interface{}) string {
+	start, end := PluralCategory(lang, from), PluralCategory(lang, to)
+	if category, ok := lookupLocale(pluralRanges, lang, "")[start+"+"+end]; ok {
+		return category
+	}
+	return end
+}
@@ -0,0 +1,159 @@
+package ii18n
+
+// pluralRuleSet CLDR plural rules shared by a space separated list of
+// locales. Categories without a rule are "other".
+type pluralRuleSet struct {
+	locales string
+	rules   map[string]string
+}
+
+// decimalMany the "many" rule of languages using it for large round numbers
+// and compact exponents, "1 million de".
+const decimalMany = "e = 0 and i != 0 and i % 1000000 = 0 and v = 0 or e != 0..5"
+
+// cardinalRules CLDR cardinal plural rules.
+var cardinalRules = []pluralRuleSet{
+	{"bm bo dz hnj id ig ii in ja jbo jv jw kde kea km ko lkt lo ms my nqo osa root sah ses sg su th to tpi vi wo yo yue zh", nil},
+	{"am as bn doi fa gu hi kn pcm zu", map[string]string{"one": "i = 0 or n = 1"}},
+	{"ff hy kab", map[string]string{"one": "i = 0,1"}},
+	{"ast de en et fi fy gl ia io ji lij nl sc sv sw ur yi", map[string]string{"one": "i = 1 and v = 0"}},
+	{"si", map[string]string{"one": "n = 0,1 or i = 0 and f = 1"}},
+	{"ak bho guw ln mg nso pa ti wa", map[string]string{"one": "n = 0..1"}},
+	{"tzm", map[string]string{"one": "n = 0..1 or n = 11..99"}},
+	{"af an asa az bal bem bez bg brx ce cgg chr ckb dv ee el eo eu fo fur gsw ha haw hu jgo jmc ka kaj kcg kk kkj kl ks ksb ku ky lb lg mas mgo ml mn mr nah nb nd ne nn nnh no nr ny nyn om or os pap ps rm rof rwk saq sd sdh seh sn so sq ss ssy st syr ta te teo tig tk tn tr ts ug uz ve vo vun wae xh xog", map[string]string{"one": "n = 1"}},
+	{"da", map[string]string{"one": "n = 1 or t != 0 and i = 0,1"}},
+	{"is", map[string]string{"one": "t = 0 and i % 10 = 1 and i % 100 != 11 or t % 10 = 1 and t % 100 != 11"}},
+	{"mk", map[string]string{"one": "v = 0 and i % 10 = 1 and i % 100 != 11 or f % 10 = 1 and f % 100 != 11"}},
+	{"ceb fil tl", map[string]string{"one": "v = 0 and i = 1,2,3 or v = 0 and i % 10 != 4,6,9 or v != 0 and f % 10 != 4,6,9"}},
+	{"lv prg", map[string]string{
+		"zero": "n % 10 = 0 or n % 100 = 11..19 or v = 2 and f % 100 = 11..19",
+		"one":  "n % 10 = 1 and n % 100 != 11 or v = 2 and f % 10 = 1 and f % 100 != 11 or v != 2 and f % 10 = 1",
+	}},
+	{"lag", map[string]string{"zero": "n = 0", "one": "i = 0,1 and n != 0"}},
+	{"ksh", map[string]string{"zero": "n = 0", "one": "n = 1"}},
+	{"he", map[string]string{"one": "i = 1 and v = 0 or i = 0 and v != 0", "two": "i = 2 and v = 0"}},
+	{"iu naq sat se sma smi smj smn sms", map[string]string{"one": "n = 1", "two": "n = 2"}},
+	{"shi", map[string]string{"one": "i = 0 or n = 1", "few": "n = 2..10"}},
+	{"mo ro", map[string]string{"one": "i = 1 and v = 0", "few": "v != 0 or n = 0 or n != 1 and n % 100 = 1..19"}},
+	{"bs hr sh sr", map[string]string{
+		"one": "v = 0 and i % 10 = 1 and i % 100 != 11 or f % 10 = 1 and f % 100 != 11",
+		"few": "v = 0 and i % 10 = 2..4 and i % 100 != 12..14 or f % 10 = 2..4 and f % 100 != 12..14",
+	}},
+	{"fr", map[string]string{"one": "i = 0,1", "many": decimalMany}},
+	{"pt", map[string]string{"one": "i = 0..1", "many": decimalMany}},
+	{"ca it lld pt-PT vec", map[string]string{"one": "i = 1 and v = 0", "many": decimalMany}},
+	{"es", map[string]string{"one": "n = 1", "many": decimalMany}},
+	{"gd", map[string]string{"one": "n = 1,11", "two": "n = 2,12", "few": "n = 3..10,13..19"}},
+	{"sl", map[string]string{"one": "v = 0 and i % 100 = 1", "two": "v = 0 and i % 100 = 2", "few": "v = 0 and i % 100 = 3..4 or v != 0"}},
+	{"dsb hsb", map[string]string{
+		"one": "v = 0 and i % 100 = 1 or f % 100 = 1",
+		"two": "v = 0 and i % 100 = 2 or f % 100 = 2",
+		"few": "v = 0 and i % 100 = 3..4 or f % 100 = 3..4",
+	}},
+	{"cs sk", map[string]string{"one": "i = 1 and v = 0", "few": "i = 2..4 and v = 0", "many": "v != 0"}},
+	{"pl", map[string]string{
+		"one":  "i = 1 and v = 0",
+		"few":  "v = 0 and i % 10 = 2..4 and i % 100 != 12..14",
+		"many": "v = 0 and i != 1 and i % 10 = 0..1 or v = 0 and i % 10 = 5..9 or v = 0 and i % 100 = 12..14",
+	}},
+	{"be", map[string]string{
+		"one":  "n % 10 = 1 and n % 100 != 11",
+		"few":  "n % 10 = 2..4 and n % 100 != 12..14",
+		"many": "n % 10 = 0 or n % 10 = 5..9 or n % 100 = 11..14",
+	}},
+	{"lt", map[string]string{
+		"one":  "n % 10 = 1 and n % 100 != 11..19",
+		"few":  "n % 10 = 2..9 and n % 100 != 11..19",
+		"many": "f != 0",
+	}},
+	{"ru uk", map[string]string{
+		"one":  "v = 0 and i % 10 = 1 and i % 100 != 11",
+		"few":  "v = 0 and i % 10 = 2..4 and i % 100 != 12..14",
+		"many": "v = 0 and i % 10 = 0 or v = 0 and i % 10 = 5..9 or v = 0 and i % 100 = 11..14",
+	}},
+	{"br", map[string]string{
+		"one":  "n % 10 = 1 and n % 100 != 11,71,91",
+		"two":  "n % 10 = 2 and n % 100 != 12,72,92",
+		"few":  "n % 10 = 3..4,9 and n % 100 != 10..19,70..79,90..99",
+		"many": "n != 0 and n % 1000000 = 0",
+	}},
+	{"mt", map[string]string{"one": "n = 1", "two": "n = 2", "few": "n = 0 or n % 100 = 3..10", "many": "n % 100 = 11..19"}},
+	{"ga", map[string]string{"one": "n = 1", "two": "n = 2", "few": "n = 3..6", "many": "n = 7..10"}},
+	{"gv", map[string]string{
+		"one":  "v = 0 and i % 10 = 1",
+		"two":  "v = 0 and i % 10 = 2",
+		"few":  "v = 0 and i % 100 = 0,20,40,60,80",
+		"many": "v != 0",
+	}},
+	{"kw", map[string]string{
+		"zero": "n = 0",
+		"one":  "n = 1",
+		"two":  "n % 100 = 2,22,42,62,82 or n % 1000 = 0 and n % 100000 = 1000..20000,40000,60000,80000 or n != 0 and n % 1000000 = 100000",
+		"few":  "n % 100 = 3,23,43,63,83",
+		"many": "n != 1 and n % 100 = 1,21,41,61,81",
+	}},
+	{"ar ars", map[string]string{"zero": "n = 0", "one": "n = 1", "two": "n = 2", "few": "n % 100 = 3..10", "many": "n % 100 = 11..99"}},
+	{"cy", map[string]string{"zero": "n = 0", "one": "n = 1", "two": "n = 2", "few": "n = 3", "many": "n = 6"}},
+}
+
+// ordinalRules CLDR ordinal plural rules. Locales not listed only use
+// "other".
+var ordinalRules = []pluralRuleSet{
+	{"root", nil},
+	{"en", map[string]string{
+		"one": "n % 10 = 1 and n % 100 != 11",
+		"two": "n % 10 = 2 and n % 100 != 12",
+		"few": "n % 10 = 3 and n % 100 != 13",
+	}},
+	{"cy", map[string]string{"zero": "n = 0,7,8,9", "one": "n = 1", "two": "n = 2", "few": "n = 3,4", "many": "n = 5,6"}},
+	{"uk", map[string]string{"few": "n % 10 = 3 and n % 100 != 13"}},
+	{"be", map[string]string{"few": "n % 10 = 2,3 and n % 100 != 12,13"}},
+	{"fil fr ga hy lo mo ms ro tl vi", map[string]string{"one": "n = 1"}},
+	{"sv", map[string]string{"one": "n % 10 = 1,2 and n % 100 != 11,12"}},
+	{"hu", map[string]string{"one": "n = 1,5"}},
+	{"it sc scn", map[string]string{"many": "n = 11,8,80,800"}},
+	{"lij", map[string]string{"many": "n = 11,8,80..89,800..899"}},
+	{"ca", map[string]string{"one": "n = 1,3", "two": "n = 2", "few": "n = 4"}},
+	{"ka", map[string]string{"one": "i = 1", "many": "i = 0 or i % 100 = 2..20,40,60,80"}},
+	{"mk", map[string]string{
+		"one":  "i % 10 = 1 and i % 100 != 11",
+		"two":  "i % 10 = 2 and i % 100 != 12",
+		"many": "i % 10 = 7,8 and i % 100 != 17,18",
+	}},
+	{"kk", map[string]string{"many": "n % 10 = 6 or n % 10 = 9 or n % 10 = 0 and n != 0"}},
+	{"az", map[string]string{
+		"one":  "i % 10 = 1,2,5,7,8 or i % 100 = 20,50,70,80",
+		"few":  "i % 10 = 3,4 or i % 1000 = 100,200,300,400,500,600,700,800,900",
+		"many": "i = 0 or i % 10 = 6 or i % 100 = 40,60,90",
+	}},
+	{"as bn", map[string]string{"one": "n = 1,5,7,8,9,10", "two": "n = 2,3", "few": "n = 4", "many": "n = 6"}},
+	{"gu hi", map[string]string{"one": "n = 1", "two": "n = 2,3", "few": "n = 4", "many": "n = 6"}},
+	{"or", map[string]string{"one": "n = 1,5,7..9", "two": "n = 2,3", "few": "n = 4", "many": "n = 6"}},
+	{"mr", map[string]string{"one": "n = 1", "two": "n = 2,3", "few": "n = 4"}},
+	{"ne", map[string]string{"one": "n = 1..4"}},
+	{"sq", map[string]string{"one": "n = 1", "many": "n % 10 = 4 and n % 100 != 14"}},
+	{"tk", map[string]string{"few": "n % 10 = 6,9 or n = 10"}},
+	{"gd", map[string]string{"one": "n = 1,11", "two": "n = 2,12", "few": "n = 3,13"}},
+	{"kw", map[string]string{
+		"one":  "n = 1..4 or n % 100 = 1..4,21..24,41..44,61..64,81..84",
+		"many": "n = 5 or n % 100 = 5",
+	}},
+}
+
+// pluralRanges CLDR plural range rules that differ from the category of the
+// range end, keyed by "start+end" categories. Locales not listed, and pairs
+// not listed, take the category of the end.
+var pluralRanges = map[string]map[string]string{
+	"en": {"other+one": "other"},
+	"es": {"other+one": "other"},
+	"sv": {"other+one": "other"},
+	"nb": {"other+one": "other"},
+	"no": {"other+one": "other"},
+	"ro": {"few+one": "few"},
+	"lv": {
+		"zero+zero":  "other",
+		"one+zero":   "other",
+		"one+one":    "other",
+		"other+zero": "other",
+	},
+}
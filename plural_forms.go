@@ -0,0 +1,314 @@
+package ii18n
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultPluralForms Plural-Forms of catalogs without the header, the
+// Germanic rule gettext itself assumes.
+const defaultPluralForms = "nplurals=2; plural=(n != 1);"
+
+// PluralForms a parsed gettext Plural-Forms header such as
+// "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);".
+type PluralForms struct {
+	// NPlurals number of plural forms.
+	NPlurals int
+	// Expr the C expression of the form index.
+	Expr string
+	eval pluralExpr
+}
+
+// pluralExpr an evaluated Plural-Forms expression.
+type pluralExpr func(n int64) int64
+
+// ParsePluralForms Parses a gettext Plural-Forms header value.
+func ParsePluralForms(header string) (*PluralForms, error) {
+	pf := &PluralForms{}
+	for _, field := range strings.Split(header, ";") {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "nplurals":
+			n, err := strconv.Atoi(strings.TrimSpace(val))
+			if err != nil || n < 1 {
+				return nil, errors.New("invalid nplurals in Plural-Forms: " + header)
+			}
+			pf.NPlurals = n
+		case "plural":
+			pf.Expr = strings.TrimSpace(val)
+		}
+	}
+	if pf.NPlurals == 0 || pf.Expr == "" {
+		return nil, errors.New("Plural-Forms needs nplurals and plural: " + header)
+	}
+	eval, err := parsePluralExpr(pf.Expr)
+	if err != nil {
+		return nil, err
+	}
+	pf.eval = eval
+	return pf, nil
+}
+
+// Index Returns the index of the plural form of n, clamped to the forms
+// declared by nplurals.
+func (pf *PluralForms) Index(n int64) int {
+	index := pf.eval(n)
+	if index < 0 || index >= int64(pf.NPlurals) {
+		return 0
+	}
+	return int(index)
+}
+
+// pluralExprs cache of compiled expressions of pluralforms arguments.
+var pluralExprs sync.Map
+
+// compilePluralExpr Returns the compiled Plural-Forms expression expr.
+func compilePluralExpr(expr string) (pluralExpr, error) {
+	if eval, ok := pluralExprs.Load(expr); ok {
+		return eval.(pluralExpr), nil
+	}
+	eval, err := parsePluralExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	pluralExprs.Store(expr, eval)
+	return eval, nil
+}
+
+// gettextPattern Returns the plural forms of a gettext entry as a
+// pluralforms argument on n, selected at runtime by the Plural-Forms header.
+// The last form is "other".
+func gettextPattern(header string, forms []string) string {
+	pf, err := ParsePluralForms(header)
+	if err != nil {
+		pf, _ = ParsePluralForms(defaultPluralForms)
+	}
+	var b strings.Builder
+	b.WriteString("{n, pluralforms, " + pf.Expr + ",")
+	for i, form := range forms {
+		selector := strconv.Itoa(i)
+		if i == len(forms)-1 {
+			selector = "other"
+		}
+		b.WriteString(" " + selector + " {" + form + "}")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// pluralExprParser recursive descent parser of the C subset used by
+// Plural-Forms: n, integers, ?:, ||, &&, comparisons, arithmetic and !.
+type pluralExprParser struct {
+	expr  string
+	pos   int
+	depth int
+}
+
+// maxPluralExprDepth limit of nested parentheses, ternaries and negations.
+const maxPluralExprDepth = 64
+
+// parsePluralExpr Compiles a Plural-Forms expression.
+func parsePluralExpr(expr string) (pluralExpr, error) {
+	p := &pluralExprParser{expr: expr}
+	eval, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.expr) {
+		return nil, p.errorf("unexpected " + strconv.Quote(p.expr[p.pos:p.pos+1]))
+	}
+	return eval, nil
+}
+
+func (p *pluralExprParser) errorf(msg string) error {
+	return errors.New("plural expression is invalid: " + msg + " at offset " + strconv.Itoa(p.pos))
+}
+
+func (p *pluralExprParser) skipSpace() {
+	for p.pos < len(p.expr) && isSpace(p.expr[p.pos]) {
+		p.pos++
+	}
+}
+
+// consume Advances past op if it is next.
+func (p *pluralExprParser) consume(op string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.expr[p.pos:], op) {
+		p.pos += len(op)
+		return true
+	}
+	return false
+}
+
+func (p *pluralExprParser) parseTernary() (pluralExpr, error) {
+	if p.depth++; p.depth > maxPluralExprDepth {
+		return nil, p.errorf("expression nested too deeply")
+	}
+	defer func() { p.depth-- }()
+	cond, err := p.parseBinary(0)
+	if err != nil || !p.consume("?") {
+		return cond, err
+	}
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(":") {
+		return nil, p.errorf("expected ':'")
+	}
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int64) int64 {
+		if cond(n) != 0 {
+			return then(n)
+		}
+		return otherwise(n)
+	}, nil
+}
+
+// pluralOps binary operators by increasing precedence. Longer operators come
+// first within a level so "<=" is not read as "<".
+var pluralOps = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// parseBinary Parses left-associative operators of precedence level and up.
+func (p *pluralExprParser) parseBinary(level int) (pluralExpr, error) {
+	if level == len(pluralOps) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range pluralOps[level] {
+			if p.consume(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = pluralBinary(op, left, right)
+	}
+}
+
+// pluralBinary Returns the evaluation of left op right. Division by zero is 0.
+func pluralBinary(op string, left, right pluralExpr) pluralExpr {
+	bool64 := func(b bool) int64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	return func(n int64) int64 {
+		a := left(n)
+		switch op {
+		case "||":
+			return bool64(a != 0 || right(n) != 0)
+		case "&&":
+			return bool64(a != 0 && right(n) != 0)
+		}
+		b := right(n)
+		switch op {
+		case "==":
+			return bool64(a == b)
+		case "!=":
+			return bool64(a != b)
+		case "<":
+			return bool64(a < b)
+		case "<=":
+			return bool64(a <= b)
+		case ">":
+			return bool64(a > b)
+		case ">=":
+			return bool64(a >= b)
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		case "*":
+			return a * b
+		case "/", "%":
+			if b == 0 {
+				return 0
+			}
+			if op == "/" {
+				return a / b
+			}
+			return a % b
+		}
+		return 0
+	}
+}
+
+func (p *pluralExprParser) parseUnary() (pluralExpr, error) {
+	if p.consume("!") {
+		if p.depth++; p.depth > maxPluralExprDepth {
+			return nil, p.errorf("expression nested too deeply")
+		}
+		defer func() { p.depth-- }()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int64) int64 {
+			if operand(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *pluralExprParser) parsePrimary() (pluralExpr, error) {
+	if p.consume("(") {
+		inner, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return inner, nil
+	}
+	if p.consume("n") {
+		return func(n int64) int64 { return n }, nil
+	}
+	start := p.pos
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos >= len(p.expr) {
+			return nil, p.errorf("unexpected end")
+		}
+		return nil, p.errorf("unexpected " + strconv.Quote(p.expr[p.pos:p.pos+1]))
+	}
+	value, err := strconv.ParseInt(p.expr[start:p.pos], 10, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number " + p.expr[start:p.pos])
+	}
+	return func(int64) int64 { return value }, nil
+}
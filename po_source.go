@@ -0,0 +1,273 @@
+package ii18n
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Type POSource reads gettext PO catalogs.
+type POSource struct {
+	MessageSource
+}
+
+// New POSource
+func NewPOSource(conf *Config) Source {
+	s := &POSource{}
+	s.init(conf, "po", poCodec{})
+
+	return s
+}
+
+// poEntry an entry of a PO file.
+type poEntry struct {
+	comments []string
+	ctxt     string
+	id       string
+	idPlural string
+	strs     []string
+}
+
+// poCodec gettext PO files. The msgid is the key; fuzzy entries count as
+// untranslated. Plural entries become a pluralforms argument on n selected
+// by the Plural-Forms header.
+type poCodec struct{}
+
+func (poCodec) Decode(data []byte) (TMsgs, error) {
+	c, err := poCodec{}.DecodeCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make(TMsgs, len(c.Entries))
+	for _, e := range c.Entries {
+		switch {
+		case hasFlag(e.Flags, "fuzzy"):
+			e.Value = ""
+		case len(e.Forms) > 1:
+			e.Value = gettextPattern(c.Meta["Plural-Forms"], e.Forms)
+		}
+		msgs[e.Key] = e.Value
+	}
+	return msgs, nil
+}
+
+func (poCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	return poCodec{}.EncodeCatalog(NewCatalog(msgs), lang)
+}
+
+// DecodeCatalog Decodes entries with their comments; the header entry
+// becomes Meta.
+func (poCodec) DecodeCatalog(data []byte) (*Catalog, error) {
+	entries, err := parsePO(data)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{Meta: make(map[string]string)}
+	for _, pe := range entries {
+		if pe.id == "" && pe.ctxt == "" {
+			if len(pe.strs) > 0 {
+				for _, line := range strings.Split(pe.strs[0], "\n") {
+					if pos := strings.Index(line, ":"); pos != -1 {
+						c.Meta[strings.TrimSpace(line[:pos])] = strings.TrimSpace(line[pos+1:])
+					}
+				}
+			}
+			continue
+		}
+		e := Entry{Key: pe.id, Context: pe.ctxt, Plural: pe.idPlural}
+		if len(pe.strs) > 0 {
+			e.Value = pe.strs[0]
+		}
+		if pe.idPlural != "" {
+			e.Forms = pe.strs
+		}
+		for _, comment := range pe.comments {
+			switch {
+			case strings.HasPrefix(comment, "#."):
+				e.Extracted = append(e.Extracted, strings.TrimSpace(comment[2:]))
+			case strings.HasPrefix(comment, "#:"):
+				e.Refs = append(e.Refs, strings.Fields(comment[2:])...)
+			case strings.HasPrefix(comment, "#,"):
+				for _, flag := range strings.Split(comment[2:], ",") {
+					if flag = strings.TrimSpace(flag); flag != "" {
+						e.Flags = append(e.Flags, flag)
+					}
+				}
+			case strings.HasPrefix(comment, "#|"):
+				e.Previous = append(e.Previous, strings.TrimSpace(comment[2:]))
+			default:
+				e.Comments = append(e.Comments, strings.TrimSpace(comment[1:]))
+			}
+		}
+		c.Entries = append(c.Entries, e)
+	}
+	return c, nil
+}
+
+// EncodeCatalog Encodes the header with sorted Meta fields, then the entries
+// sorted by key with their comments.
+func (poCodec) EncodeCatalog(c *Catalog, lang string) ([]byte, error) {
+	meta := map[string]string{"Content-Type": "text/plain; charset=UTF-8"}
+	if lang != "" {
+		meta["Language"] = strings.Replace(lang, "-", "_", 1)
+	}
+	for key, val := range c.Meta {
+		meta[key] = val
+	}
+	var b bytes.Buffer
+	b.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	for _, key := range sortedKeys(meta) {
+		b.WriteString(poQuote(key+": "+meta[key]+"\n") + "\n")
+	}
+	sorted := &Catalog{Entries: append([]Entry(nil), c.Entries...)}
+	sorted.Sort()
+	for _, e := range sorted.Entries {
+		b.WriteString("\n")
+		for _, comment := range e.Comments {
+			b.WriteString(strings.TrimRight("# "+comment, " ") + "\n")
+		}
+		for _, comment := range e.Extracted {
+			b.WriteString("#. " + comment + "\n")
+		}
+		if len(e.Refs) > 0 {
+			b.WriteString("#: " + strings.Join(e.Refs, " ") + "\n")
+		}
+		if len(e.Flags) > 0 {
+			b.WriteString("#, " + strings.Join(e.Flags, ", ") + "\n")
+		}
+		for _, previous := range e.Previous {
+			b.WriteString("#| " + previous + "\n")
+		}
+		if e.Context != "" {
+			b.WriteString("msgctxt ")
+			writePOString(&b, e.Context)
+		}
+		b.WriteString("msgid ")
+		writePOString(&b, e.Key)
+		if e.Plural != "" && len(e.Forms) > 0 {
+			b.WriteString("msgid_plural ")
+			writePOString(&b, e.Plural)
+			for i, form := range e.Forms {
+				b.WriteString("msgstr[" + strconv.Itoa(i) + "] ")
+				writePOString(&b, form)
+			}
+			continue
+		}
+		b.WriteString("msgstr ")
+		writePOString(&b, e.Value)
+	}
+	return b.Bytes(), nil
+}
+
+// hasFlag Whether flags contains flag.
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// writePOString Writes s quoted, splitting multi-line strings after each
+// newline.
+func writePOString(b *bytes.Buffer, s string) {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 1 {
+		b.WriteString(`""` + "\n")
+	}
+	for _, line := range lines {
+		b.WriteString(poQuote(line))
+		b.WriteString("\n")
+	}
+}
+
+// poQuote Quotes s as a PO string.
+func poQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s) + `"`
+}
+
+// parsePO Parses the entries of a PO file, skipping obsolete entries.
+func parsePO(data []byte) ([]poEntry, error) {
+	var entries []poEntry
+	var e poEntry
+	var field *string
+	started := false
+	flush := func() {
+		if started {
+			entries = append(entries, e)
+		}
+		e, field, started = poEntry{}, nil, false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		errorf := func(msg string) error {
+			return errors.New("line " + strconv.Itoa(n) + ": " + msg)
+		}
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#~"):
+			field = nil
+		case strings.HasPrefix(line, "#"):
+			if started && len(e.strs) > 0 {
+				flush()
+			}
+			e.comments = append(e.comments, line)
+		case line[0] == '"':
+			if field == nil {
+				return nil, errorf("unexpected string")
+			}
+			s, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, errorf("invalid string " + line)
+			}
+			*field += s
+		default:
+			keyword, rest := line, ""
+			if pos := strings.IndexAny(line, " \t"); pos != -1 {
+				keyword, rest = line[:pos], strings.TrimSpace(line[pos:])
+			}
+			s, err := strconv.Unquote(rest)
+			if err != nil {
+				return nil, errorf("invalid string " + rest)
+			}
+			if (keyword == "msgctxt" || keyword == "msgid") && len(e.strs) > 0 {
+				flush()
+			}
+			started = true
+			switch {
+			case keyword == "msgctxt":
+				e.ctxt, field = s, &e.ctxt
+			case keyword == "msgid":
+				e.id, field = s, &e.id
+			case keyword == "msgid_plural":
+				e.idPlural, field = s, &e.idPlural
+			case keyword == "msgstr":
+				e.strs = append(e.strs, s)
+				field = &e.strs[len(e.strs)-1]
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+				index, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+				if err != nil || index != len(e.strs) {
+					return nil, errorf("invalid " + keyword)
+				}
+				e.strs = append(e.strs, s)
+				field = &e.strs[len(e.strs)-1]
+			default:
+				return nil, errorf("unknown keyword " + keyword)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+package ii18n
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PseudoLang pseudo-locale. Messages requested in it are the original
+// messages with accented letters, bracket markers and about 30% padding, so
+// hard-coded and truncated strings stand out. Empty disables it.
+var PseudoLang = "en-XA"
+
+// pseudoChars accented replacements of ASCII letters.
+var pseudoChars = map[rune]rune{
+	'a': 'å', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// Pseudolocalize Returns pattern with accented letters, wrapped in brackets
+// and padded by about 30%. Placeholders in braces are kept as they are.
+func Pseudolocalize(pattern string) string {
+	var b strings.Builder
+	b.WriteString("[")
+	depth := 0
+	for _, r := range pattern {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case depth == 0:
+			if c, ok := pseudoChars[r]; ok {
+				r = c
+			}
+		}
+		b.WriteRune(r)
+	}
+	if n := (utf8.RuneCountInString(pattern)*3 + 9) / 10; n > 0 {
+		b.WriteString(" ")
+		b.WriteString(strings.Repeat("~", n))
+	}
+	b.WriteString("]")
+	return b.String()
+}
@@ -0,0 +1,101 @@
+//go:build race
+
+package ii18n
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Stress tests of concurrent use, built only by go test -race.
+
+func raceI18N(t *testing.T, conf Config) *I18N {
+	conf.FileMap = map[string]string{}
+	return NewI18N(map[string]Config{"app": conf}, quietLogger())
+}
+
+func writeCatalog(t *testing.T, filename string, data string) {
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRaceTranslateDuringReload(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(dir+"/de", 0755)
+	writeCatalog(t, dir+"/de/shop.json", `{"Cart": "Warenkorb"}`)
+	i := raceI18N(t, Config{SourceNewFunc: NewJSONSource, BasePath: dir})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 200; n++ {
+				res := T("shop", "Cart", nil, []string{"de", "de-AT", "fr"}[(g+n)%3])
+				if res != "Warenkorb" && res != "Einkaufswagen" && res != "Cart" {
+					t.Errorf("T(Cart) = %q", res)
+					return
+				}
+			}
+		}(g)
+	}
+	for n := 0; n < 20; n++ {
+		writeCatalog(t, dir+"/de/shop.json", []string{`{"Cart": "Warenkorb"}`, `{"Cart": "Einkaufswagen"}`}[n%2])
+		i.Reload()
+	}
+	wg.Wait()
+}
+
+func TestRaceColdLoad(t *testing.T) {
+	catalogs := make(map[string]TMsgs)
+	for c := 0; c < 10; c++ {
+		catalogs["de/c"+strconv.Itoa(c)] = TMsgs{"Cart": "Warenkorb"}
+	}
+	raceI18N(t, Config{SourceNewFunc: NewMemSource(catalogs), BasePath: "mem"})
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for c := 0; c < 10; c++ {
+				if res := T("app.c"+strconv.Itoa((g+c)%10), "Cart", nil, "de-CH"); res != "Warenkorb" {
+					t.Errorf("T(c%d) = %q", c, res)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestRaceRecordMissingDuringRead(t *testing.T) {
+	catalogs := map[string]TMsgs{"de/shop": {"Cart": "Warenkorb"}}
+	i := raceI18N(t, Config{SourceNewFunc: NewMemSource(catalogs), BasePath: "mem", RecordMissing: true})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				T("shop", "Cart", nil, "de")
+				T("shop", "Missing "+strconv.Itoa(g*50+n), nil, "de")
+			}
+		}(g)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 0; n < 20; n++ {
+			i.Reload()
+			if _, err := i.Coverage("shop"); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	wg.Wait()
+	msgs, _ := i.getConfig("app.shop").source.(Catalogs).LoadCatalog("app.shop", "de")
+	if len(msgs) != 401 {
+		t.Errorf("recorded %d messages, want 401", len(msgs))
+	}
+}
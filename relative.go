@@ -0,0 +1,157 @@
+package ii18n
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// RelativeWidth the length of a relative time.
+type RelativeWidth int
+
+// Relative time widths.
+const (
+	// RelativeLong "in 3 hours".
+	RelativeLong RelativeWidth = iota
+	// RelativeShort "in 3 hr.".
+	RelativeShort
+	// RelativeNarrow "in 3h".
+	RelativeNarrow
+)
+
+// RelativeUnit unit of a relative time.
+type RelativeUnit string
+
+// Relative time units.
+const (
+	RelativeSecond RelativeUnit = "second"
+	RelativeMinute RelativeUnit = "minute"
+	RelativeHour   RelativeUnit = "hour"
+	RelativeDay    RelativeUnit = "day"
+	RelativeWeek   RelativeUnit = "week"
+	RelativeMonth  RelativeUnit = "month"
+	RelativeYear   RelativeUnit = "year"
+)
+
+// relativePatterns CLDR relative time patterns of a unit: the future and
+// past patterns by plural category and the phrases of small offsets such as
+// -1 for "yesterday".
+type relativePatterns struct {
+	future   map[string]string
+	past     map[string]string
+	relative map[int]string
+}
+
+// FormatRelativeTime Formats t relative to ref in lang, in the largest unit
+// that fits the distance between them, preferring phrases such as
+// "yesterday" or "now" to numbers.
+func FormatRelativeTime(t time.Time, ref time.Time, lang string, width RelativeWidth) string {
+	value, unit := relativeOffset(t, ref)
+	out, _ := FormatRelative(value, unit, lang, width, false)
+	return out
+}
+
+// FormatRelative Formats value units from now in lang, "in 2 days" or
+// "2 days ago". Unless numeric, offsets with a phrase of their own, such as
+// "tomorrow" for one day, use it.
+func FormatRelative(value int, unit RelativeUnit, lang string, width RelativeWidth, numeric bool) (string, error) {
+	units := lookupLocale(relativeTimes, lang, "en")
+	patterns := units[string(unit)+relativeWidthSuffix[width]]
+	if patterns == nil {
+		patterns = units[string(unit)]
+	}
+	if patterns == nil {
+		return "", errors.New("ii18n: unknown relative time unit " + string(unit))
+	}
+	if !numeric {
+		if phrase, ok := patterns.relative[value]; ok {
+			return phrase, nil
+		}
+	}
+	forms := patterns.future
+	if value < 0 {
+		forms, value = patterns.past, -value
+	}
+	num, _ := FormatNumber(value, lang, nil)
+	pattern, ok := forms[PluralCategory(lang, value)]
+	if !ok {
+		pattern = forms["other"]
+	}
+	return strings.Replace(pattern, "{0}", num, 1), nil
+}
+
+// relativeWidthSuffix suffix of the keys of relativeTimes by width.
+var relativeWidthSuffix = map[RelativeWidth]string{
+	RelativeShort:  "-short",
+	RelativeNarrow: "-narrow",
+}
+
+// relativeOffset Returns the distance from ref to t in the largest unit it
+// fills. Days, months and years are counted on the calendar of ref.
+func relativeOffset(t time.Time, ref time.Time) (int, RelativeUnit) {
+	d := t.Sub(ref)
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs < time.Minute:
+		return int(d.Round(time.Second) / time.Second), RelativeSecond
+	case abs < time.Hour:
+		return int(d.Round(time.Minute) / time.Minute), RelativeMinute
+	case abs < 24*time.Hour:
+		return int(d.Round(time.Hour) / time.Hour), RelativeHour
+	}
+	t = t.In(ref.Location())
+	y1, m1, d1 := ref.Date()
+	y2, m2, d2 := t.Date()
+	days := int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+	months := (y2-y1)*12 + int(m2-m1)
+	switch {
+	case days > -7 && days < 7:
+		return days, RelativeDay
+	case months == 0 || (days > -28 && days < 28):
+		return days / 7, RelativeWeek
+	case months > -12 && months < 12:
+		return months, RelativeMonth
+	}
+	return y2 - y1, RelativeYear
+}
+
+// plurals Returns patterns by plural category from one and other forms.
+func plurals(one string, other string) map[string]string {
+	return map[string]string{"one": one, "other": other}
+}
+
+// slavicPlurals Returns patterns by plural category for languages with
+// one, few and many forms.
+func slavicPlurals(one string, few string, many string, other string) map[string]string {
+	return map[string]string{"one": one, "few": few, "many": many, "other": other}
+}
+
+// relativeTimes relative time patterns by locale and unit, with "-short"
+// and "-narrow" keys for the shorter widths.
+var relativeTimes = map[string]map[string]*relativePatterns{
+	"en": {
+		"year":          {plurals("in {0} year", "in {0} years"), plurals("{0} year ago", "{0} years ago"), map[int]string{-1: "last year", 0: "this year", 1: "next year"}},
+		"year-short":    {plurals("in {0} yr.", "in {0} yr."), plurals("{0} yr. ago", "{0} yr. ago"), map[int]string{-1: "last yr.", 0: "this yr.", 1: "next yr."}},
+		"year-narrow":   {plurals("in {0}y", "in {0}y"), plurals("{0}y ago", "{0}y ago"), map[int]string{-1: "last yr.", 0: "this yr.", 1: "next yr."}},
+		"month":         {plurals("in {0} month", "in {0} months"), plurals("{0} month ago", "{0} months ago"), map[int]string{-1: "last month", 0: "this month", 1: "next month"}},
+		"month-short":   {plurals("in {0} mo.", "in {0} mo."), plurals("{0} mo. ago", "{0} mo. ago"), map[int]string{-1: "last mo.", 0: "this mo.", 1: "next mo."}},
+		"month-narrow":  {plurals("in {0}mo", "in {0}mo"), plurals("{0}mo ago", "{0}mo ago"), map[int]string{-1: "last mo.", 0: "this mo.", 1: "next mo."}},
+		"week":          {plurals("in {0} week", "in {0} weeks"), plurals("{0} week ago", "{0} weeks ago"), map[int]string{-1: "last week", 0: "this week", 1: "next week"}},
+		"week-short":    {plurals("in {0} wk.", "in {0} wk."), plurals("{0} wk. ago", "{0} wk. ago"), map[int]string{-1: "last wk.", 0: "this wk.", 1: "next wk."}},
+		"week-narrow":   {plurals("in {0}w", "in {0}w"), plurals("{0}w ago", "{0}w ago"), map[int]string{-1: "last wk.", 0: "this wk.", 1: "next wk."}},
+		"day":           {plurals("in {0} day", "in {0} days"), plurals("{0} day ago", "{0} days ago"), map[int]string{-1: "yesterday", 0: "today", 1: "tomorrow"}},
+		"day-narrow":    {plurals("in {0}d", "in {0}d"), plurals("{0}d ago", "{0}d ago"), map[int]string{-1: "yesterday", 0: "today", 1: "tomorrow"}},
+		"hour":          {plurals("in {0} hour", "in {0} hours"), plurals("{0} hour ago", "{0} hours ago"), map[int]string{0: "this hour"}},
+		"hour-short":    {plurals("in {0} hr.", "in {0} hr."), plurals("{0} hr. ago", "{0} hr. ago"), map[int]string{0: "this hour"}},
+		"hour-narrow":   {plurals("in {0}h", "in {0}h"), plurals("{0}h ago", "{0}h ago"), map[int]string{0: "this hour"}},
+		"minute":        {plurals("in {0} minute", "in {0} minutes"), plurals("{0} minute ago", "{0} minutes ago"), map[int]string{0: "this minute"}},
+		"minute-short":  {plurals("in {0} min.", "in {0} min."), plurals("{0} min. ago", "{0} min. ago"), map[int]string{0: "this minute"}},
+		"minute-narrow": {plurals("in {0}m", "in {0}m"), plurals("{0}m ago", "{0}m ago"), map[int]string{0: "this minute"}},
+		"second":        {plurals("in {0} second", "in {0} seconds"), plurals("{0} second ago", "{0} seconds ago"), map[int]string{0: "now"}},
+		"second-short":  {plurals("in {0} sec.", "in {0} sec."), plurals("{0} sec. ago", "{0} sec. ago"), map[int]string{0: "now"}},
+		"second-narrow": {plurals("in {0}s", "in {0}s"), plurals("{0}s ago", "{0}s ago"), map[int]string{0: "now"}},
+	},
+}
@@ -0,0 +1,99 @@
+package ii18n
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// minisignAlg the signature algorithm of minisign signatures over the data
+// itself; "ED", over its BLAKE2b hash, needs a hash outside the standard
+// library.
+const minisignAlg = "Ed"
+
+// VerifySignature Verifies sig, the detached signature of data by key,
+// wrapping ErrInvalidSignature when it does not match. sig is an ed25519
+// signature, raw or in base64, or a minisign signature made with
+// "minisign -l", whose trusted comment is verified too.
+func VerifySignature(data []byte, sig []byte, key ed25519.PublicKey) error {
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: public key of %d bytes", ErrInvalidSignature, len(key))
+	}
+	if bytes.HasPrefix(sig, []byte("untrusted comment:")) {
+		return verifyMinisign(data, sig, key)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+		}
+		sig = decoded
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// verifyMinisign Verifies the minisign signature file sig of data.
+func verifyMinisign(data []byte, sig []byte, key ed25519.PublicKey) error {
+	lines := strings.Split(strings.ReplaceAll(string(sig), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("%w: malformed minisign signature", ErrInvalidSignature)
+	}
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed minisign signature", ErrInvalidSignature)
+	}
+	if alg := string(raw[:2]); alg != minisignAlg {
+		return fmt.Errorf("%w: minisign algorithm %q, sign with minisign -l", ErrInvalidSignature, alg)
+	}
+	if !ed25519.Verify(key, data, raw[10:]) {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	comment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if err != nil || !ed25519.Verify(key, append(append([]byte{}, raw[10:]...), comment...), global) {
+		return fmt.Errorf("%w: trusted comment does not match", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// ParsePublicKey Returns the ed25519 public key of s, 32 bytes in base64 or
+// a minisign public key, the base64 line of its .pub file or the whole file.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "untrusted comment:") {
+		_, s, _ = strings.Cut(s, "\n")
+		s = strings.TrimSpace(s)
+	}
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid public key: " + err.Error())
+	}
+	switch {
+	case len(raw) == ed25519.PublicKeySize:
+		return ed25519.PublicKey(raw), nil
+	case len(raw) == 2+8+ed25519.PublicKeySize && string(raw[:2]) == minisignAlg:
+		return ed25519.PublicKey(raw[10:]), nil
+	}
+	return nil, fmt.Errorf("invalid public key of %d bytes", len(raw))
+}
+
+// verifyFile Verifies the catalog data of filename against its detached
+// signature filename+".sig".
+func verifyFile(filename string, data []byte, key ed25519.PublicKey) error {
+	sig, err := os.ReadFile(filename + ".sig")
+	if err != nil {
+		// A missing signature is not a missing catalog.
+		return &LoadError{Path: filename, Err: fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())}
+	}
+	if err := VerifySignature(data, sig, key); err != nil {
+		return &LoadError{Path: filename, Err: err}
+	}
+	return nil
+}
@@ -1,9 +1,22 @@
 package ii18n
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unique"
 )
 
 type TMsgs map[string]string
@@ -17,6 +30,141 @@ type Source interface {
 	LoadFallbackMsgs(category string, fallbackLang string, msgs TMsgs, originalMsgFile string) (TMsgs, error)
 }
 
+// Saver is implemented by sources that can write messages back to their catalogs.
+type Saver interface {
+	// SaveMsgs merges msgs into the catalog of category and lang. Keys
+	// already present in the catalog keep their values.
+	SaveMsgs(category string, lang string, msgs TMsgs) error
+}
+
+// ContextSource is implemented by sources that accept a context, which
+// carries the trace of the request triggering a catalog load.
+type ContextSource interface {
+	TranslateContext(ctx context.Context, category string, message string, lang string) (string, error)
+}
+
+// BatchSource is implemented by sources that translate many messages at
+// once, see (*I18N) TranslateBatch.
+type BatchSource interface {
+	// TranslateBatch Returns the translations of messages in lang, leaving
+	// out the messages without translation.
+	TranslateBatch(category string, messages []string, lang string) (TMsgs, error)
+}
+
+// Catalogs is implemented by sources that can enumerate their catalogs.
+type Catalogs interface {
+	// AvailableLanguages Returns the languages with catalogs, sorted.
+	AvailableLanguages() ([]string, error)
+	// LoadCatalog Returns the messages of a single catalog, without fallback.
+	LoadCatalog(category string, lang string) (TMsgs, error)
+}
+
+// CategoryLister is implemented by sources that can enumerate the names of
+// their catalogs, the categories without prefix.
+type CategoryLister interface {
+	// CategoryNames Returns the catalog names, sorted.
+	CategoryNames() ([]string, error)
+}
+
+// Versioner is implemented by sources serving versioned catalogs, see
+// Config.Version. A version is a directory of catalogs, switched as a
+// whole.
+type Versioner interface {
+	Version() string
+	PinVersion(version string) error
+}
+
+// Reloader is implemented by sources that can refresh their cached catalogs.
+type Reloader interface {
+	Reload() error
+}
+
+// CatalogReloader is implemented by sources that can refresh the cached
+// catalogs of a category, see (*I18N) ReloadCatalog.
+type CatalogReloader interface {
+	ReloadCatalog(category string, lang string) error
+}
+
+// preloader is implemented by sources that can load catalogs ahead of use,
+// see (*I18N) Preload.
+type preloader interface {
+	preload(ctx context.Context, category string, lang string) error
+}
+
+// MissingFilePolicy how a source treats catalog files that do not exist.
+type MissingFilePolicy int
+
+// Missing file policies.
+const (
+	// FallbackOnly replaces a missing file by the catalogs of its fallback
+	// languages. When none exists LoadMsgs returns ErrCatalogNotFound and
+	// the messages have no translation.
+	FallbackOnly MissingFilePolicy = iota
+	// IgnoreMissingFile treats every missing file as an empty catalog.
+	IgnoreMissingFile
+	// ErrorOnMissingFile requires the file of the requested language, even
+	// when fallbacks exist: LoadMsgs and Translate return ErrCatalogNotFound
+	// without it. The original language is exempt.
+	ErrorOnMissingFile
+)
+
+// KeyNormalization how message keys are normalized, a combination of the
+// Normalize flags.
+type KeyNormalization int
+
+// Key normalization flags.
+const (
+	// NormalizeTrim removes leading and trailing whitespace.
+	NormalizeTrim KeyNormalization = 1 << iota
+	// NormalizeSpace collapses runs of whitespace into a single space.
+	NormalizeSpace
+	// NormalizeCase matches keys case-insensitively.
+	NormalizeCase
+)
+
+// Normalize Returns key normalized by n.
+func (n KeyNormalization) Normalize(key string) string {
+	if n&NormalizeTrim != 0 {
+		key = strings.TrimSpace(key)
+	}
+	if n&NormalizeSpace != 0 {
+		var b strings.Builder
+		space := false
+		for _, r := range key {
+			if unicode.IsSpace(r) {
+				space = true
+				continue
+			}
+			if space {
+				b.WriteByte(' ')
+				space = false
+			}
+			b.WriteRune(r)
+		}
+		if space {
+			b.WriteByte(' ')
+		}
+		key = b.String()
+	}
+	if n&NormalizeCase != 0 {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// normalizeMsgs Returns msgs with keys normalized by n. Of keys that become
+// equal, the first in sort order with a translation wins.
+func normalizeMsgs(msgs TMsgs, n KeyNormalization) TMsgs {
+	normalized := make(TMsgs, len(msgs))
+	for _, key := range sortedKeys(msgs) {
+		nkey := n.Normalize(key)
+		if normalized[nkey] == "" {
+			normalized[nkey] = msgs[key]
+		}
+	}
+	return normalized
+}
+
 // MessageSource
 type MessageSource struct {
 	// string the language that the original messages are in
@@ -25,53 +173,241 @@ type MessageSource struct {
 	BasePath         string
 	FileMap          map[string]string
 	fileSuffix       string
-	loadFunc         func(filename string) (TMsgs, error)
-	messages         map[string]TMsgs
-	mutex            sync.RWMutex
+	// readFile reads, verifies and decrypts a catalog file, nil when the
+	// source does not read files.
+	readFile       func(filename string) ([]byte, error)
+	loadFunc       func(filename string) (TMsgs, error)
+	saveFunc       func(filename string, lang string, msgs TMsgs) error
+	messages       map[string]TMsgs
+	missingFiles   MissingFilePolicy
+	normalizeKeys  KeyNormalization
+	fallbacks      map[string][]string
+	validateOnLoad bool
+	compileOnLoad  bool
+	// patterns the patterns compiled at load, by {lang}/{category}.
+	patterns map[string]compiledPatterns
+	// fetches bounds the concurrent fetches of catalog files, nil when they
+	// are fetched in turn.
+	fetches chan struct{}
+	// store the catalog files read, shared with the sources reading the
+	// same files.
+	store *catalogStore
+	// chains the fallback chains by language.
+	chains     sync.Map
+	observer   *observer
+	version    atomic.Pointer[string]
+	generation uint64
+	mutex      sync.RWMutex
 }
 
 // translate
 func (ms *MessageSource) Translate(category string, message string, lang string) (string, error) {
+	return ms.TranslateContext(context.Background(), category, message, lang)
+}
+
+// TranslateContext translate, tracing catalog loads as children of ctx.
+func (ms *MessageSource) TranslateContext(ctx context.Context, category string, message string, lang string) (string, error) {
 	if ms.ForceTranslation || lang != ms.OriginalLang {
-		return ms.TranslateMsg(category, message, lang)
+		return ms.translateMsg(ctx, category, message, lang)
 	}
 	return "", nil
 }
 
 // translate
 func (ms *MessageSource) TranslateMsg(category string, message string, lang string) (string, error) {
-	cates := strings.Split(category, ".")
-	key := cates[0] + "/" + lang + "/" + cates[1]
+	return ms.translateMsg(context.Background(), category, message, lang)
+}
 
+func (ms *MessageSource) translateMsg(ctx context.Context, category string, message string, lang string) (string, error) {
+	if _, _, err := splitCategory(category); err != nil {
+		return "", err
+	}
+	msgs, err := ms.catalog(ctx, category, lang)
+	if err != nil {
+		return "", err
+	}
+	if msg := msgs[ms.normalizeKeys.Normalize(message)]; msg != "" {
+		return msg, nil
+	}
+	return "", &MissingTranslationError{Category: category, Message: message, Lang: lang}
+}
+
+// TranslateBatch Returns the translations of messages in lang from a single
+// lookup of the catalog, its fallbacks merged. As Translate, it translates
+// nothing in the original language unless ForceTranslation is set.
+func (ms *MessageSource) TranslateBatch(category string, messages []string, lang string) (TMsgs, error) {
+	translations := make(TMsgs, len(messages))
+	if !ms.ForceTranslation && lang == ms.OriginalLang {
+		return translations, nil
+	}
+	if _, _, err := splitCategory(category); err != nil {
+		return nil, err
+	}
+	msgs, err := ms.catalog(context.Background(), category, lang)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		if msg := msgs[ms.normalizeKeys.Normalize(message)]; msg != "" {
+			translations[message] = msg
+		}
+	}
+	return translations, nil
+}
+
+// catalog Returns the cached messages of category and lang, loading them on
+// first use. Catalogs that do not exist are cached empty, so messages missing
+// from them do not read the disk again until Reload.
+func (ms *MessageSource) catalog(ctx context.Context, category string, lang string) (TMsgs, error) {
+	key := lang + "/" + category
 	ms.mutex.RLock()
-	defer ms.mutex.RUnlock()
+	msgs, ok := ms.messages[key]
+	generation := ms.generation
+	ms.mutex.RUnlock()
+	ms.observer.cacheLookup(ok)
+	if ok {
+		return msgs, nil
+	}
+	msgs, err := ms.loadMsgs(ctx, category, lang)
+	if errors.Is(err, ErrCatalogNotFound) && ms.missingFiles == FallbackOnly {
+		msgs, err = TMsgs{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ms.normalizeKeys != 0 {
+		msgs = normalizeMsgs(msgs, ms.normalizeKeys)
+	}
+	if ms.validateOnLoad && lang != ms.OriginalLang {
+		original, _ := ms.loadFunc(ms.GetMsgFilePath(category, ms.OriginalLang))
+		for _, issue := range ValidateMsgs(lang, msgs, original) {
+			ms.observer.invalid(category, issue)
+		}
+	}
+	patterns := ms.compile(category, lang, msgs)
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	if cached, ok := ms.messages[key]; ok {
+		return cached, nil
+	}
+	if ms.generation == generation {
+		ms.messages[key] = msgs
+		ms.setPatterns(key, patterns)
+	}
+	return msgs, nil
+}
+
+// compiledPatterns the ICU patterns of a catalog parsed at load.
+type compiledPatterns struct {
+	nodes map[string][]Node
+	stats CatalogStats
+}
+
+// patternCompiler is implemented by sources compiling the patterns of their
+// catalogs at load, see Config.CompileOnLoad.
+type patternCompiler interface {
+	compiledPattern(category string, lang string, pattern string) ([]Node, bool)
+	catalogStats() []CatalogStats
+}
 
-	if _, ok := ms.messages[key]; !ok {
-		val, err := ms.LoadMsgs(category, lang)
+// compile Parses the patterns of msgs when CompileOnLoad is set, logging
+// those that fail to parse unless ValidateOnLoad already has.
+func (ms *MessageSource) compile(category string, lang string, msgs TMsgs) *compiledPatterns {
+	if !ms.compileOnLoad {
+		return nil
+	}
+	start := time.Now()
+	patterns := &compiledPatterns{
+		nodes: make(map[string][]Node),
+		stats: CatalogStats{Category: category, Lang: lang, Messages: len(msgs)},
+	}
+	for key, val := range msgs {
+		if !strings.Contains(val, "{") {
+			continue
+		}
+		patterns.stats.Patterns++
+		nodes, err := parsePattern(val)
 		if err != nil {
-			return "", err
+			patterns.stats.InvalidPatterns++
+			if !ms.validateOnLoad || lang == ms.OriginalLang {
+				ms.observer.invalid(category, ValidationIssue{Lang: lang, Key: key, Kind: IssueInvalidPattern, Detail: err.Error()})
+			}
+			continue
+		}
+		if complexArg.MatchString(val) {
+			patterns.nodes[val] = nodes
 		}
-		ms.messages[key] = val
 	}
-	if msg, ok := ms.messages[key][message]; ok && msg != "" {
-		return msg, nil
+	patterns.stats.CompileTime = time.Since(start)
+	return patterns
+}
+
+// setPatterns Caches the patterns compiled of the catalog key; ms.mutex is
+// held.
+func (ms *MessageSource) setPatterns(key string, patterns *compiledPatterns) {
+	if patterns != nil {
+		ms.patterns[key] = *patterns
 	}
+}
 
-	ms.messages[key] = TMsgs{message: ""}
-	return "", nil
+// compiledPattern Returns the nodes of pattern compiled with the catalog of
+// category and lang.
+func (ms *MessageSource) compiledPattern(category string, lang string, pattern string) ([]Node, bool) {
+	if !ms.compileOnLoad {
+		return nil, false
+	}
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+	nodes, ok := ms.patterns[lang+"/"+category].nodes[pattern]
+	return nodes, ok
 }
 
-// Get messages file path.
+// catalogStats Returns the statistics of the catalogs compiled, by language
+// and category.
+func (ms *MessageSource) catalogStats() []CatalogStats {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+	stats := make([]CatalogStats, 0, len(ms.patterns))
+	for _, key := range sortedKeys(ms.patterns) {
+		stats = append(stats, ms.patterns[key].stats)
+	}
+	return stats
+}
+
+// preload Loads the catalog of category and lang into the cache.
+func (ms *MessageSource) preload(ctx context.Context, category string, lang string) error {
+	_, err := ms.catalog(ctx, category, lang)
+	return err
+}
+
+// splitCategory Returns the prefix of category, which selects its Config,
+// and its name, which selects its catalog file: "app.admin.users" is prefix
+// "app" and name "admin.users". A category without a dot names itself.
+func splitCategory(category string) (prefix string, name string, err error) {
+	if category == "" || strings.HasPrefix(category, ".") || strings.HasSuffix(category, ".") || strings.Contains(category, "..") {
+		return "", "", fmt.Errorf("%w %q", ErrInvalidCategory, category)
+	}
+	prefix, name, ok := strings.Cut(category, ".")
+	if !ok {
+		name = category
+	}
+	return prefix, name, nil
+}
+
+// Get messages file path. A category name in FileMap uses the mapped file
+// under {BasePath}/{lang}; any other name is the file name with the suffix
+// of the source, dots and backslashes being directories: "app.admin.users"
+// is {BasePath}/{lang}/admin/users.json. Versioned sources read
+// {BasePath}/{version}/{lang}.
 func (ms *MessageSource) GetMsgFilePath(category string, lang string) string {
-	suffix := strings.Split(category, ".")[1]
-	path := ms.BasePath + "/" + lang + "/"
-	if v, ok := ms.FileMap[suffix]; !ok {
-		path += v
-	} else {
-		path += strings.Replace(suffix, "\\", "/", -1)
-		if ms.fileSuffix != "" {
-			path += "." + ms.fileSuffix
-		}
+	_, name, _ := splitCategory(category)
+	path := ms.dir() + "/" + lang + "/"
+	if v := ms.FileMap[name]; v != "" {
+		return path + v
+	}
+	path += strings.NewReplacer("\\", "/", ".", "/").Replace(name)
+	if ms.fileSuffix != "" {
+		path += "." + ms.fileSuffix
 	}
 	return path
 }
@@ -84,51 +420,285 @@ func (ms *MessageSource) GetMsgFilePath(category string, lang string) string {
 // load the messages for [[originalLang]]. For example: [[originalLang]] is `en-GB`,
 // language is `en`. The method will load the messages for `en` and merge them over `en-GB`.
 func (ms *MessageSource) LoadMsgs(category string, lang string) (TMsgs, error) {
+	return ms.loadMsgs(context.Background(), category, lang)
+}
+
+// loadMsgs LoadMsgs, applying the MissingFilePolicy of the source.
+func (ms *MessageSource) loadMsgs(ctx context.Context, category string, lang string) (TMsgs, error) {
+	required := ms.missingFiles == ErrorOnMissingFile && lang != ms.OriginalLang && !strings.Contains(lang, "@")
+	msgs, err := ms.load(ctx, category, lang, required)
+	if errors.Is(err, ErrCatalogNotFound) && ms.missingFiles == IgnoreMissingFile {
+		return TMsgs{}, nil
+	}
+	return msgs, err
+}
+
+// load Loads the catalog of lang merged over its fallbacks. Unless required,
+// the file of lang may be missing when a fallback exists.
+func (ms *MessageSource) load(ctx context.Context, category string, lang string, required bool) (msgs TMsgs, err error) {
+	if _, _, err := splitCategory(category); err != nil {
+		return nil, err
+	}
+	_, span := ms.observer.startSpan(ctx, "ii18n.LoadMsgs")
+	defer func(start time.Time) {
+		ms.observer.loaded(category, lang, start, err)
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}(time.Now())
 	msgFile := ms.GetMsgFilePath(category, lang)
-	msgs, err := ms.loadFunc(msgFile)
-	if err != nil {
+	span.SetAttribute("ii18n.category", category)
+	span.SetAttribute("ii18n.lang", lang)
+	span.SetAttribute("ii18n.source", ms.fileSuffix)
+	if ms.observer.tracing() {
+		if info, err := os.Stat(msgFile); err == nil {
+			span.SetAttribute("ii18n.bytes", info.Size())
+		}
+	}
+	if _, ok := ctx.Value(fetchedKey{}).(map[string]fetched); !ok && ms.fetches != nil {
+		ctx = ms.prefetch(ctx, category, lang)
+	}
+	msgs, err = ms.read(ctx, category, lang)
+	if err != nil && (required || !errors.Is(err, ErrCatalogNotFound)) {
 		return nil, err
 	}
-	fbLang := lang[0:2]
-	fbOriginalLang := ms.OriginalLang[0:2]
-	if lang != fbLang {
-		msgs, err = ms.LoadFallbackMsgs(category, fbLang, msgs, msgFile)
-	} else if lang == fbOriginalLang {
-		msgs, err = ms.LoadFallbackMsgs(category, ms.OriginalLang, msgs, msgFile)
-	} else {
+	// The fallbacks merge into a copy of the catalog read.
+	msgs = maps.Clone(msgs)
+	if chain, ok := ms.fallbacks[lang]; ok {
+		return ms.loadChain(ctx, category, chain, msgs, msgFile)
+	}
+	fbLang := parentLang(lang)
+	if fbLang == "" && lang != ms.OriginalLang && lang == baseLang(ms.OriginalLang) {
+		fbLang = ms.OriginalLang
+	}
+	if fbLang != "" {
+		return ms.loadFallback(ctx, category, fbLang, msgs, msgFile)
+	}
+	if msgs == nil {
+		return nil, &LoadError{Path: msgFile, Err: ErrCatalogNotFound}
+	}
+	return msgs, nil
+}
+
+// loadChain Merges msgs, the catalog of msgFile, over the catalogs of chain
+// in order. The languages of chain are loaded without their own fallbacks.
+func (ms *MessageSource) loadChain(ctx context.Context, category string, chain []string, msgs TMsgs, msgFile string) (TMsgs, error) {
+	for _, fbLang := range chain {
+		fbMsgs, err := ms.read(ctx, category, fbLang)
+		if err != nil && !errors.Is(err, ErrCatalogNotFound) {
+			return nil, err
+		}
+		if fbMsgs == nil {
+			continue
+		}
+		ms.observer.fallback(category, fbLang)
 		if msgs == nil {
-			return nil, errors.New("the message file for category " + category + " does not exist: " + msgFile)
+			msgs = maps.Clone(fbMsgs)
+		} else {
+			mergeMsgs(msgs, fbMsgs)
 		}
 	}
-	if err != nil {
+	if msgs == nil {
+		return nil, &LoadError{Path: msgFile, Err: ErrCatalogNotFound}
+	}
+	return msgs, nil
+}
+
+// mergeMsgs Fills the messages of msgs that are missing or empty from
+// fbMsgs.
+func mergeMsgs(msgs TMsgs, fbMsgs TMsgs) {
+	for key, val := range fbMsgs {
+		if v, ok := msgs[key]; val != "" && (!ok || v == "") {
+			msgs[key] = val
+		}
+	}
+}
+
+// internMsgs Returns a copy of msgs with interned keys and messages, shared
+// by the equal strings interned, and the handles of the strings. The strings
+// stay canonical only while their handles are kept.
+func internMsgs(msgs TMsgs) (TMsgs, []unique.Handle[string]) {
+	interned := make(TMsgs, len(msgs))
+	handles := make([]unique.Handle[string], 0, 2*len(msgs))
+	for key, val := range msgs {
+		k, v := unique.Make(key), unique.Make(val)
+		interned[k.Value()] = v.Value()
+		handles = append(handles, k, v)
+	}
+	return interned, handles
+}
+
+// fetched a catalog file as read, cached by read until a reload.
+type fetched struct {
+	msgs     TMsgs
+	err      error
+	category string
+	lang     string
+	// handles the handles of the strings of msgs, kept with the file so the
+	// files read later share them.
+	handles []unique.Handle[string]
+}
+
+// fetchedKey the context key of the catalog files fetched for a load.
+type fetchedKey struct{}
+
+// prefetch Returns ctx with the catalog files of category in lang and its
+// fallbacks, those not cached fetched concurrently within the bound of the
+// source.
+func (ms *MessageSource) prefetch(ctx context.Context, category string, lang string) context.Context {
+	langs := ms.fallbackChain(lang)
+	if len(langs) < 2 {
+		return ctx
+	}
+	files := make(map[string]fetched, len(langs))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, l := range langs {
+		if _, ok := ms.cached(category, l); ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msgs, err := ms.read(ctx, category, l)
+			mutex.Lock()
+			files[l+"/"+category] = fetched{msgs: msgs, err: err}
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+	return context.WithValue(ctx, fetchedKey{}, files)
+}
+
+// read Returns the catalog file of category and lang as read, shared by the
+// loads of every language falling back to it: fetched in ctx by prefetch,
+// cached, or else fetched now within the bound of the source. The keys and
+// messages of the catalogs read are interned, and must not be modified.
+func (ms *MessageSource) read(ctx context.Context, category string, lang string) (TMsgs, error) {
+	key := lang + "/" + category
+	if files, ok := ctx.Value(fetchedKey{}).(map[string]fetched); ok {
+		if f, ok := files[key]; ok {
+			return f.msgs, f.err
+		}
+	}
+	path := ms.GetMsgFilePath(category, lang)
+	f, ok, generation := ms.store.get(path)
+	if ok {
+		return f.msgs, f.err
+	}
+	if ms.fetches != nil {
+		ms.fetches <- struct{}{}
+		defer func() { <-ms.fetches }()
+	}
+	msgs, err := ms.loadFunc(path)
+	if err != nil && !errors.Is(err, ErrCatalogNotFound) {
 		return nil, err
 	}
+	var handles []unique.Handle[string]
+	if msgs != nil {
+		msgs, handles = internMsgs(msgs)
+	}
+	ms.store.put(path, fetched{msgs, err, category, lang, handles}, generation)
+	return msgs, err
+}
 
-	return msgs, nil
+// cached Returns the catalog file of category and lang cached by read.
+func (ms *MessageSource) cached(category string, lang string) (fetched, bool) {
+	f, ok, _ := ms.store.get(ms.GetMsgFilePath(category, lang))
+	return f, ok
+}
+
+// evict Removes the catalog files matched from the cache of read, so they
+// are read again, by every source sharing them.
+func (ms *MessageSource) evict(match func(category string, lang string) bool) {
+	ms.store.evict(func(path string, f fetched) bool {
+		return match(f.category, f.lang)
+	})
+}
+
+// fallbackChain Returns lang followed by the languages whose catalogs load
+// merges it over, in order. The chains are computed once, and must not be
+// modified.
+func (ms *MessageSource) fallbackChain(lang string) []string {
+	if chain, ok := ms.chains.Load(lang); ok {
+		return chain.([]string)
+	}
+	chain := ms.resolveChain(lang)
+	ms.chains.Store(lang, chain)
+	return chain
+}
+
+// resolveChain Returns the fallback chain of lang, see fallbackChain.
+func (ms *MessageSource) resolveChain(lang string) []string {
+	langs := []string{lang}
+	for {
+		if chain, ok := ms.fallbacks[lang]; ok {
+			return append(langs, chain...)
+		}
+		fbLang := parentLang(lang)
+		if fbLang == "" && lang != ms.OriginalLang && lang == baseLang(ms.OriginalLang) {
+			fbLang = ms.OriginalLang
+		}
+		if fbLang == "" {
+			return langs
+		}
+		langs = append(langs, fbLang)
+		if fbLang == ms.OriginalLang {
+			return langs
+		}
+		lang = fbLang
+	}
+}
+
+// parentLang Returns lang without its last subtag, "zh-Hant" for
+// "zh-Hant-TW", or "" for a bare language. The parent of a variant such as
+// "de@brandX" is its language, "de".
+func parentLang(lang string) string {
+	if pos := strings.IndexByte(lang, '@'); pos > 0 {
+		return lang[:pos]
+	}
+	if pos := strings.LastIndexAny(lang, "-_"); pos > 0 {
+		return lang[:pos]
+	}
+	return ""
 }
 
 // Loads the message translation for the specified $language and $category.
 // If translation for specific locale code such as `en-US` isn't found it
 // tries more generic `en`. When both are present, the `en-US` messages will be merged
+// over `en`. A fallback other than [[originalLang]] falls back in turn, so
+// `zh-Hant-TW` is merged over `zh-Hant`, itself merged over `zh`.
 func (ms *MessageSource) LoadFallbackMsgs(category string, fallbackLang string, msgs TMsgs, originalMsgFile string) (TMsgs, error) {
+	return ms.loadFallback(context.Background(), category, fallbackLang, msgs, originalMsgFile)
+}
+
+// loadFallback LoadFallbackMsgs, reading the files fetched in ctx.
+func (ms *MessageSource) loadFallback(ctx context.Context, category string, fallbackLang string, msgs TMsgs, originalMsgFile string) (TMsgs, error) {
 	fbMsgFile := ms.GetMsgFilePath(category, fallbackLang)
-	fbMsgs, _ := ms.loadFunc(fbMsgFile)
+	var fbMsgs TMsgs
+	var err error
+	if fallbackLang == ms.OriginalLang {
+		fbMsgs, err = ms.read(ctx, category, fallbackLang)
+	} else {
+		fbMsgs, err = ms.load(ctx, category, fallbackLang, false)
+	}
+	if err != nil && !errors.Is(err, ErrCatalogNotFound) {
+		return nil, err
+	}
 	if msgs == nil && fbMsgs == nil &&
 		fallbackLang != ms.OriginalLang &&
-		fallbackLang != ms.OriginalLang[0:2] {
-		return nil, errors.New("The message file for category " + category + " does not exist: " + originalMsgFile + " Fallback file does not exist as well: " + fbMsgFile)
+		fallbackLang != baseLang(ms.OriginalLang) {
+		return nil, &LoadError{Path: originalMsgFile, Err: fmt.Errorf("%w, nor its fallback %s", ErrCatalogNotFound, fbMsgFile)}
 	} else if msgs == nil {
-		return fbMsgs, nil
-	} else if fbMsgs != nil {
-		ms.mutex.Lock()
-		defer ms.mutex.Unlock()
-
-		for key, val := range fbMsgs {
-			v, ok := msgs[key]
-			if val != "" && (!ok || v == "") {
-				msgs[key] = val
-			}
+		if fbMsgs == nil {
+			return nil, nil
 		}
+		ms.observer.fallback(category, fallbackLang)
+		return maps.Clone(fbMsgs), nil
+	} else if fbMsgs != nil {
+		ms.observer.fallback(category, fallbackLang)
+		mergeMsgs(msgs, fbMsgs)
 	}
 
 	return msgs, nil
@@ -138,3 +708,184 @@ func (ms *MessageSource) LoadFallbackMsgs(category string, fallbackLang string,
 func LoadMsgsFromFile(filename string) (TMsgs, error) {
 	return nil, nil
 }
+
+// Saves msgs into the catalog file, keeping the values already present.
+func (ms *MessageSource) SaveMsgs(category string, lang string, msgs TMsgs) error {
+	if ms.saveFunc == nil {
+		return fmt.Errorf("saving messages of category %s: %w", category, errors.ErrUnsupported)
+	}
+	msgFile := ms.GetMsgFilePath(category, lang)
+	current, err := ms.loadFunc(msgFile)
+	if err != nil && !errors.Is(err, ErrCatalogNotFound) {
+		return err
+	}
+	if current == nil {
+		current = make(TMsgs, len(msgs))
+	}
+	for key, val := range msgs {
+		if _, ok := current[key]; !ok {
+			current[key] = val
+		}
+	}
+	if err := ms.saveFunc(msgFile, lang, current); err != nil {
+		return err
+	}
+	ms.evict(func(c string, l string) bool { return c == category && l == lang })
+	return nil
+}
+
+// Reload reads every cached catalog again. A catalog that fails to load
+// keeps its previous messages and the first error, in the order of
+// language and category, is returned; one that no longer exists is cached
+// empty.
+func (ms *MessageSource) Reload() error {
+	all := func(category string, lang string) bool { return true }
+	ms.evict(all)
+	return ms.reload(all)
+}
+
+// ReloadCatalog Refreshes the cached catalogs of category in lang and in
+// the languages falling back to it, or in every language when lang is "".
+func (ms *MessageSource) ReloadCatalog(category string, lang string) error {
+	ms.evict(func(c string, l string) bool {
+		return c == category && (lang == "" || l == lang)
+	})
+	return ms.reload(func(c string, l string) bool {
+		return c == category && (lang == "" || slices.Contains(ms.fallbackChain(l), lang))
+	})
+}
+
+// reload Refreshes the cached catalogs matched by match.
+func (ms *MessageSource) reload(match func(category string, lang string) bool) error {
+	ms.mutex.RLock()
+	keys := sortedKeys(ms.messages)
+	generation := ms.generation
+	ms.mutex.RUnlock()
+
+	var firstErr error
+	for _, key := range keys {
+		lang, category, _ := strings.Cut(key, "/")
+		if !match(category, lang) {
+			continue
+		}
+		msgs, err := ms.LoadMsgs(category, lang)
+		if errors.Is(err, ErrCatalogNotFound) {
+			msgs, err = TMsgs{}, nil
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ms.normalizeKeys != 0 {
+			msgs = normalizeMsgs(msgs, ms.normalizeKeys)
+		}
+		patterns := ms.compile(category, lang, msgs)
+		ms.mutex.Lock()
+		if ms.generation == generation {
+			ms.messages[key] = msgs
+			ms.setPatterns(key, patterns)
+		}
+		ms.mutex.Unlock()
+	}
+	return firstErr
+}
+
+// dir Returns the directory of the language directories, BasePath or the
+// directory of the pinned version.
+func (ms *MessageSource) dir() string {
+	if version := ms.version.Load(); version != nil {
+		return ms.BasePath + "/" + *version
+	}
+	return ms.BasePath
+}
+
+// Version Returns the pinned version of the catalogs, "" when the source is
+// not versioned.
+func (ms *MessageSource) Version() string {
+	if version := ms.version.Load(); version != nil {
+		return *version
+	}
+	return ""
+}
+
+// PinVersion Switches to the catalogs of version, the directory
+// {BasePath}/{version}, dropping the cached catalogs at once so that no
+// message of the previous version is served after it returns.
+func (ms *MessageSource) PinVersion(version string) error {
+	if ms.version.Load() == nil {
+		return errors.New("source of " + ms.BasePath + " is not versioned")
+	}
+	if version == "" || strings.ContainsAny(version, "/\\") || version == "." || version == ".." {
+		return errors.New("invalid version " + strconv.Quote(version))
+	}
+	if info, err := os.Stat(ms.BasePath + "/" + version); err != nil {
+		return &LoadError{Path: ms.BasePath + "/" + version, Err: err}
+	} else if !info.IsDir() {
+		return errors.New(ms.BasePath + "/" + version + " is not a directory")
+	}
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	ms.version.Store(&version)
+	ms.messages = make(map[string]TMsgs)
+	ms.patterns = make(map[string]compiledPatterns)
+	ms.generation++
+	return nil
+}
+
+// Returns the languages with a directory under BasePath, or the pinned
+// version, sorted.
+func (ms *MessageSource) AvailableLanguages() ([]string, error) {
+	entries, err := os.ReadDir(ms.dir())
+	if err != nil {
+		return nil, err
+	}
+	var langs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			langs = append(langs, entry.Name())
+		}
+	}
+	return langs, nil
+}
+
+// Returns the names of the catalogs of OriginalLang, sorted. Files mapped by
+// FileMap are named by their entry, others by their path without suffix,
+// directories being dots.
+func (ms *MessageSource) CategoryNames() ([]string, error) {
+	root := ms.dir() + "/" + ms.OriginalLang
+	mapped := make(map[string]string, len(ms.FileMap))
+	for name, file := range ms.FileMap {
+		if file != "" {
+			mapped[file] = name
+		}
+	}
+	var names []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if name, ok := mapped[rel]; ok {
+			names = append(names, name)
+		} else if ms.fileSuffix != "" && strings.HasSuffix(rel, "."+ms.fileSuffix) {
+			names = append(names, strings.ReplaceAll(strings.TrimSuffix(rel, "."+ms.fileSuffix), "/", "."))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Loads the messages of the catalog for category and lang, without fallback.
+func (ms *MessageSource) LoadCatalog(category string, lang string) (TMsgs, error) {
+	return ms.loadFunc(ms.GetMsgFilePath(category, lang))
+}
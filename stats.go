@@ -0,0 +1,47 @@
+package ii18n
+
+import "time"
+
+// Stats statistics of the catalogs loaded by an I18N.
+type Stats struct {
+	// Catalogs the catalogs compiled at load, see Config.CompileOnLoad, by
+	// source then language and category.
+	Catalogs []CatalogStats
+}
+
+// CatalogStats statistics of a catalog compiled at load.
+type CatalogStats struct {
+	Category string
+	Lang     string
+	// Messages in the catalog, its fallbacks merged.
+	Messages int
+	// Patterns messages with placeholders or ICU arguments.
+	Patterns int
+	// InvalidPatterns patterns that failed to parse.
+	InvalidPatterns int
+	// CompileTime the time taken to parse the patterns.
+	CompileTime time.Duration
+}
+
+// Stats Returns the statistics of the catalogs loaded by the sources of i,
+// in the order of their prefixes, then of the overrides.
+func (i *I18N) Stats() Stats {
+	var stats Stats
+	i.mutex.RLock()
+	var sources []Source
+	for _, prefix := range sortedKeys(i.Translations) {
+		if s := i.Translations[prefix].source; s != nil {
+			sources = append(sources, s)
+		}
+	}
+	i.mutex.RUnlock()
+	for _, o := range i.overrides {
+		sources = append(sources, o.source)
+	}
+	for _, s := range sources {
+		if pc, ok := s.(patternCompiler); ok {
+			stats.Catalogs = append(stats.Catalogs, pc.catalogStats()...)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,151 @@
+package ii18n
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Type StringsSource reads Apple .strings catalogs.
+type StringsSource struct {
+	MessageSource
+}
+
+// New StringsSource
+func NewStringsSource(conf *Config) Source {
+	s := &StringsSource{}
+	s.init(conf, "strings", stringsCodec{})
+
+	return s
+}
+
+// stringsCodec Apple .strings files: "key" = "value"; with C comments.
+type stringsCodec struct{}
+
+func (stringsCodec) Decode(data []byte) (TMsgs, error) {
+	msgs := make(TMsgs)
+	s := string(data)
+	pos := 0
+	errorf := func(msg string) error {
+		return errors.New(msg + " at offset " + strconv.Itoa(pos))
+	}
+	skip := func() error {
+		for pos < len(s) {
+			switch {
+			case s[pos] == ' ' || s[pos] == '\t' || s[pos] == '\n' || s[pos] == '\r':
+				pos++
+			case strings.HasPrefix(s[pos:], "//"):
+				end := strings.IndexByte(s[pos:], '\n')
+				if end == -1 {
+					pos = len(s)
+				} else {
+					pos += end
+				}
+			case strings.HasPrefix(s[pos:], "/*"):
+				end := strings.Index(s[pos+2:], "*/")
+				if end == -1 {
+					return errorf("unterminated comment")
+				}
+				pos += end + 4
+			default:
+				return nil
+			}
+		}
+		return nil
+	}
+	str := func() (string, error) {
+		if pos >= len(s) || s[pos] != '"' {
+			return "", errorf("expected string")
+		}
+		var b strings.Builder
+		for pos++; pos < len(s); pos++ {
+			c := s[pos]
+			if c == '"' {
+				pos++
+				return b.String(), nil
+			}
+			if c != '\\' {
+				b.WriteByte(c)
+				continue
+			}
+			pos++
+			if pos >= len(s) {
+				break
+			}
+			switch s[pos] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				if pos+4 >= len(s) {
+					return "", errorf("invalid escape")
+				}
+				r, err := strconv.ParseUint(s[pos+1:pos+5], 16, 32)
+				if err != nil {
+					return "", errorf("invalid escape")
+				}
+				b.WriteRune(rune(r))
+				pos += 4
+			default:
+				b.WriteByte(s[pos])
+			}
+		}
+		return "", errorf("unterminated string")
+	}
+	for {
+		if err := skip(); err != nil {
+			return nil, err
+		}
+		if pos >= len(s) {
+			return msgs, nil
+		}
+		key, err := str()
+		if err != nil {
+			return nil, err
+		}
+		if err := skip(); err != nil {
+			return nil, err
+		}
+		if pos >= len(s) || s[pos] != '=' {
+			return nil, errorf("expected '='")
+		}
+		pos++
+		if err := skip(); err != nil {
+			return nil, err
+		}
+		val, err := str()
+		if err != nil {
+			return nil, err
+		}
+		if err := skip(); err != nil {
+			return nil, err
+		}
+		if pos >= len(s) || s[pos] != ';' {
+			return nil, errorf("expected ';'")
+		}
+		pos++
+		msgs[key] = val
+	}
+}
+
+// Encode Encodes msgs with sorted keys.
+func (stringsCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	var b bytes.Buffer
+	for _, key := range sortedKeys(msgs) {
+		b.WriteString(stringsQuote(key) + " = " + stringsQuote(msgs[key]) + ";\n")
+	}
+	return b.Bytes(), nil
+}
+
+// stringsQuote Quotes s as a .strings string.
+func stringsQuote(s string) string {
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, "�")
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s) + `"`
+}
@@ -0,0 +1,207 @@
+package ii18n
+
+import (
+	"strings"
+	"time"
+)
+
+// TimeZoneStyle the kind of name TimeZoneName returns.
+type TimeZoneStyle int
+
+// Time zone name styles.
+const (
+	// TimeZoneGeneric "Central European Time", whatever the season.
+	TimeZoneGeneric TimeZoneStyle = iota
+	// TimeZoneStandard "Central European Standard Time".
+	TimeZoneStandard
+	// TimeZoneDaylight "Central European Summer Time".
+	TimeZoneDaylight
+	// TimeZoneCity the exemplar city of the zone, "Berlin".
+	TimeZoneCity
+)
+
+// metazone CLDR names shared by the zones of a metazone, in the languages
+// of displayLangs. Metazones without daylight time leave daylight empty.
+type metazone struct {
+	generic  [7]string
+	standard [7]string
+	daylight [7]string
+}
+
+// zoneMetazones metazones of IANA time zones.
+var zoneMetazones = map[string]string{
+	"Europe/Amsterdam": "Europe_Central", "Europe/Berlin": "Europe_Central", "Europe/Brussels": "Europe_Central",
+	"Europe/Copenhagen": "Europe_Central", "Europe/Madrid": "Europe_Central", "Europe/Oslo": "Europe_Central",
+	"Europe/Paris": "Europe_Central", "Europe/Prague": "Europe_Central", "Europe/Rome": "Europe_Central",
+	"Europe/Stockholm": "Europe_Central", "Europe/Vienna": "Europe_Central", "Europe/Warsaw": "Europe_Central",
+	"Europe/Zurich": "Europe_Central",
+	"Europe/Athens": "Europe_Eastern", "Europe/Bucharest": "Europe_Eastern", "Europe/Helsinki": "Europe_Eastern",
+	"Europe/Kiev": "Europe_Eastern", "Europe/Kyiv": "Europe_Eastern",
+	"Europe/Lisbon": "Europe_Western", "Atlantic/Canary": "Europe_Western",
+	"Europe/London": "GMT", "Europe/Dublin": "GMT", "Africa/Abidjan": "GMT",
+	"Europe/Moscow":    "Moscow",
+	"America/New_York": "America_Eastern", "America/Detroit": "America_Eastern", "America/Toronto": "America_Eastern",
+	"America/Chicago": "America_Central", "America/Mexico_City": "America_Central", "America/Winnipeg": "America_Central",
+	"America/Denver": "America_Mountain", "America/Phoenix": "America_Mountain", "America/Edmonton": "America_Mountain",
+	"America/Los_Angeles": "America_Pacific", "America/Vancouver": "America_Pacific",
+	"America/Sao_Paulo": "Brasilia",
+	"Asia/Tokyo":        "Japan",
+	"Asia/Shanghai":     "China",
+	"Asia/Kolkata":      "India", "Asia/Calcutta": "India",
+	"Australia/Sydney": "Australia_Eastern", "Australia/Melbourne": "Australia_Eastern", "Australia/Brisbane": "Australia_Eastern",
+	"UTC": "UTC", "Etc/UTC": "UTC",
+}
+
+// metazones names of metazones.
+var metazones = map[string]*metazone{
+	"Europe_Central": {
+		generic:  [7]string{"Central European Time", "Mitteleuropäische Zeit", "heure d’Europe centrale", "hora de Europa central", "中央ヨーロッパ時間", "中欧时间", "Центральная Европа"},
+		standard: [7]string{"Central European Standard Time", "Mitteleuropäische Normalzeit", "heure normale d’Europe centrale", "hora estándar de Europa central", "中央ヨーロッパ標準時", "中欧标准时间", "Центральная Европа, стандартное время"},
+		daylight: [7]string{"Central European Summer Time", "Mitteleuropäische Sommerzeit", "heure d’été d’Europe centrale", "hora de verano de Europa central", "中央ヨーロッパ夏時間", "中欧夏令时间", "Центральная Европа, летнее время"},
+	},
+	"Europe_Eastern": {
+		generic:  [7]string{"Eastern European Time", "Osteuropäische Zeit", "heure d’Europe de l’Est", "hora de Europa oriental", "東ヨーロッパ時間", "东欧时间", "Восточная Европа"},
+		standard: [7]string{"Eastern European Standard Time", "Osteuropäische Normalzeit", "heure normale d’Europe de l’Est", "hora estándar de Europa oriental", "東ヨーロッパ標準時", "东欧标准时间", "Восточная Европа, стандартное время"},
+		daylight: [7]string{"Eastern European Summer Time", "Osteuropäische Sommerzeit", "heure d’été d’Europe de l’Est", "hora de verano de Europa oriental", "東ヨーロッパ夏時間", "东欧夏令时间", "Восточная Европа, летнее время"},
+	},
+	"Europe_Western": {
+		generic:  [7]string{"Western European Time", "Westeuropäische Zeit", "heure d’Europe de l’Ouest", "hora de Europa occidental", "西ヨーロッパ時間", "西欧时间", "Западная Европа"},
+		standard: [7]string{"Western European Standard Time", "Westeuropäische Normalzeit", "heure normale d’Europe de l’Ouest", "hora estándar de Europa occidental", "西ヨーロッパ標準時", "西欧标准时间", "Западная Европа, стандартное время"},
+		daylight: [7]string{"Western European Summer Time", "Westeuropäische Sommerzeit", "heure d’été d’Europe de l’Ouest", "hora de verano de Europa occidental", "西ヨーロッパ夏時間", "西欧夏令时间", "Западная Европа, летнее время"},
+	},
+	"GMT": {
+		generic:  [7]string{"Greenwich Mean Time", "Mittlere Greenwich-Zeit", "heure moyenne de Greenwich", "hora del meridiano de Greenwich", "グリニッジ標準時", "格林尼治标准时间", "Среднее время по Гринвичу"},
+		standard: [7]string{"Greenwich Mean Time", "Mittlere Greenwich-Zeit", "heure moyenne de Greenwich", "hora del meridiano de Greenwich", "グリニッジ標準時", "格林尼治标准时间", "Среднее время по Гринвичу"},
+	},
+	"Moscow": {
+		generic:  [7]string{"Moscow Time", "Moskauer Zeit", "heure de Moscou", "hora de Moscú", "モスクワ時間", "莫斯科时间", "Москва"},
+		standard: [7]string{"Moscow Standard Time", "Moskauer Normalzeit", "heure normale de Moscou", "hora estándar de Moscú", "モスクワ標準時", "莫斯科标准时间", "Москва, стандартное время"},
+		daylight: [7]string{"Moscow Summer Time", "Moskauer Sommerzeit", "heure d’été de Moscou", "hora de verano de Moscú", "モスクワ夏時間", "莫斯科夏令时间", "Москва, летнее время"},
+	},
+	"America_Eastern": {
+		generic:  [7]string{"Eastern Time", "Nordamerikanische Ostküstenzeit", "heure de l’Est nord-américain", "hora oriental", "アメリカ東部時間", "北美东部时间", "Восточная Америка"},
+		standard: [7]string{"Eastern Standard Time", "Nordamerikanische Ostküsten-Normalzeit", "heure normale de l’Est nord-américain", "hora estándar oriental", "アメリカ東部標準時", "北美东部标准时间", "Восточная Америка, стандартное время"},
+		daylight: [7]string{"Eastern Daylight Time", "Nordamerikanische Ostküsten-Sommerzeit", "heure d’été de l’Est nord-américain", "hora de verano oriental", "アメリカ東部夏時間", "北美东部夏令时间", "Восточная Америка, летнее время"},
+	},
+	"America_Central": {
+		generic:  [7]string{"Central Time", "Nordamerikanische Zentralzeit", "heure du centre nord-américain", "hora central", "アメリカ中部時間", "北美中部时间", "Центральная Америка"},
+		standard: [7]string{"Central Standard Time", "Nordamerikanische Zentral-Normalzeit", "heure normale du centre nord-américain", "hora estándar central", "アメリカ中部標準時", "北美中部标准时间", "Центральная Америка, стандартное время"},
+		daylight: [7]string{"Central Daylight Time", "Nordamerikanische Zentral-Sommerzeit", "heure d’été du centre nord-américain", "hora de verano central", "アメリカ中部夏時間", "北美中部夏令时间", "Центральная Америка, летнее время"},
+	},
+	"America_Mountain": {
+		generic:  [7]string{"Mountain Time", "Rocky-Mountain-Zeit", "heure des Rocheuses", "hora de las Montañas Rocosas", "アメリカ山地時間", "北美山区时间", "Горное время (Северная Америка)"},
+		standard: [7]string{"Mountain Standard Time", "Rocky-Mountain-Normalzeit", "heure normale des Rocheuses", "hora estándar de las Montañas Rocosas", "アメリカ山地標準時", "北美山区标准时间", "Стандартное горное время (Северная Америка)"},
+		daylight: [7]string{"Mountain Daylight Time", "Rocky-Mountain-Sommerzeit", "heure d’été des Rocheuses", "hora de verano de las Montañas Rocosas", "アメリカ山地夏時間", "北美山区夏令时间", "Летнее горное время (Северная Америка)"},
+	},
+	"America_Pacific": {
+		generic:  [7]string{"Pacific Time", "Nordamerikanische Westküstenzeit", "heure du Pacifique nord-américain", "hora del Pacífico", "アメリカ太平洋時間", "北美太平洋时间", "Тихоокеанское время"},
+		standard: [7]string{"Pacific Standard Time", "Nordamerikanische Westküsten-Normalzeit", "heure normale du Pacifique nord-américain", "hora estándar del Pacífico", "アメリカ太平洋標準時", "北美太平洋标准时间", "Тихоокеанское стандартное время"},
+		daylight: [7]string{"Pacific Daylight Time", "Nordamerikanische Westküsten-Sommerzeit", "heure d’été du Pacifique nord-américain", "hora de verano del Pacífico", "アメリカ太平洋夏時間", "北美太平洋夏令时间", "Тихоокеанское летнее время"},
+	},
+	"Brasilia": {
+		generic:  [7]string{"Brasilia Time", "Brasília-Zeit", "heure de Brasilia", "hora de Brasilia", "ブラジリア時間", "巴西利亚时间", "Бразилия"},
+		standard: [7]string{"Brasilia Standard Time", "Brasília-Normalzeit", "heure normale de Brasilia", "hora estándar de Brasilia", "ブラジリア標準時", "巴西利亚标准时间", "Бразилия, стандартное время"},
+		daylight: [7]string{"Brasilia Summer Time", "Brasília-Sommerzeit", "heure d’été de Brasilia", "hora de verano de Brasilia", "ブラジリア夏時間", "巴西利亚夏令时间", "Бразилия, летнее время"},
+	},
+	"Japan": {
+		generic:  [7]string{"Japan Time", "Japanische Zeit", "heure du Japon", "hora de Japón", "日本時間", "日本时间", "Япония"},
+		standard: [7]string{"Japan Standard Time", "Japanische Normalzeit", "heure normale du Japon", "hora estándar de Japón", "日本標準時", "日本标准时间", "Япония, стандартное время"},
+		daylight: [7]string{"Japan Daylight Time", "Japanische Sommerzeit", "heure d’été du Japon", "hora de verano de Japón", "日本夏時間", "日本夏令时间", "Япония, летнее время"},
+	},
+	"China": {
+		generic:  [7]string{"China Time", "Chinesische Zeit", "heure de la Chine", "hora de China", "中国時間", "中国时间", "Китай"},
+		standard: [7]string{"China Standard Time", "Chinesische Normalzeit", "heure normale de la Chine", "hora estándar de China", "中国標準時", "中国标准时间", "Китай, стандартное время"},
+		daylight: [7]string{"China Daylight Time", "Chinesische Sommerzeit", "heure d’été de Chine", "hora de verano de China", "中国夏時間", "中国夏令时间", "Китай, летнее время"},
+	},
+	"India": {
+		generic:  [7]string{"India Standard Time", "Indische Normalzeit", "heure de l’Inde", "hora de India", "インド標準時", "印度时间", "Индия"},
+		standard: [7]string{"India Standard Time", "Indische Normalzeit", "heure de l’Inde", "hora de India", "インド標準時", "印度时间", "Индия"},
+	},
+	"Australia_Eastern": {
+		generic:  [7]string{"Eastern Australia Time", "Ostaustralische Zeit", "heure de l’Est de l’Australie", "hora de Australia oriental", "オーストラリア東部時間", "澳大利亚东部时间", "Восточная Австралия"},
+		standard: [7]string{"Australian Eastern Standard Time", "Ostaustralische Normalzeit", "heure normale de l’Est de l’Australie", "hora estándar de Australia oriental", "オーストラリア東部標準時", "澳大利亚东部标准时间", "Восточная Австралия, стандартное время"},
+		daylight: [7]string{"Australian Eastern Daylight Time", "Ostaustralische Sommerzeit", "heure d’été de l’Est de l’Australie", "hora de verano de Australia oriental", "オーストラリア東部夏時間", "澳大利亚东部夏令时间", "Восточная Австралия, летнее время"},
+	},
+	"UTC": {
+		generic:  [7]string{"Coordinated Universal Time", "Koordinierte Weltzeit", "temps universel coordonné", "tiempo universal coordinado", "協定世界時", "协调世界时", "Всемирное координированное время"},
+		standard: [7]string{"Coordinated Universal Time", "Koordinierte Weltzeit", "temps universel coordonné", "tiempo universal coordinado", "協定世界時", "协调世界时", "Всемирное координированное время"},
+	},
+}
+
+// zoneDaylightNames daylight names of zones whose daylight time is not
+// the one of their metazone.
+var zoneDaylightNames = map[string][7]string{
+	"Europe/London": {"British Summer Time", "Britische Sommerzeit", "heure d’été britannique", "hora de verano británica", "英国夏時間", "英国夏令时间", "Великобритания, летнее время"},
+	"Europe/Dublin": {"Irish Standard Time", "Irische Sommerzeit", "heure d’été irlandaise", "hora de verano de Irlanda", "アイルランド標準時", "爱尔兰标准时间", "Ирландия, стандартное время"},
+}
+
+// exemplarCities localized exemplar cities of zones. Other zones use the
+// city of their IANA identifier.
+var exemplarCities = map[string][7]string{
+	"America/Chicago":     {"Chicago", "Chicago", "Chicago", "Chicago", "シカゴ", "芝加哥", "Чикаго"},
+	"America/Denver":      {"Denver", "Denver", "Denver", "Denver", "デンバー", "丹佛", "Денвер"},
+	"America/Los_Angeles": {"Los Angeles", "Los Angeles", "Los Angeles", "Los Ángeles", "ロサンゼルス", "洛杉矶", "Лос-Анджелес"},
+	"America/New_York":    {"New York", "New York", "New York", "Nueva York", "ニューヨーク", "纽约", "Нью-Йорк"},
+	"America/Sao_Paulo":   {"São Paulo", "São Paulo", "São Paulo", "São Paulo", "サンパウロ", "圣保罗", "Сан-Паулу"},
+	"Asia/Kolkata":        {"Kolkata", "Kalkutta", "Calcutta", "Calcuta", "コルカタ", "加尔各答", "Калькутта"},
+	"Asia/Shanghai":       {"Shanghai", "Shanghai", "Shanghai", "Shanghái", "上海", "上海", "Шанхай"},
+	"Asia/Tokyo":          {"Tokyo", "Tokio", "Tokyo", "Tokio", "東京", "东京", "Токио"},
+	"Australia/Sydney":    {"Sydney", "Sydney", "Sydney", "Sídney", "シドニー", "悉尼", "Сидней"},
+	"Europe/Athens":       {"Athens", "Athen", "Athènes", "Atenas", "アテネ", "雅典", "Афины"},
+	"Europe/Berlin":       {"Berlin", "Berlin", "Berlin", "Berlín", "ベルリン", "柏林", "Берлин"},
+	"Europe/Kyiv":         {"Kyiv", "Kiew", "Kyiv", "Kiev", "キーウ", "基辅", "Киев"},
+	"Europe/Lisbon":       {"Lisbon", "Lissabon", "Lisbonne", "Lisboa", "リスボン", "里斯本", "Лиссабон"},
+	"Europe/London":       {"London", "London", "Londres", "Londres", "ロンドン", "伦敦", "Лондон"},
+	"Europe/Madrid":       {"Madrid", "Madrid", "Madrid", "Madrid", "マドリード", "马德里", "Мадрид"},
+	"Europe/Moscow":       {"Moscow", "Moskau", "Moscou", "Moscú", "モスクワ", "莫斯科", "Москва"},
+	"Europe/Paris":        {"Paris", "Paris", "Paris", "París", "パリ", "巴黎", "Париж"},
+	"Europe/Rome":         {"Rome", "Rom", "Rome", "Roma", "ローマ", "罗马", "Рим"},
+	"Europe/Vienna":       {"Vienna", "Wien", "Vienne", "Viena", "ウィーン", "维也纳", "Вена"},
+	"Europe/Zurich":       {"Zurich", "Zürich", "Zurich", "Zúrich", "チューリッヒ", "苏黎世", "Цюрих"},
+}
+
+// cityZoneFormats patterns naming a zone without metazone names after its
+// city {0}, in the languages of displayLangs.
+var cityZoneFormats = [7]string{"{0} Time", "Zeit: {0}", "heure : {0}", "hora de {0}", "{0}時間", "{0}时间", "{0}"}
+
+// TimeZoneName Returns the name of tz in lang with style, falling back to
+// English for languages without zone names. Zones without names of their
+// own are named after their city, "Kathmandu Time".
+func TimeZoneName(tz *time.Location, lang string, style TimeZoneStyle) string {
+	return zoneName(tz.String(), lang, style)
+}
+
+// zoneName Returns the name of the IANA zone id, see TimeZoneName.
+func zoneName(id string, lang string, style TimeZoneStyle) string {
+	col := max(displayColumn(lang), 0)
+	if style == TimeZoneCity {
+		return exemplarCity(id, col)
+	}
+	mz, ok := metazones[zoneMetazones[id]]
+	if !ok {
+		return strings.Replace(cityZoneFormats[col], "{0}", exemplarCity(id, col), 1)
+	}
+	switch style {
+	case TimeZoneStandard:
+		return mz.standard[col]
+	case TimeZoneDaylight:
+		if names, ok := zoneDaylightNames[id]; ok {
+			return names[col]
+		}
+		if mz.daylight[col] != "" {
+			return mz.daylight[col]
+		}
+		return mz.standard[col]
+	}
+	return mz.generic[col]
+}
+
+// exemplarCity Returns the city of the zone id in the language of column
+// col, "Sao Paulo" from "America/Sao_Paulo" when it has no localized name.
+func exemplarCity(id string, col int) string {
+	if names, ok := exemplarCities[id]; ok {
+		return names[col]
+	}
+	city := id[strings.LastIndex(id, "/")+1:]
+	return strings.Replace(city, "_", " ", -1)
+}
@@ -0,0 +1,217 @@
+package ii18n
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// TranslationMemory an index of translated messages answering fuzzy
+// queries, e.g. to suggest translations of new messages in a translation
+// UI. Add the messages, then Suggest, which is safe for concurrent use.
+type TranslationMemory struct {
+	// MinScore the score below which matches are not suggested, 0.5 when
+	// zero.
+	MinScore float64
+	units    []tmUnit
+	// sizes the number of trigrams of the units.
+	sizes []int
+	seen  map[tmUnit]bool
+	// index the units by language and trigram of their source.
+	index map[string]map[string][]int
+}
+
+// tmUnit a source text and its translation.
+type tmUnit struct {
+	category    string
+	source      string
+	lang        string
+	translation string
+}
+
+// Suggestion a translation of a text similar to the one queried.
+type Suggestion struct {
+	Category    string
+	Source      string
+	Translation string
+	// Score the similarity of Source to the query, from 0 to 1 for the
+	// same text.
+	Score float64
+}
+
+// NewTranslationMemory Returns an empty TranslationMemory.
+func NewTranslationMemory() *TranslationMemory {
+	return &TranslationMemory{seen: make(map[tmUnit]bool), index: make(map[string]map[string][]int)}
+}
+
+// TranslationMemory Returns a TranslationMemory of the translations of the
+// categories of i, every category of Categories when none is given. The
+// sources of the categories must implement Catalogs.
+func (i *I18N) TranslationMemory(categories ...string) (*TranslationMemory, error) {
+	if len(categories) == 0 {
+		var err error
+		if categories, err = i.Categories(); err != nil {
+			return nil, err
+		}
+	}
+	tm := NewTranslationMemory()
+	for _, category := range categories {
+		category = i.normalizeCategory(category)
+		s, ol := i.getSource(category)
+		cs, ok := s.(Catalogs)
+		if !ok {
+			continue
+		}
+		langs, err := cs.AvailableLanguages()
+		if err != nil {
+			return nil, err
+		}
+		original, err := cs.LoadCatalog(category, ol)
+		if err != nil && !errors.Is(err, ErrCatalogNotFound) {
+			return nil, err
+		}
+		for _, lang := range langs {
+			if lang == ol {
+				continue
+			}
+			msgs, err := cs.LoadCatalog(category, lang)
+			if err != nil {
+				if errors.Is(err, ErrCatalogNotFound) {
+					continue
+				}
+				return nil, err
+			}
+			for key, translation := range msgs {
+				source := key
+				if original[key] != "" {
+					source = original[key]
+				}
+				tm.Add(category, source, lang, translation)
+			}
+		}
+	}
+	return tm, nil
+}
+
+// Add Adds translation, the translation of source to lang in category.
+// Empty translations are skipped.
+func (tm *TranslationMemory) Add(category string, source string, lang string, translation string) {
+	u := tmUnit{category: category, source: source, lang: lang, translation: translation}
+	if source == "" || translation == "" || tm.seen[u] {
+		return
+	}
+	tm.seen[u] = true
+	trigrams := tm.index[lang]
+	if trigrams == nil {
+		trigrams = make(map[string][]int)
+		tm.index[lang] = trigrams
+	}
+	grams := trigramSet(source)
+	for _, g := range grams {
+		trigrams[g] = append(trigrams[g], len(tm.units))
+	}
+	tm.units = append(tm.units, u)
+	tm.sizes = append(tm.sizes, len(grams))
+}
+
+// Suggest Returns at most n translations to lang of the texts most similar
+// to source, best first. Candidates sharing trigrams with source are scored
+// by the Levenshtein distance of their text, ignoring case.
+func (tm *TranslationMemory) Suggest(source string, lang string, n int) []Suggestion {
+	trigrams := tm.index[lang]
+	if trigrams == nil || n <= 0 {
+		return nil
+	}
+	query := trigramSet(source)
+	shared := make(map[int]int)
+	for _, g := range query {
+		for _, u := range trigrams[g] {
+			shared[u]++
+		}
+	}
+	// Rank by the Dice coefficient of the trigrams, which is cheap, then
+	// score the best candidates exactly.
+	type candidate struct {
+		unit int
+		dice float64
+	}
+	candidates := make([]candidate, 0, len(shared))
+	for u, count := range shared {
+		dice := 2 * float64(count) / float64(len(query)+tm.sizes[u])
+		candidates = append(candidates, candidate{u, dice})
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].dice != candidates[b].dice {
+			return candidates[a].dice > candidates[b].dice
+		}
+		return candidates[a].unit < candidates[b].unit
+	})
+	if limit := max(4*n, 20); len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	minScore := tm.MinScore
+	if minScore == 0 {
+		minScore = 0.5
+	}
+	lower := strings.ToLower(source)
+	var suggestions []Suggestion
+	for _, c := range candidates {
+		u := tm.units[c.unit]
+		if score := similarity(lower, strings.ToLower(u.source)); score >= minScore {
+			suggestions = append(suggestions, Suggestion{Category: u.category, Source: u.source, Translation: u.translation, Score: score})
+		}
+	}
+	sort.SliceStable(suggestions, func(a, b int) bool { return suggestions[a].Score > suggestions[b].Score })
+	if len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
+
+// trigramSet Returns the distinct trigrams of s, ignoring case, padded so
+// that short texts have some.
+func trigramSet(s string) []string {
+	runes := []rune("  " + strings.ToLower(s) + " ")
+	seen := make(map[string]bool, len(runes))
+	var trigrams []string
+	for n := 0; n+3 <= len(runes); n++ {
+		g := string(runes[n : n+3])
+		if !seen[g] {
+			seen[g] = true
+			trigrams = append(trigrams, g)
+		}
+	}
+	return trigrams
+}
+
+// similarity Returns 1 minus the Levenshtein distance of a and b relative
+// to the length of the longer.
+func similarity(a string, b string) float64 {
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein([]rune(a), []rune(b)))/float64(longest)
+}
+
+// levenshtein Returns the edit distance of a and b.
+func levenshtein(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for k := range prev {
+		prev[k] = k
+	}
+	for m := 1; m <= len(a); m++ {
+		cur[0] = m
+		for k := 1; k <= len(b); k++ {
+			cost := 1
+			if a[m-1] == b[k-1] {
+				cost = 0
+			}
+			cur[k] = min(prev[k]+1, cur[k-1]+1, prev[k-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,130 @@
+package ii18n
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Message is implemented by typed messages, structs whose exported fields
+// are the params of a message, e.g.
+//
+//	type WelcomeMsg struct{ Name string }
+//
+//	func (WelcomeMsg) MessageKey() (string, string) { return "app.home", "Welcome, {name}!" }
+type Message interface {
+	MessageKey() (category string, key string)
+}
+
+// langKey the context key of the language set by WithLang.
+type langKey struct{}
+
+// WithLang Returns a copy of ctx carrying lang, the language of Translate.
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// LangFromContext Returns the language set by WithLang, or "".
+func LangFromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(langKey{}).(string)
+	return lang
+}
+
+// Translate Translates msg to the language of ctx, see WithLang, or to the
+// default language of Translator. The params are the exported fields of msg,
+// named by their `param` tag or by their name with a lowercase initial, and
+// `param:"-"` fields are skipped.
+func Translate[M Message](ctx context.Context, msg M) string {
+	category, key := msg.MessageKey()
+	result, _ := Translator.translate(ctx, Translator.normalizeCategory(category), key, messageParams(msg), LangFromContext(ctx))
+	return result
+}
+
+// messageField a param of a typed message.
+type messageField struct {
+	index int
+	name  string
+}
+
+// messageFields the params of typed messages by struct type.
+var messageFields sync.Map
+
+// messageParams Returns the params of msg, nil when msg is not a struct.
+func messageParams(msg Message) map[string]string {
+	v := reflect.ValueOf(msg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	fields, ok := messageFields.Load(v.Type())
+	if !ok {
+		fields, _ = messageFields.LoadOrStore(v.Type(), structFields(v.Type()))
+	}
+	params := make(map[string]string)
+	for _, f := range fields.([]messageField) {
+		params[f.name] = paramString(v.Field(f.index))
+	}
+	return params
+}
+
+// structFields Returns the params of the struct type t.
+func structFields(t reflect.Type) []messageField {
+	var fields []messageField
+	for n := 0; n < t.NumField(); n++ {
+		sf := t.Field(n)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("param"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			r, size := utf8.DecodeRuneInString(sf.Name)
+			name = string(unicode.ToLower(r)) + sf.Name[size:]
+		}
+		fields = append(fields, messageField{index: n, name: name})
+	}
+	return fields
+}
+
+// paramString Returns the param value of the field v: numbers in the
+// unformatted notation the formatter parses, times in RFC 3339 for date
+// arguments, Stringers by their String.
+func paramString(v reflect.Value) string {
+	if !v.IsValid() || (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return ""
+	}
+	switch val := v.Interface().(type) {
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case *time.Time:
+		return val.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return val.String()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,146 @@
+package ii18n
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// IssueKind the kind of problem of a ValidationIssue.
+type IssueKind string
+
+// Issue kinds.
+const (
+	// IssueEmpty the translation is empty.
+	IssueEmpty IssueKind = "empty"
+	// IssueWhitespace the translation starts or ends with whitespace the
+	// original message does not have, or the other way round.
+	IssueWhitespace IssueKind = "whitespace"
+	// IssueInvalidUTF8 the key or translation is not valid UTF-8.
+	IssueInvalidUTF8 IssueKind = "invalid-utf8"
+	// IssueInvalidPattern the translation is not a valid ICU message pattern.
+	IssueInvalidPattern IssueKind = "invalid-pattern"
+	// IssuePlaceholders the translation and the original message use
+	// different placeholders.
+	IssuePlaceholders IssueKind = "placeholders"
+)
+
+// ValidationIssue a problem of a message in a catalog.
+type ValidationIssue struct {
+	Lang   string
+	Key    string
+	Kind   IssueKind
+	Detail string
+}
+
+// Validate Returns the issues of every available language of category,
+// sorted. Categories without a dot are resolved like T does. The source of
+// category must implement Catalogs.
+func Validate(category string) ([]ValidationIssue, error) {
+	return Translator.Validate(category)
+}
+
+// Validate Returns the issues of every available language of category.
+// Catalogs that do not exist are skipped; other load errors are returned.
+func (i *I18N) Validate(category string) ([]ValidationIssue, error) {
+	category = i.normalizeCategory(category)
+	cs, ol, err := i.catalogs(category)
+	if err != nil {
+		return nil, err
+	}
+	langs, err := cs.AvailableLanguages()
+	if err != nil {
+		return nil, err
+	}
+	original, err := loadCatalog(cs, category, ol)
+	if err != nil {
+		return nil, err
+	}
+	var issues []ValidationIssue
+	for _, lang := range langs {
+		if lang == ol {
+			continue
+		}
+		msgs, err := loadCatalog(cs, category, lang)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, ValidateMsgs(lang, msgs, original)...)
+	}
+	return issues, nil
+}
+
+// ValidateMsgs Returns the issues of msgs, the catalog of lang, sorted by key.
+// Translations are compared with the messages of original, or with their
+// keys when original has none.
+func ValidateMsgs(lang string, msgs TMsgs, original TMsgs) []ValidationIssue {
+	var issues []ValidationIssue
+	report := func(key string, kind IssueKind, detail string) {
+		issues = append(issues, ValidationIssue{Lang: lang, Key: key, Kind: kind, Detail: detail})
+	}
+	for key, val := range msgs {
+		if !utf8.ValidString(key) || !utf8.ValidString(val) {
+			report(key, IssueInvalidUTF8, "")
+			continue
+		}
+		if val == "" {
+			report(key, IssueEmpty, "")
+			continue
+		}
+		source := key
+		if original[key] != "" {
+			source = original[key]
+		}
+		if edgeSpace(val) != edgeSpace(source) {
+			report(key, IssueWhitespace, "leading or trailing whitespace differs from "+source)
+		}
+		names, err := Placeholders(val)
+		if err != nil {
+			report(key, IssueInvalidPattern, err.Error())
+			continue
+		}
+		expected, err := Placeholders(source)
+		if err != nil {
+			continue
+		}
+		var diff []string
+		for _, name := range expected {
+			if !contains(names, name) {
+				diff = append(diff, "missing "+name)
+			}
+		}
+		for _, name := range names {
+			if !contains(expected, name) {
+				diff = append(diff, "unknown "+name)
+			}
+		}
+		if len(diff) > 0 {
+			report(key, IssuePlaceholders, strings.Join(diff, ", "))
+		}
+	}
+	sort.Slice(issues, func(a, b int) bool {
+		if issues[a].Key != issues[b].Key {
+			return issues[a].Key < issues[b].Key
+		}
+		return issues[a].Kind < issues[b].Kind
+	})
+	return issues
+}
+
+// edgeSpace Returns whether s starts and whether it ends with whitespace.
+func edgeSpace(s string) [2]bool {
+	first, _ := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	return [2]bool{unicode.IsSpace(first), unicode.IsSpace(last)}
+}
+
+// contains Whether names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
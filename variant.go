@@ -0,0 +1,78 @@
+package ii18n
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+)
+
+// VariantSelector Returns the variant of message in category to serve to the
+// request of ctx, "" for the message itself. The variant v of a message is
+// the catalog key message+"|"+v, e.g. "Buy now|b", and messages without it
+// in the catalog of the language are served as usual.
+type VariantSelector func(ctx context.Context, category string, message string) string
+
+// Experiment an A/B test of the copy of a message.
+type Experiment struct {
+	// ID the experiment, which salts the assignment of buckets.
+	ID string
+	// Weights of the variants, "" being the message itself, e.g.
+	// {"": 50, "b": 50}.
+	Weights map[string]int
+}
+
+// bucketKey the context key of the bucket set by WithBucket.
+type bucketKey struct{}
+
+// WithBucket Returns a copy of ctx carrying bucket, e.g. a user ID, which
+// assigns the request to the variants of experiments.
+func WithBucket(ctx context.Context, bucket string) context.Context {
+	return context.WithValue(ctx, bucketKey{}, bucket)
+}
+
+// BucketFromContext Returns the bucket set by WithBucket, or "".
+func BucketFromContext(ctx context.Context) string {
+	bucket, _ := ctx.Value(bucketKey{}).(string)
+	return bucket
+}
+
+// NewExperimentSelector Returns a VariantSelector serving the experiments
+// by message, in any category. A bucket is assigned to a variant by the
+// hash of the experiment ID and the bucket, so it keeps its variant across
+// requests and processes; requests without a bucket get the message itself.
+func NewExperimentSelector(experiments map[string]Experiment) VariantSelector {
+	type variant struct {
+		name string
+		upTo uint32
+	}
+	type plan struct {
+		id       string
+		total    uint32
+		variants []variant
+	}
+	plans := make(map[string]plan, len(experiments))
+	for message, e := range experiments {
+		p := plan{id: e.ID}
+		for _, name := range sortedKeys(e.Weights) {
+			if w := e.Weights[name]; w > 0 {
+				p.total += uint32(w)
+				p.variants = append(p.variants, variant{name, p.total})
+			}
+		}
+		if p.total > 0 {
+			plans[message] = p
+		}
+	}
+	return func(ctx context.Context, category string, message string) string {
+		p, ok := plans[message]
+		bucket := BucketFromContext(ctx)
+		if !ok || bucket == "" {
+			return ""
+		}
+		h := fnv.New32a()
+		h.Write([]byte(p.id + "/" + bucket))
+		n := h.Sum32() % p.total
+		pos := sort.Search(len(p.variants), func(k int) bool { return n < p.variants[k].upTo })
+		return p.variants[pos].name
+	}
+}
@@ -0,0 +1,74 @@
+package ii18n
+
+import (
+	"encoding/xml"
+)
+
+// Type XLIFFSource reads XLIFF 1.2 catalogs.
+type XLIFFSource struct {
+	MessageSource
+}
+
+// New XLIFFSource
+func NewXLIFFSource(conf *Config) Source {
+	s := &XLIFFSource{}
+	s.init(conf, "xliff", xliffCodec{})
+
+	return s
+}
+
+// xliffDoc the parts of an XLIFF 1.2 document used for catalogs.
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr,omitempty"`
+	Datatype       string      `xml:"datatype,attr"`
+	Units          []xliffUnit `xml:"body>trans-unit"`
+}
+
+type xliffUnit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target"`
+}
+
+// xliffCodec XLIFF 1.2 documents. The id of a trans-unit is the key, its
+// source when there is no id.
+type xliffCodec struct{}
+
+func (xliffCodec) Decode(data []byte) (TMsgs, error) {
+	var doc xliffDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	msgs := make(TMsgs)
+	for _, file := range doc.Files {
+		for _, unit := range file.Units {
+			key := unit.ID
+			if key == "" {
+				key = unit.Source
+			}
+			msgs[key] = unit.Target
+		}
+	}
+	return msgs, nil
+}
+
+// Encode Encodes msgs as a single file with sorted trans-units.
+func (xliffCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	file := xliffFile{Original: "messages", SourceLanguage: DefaultOriginalLang, TargetLanguage: lang, Datatype: "plaintext"}
+	for _, key := range sortedKeys(msgs) {
+		file.Units = append(file.Units, xliffUnit{ID: key, Source: key, Target: msgs[key]})
+	}
+	data, err := xml.MarshalIndent(xliffDoc{Version: "1.2", Files: []xliffFile{file}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
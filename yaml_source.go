@@ -0,0 +1,170 @@
+package ii18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Type YAMLSource reads flat YAML catalogs.
+type YAMLSource struct {
+	MessageSource
+}
+
+// New YAMLSource
+func NewYAMLSource(conf *Config) Source {
+	s := &YAMLSource{}
+	s.init(conf, "yaml", yamlCodec{})
+
+	return s
+}
+
+// yamlCodec flat YAML mappings of message to translation. Plain, single and
+// double quoted scalars are supported; nested mappings, sequences and block
+// scalars are not.
+type yamlCodec struct{}
+
+func (yamlCodec) Decode(data []byte) (TMsgs, error) {
+	c, err := yamlCodec{}.DecodeCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.Msgs(), nil
+}
+
+// DecodeCatalog Decodes entries; comment lines directly above a key become
+// its comments.
+func (yamlCodec) DecodeCatalog(data []byte) (*Catalog, error) {
+	c := &Catalog{}
+	var comments []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && trimmed[0] == '#' {
+			comments = append(comments, strings.TrimSpace(trimmed[1:]))
+			continue
+		}
+		if trimmed == "" || trimmed == "---" || trimmed == "..." {
+			comments = nil
+			continue
+		}
+		errorf := func(msg string) error {
+			return errors.New("line " + strconv.Itoa(n) + ": " + msg)
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return nil, errorf("nested values are not supported")
+		}
+		key, rest, err := yamlScalar(line, true)
+		if err != nil {
+			return nil, errorf(err.Error())
+		}
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, ":") {
+			return nil, errorf("expected ':' after key")
+		}
+		rest = strings.TrimSpace(rest[1:])
+		val := ""
+		if rest != "" && rest[0] != '#' {
+			if rest[0] == '|' || rest[0] == '>' || rest[0] == '[' || rest[0] == '{' || rest[0] == '&' || rest[0] == '*' {
+				return nil, errorf("unsupported value " + rest)
+			}
+			if val, rest, err = yamlScalar(rest, false); err != nil {
+				return nil, errorf(err.Error())
+			}
+			if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+				return nil, errorf("unexpected " + rest)
+			}
+		}
+		if val == "~" || val == "null" {
+			val = ""
+		}
+		c.Entries = append(c.Entries, Entry{Key: key, Value: val, Comments: comments})
+		comments = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// yamlScalar Parses the scalar at the start of s and returns the rest. A
+// plain key ends at ": ", a plain value at " #".
+func yamlScalar(s string, isKey bool) (string, string, error) {
+	switch s[0] {
+	case '"':
+		for i := 1; i < len(s); i++ {
+			if s[i] == '\\' {
+				i++
+				continue
+			}
+			if s[i] == '"' {
+				val, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return "", "", errors.New("invalid string " + s[:i+1])
+				}
+				return val, s[i+1:], nil
+			}
+		}
+		return "", "", errors.New("unterminated string")
+	case '\'':
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				b.WriteByte(s[i])
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			return b.String(), s[i+1:], nil
+		}
+		return "", "", errors.New("unterminated string")
+	}
+	end := len(s)
+	if isKey {
+		if pos := strings.Index(s+" ", ": "); pos != -1 {
+			end = pos
+		}
+	} else if pos := strings.Index(s, " #"); pos != -1 {
+		end = pos
+	}
+	return strings.TrimSpace(s[:end]), s[end:], nil
+}
+
+func (yamlCodec) Encode(msgs TMsgs, lang string) ([]byte, error) {
+	return yamlCodec{}.EncodeCatalog(NewCatalog(msgs), lang)
+}
+
+// EncodeCatalog Encodes entries sorted by key with double quoted keys and
+// values, comments above their entry.
+func (yamlCodec) EncodeCatalog(c *Catalog, lang string) ([]byte, error) {
+	sorted := &Catalog{Entries: append([]Entry(nil), c.Entries...)}
+	sorted.Sort()
+	var b bytes.Buffer
+	for _, e := range sorted.Entries {
+		for _, comment := range e.Comments {
+			b.WriteString(strings.TrimRight("# "+comment, " ") + "\n")
+		}
+		b.Write(yamlQuote(e.Key))
+		b.WriteString(": ")
+		b.Write(yamlQuote(e.Value))
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// yamlQuote Quotes s as a double quoted scalar. JSON strings are valid YAML.
+func yamlQuote(s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}